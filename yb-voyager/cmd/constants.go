@@ -15,6 +15,8 @@ limitations under the License.
 */
 package cmd
 
+import "github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+
 const (
 	KB                            = 1024
 	MB                            = 1024 * 1024
@@ -34,22 +36,49 @@ const (
 	SPLIT_INFO_PATTERN            = "[0-9]*.[0-9]*.[0-9]*.[0-9]*"
 	LAST_SPLIT_PATTERN            = "0.[0-9]*.[0-9]*.[0-9]*"
 	COPY_MAX_RETRY_COUNT          = 10
+	COPY_BACKOFF_LINEAR           = "linear"
+	COPY_BACKOFF_EXPONENTIAL      = "exponential"
+	COPY_RETRY_SLEEP_STEP_SECOND  = 10
 	MAX_SLEEP_SECOND              = 60
 	DEFAULT_BATCH_SIZE_ORACLE     = 10000000
 	DEFAULT_BATCH_SIZE_YUGABYTEDB = 20000
 	INDEX_RETRY_COUNT             = 5
 	DDL_MAX_RETRY_COUNT           = 5
 	SCHEMA_VERSION_MISMATCH_ERR   = "Query error: schema version mismatch for table"
+	QUERY_CANCELED_ERR_CODE       = "57014" // SQLSTATE of a statement cancelled, e.g. on statement_timeout
+	DEADLOCK_DETECTED_ERR_CODE    = "40P01"
+	SERIALIZATION_FAILED_ERR_CODE = "40001"
 	SNAPSHOT_ONLY                 = "snapshot-only"
 	SNAPSHOT_AND_CHANGES          = "snapshot-and-changes"
 	CHANGES_ONLY                  = "changes-only"
 	TARGET_DB                     = "target"
 	FF_DB                         = "ff"
+	VSN_GAP_DETECTION_DISABLED    = "disabled"
+	VSN_GAP_DETECTION_WARN        = "warn"
+	VSN_GAP_DETECTION_ABORT       = "abort"
+	UNKNOWN_TABLE_SKIP            = "skip"
+	UNKNOWN_TABLE_ERROR           = "error"
+	UNKNOWN_TABLE_AUTO_CREATE     = "auto-create"
+	IMPORT_ORDER_INPROGRESS_FIRST = "inprogress-first"
+	IMPORT_ORDER_LARGEST_FIRST    = "largest-first"
+	IMPORT_ORDER_SMALLEST_FIRST   = "smallest-first"
+	IMPORT_ORDER_DESCRIPTOR       = "descriptor"
+	PROGRESS_OUTPUT_BAR           = "bar"
+	PROGRESS_OUTPUT_JSON          = "json"
+	STATUS_OUTPUT_TABLE           = "table"
+	STATUS_OUTPUT_JSON            = "json"
 )
 
 var supportedSourceDBTypes = []string{ORACLE, MYSQL, POSTGRESQL, YUGABYTEDB}
-var supportedTargetDBTypes = []string{YUGABYTEDB, ORACLE}
+var supportedTargetDBTypes = []string{YUGABYTEDB, ORACLE, MYSQL}
 var validExportTypes = []string{SNAPSHOT_ONLY, CHANGES_ONLY, SNAPSHOT_AND_CHANGES}
+var validVsnGapDetectionModes = []string{VSN_GAP_DETECTION_DISABLED, VSN_GAP_DETECTION_WARN, VSN_GAP_DETECTION_ABORT}
+var validUnknownTablePolicies = []string{UNKNOWN_TABLE_SKIP, UNKNOWN_TABLE_ERROR, UNKNOWN_TABLE_AUTO_CREATE}
+var validCopyRetryBackoffs = []string{COPY_BACKOFF_LINEAR, COPY_BACKOFF_EXPONENTIAL}
+var validProgressOutputs = []string{PROGRESS_OUTPUT_BAR, PROGRESS_OUTPUT_JSON}
+var validStatusOutputs = []string{STATUS_OUTPUT_TABLE, STATUS_OUTPUT_JSON}
+var validImportOrders = []string{IMPORT_ORDER_INPROGRESS_FIRST, IMPORT_ORDER_LARGEST_FIRST, IMPORT_ORDER_SMALLEST_FIRST, IMPORT_ORDER_DESCRIPTOR}
+var validOnPrimaryKeyConflicts = []string{tgtdb.ON_PRIMARY_KEY_CONFLICT_ERROR, tgtdb.ON_PRIMARY_KEY_CONFLICT_SKIP, tgtdb.ON_PRIMARY_KEY_CONFLICT_UPDATE}
 
 var validSSLModes = map[string][]string{
 	"mysql":      {"disable", "prefer", "require", "verify-ca", "verify-full"},
@@ -0,0 +1,242 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/datafile"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+const (
+	CUTOVER_CHECK_ROW_COUNTS   = "row-counts"
+	CUTOVER_CHECK_SEQUENCES    = "sequences"
+	CUTOVER_CHECK_FOREIGN_KEYS = "foreign-keys"
+
+	CUTOVER_CHECK_PASSED = "passed"
+	CUTOVER_CHECK_FAILED = "failed"
+)
+
+// The sampled checksums are not among the checks, as they have to be computed on both the databases,
+// but the switchover is connected only to the fall forward database.
+var validCutoverChecks = []string{CUTOVER_CHECK_ROW_COUNTS, CUTOVER_CHECK_SEQUENCES, CUTOVER_CHECK_FOREIGN_KEYS}
+
+var generateCutoverReport bool
+var cutoverChecks []string
+
+// CutoverReport is the sign-off document of the switchover, written to <export-dir>/reports/cutover_report.{json,txt}.
+type CutoverReport struct {
+	MigrationUUID string          `json:"migration_uuid"`
+	GeneratedAt   string          `json:"generated_at"`
+	Passed        bool            `json:"passed"`
+	Checks        []*CutoverCheck `json:"checks"`
+}
+
+type CutoverCheck struct {
+	Name    string      `json:"name"`
+	Status  string      `json:"status"`
+	Message string      `json:"message,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+type TableRowCountCheck struct {
+	TableName       string `json:"table_name"`
+	SnapshotRows    int64  `json:"snapshot_rows"`
+	StreamedInserts int64  `json:"streamed_inserts"`
+	StreamedDeletes int64  `json:"streamed_deletes"`
+	ExpectedRows    int64  `json:"expected_rows"`
+	ActualRows      int64  `json:"actual_rows"`
+}
+
+type SequenceValueCheck struct {
+	SequenceName      string `json:"sequence_name"`
+	ExportedLastValue int64  `json:"exported_last_value"`
+	TargetLastValue   int64  `json:"target_last_value"`
+}
+
+func validateCutoverChecksFlag() {
+	for i, check := range cutoverChecks {
+		cutoverChecks[i] = strings.ToLower(check)
+		if !slices.Contains(validCutoverChecks, cutoverChecks[i]) {
+			utils.ErrExit("Error: Invalid cutover-report-checks: %q. Supported values are: %s", check, validCutoverChecks)
+		}
+	}
+}
+
+// writeCutoverReport runs the enabled checks on the target db and writes the report. It fails only if the report
+// can't be written; the failed checks are reported in it.
+func writeCutoverReport(sequencesLastVal map[string]int64) {
+	report := &CutoverReport{
+		MigrationUUID: migrationUUID.String(),
+		GeneratedAt:   time.Now().Format(time.RFC3339),
+	}
+	checkFns := map[string]func() *CutoverCheck{
+		CUTOVER_CHECK_ROW_COUNTS:   checkCutoverRowCounts,
+		CUTOVER_CHECK_SEQUENCES:    func() *CutoverCheck { return checkCutoverSequences(sequencesLastVal) },
+		CUTOVER_CHECK_FOREIGN_KEYS: checkCutoverForeignKeys,
+	}
+	for _, name := range validCutoverChecks {
+		if !slices.Contains(cutoverChecks, name) {
+			continue
+		}
+		utils.PrintAndLog("running cutover check: %s", name)
+		check := checkFns[name]()
+		check.Name = name
+		report.Checks = append(report.Checks, check)
+	}
+	report.Passed = !lo.ContainsBy(report.Checks, func(check *CutoverCheck) bool { return check.Status == CUTOVER_CHECK_FAILED })
+
+	reportDir := filepath.Join(exportDir, "reports")
+	err := os.MkdirAll(reportDir, 0755)
+	if err != nil {
+		utils.ErrExit("create reports dir %q: %s", reportDir, err)
+	}
+	jsonBytes, err := json.MarshalIndent(report, "", "    ")
+	if err != nil {
+		utils.ErrExit("marshal cutover report: %s", err)
+	}
+	jsonPath := filepath.Join(reportDir, "cutover_report.json")
+	err = os.WriteFile(jsonPath, jsonBytes, 0644)
+	if err != nil {
+		utils.ErrExit("write cutover report to %q: %s", jsonPath, err)
+	}
+	summary := report.Summary()
+	txtPath := filepath.Join(reportDir, "cutover_report.txt")
+	err = os.WriteFile(txtPath, []byte(summary), 0644)
+	if err != nil {
+		utils.ErrExit("write cutover report to %q: %s", txtPath, err)
+	}
+	fmt.Print(summary)
+	utils.PrintAndLog("cutover report written to %q and %q", jsonPath, txtPath)
+}
+
+func (r *CutoverReport) Summary() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Cutover report of migration %s, generated at %s\n", r.MigrationUUID, r.GeneratedAt)
+	for _, check := range r.Checks {
+		fmt.Fprintf(&sb, "  %-14s %s", check.Name+":", strings.ToUpper(check.Status))
+		if check.Message != "" {
+			fmt.Fprintf(&sb, " - %s", check.Message)
+		}
+		sb.WriteString("\n")
+	}
+	if r.Passed {
+		sb.WriteString("All the checks passed.\n")
+	} else {
+		sb.WriteString("Some of the checks FAILED, see the json report for the details.\n")
+	}
+	return sb.String()
+}
+
+func failedCutoverCheck(err error) *CutoverCheck {
+	log.Errorf("cutover check failed: %s", err)
+	return &CutoverCheck{Status: CUTOVER_CHECK_FAILED, Message: err.Error()}
+}
+
+// checkCutoverRowCounts compares the rows of the tables with the rows of the snapshot
+// plus the inserts and minus the deletes streamed to them.
+func checkCutoverRowCounts() *CutoverCheck {
+	eventCounts, err := tdb.GetImportedEventCountsByTable(migrationUUID)
+	if err != nil {
+		return failedCutoverCheck(err)
+	}
+	snapshotRows := make(map[string]int64)
+	for _, fileEntry := range datafile.OpenDescriptor(exportDir).DataFileList {
+		snapshotRows[getTargetTableName(fileEntry.TableName)] += fileEntry.RowCount
+	}
+	var tableChecks []*TableRowCountCheck
+	var mismatchedTables []string
+	for _, tableName := range lo.Union(lo.Keys(snapshotRows), lo.Keys(eventCounts)) {
+		tableCheck := &TableRowCountCheck{TableName: tableName, SnapshotRows: snapshotRows[tableName]}
+		if counts, ok := eventCounts[tableName]; ok {
+			tableCheck.StreamedInserts = counts.NumInserts
+			tableCheck.StreamedDeletes = counts.NumDeletes
+		}
+		tableCheck.ExpectedRows = tableCheck.SnapshotRows + tableCheck.StreamedInserts - tableCheck.StreamedDeletes
+		tableCheck.ActualRows, err = tdb.GetRowCount(tableName)
+		if err != nil {
+			return failedCutoverCheck(err)
+		}
+		if tableCheck.ActualRows != tableCheck.ExpectedRows {
+			mismatchedTables = append(mismatchedTables, tableName)
+		}
+		tableChecks = append(tableChecks, tableCheck)
+	}
+	slices.SortFunc(tableChecks, func(a, b *TableRowCountCheck) bool { return a.TableName < b.TableName })
+	check := &CutoverCheck{Status: CUTOVER_CHECK_PASSED, Details: tableChecks}
+	if len(mismatchedTables) > 0 {
+		slices.Sort(mismatchedTables)
+		check.Status = CUTOVER_CHECK_FAILED
+		check.Message = fmt.Sprintf("row count mismatch in tables: %v", mismatchedTables)
+	}
+	return check
+}
+
+// checkCutoverSequences checks that the sequences are restored at least up to their last value on the source.
+func checkCutoverSequences(sequencesLastVal map[string]int64) *CutoverCheck {
+	var sequenceChecks []*SequenceValueCheck
+	var behindSequences []string
+	for sequenceName, lastValue := range sequencesLastVal {
+		if lastValue == 0 {
+			continue // not restored, see RestoreSequences()
+		}
+		targetLastValue, err := tdb.GetSequenceLastValue(tconf.GetTargetTableName(sequenceName))
+		if err != nil {
+			return failedCutoverCheck(err)
+		}
+		sequenceChecks = append(sequenceChecks, &SequenceValueCheck{
+			SequenceName:      sequenceName,
+			ExportedLastValue: lastValue,
+			TargetLastValue:   targetLastValue,
+		})
+		if targetLastValue < lastValue {
+			behindSequences = append(behindSequences, sequenceName)
+		}
+	}
+	slices.SortFunc(sequenceChecks, func(a, b *SequenceValueCheck) bool { return a.SequenceName < b.SequenceName })
+	check := &CutoverCheck{Status: CUTOVER_CHECK_PASSED, Details: sequenceChecks}
+	if len(behindSequences) > 0 {
+		slices.Sort(behindSequences)
+		check.Status = CUTOVER_CHECK_FAILED
+		check.Message = fmt.Sprintf("sequences behind their exported last value: %v", behindSequences)
+	}
+	return check
+}
+
+func checkCutoverForeignKeys() *CutoverCheck {
+	invalidForeignKeys, err := tdb.GetInvalidForeignKeys()
+	if err != nil {
+		return failedCutoverCheck(err)
+	}
+	if len(invalidForeignKeys) > 0 {
+		return &CutoverCheck{
+			Status:  CUTOVER_CHECK_FAILED,
+			Message: fmt.Sprintf("%d foreign keys are disabled or not validated", len(invalidForeignKeys)),
+			Details: invalidForeignKeys,
+		}
+	}
+	return &CutoverCheck{Status: CUTOVER_CHECK_PASSED}
+}
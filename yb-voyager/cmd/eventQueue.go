@@ -19,6 +19,7 @@ package cmd
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -68,6 +69,7 @@ type EventQueueSegment struct {
 	file       *os.File
 	scanner    *bufio.Scanner
 	buffer     []byte // buffer for scanning from file
+	offset     int64  // offset of the next line to be read
 }
 
 var EOFMarker = `\.`
@@ -80,17 +82,24 @@ func NewEventQueueSegment(filePath string, segmentNum int64) *EventQueueSegment
 	}
 }
 
-func (eqs *EventQueueSegment) Open() error {
+// Open opens the segment file to read the events from the given offset, which must be the start of a line.
+func (eqs *EventQueueSegment) Open(offset int64) error {
 	file, err := os.OpenFile(eqs.FilePath, os.O_RDONLY, 0640)
 	if err != nil {
 		return fmt.Errorf("failed to open segment file %s: %w", eqs.FilePath, err)
 	}
 	eqs.file = file
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to seek to offset %d of segment file %s: %w", offset, eqs.FilePath, err)
+	}
+	eqs.offset = offset
 
 	fn := func() (int64, error) {
 		return metaDB.GetLastValidOffsetInSegmentFile(eqs.SegmentNum)
 	}
-	eqs.scanner = bufio.NewScanner(utils.NewTailReader(file, fn))
+	eqs.scanner = bufio.NewScanner(utils.NewTailReaderAt(file, offset, fn))
 
 	// providing buffer to scanner for scanning
 	eqs.buffer = make([]byte, 0, 100*KB)
@@ -114,6 +123,8 @@ func (eqs *EventQueueSegment) NextEvent() (*tgtdb.Event, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read line from %s: %w", eqs.FilePath, err)
 	}
+	lineOffset := eqs.offset
+	eqs.offset += int64(len(line)) + 1 // with the newline
 
 	if string(line) == EOFMarker {
 		log.Infof("reached EOF marker in segment %s", eqs.FilePath)
@@ -123,11 +134,16 @@ func (eqs *EventQueueSegment) NextEvent() (*tgtdb.Event, error) {
 
 	err = json.Unmarshal(line, &event)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal json event %s: %w", string(line), err)
+		return nil, fmt.Errorf("failed to unmarshal json event %s at offset %d of %s: %w", string(line), lineOffset, eqs.FilePath, err)
 	}
 	return &event, nil
 }
 
+// Offset returns the offset in the segment file after the last event read.
+func (eqs *EventQueueSegment) Offset() int64 {
+	return eqs.offset
+}
+
 func (eqs *EventQueueSegment) IsProcessed() bool {
 	return eqs.processed
 }
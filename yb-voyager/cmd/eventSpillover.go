@@ -0,0 +1,227 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/goccy/go-json"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+)
+
+var eventSpillDir string
+var eventSpillMaxSizeMB int64
+
+/*
+EventSpillover sits between the queue reader and the processor of an event channel. Events which don't fit
+in the in-memory buffer of the channel are appended to a file, and are moved to the channel from there,
+in order, as the processor catches up. The reader blocks only once the spill files of all the channels
+together reach the configured size.
+*/
+type EventSpillover struct {
+	chanNo    int
+	in        chan *tgtdb.Event
+	out       chan *tgtdb.Event
+	filePath  string
+	writer    *bufio.Writer
+	file      *os.File
+	readFile  *os.File
+	reader    *bufio.Reader
+	numEvents int64 // number of events in the spill file yet to be moved to `out`
+
+	spilledBytes    *int64 // shared by the spillovers of all the channels
+	maxSpilledBytes int64
+}
+
+// setupEventSpillover returns the channels the reader sends the events to, each of which is drained into
+// the corresponding channel of `evChans` with spillover to disk.
+func setupEventSpillover(evChans []chan *tgtdb.Event, streamErrs chan<- error) ([]chan *tgtdb.Event, error) {
+	spillDir := eventSpillDir
+	if spillDir == "" {
+		spillDir = filepath.Join(exportDir, "metainfo", fmt.Sprintf("event_spill_%s", importDestinationType))
+	}
+	// the spilled events are not applied yet, so they are read again from the queue on restart
+	err := os.RemoveAll(spillDir)
+	if err != nil {
+		return nil, fmt.Errorf("remove event spill dir %q: %w", spillDir, err)
+	}
+	err = os.MkdirAll(spillDir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("create event spill dir %q: %w", spillDir, err)
+	}
+	log.Infof("spilling events to %q, up to %d MB", spillDir, eventSpillMaxSizeMB)
+
+	var spilledBytes int64
+	var inChans []chan *tgtdb.Event
+	for i, evChan := range evChans {
+		spillover := &EventSpillover{
+			chanNo:          i,
+			in:              make(chan *tgtdb.Event),
+			out:             evChan,
+			filePath:        filepath.Join(spillDir, fmt.Sprintf("channel_%d.ndjson", i)),
+			spilledBytes:    &spilledBytes,
+			maxSpilledBytes: eventSpillMaxSizeMB * MB,
+		}
+		inChans = append(inChans, spillover.in)
+		go func() {
+			err := spillover.run()
+			if err != nil {
+				streamErrs <- fmt.Errorf("event spillover of channel %d: %w", spillover.chanNo, err)
+			}
+		}()
+	}
+	return inChans, nil
+}
+
+func (s *EventSpillover) run() error {
+	var next *tgtdb.Event // next spilled event to be moved to `out`
+	for {
+		if s.numEvents == 0 {
+			event := <-s.in
+			select {
+			case s.out <- event:
+			default:
+				err := s.spill(event)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if next == nil {
+			var err error
+			next, err = s.unspill()
+			if err != nil {
+				return err
+			}
+		}
+		in := s.in
+		if atomic.LoadInt64(s.spilledBytes) >= s.maxSpilledBytes {
+			in = nil // block the reader until the processor catches up
+		}
+		select {
+		case event := <-in:
+			err := s.spill(event)
+			if err != nil {
+				return err
+			}
+		case s.out <- next:
+			next = nil
+			s.numEvents--
+			if s.numEvents == 0 {
+				err := s.reset()
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (s *EventSpillover) spill(event *tgtdb.Event) error {
+	if s.file == nil {
+		file, err := os.OpenFile(s.filePath, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
+		if err != nil {
+			return fmt.Errorf("open spill file %q: %w", s.filePath, err)
+		}
+		s.file = file
+		s.writer = bufio.NewWriter(file)
+		err = s.openReader()
+		if err != nil {
+			return err
+		}
+	}
+	line, err := json.Marshal(&spilledEvent{Event: event, TargetSchemaName: event.TargetSchemaName, ExportedEvent: event.ExportedEvent})
+	if err != nil {
+		return fmt.Errorf("marshal event with vsn %d: %w", event.Vsn, err)
+	}
+	_, err = s.writer.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("write to spill file %q: %w", s.filePath, err)
+	}
+	s.numEvents++
+	atomic.AddInt64(s.spilledBytes, int64(len(line)+1))
+	return nil
+}
+
+// spilledEvent is an event in the spill file, along with the fields of the event which are not exported.
+type spilledEvent struct {
+	*tgtdb.Event
+	TargetSchemaName string       `json:"target_schema_name,omitempty"`
+	ExportedEvent    *tgtdb.Event `json:"exported_event,omitempty"`
+}
+
+func (s *EventSpillover) unspill() (*tgtdb.Event, error) {
+	err := s.writer.Flush()
+	if err != nil {
+		return nil, fmt.Errorf("flush spill file %q: %w", s.filePath, err)
+	}
+	line, err := s.reader.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("read spill file %q: %w", s.filePath, err)
+	}
+	atomic.AddInt64(s.spilledBytes, -int64(len(line)))
+	spilled := spilledEvent{Event: &tgtdb.Event{}}
+	err = json.Unmarshal(line, &spilled)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal event from spill file %q: %w", s.filePath, err)
+	}
+	event := *spilled.Event
+	event.TargetSchemaName, event.ExportedEvent = spilled.TargetSchemaName, spilled.ExportedEvent
+	// the markers are recognized by their identity
+	switch event.Op {
+	case END_OF_QUEUE_SEGMENT_EVENT.Op:
+		return END_OF_QUEUE_SEGMENT_EVENT, nil
+	case CHECKPOINT_EVENT.Op:
+		return CHECKPOINT_EVENT, nil
+	}
+	return &event, nil
+}
+
+// reset empties the spill file once all the events in it are moved to the channel.
+func (s *EventSpillover) reset() error {
+	err := s.writer.Flush()
+	if err != nil {
+		return fmt.Errorf("flush spill file %q: %w", s.filePath, err)
+	}
+	err = s.file.Truncate(0)
+	if err != nil {
+		return fmt.Errorf("truncate spill file %q: %w", s.filePath, err)
+	}
+	_, err = s.file.Seek(0, 0)
+	if err != nil {
+		return fmt.Errorf("seek spill file %q: %w", s.filePath, err)
+	}
+	s.readFile.Close()
+	return s.openReader()
+}
+
+func (s *EventSpillover) openReader() error {
+	readFile, err := os.Open(s.filePath)
+	if err != nil {
+		return fmt.Errorf("open spill file %q: %w", s.filePath, err)
+	}
+	s.readFile = readFile
+	s.reader = bufio.NewReader(readFile)
+	return nil
+}
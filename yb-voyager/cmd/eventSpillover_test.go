@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+)
+
+func TestEventSpilloverRetainsUnexportedFields(t *testing.T) {
+	assert := assert.New(t)
+	var spilledBytes int64
+	s := &EventSpillover{filePath: filepath.Join(t.TempDir(), "channel_0.ndjson"), spilledBytes: &spilledBytes}
+	value := "1"
+	event := &tgtdb.Event{Vsn: 7, Op: "c", SchemaName: "sales", TableName: "orders", Fields: map[string]*string{"id": &value}}
+	event.ExportedEvent = event.Clone()
+	event.TargetSchemaName = "sales_v2"
+	assert.NoError(s.spill(event))
+	assert.NoError(s.spill(CHECKPOINT_EVENT))
+
+	unspilled, err := s.unspill()
+	assert.NoError(err)
+	assert.Equal(event, unspilled)
+	unspilled, err = s.unspill()
+	assert.NoError(err)
+	assert.Same(CHECKPOINT_EVENT, unspilled)
+}
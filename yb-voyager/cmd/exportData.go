@@ -484,15 +484,16 @@ func checkAndHandleSnapshotComplete(status *dbzm.ExportStatus, progressTracker *
 	}
 	progressTracker.Done(status)
 	createExportDataDoneFlag()
-	err := writeDataFileDescriptor(exportDir, status)
+	// The files are renamed first, as the checksums in the data file descriptor are computed from the renamed files.
+	err := renameDbzmExportedDataFiles()
 	if err != nil {
-		return false, fmt.Errorf("failed to write data file descriptor: %w", err)
+		return false, fmt.Errorf("failed to rename dbzm exported data files: %v", err)
 	}
-	log.Infof("snapshot export is complete.")
-	err = renameDbzmExportedDataFiles()
+	err = writeDataFileDescriptor(exportDir, status)
 	if err != nil {
-		return false, fmt.Errorf("failed to rename dbzm exported data files: %v", err)
+		return false, fmt.Errorf("failed to write data file descriptor: %w", err)
 	}
+	log.Infof("snapshot export is complete.")
 	if changeStreamingIsEnabled(exportType) {
 		color.Blue("streaming changes to a local queue file...")
 		if !disablePb {
@@ -532,20 +533,27 @@ func writeDataFileDescriptor(exportDir string, status *dbzm.ExportStatus) error
 		if source.DBType == POSTGRESQL && table.SchemaName != "public" {
 			tableName = fmt.Sprintf("%s.%s", table.SchemaName, tableName)
 		}
+		filePath := fmt.Sprintf("%s_data.sql", tableName)
+		checksum, err := datafile.ComputeChecksum(filepath.Join(exportDir, "data", filePath))
+		if err != nil {
+			return fmt.Errorf("compute checksum of the exported data file: %w", err)
+		}
 		fileEntry := &datafile.FileEntry{
 			TableName: tableName,
-			FilePath:  fmt.Sprintf("%s_data.sql", tableName),
+			FilePath:  filePath,
 			RowCount:  table.ExportedRowCountSnapshot,
 			FileSize:  -1, // Not available.
+			Checksum:  checksum,
 		}
 		dataFileList = append(dataFileList, fileEntry)
 	}
 	dfd := datafile.Descriptor{
-		FileFormat:   datafile.TEXT,
-		Delimiter:    "\t",
-		HasHeader:    true,
-		ExportDir:    exportDir,
-		DataFileList: dataFileList,
+		FileFormat:        datafile.TEXT,
+		Delimiter:         "\t",
+		HasHeader:         true,
+		ExportDir:         exportDir,
+		DataFileList:      dataFileList,
+		ChecksumAlgorithm: datafile.CHECKSUM_ALGORITHM_SHA256,
 	}
 	dfd.Save()
 	return nil
@@ -623,7 +631,10 @@ func checkDataDirs() {
 		os.Remove(flagFilePath)
 		os.Remove(dfdFilePath)
 		os.Remove(propertiesFilePath)
-		truncateTablesInMetaDb(exportDir, []string{QUEUE_SEGMENT_META_TABLE_NAME, EXPORTED_EVENTS_STATS_TABLE_NAME, EXPORTED_EVENTS_STATS_PER_TABLE_TABLE_NAME})
+		// QUEUE_SEGMENT_RESUME_OFFSET_TABLE_NAME is the last, as it is missing in the meta dbs of the earlier versions
+		// until the changes are imported.
+		truncateTablesInMetaDb(exportDir, []string{QUEUE_SEGMENT_META_TABLE_NAME, EXPORTED_EVENTS_STATS_TABLE_NAME, EXPORTED_EVENTS_STATS_PER_TABLE_TABLE_NAME,
+			QUEUE_SEGMENT_RESUME_OFFSET_TABLE_NAME})
 	} else {
 		if !utils.IsDirectoryEmpty(exportDataDir) {
 			if (changeStreamingIsEnabled(exportType)) &&
@@ -15,12 +15,23 @@ limitations under the License.
 */
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
 
 var fallForwardCmd = &cobra.Command{
 	Use:   "fall-forward",
 	Short: "fall-forward is used to setup and synchronize the fall forward database",
-	Long:  `Fall-forward has three commands: setup, synchronize and switchover.`,
+	Long: `Fall-forward has four commands: setup, export-changes, synchronize and switchover.
+setup imports the snapshot into the fall forward database and starts applying the changes exported from YugabyteDB
+by export-changes to it, synchronize resumes applying the changes, and switchover switches over to the fall forward database.
+Note that synchronize used to export the changes from YugabyteDB, which is now done by export-changes.
+setup can run in parallel with 'import data' on the same export-dir; each of them uses its own --parallel-jobs, and splits
+the data files on its own, as the values are converted for its database.`,
 }
 
 func init() {
@@ -36,3 +47,12 @@ func hideFlagsInFallFowardCmds(cmd *cobra.Command) {
 		}
 	}
 }
+
+// getFallForwardDBType returns the type of the fall forward database, which is of the type of the source database.
+// The export-dir is validated later by the import flags validation, Oracle is assumed until then.
+func getFallForwardDBType() string {
+	if exportDir != "" && utils.FileOrFolderExists(filepath.Join(exportDir, "metainfo")) && ExtractMetaInfo(exportDir).SourceDBType == MYSQL {
+		return MYSQL
+	}
+	return ORACLE
+}
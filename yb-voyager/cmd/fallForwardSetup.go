@@ -24,7 +24,7 @@ var fallForwardSetupCmd = &cobra.Command{
 
 	Run: func(cmd *cobra.Command, args []string) {
 		importType = SNAPSHOT_AND_CHANGES
-		tconf.TargetDBType = ORACLE
+		tconf.TargetDBType = getFallForwardDBType()
 		importDataCmd.PreRun(cmd, args)
 		importDataCmd.Run(cmd, args)
 	},
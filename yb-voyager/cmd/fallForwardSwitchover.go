@@ -16,21 +16,246 @@ limitations under the License.
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/dbzm"
+	reporter "github.com/yugabyte/yb-voyager/yb-voyager/src/reporter/stats"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+const (
+	FF_SWITCHOVER_REQUESTED_FLAG = "fallForwardSwitchoverRequested"
+	FF_STREAMING_STOPPED_FLAG    = "fallForwardStreamingStopped"
+
+	// allowed lag of the switchover request with --force, the streaming stops regardless of the remaining events
+	SWITCHOVER_ANY_LAG = -1
 )
 
+var maxSwitchoverLagEvents int64
+var forceSwitchover bool
+var verifySwitchover bool
+var switchoverWaitTimeout time.Duration
+
 var fallForwardSwitchoverCmd = &cobra.Command{
 	Use:   "switchover",
-	Short: "fall-forward switchover help",
-	Long:  `fall-forward switchover help`,
+	Short: "This command switches over to the fall forward database.",
+	Long: `This command checks that the fall forward database has caught up with the changes exported from YugabyteDB,
+waits for 'fall-forward synchronize' to apply the remaining changes and stop, and restores the sequences in the fall forward database.`,
 
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("fall-forward switchover")
+	PreRun: func(cmd *cobra.Command, args []string) {
+		tconf.TargetDBType = getFallForwardDBType()
+		validateImportFlags(cmd)
+		if maxSwitchoverLagEvents < 0 {
+			utils.ErrExit("Error: Invalid max-lag %d. It must be a non-negative number of events", maxSwitchoverLagEvents)
+		}
+		if switchoverWaitTimeout <= 0 {
+			utils.ErrExit("Error: Invalid wait-timeout %s. It must be positive", switchoverWaitTimeout)
+		}
+		validateCutoverChecksFlag()
 	},
+	Run: fallForwardSwitchoverCommandFn,
+}
+
+func fallForwardSwitchoverCommandFn(cmd *cobra.Command, args []string) {
+	err := retrieveMigrationUUID(exportDir)
+	if err != nil {
+		utils.ErrExit("failed to get migration UUID: %s", err)
+	}
+	tconf.Schema = strings.ToLower(tconf.Schema)
+	tconf.Parallelism = 1
+	tdb = tgtdb.NewTargetDB(&tconf)
+	err = tdb.Init()
+	if err != nil {
+		utils.ErrExit("Failed to initialize the fall forward DB: %s", err)
+	}
+	defer tdb.Finalize()
+	err = tdb.InitConnPool()
+	if err != nil {
+		utils.ErrExit("Failed to initialize the fall forward DB connection pool: %s", err)
+	}
+	metaDB, err = NewMetaDB(exportDir)
+	if err != nil {
+		utils.ErrExit("Failed to initialize meta db: %s", err)
+	}
+	switchedOverAt, done, err := metaDB.GetMigrationStatus(FF_SWITCHOVER_DONE_KEY)
+	if err != nil {
+		utils.ErrExit("failed to get the switchover status from meta db: %s", err)
+	}
+	if done {
+		utils.PrintAndLog("Switchover to the fall forward database is already done at %s.", switchedOverAt)
+		return
+	}
+
+	allowedLag := maxSwitchoverLagEvents
+	remainingEvents := getFallForwardRemainingEvents()
+	if remainingEvents > maxSwitchoverLagEvents {
+		if !forceSwitchover {
+			utils.ErrExit("The fall forward database is lagging behind by %d events (max allowed: %d). "+
+				"Wait for 'fall-forward synchronize' to catch up, or use --force to switch over anyway.", remainingEvents, maxSwitchoverLagEvents)
+		}
+		utils.PrintAndLog("WARNING: The fall forward database is lagging behind by %d events. Proceeding because of --force.", remainingEvents)
+		allowedLag = SWITCHOVER_ANY_LAG
+	}
+
+	requestFallForwardSwitchover(allowedLag)
+	utils.PrintAndLog("Waiting for 'fall-forward synchronize' to apply the remaining changes and stop...")
+	err = waitForFallForwardStreamingStop(switchoverWaitTimeout)
+	if err != nil {
+		removeFallForwardFlag(FF_SWITCHOVER_REQUESTED_FLAG)
+		utils.ErrExit("%s. Check that 'fall-forward synchronize' is running, and re-run the command.", err)
+	}
+
+	if verifySwitchover {
+		remainingEvents = getFallForwardRemainingEvents()
+		if allowedLag != SWITCHOVER_ANY_LAG && remainingEvents > allowedLag {
+			utils.ErrExit("Verification failed: %d events exported from YugabyteDB are not applied to the fall forward database (max allowed: %d).",
+				remainingEvents, allowedLag)
+		}
+		if remainingEvents > 0 {
+			utils.PrintAndLog("WARNING: %d events exported from YugabyteDB are not applied to the fall forward database.", remainingEvents)
+		} else {
+			utils.PrintAndLog("Verified that all the exported events are applied to the fall forward database.")
+		}
+	}
+
+	status, err := dbzm.ReadExportStatus(filepath.Join(exportDir, "data", "export_status.json"))
+	if err != nil {
+		utils.ErrExit("failed to read export status for restore sequences: %s", err)
+	}
+	restoreSequences(status.Sequences)
+
+	err = metaDB.SetMigrationStatus(FF_SWITCHOVER_DONE_KEY, time.Now().Format(time.RFC3339))
+	if err != nil {
+		utils.ErrExit("failed to mark the migration as switched over in meta db: %s", err)
+	}
+	utils.PrintAndLog("Switchover to the fall forward database is complete.")
+	if generateCutoverReport {
+		writeCutoverReport(status.Sequences)
+	}
+}
+
+// checkFallForwardSwitchoverNotDone refuses to import into the fall forward database once the migration is switched over
+// to it, as it is then the database of the applications.
+func checkFallForwardSwitchoverNotDone() {
+	switchedOverAt, done, err := metaDB.GetMigrationStatus(FF_SWITCHOVER_DONE_KEY)
+	if err != nil {
+		utils.ErrExit("failed to get the switchover status from meta db: %s", err)
+	}
+	if done {
+		utils.ErrExit("The migration is switched over to the fall forward database at %s. "+
+			"Changes can no longer be imported into it.", switchedOverAt)
+	}
+}
+
+// getFallForwardRemainingEvents returns the number of events exported from YugabyteDB which are not yet
+// applied to the fall forward database, as computed by the stats reporter of 'fall-forward synchronize'.
+func getFallForwardRemainingEvents() int64 {
+	statsReporter := reporter.NewStreamImportStatsReporter()
+	err := statsReporter.Init(tdb, migrationUUID)
+	if err != nil {
+		utils.ErrExit("failed to fetch the events applied to the fall forward database. Is 'fall-forward setup' done? %s", err)
+	}
+	totalExportedEvents, _, err := metaDB.GetTotalExportedEvents("")
+	if err != nil {
+		utils.ErrExit("failed to fetch the exported events stats from meta db: %s", err)
+	}
+	statsReporter.UpdateRemainingEvents(totalExportedEvents)
+	remainingEvents, _ := statsReporter.GetRemainingEvents()
+	return remainingEvents
+}
+
+// waitForFallForwardStreamingStop waits for 'fall-forward synchronize' to stop streaming for the switchover.
+func waitForFallForwardStreamingStop(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for !fallForwardFlagExists(FF_STREAMING_STOPPED_FLAG) {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("'fall-forward synchronize' did not stop within %s", timeout)
+		}
+		time.Sleep(5 * time.Second)
+	}
+	return nil
+}
+
+// requestFallForwardSwitchover requests 'fall-forward synchronize' to stop once the remaining events are within
+// the allowed lag, or right away for SWITCHOVER_ANY_LAG. The streaming stopped flag of an earlier request (e.g. one
+// which timed out) is removed first, so that only the stop for this request is waited for.
+func requestFallForwardSwitchover(allowedLag int64) {
+	removeFallForwardFlag(FF_STREAMING_STOPPED_FLAG)
+	err := os.WriteFile(getFallForwardFlagPath(FF_SWITCHOVER_REQUESTED_FLAG), []byte(strconv.FormatInt(allowedLag, 10)), 0644)
+	if err != nil {
+		utils.ErrExit("creating %s flag: %v", FF_SWITCHOVER_REQUESTED_FLAG, err)
+	}
+}
+
+// getFallForwardSwitchoverRequest returns whether the switchover is requested, along with the allowed lag of the request.
+func getFallForwardSwitchoverRequest() (bool, int64, error) {
+	data, err := os.ReadFile(getFallForwardFlagPath(FF_SWITCHOVER_REQUESTED_FLAG))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, 0, nil
+	}
+	if err != nil {
+		return false, 0, fmt.Errorf("read %s flag: %w", FF_SWITCHOVER_REQUESTED_FLAG, err)
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return true, 0, nil // requested by an older version, all the events are to be applied
+	}
+	allowedLag, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return false, 0, fmt.Errorf("parse %s flag: %w", FF_SWITCHOVER_REQUESTED_FLAG, err)
+	}
+	return true, allowedLag, nil
+}
+
+func getFallForwardFlagPath(flagName string) string {
+	return filepath.Join(exportDir, "metainfo", "flags", flagName)
+}
+
+func createFallForwardFlag(flagName string) {
+	_, err := os.Create(getFallForwardFlagPath(flagName))
+	if err != nil {
+		utils.ErrExit("creating %s flag: %v", flagName, err)
+	}
+}
+
+func removeFallForwardFlag(flagName string) {
+	err := os.Remove(getFallForwardFlagPath(flagName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		utils.ErrExit("removing %s flag: %v", flagName, err)
+	}
+}
+
+func fallForwardFlagExists(flagName string) bool {
+	return utils.FileOrFolderExists(getFallForwardFlagPath(flagName))
 }
 
 func init() {
 	fallForwardCmd.AddCommand(fallForwardSwitchoverCmd)
+	registerCommonGlobalFlags(fallForwardSwitchoverCmd)
+	registerCommonImportFlags(fallForwardSwitchoverCmd)
+	hideFlagsInFallFowardCmds(fallForwardSwitchoverCmd)
+
+	fallForwardSwitchoverCmd.Flags().Int64Var(&maxSwitchoverLagEvents, "max-lag", 0,
+		"maximum number of events that the fall forward database can lag behind YugabyteDB for the switchover to proceed. "+
+			"'fall-forward synchronize' stops once it is within as many events. By default, all the exported events must be applied")
+	fallForwardSwitchoverCmd.Flags().BoolVar(&forceSwitchover, "force", false,
+		"switch over even if the fall forward database is lagging behind by more than --max-lag events. "+
+			"'fall-forward synchronize' stops after applying the changes read so far, without waiting for the rest")
+	fallForwardSwitchoverCmd.Flags().DurationVar(&switchoverWaitTimeout, "wait-timeout", time.Hour,
+		"maximum time to wait for 'fall-forward synchronize' to stop, after which the switchover is abandoned")
+	fallForwardSwitchoverCmd.Flags().BoolVar(&verifySwitchover, "verify", false,
+		"verify that all the events exported from YugabyteDB are applied to the fall forward database before restoring the sequences")
+	fallForwardSwitchoverCmd.Flags().BoolVar(&generateCutoverReport, "cutover-report", false,
+		"verify the fall forward database after the switchover and write the results to <export-dir>/reports/cutover_report.json, "+
+			"along with a summary in cutover_report.txt")
+	fallForwardSwitchoverCmd.Flags().StringSliceVar(&cutoverChecks, "cutover-report-checks", validCutoverChecks,
+		fmt.Sprintf("comma separated list of the checks to run for --cutover-report: %s", strings.Join(validCutoverChecks, ", ")))
 }
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFallForwardSwitchoverRequest(t *testing.T) {
+	assert := assert.New(t)
+	savedExportDir := exportDir
+	exportDir = t.TempDir()
+	defer func() { exportDir = savedExportDir }()
+	assert.NoError(os.MkdirAll(filepath.Join(exportDir, "metainfo", "flags"), 0755))
+
+	requested, _, err := getFallForwardSwitchoverRequest()
+	assert.NoError(err)
+	assert.False(requested)
+
+	for _, lag := range []int64{0, 25, SWITCHOVER_ANY_LAG} {
+		requestFallForwardSwitchover(lag)
+		requested, allowedLag, err := getFallForwardSwitchoverRequest()
+		assert.NoError(err)
+		assert.True(requested)
+		assert.Equal(lag, allowedLag)
+	}
+
+	// requested by an older version
+	createFallForwardFlag(FF_SWITCHOVER_REQUESTED_FLAG)
+	requested, allowedLag, err := getFallForwardSwitchoverRequest()
+	assert.NoError(err)
+	assert.True(requested)
+	assert.Equal(int64(0), allowedLag)
+
+	removeFallForwardFlag(FF_SWITCHOVER_REQUESTED_FLAG)
+	requested, _, err = getFallForwardSwitchoverRequest()
+	assert.NoError(err)
+	assert.False(requested)
+}
+
+func TestFallForwardSwitchoverRetryAfterTimeout(t *testing.T) {
+	assert := assert.New(t)
+	savedExportDir := exportDir
+	exportDir = t.TempDir()
+	defer func() { exportDir = savedExportDir }()
+	assert.NoError(os.MkdirAll(filepath.Join(exportDir, "metainfo", "flags"), 0755))
+
+	// the first switchover times out, and is abandoned
+	requestFallForwardSwitchover(0)
+	assert.ErrorContains(waitForFallForwardStreamingStop(time.Nanosecond), "did not stop within")
+	removeFallForwardFlag(FF_SWITCHOVER_REQUESTED_FLAG)
+	// 'fall-forward synchronize' stops for it after all
+	createFallForwardFlag(FF_STREAMING_STOPPED_FLAG)
+
+	// the retry waits for the streaming to stop again, rather than for the stale flag
+	requestFallForwardSwitchover(0)
+	assert.False(fallForwardFlagExists(FF_STREAMING_STOPPED_FLAG))
+	assert.ErrorContains(waitForFallForwardStreamingStop(time.Nanosecond), "did not stop within")
+	createFallForwardFlag(FF_STREAMING_STOPPED_FLAG)
+	assert.NoError(waitForFallForwardStreamingStop(time.Nanosecond))
+}
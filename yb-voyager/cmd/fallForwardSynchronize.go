@@ -15,25 +15,36 @@ limitations under the License.
 */
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
 
 var fallForwardSynchronizeCmd = &cobra.Command{
 	Use:   "synchronize",
-	Short: "This command exports the changes from YugabyteDB.",
-	Long:  `This command connects to YugabyteDB and exports the changes received by it so that they can be imported into the fall forward database.`,
+	Short: "This command applies the changes to the fall forward database.",
+	Long: `This command connects to the fall forward database and continuously applies the changes exported from YugabyteDB to it.
+It resumes from the changes already applied to the fall forward database by 'fall-forward setup' or by a previous run of this command.
+The changes are exported from YugabyteDB by 'fall-forward export-changes', which this command used to do in the earlier versions.`,
 
 	Run: func(cmd *cobra.Command, args []string) {
-		source.DBType = YUGABYTEDB
-		exportType = CHANGES_ONLY
-		exportDataCmd.PreRun(cmd, args)
-		exportDataCmd.Run(cmd, args)
+		if cmd.Flags().Changed("start-clean") {
+			// The progress of applying the changes is tracked in the fall forward database. Starting afresh requires
+			// the snapshot to be imported again, which is done by 'fall-forward setup --start-clean'.
+			utils.ErrExit("Error: --start-clean is not supported with 'fall-forward synchronize'. Use 'fall-forward setup --start-clean' instead.")
+		}
+		importType = CHANGES_ONLY
+		tconf.TargetDBType = getFallForwardDBType()
+		importDataCmd.PreRun(cmd, args)
+		importDataCmd.Run(cmd, args)
 	},
 }
 
 func init() {
 	fallForwardCmd.AddCommand(fallForwardSynchronizeCmd)
 	registerCommonGlobalFlags(fallForwardSynchronizeCmd)
-	registerCommonExportFlags(fallForwardSynchronizeCmd)
-	registerExportDataFlags(fallForwardSynchronizeCmd)
+	registerCommonImportFlags(fallForwardSynchronizeCmd)
+	registerImportDataFlags(fallForwardSynchronizeCmd)
 	hideFlagsInFallFowardCmds(fallForwardSynchronizeCmd)
 }
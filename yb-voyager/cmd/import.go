@@ -18,13 +18,16 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"syscall"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"golang.org/x/exp/slices"
 	"golang.org/x/term"
 
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/datafile"
 	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
 	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
 )
@@ -53,6 +56,10 @@ func validateImportFlags(cmd *cobra.Command) {
 	validateExportDirFlag()
 	validateTargetDBType()
 	checkOrSetDefaultTargetSSLMode()
+	err := tconf.CheckSSLFiles()
+	if err != nil {
+		utils.ErrExit("Error: %s", err)
+	}
 	validateTargetPortRange()
 	if tconf.TableList != "" && tconf.ExcludeTableList != "" {
 		utils.ErrExit("Error: Only one of --table-list and --exclude-table-list are allowed")
@@ -70,13 +77,34 @@ func validateImportFlags(cmd *cobra.Command) {
 		fmt.Println("WARNING: The --disable-transactional-writes feature is in the experimental phase, not for production use case.")
 	}
 	validateBatchSizeFlag(batchSize)
+	if throttleLatencyThresholdSec < 0 {
+		utils.ErrExit("Error: Invalid throttle-latency-threshold %d. It must be a non-negative number of seconds", throttleLatencyThresholdSec)
+	}
+	if maxTablesInParallel < 1 {
+		utils.ErrExit("Error: Invalid max-tables-in-parallel: %d. It must be at least 1", maxTablesInParallel)
+	}
+	if numConversionWorkers < 1 {
+		utils.ErrExit("Error: Invalid conversion-workers: %d. It must be at least 1", numConversionWorkers)
+	}
+	if settingsChangeGracePeriodSec < 0 {
+		utils.ErrExit("Error: Invalid settings-change-grace-period: %d. It must not be negative", settingsChangeGracePeriodSec)
+	}
+	if restartFileAfterBatchFailures < 0 {
+		utils.ErrExit("Error: Invalid restart-file-after-batch-failures: %d. It must not be negative", restartFileAfterBatchFailures)
+	}
+	if maxValueSizeBytes < 0 {
+		utils.ErrExit("Error: Invalid max-value-size-bytes: %d. It must not be negative", maxValueSizeBytes)
+	}
+	validateMaxRowsPerTableFlag()
+	validateTruncateTablesFlag()
+	validateSplitFilesDirFlag()
 	validateTargetPassword(cmd)
 
 }
 
 func registerCommonImportFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&tconf.TargetDBType, "target-db-type", "",
-		"type of the target database (oracle, yugabytedb)")
+		"type of the target database (oracle, mysql, yugabytedb)")
 
 	cmd.Flags().StringVar(&tconf.Host, "target-db-host", "127.0.0.1",
 		"host on which the YugabyteDB server is running")
@@ -103,18 +131,22 @@ func registerCommonImportFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&tconf.TNSAlias, "oracle-tns-alias", "",
 		"[For Oracle Only] Name of TNS Alias you wish to use to connect to Oracle instance. Refer to documentation to learn more about configuring tnsnames.ora and aliases")
 
+	cmd.Flags().StringVar(&tconf.ApplicationName, "application-name", "yb-voyager",
+		"base of the application_name set on the target db sessions, which is suffixed with the migration UUID and "+
+			"the phase (ddl, snapshot or streaming), e.g. yb-voyager:<migration-uuid>:snapshot")
+
 	cmd.Flags().StringVar(&tconf.Schema, "target-db-schema", "",
 		"target schema name in YugabyteDB (Note: works only for source as Oracle and MySQL, in case of PostgreSQL you can ALTER schema name post import)")
 
 	// TODO: SSL related more args might come. Need to explore SSL part completely.
 	cmd.Flags().StringVar(&tconf.SSLCertPath, "target-ssl-cert", "",
-		"provide target SSL Certificate Path")
+		"target SSL client certificate path, used along with --target-ssl-key for the client certificate authentication")
 
 	cmd.Flags().StringVar(&tconf.SSLMode, "target-ssl-mode", "prefer",
 		"specify the target SSL mode out of - disable, allow, prefer, require, verify-ca, verify-full")
 
 	cmd.Flags().StringVar(&tconf.SSLKey, "target-ssl-key", "",
-		"target SSL Key Path")
+		"target SSL client key path")
 
 	cmd.Flags().StringVar(&tconf.SSLRootCert, "target-ssl-root-cert", "",
 		"target SSL Root Certificate Path")
@@ -136,11 +168,94 @@ func registerImportDataFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&disablePb, "disable-pb", false,
 		"true - to disable progress bar during data import (default false)")
 	cmd.Flags().StringVar(&tconf.ExcludeTableList, "exclude-table-list", "",
-		"list of tables to exclude while importing data (ignored if --table-list is used)")
+		"list of tables to exclude while importing data (ignored if --table-list is used). "+
+			"The entries can be glob patterns (e.g. sales_*) or regular expressions enclosed in slashes (e.g. /^audit_.*$/)")
 	cmd.Flags().StringVar(&tconf.TableList, "table-list", "",
-		"list of tables to import data")
+		"list of tables to import data. "+
+			"The entries can be glob patterns (e.g. sales_*) or regular expressions enclosed in slashes (e.g. /^audit_.*$/)")
+	cmd.Flags().BoolVar(&strictTypeCheck, "strict-type-check", false,
+		"(YugabyteDB only) reject the rows with values which the target would silently coerce to the column type, "+
+			"i.e. numeric values with more digits than the precision/scale of the column and timestamps with more "+
+			"fractional seconds digits than the column precision. With import data, also abort the import if any "+
+			"of the exported columns has a data type whose values may not convert cleanly (no value converter, or known "+
+			"to need manual handling, e.g. Oracle RAW or MySQL SET); such columns are reported before the import in any case. "+
+			"The data types are checked only for the data exported with debezium; "+
+			"the data exported with ora2pg or pg_dump is reported as not checked")
+	cmd.Flags().StringVar(&progressOutput, "progress-output", PROGRESS_OUTPUT_BAR,
+		fmt.Sprintf("format of the progress of the import: %s (progress bars) or %s (progress bars, and a JSON document "+
+			"with the progress of each table written to --progress-file every %s and when a table is imported)",
+			PROGRESS_OUTPUT_BAR, PROGRESS_OUTPUT_JSON, PROGRESS_FILE_UPDATE_INTERVAL))
+	cmd.Flags().StringVar(&progressFilePath, "progress-file", "",
+		"path of the file to write the progress to with --progress-output json. An existing file is replaced")
+	cmd.Flags().IntVar(&maxTablesInParallel, "max-tables-in-parallel", 1,
+		"maximum number of tables (data files) imported concurrently, e.g. for schemas with many small tables. "+
+			"The batches of all of them share the --parallel-jobs connections to the target db")
+	cmd.Flags().StringToIntVar(&tableParallelism, "table-parallelism", nil,
+		"maximum number of batches of the given tables imported at a time, e.g. --table-parallelism orders=16,countries=1. "+
+			"It is capped at --parallel-jobs, the number of connections to the target db shared by all the tables")
+	cmd.Flags().IntVar(&numConversionWorkers, "conversion-workers", 1,
+		"number of workers converting the lines of a data file (value conversion, --line-transformer and the value checks) "+
+			"in parallel while the file is split into batches. The order of the lines is preserved")
+	cmd.Flags().IntVar(&settingsChangeGracePeriodSec, "settings-change-grace-period", 10,
+		"seconds to wait (to allow aborting) before resuming the import with settings, like the target schema or the table lists, "+
+			"changed since the last run, when the prompts are disabled with --yes. "+
+			"Without --yes, the import asks for a confirmation, or aborts if the input is not a terminal")
+	cmd.Flags().IntVar(&restartFileAfterBatchFailures, "restart-file-after-batch-failures", 0,
+		"number of batches of a data file failing (after their retries) for the file to be imported again from the beginning, once, "+
+			"before giving up. The batches already imported are skipped. (0 to abort on the first failed batch)")
+	cmd.Flags().Int64Var(&maxValueSizeBytes, "max-value-size-bytes", 0,
+		"maximum size (in bytes) of a single value of a row. Rows with a larger value are rejected instead of "+
+			"failing the COPY of their batch. The rows are still read into memory whole, and a batch holds up to "+
+			"--batch-size of them, hence lower --batch-size for data with very large values. (0 to disable)")
+	cmd.Flags().StringVar(&otelEndpoint, "otel-endpoint", "",
+		"OTLP/HTTP endpoint (e.g. http://localhost:4318) to export the OpenTelemetry traces of the import to, "+
+			"with a span per table and per batch. Tracing is disabled if not set")
+	cmd.Flags().StringVar(&importOrder, "import-order", IMPORT_ORDER_INPROGRESS_FIRST,
+		fmt.Sprintf("order in which the pending tables are imported: %s, %s, %s, %s. "+
+			"The partially imported tables are always resumed first; %s and %s keep the order of the data file descriptor, "+
+			"the others order the tables by their size (row count, or file size for import data file)",
+			IMPORT_ORDER_INPROGRESS_FIRST, IMPORT_ORDER_LARGEST_FIRST, IMPORT_ORDER_SMALLEST_FIRST, IMPORT_ORDER_DESCRIPTOR,
+			IMPORT_ORDER_INPROGRESS_FIRST, IMPORT_ORDER_DESCRIPTOR))
+	cmd.Flags().StringVar(&tableImportOrder, "table-import-order", "",
+		"comma separated list of tables (or path to a file with one table per line) to import first, in the given order, "+
+			"e.g. to import the tables referenced by foreign keys before the tables referencing them. "+
+			"The other tables are imported after them in their natural order. "+
+			"Set --max-tables-in-parallel to 1 to start the import of a table only after the previous ones are done")
+	cmd.Flags().BoolVar(&reorderByDependencies, "reorder-by-dependencies", false,
+		"import the tables referenced by foreign keys before the tables referencing them, "+
+			"as per the foreign keys in the exported schema")
+	cmd.Flags().IntVar(&copyMaxRetries, "copy-max-retries", COPY_MAX_RETRY_COUNT,
+		"maximum number of retries of a batch, after its first attempt, when the COPY fails with a retryable error. "+
+			"Set to 0 to not retry the batches")
+	cmd.Flags().StringVar(&copyRetryBackoff, "copy-retry-backoff", COPY_BACKOFF_LINEAR,
+		fmt.Sprintf("backoff between the attempts to import a batch: %s (sleep %d seconds longer after every attempt) or "+
+			"%s (double the sleep after every attempt), capped at %d seconds",
+			COPY_BACKOFF_LINEAR, COPY_RETRY_SLEEP_STEP_SECOND, COPY_BACKOFF_EXPONENTIAL, MAX_SLEEP_SECOND))
+	cmd.Flags().StringVar(&onPrimaryKeyConflict, "on-primary-key-conflict", tgtdb.ON_PRIMARY_KEY_CONFLICT_ERROR,
+		fmt.Sprintf("(YugabyteDB only) action on the rows of the snapshot conflicting with the existing rows of the table: "+
+			"%s (fail the import), %s (keep the existing rows) or %s (overwrite the existing rows). "+
+			"With %s and %s, each batch is COPYed into a temporary table and then moved into the table with "+
+			"INSERT ... ON CONFLICT, which is considerably slower than the COPY into the table. "+
+			"Only the conflicts on the primary key are handled, the violations of the other unique constraints fail the import",
+			tgtdb.ON_PRIMARY_KEY_CONFLICT_ERROR, tgtdb.ON_PRIMARY_KEY_CONFLICT_SKIP, tgtdb.ON_PRIMARY_KEY_CONFLICT_UPDATE,
+			tgtdb.ON_PRIMARY_KEY_CONFLICT_SKIP, tgtdb.ON_PRIMARY_KEY_CONFLICT_UPDATE))
+	cmd.Flags().BoolVar(&skipMissingTables, "skip-missing-tables", false,
+		"skip the tables which don't exist on the target instead of failing their import. "+
+			"The skipped tables are listed at the end of the import")
 	cmd.Flags().Int64Var(&batchSize, "batch-size", -1,
-		"maximum number of rows in each batch generated during import.")
+		"maximum number of rows in each batch generated during import. "+
+			"With --batch-size-bytes, a batch is cut at whichever of the two limits is reached first")
+	cmd.Flags().Int64Var(&batchSizeBytes, "batch-size-bytes", 0,
+		"maximum size (in bytes) of the data of each batch generated during import, for tables with wide rows. "+
+			"The rows are still limited by --batch-size (or its default), and a batch is cut at whichever of the two "+
+			"limits is reached first. It is capped at the maximum batch size in bytes for the target db (0 to use that maximum)")
+	cmd.Flags().IntVar(&throttleLatencyThresholdSec, "throttle-latency-threshold", 0,
+		"batch import latency (in seconds) above which the target db is considered to be under pressure. "+
+			"The number of batches imported in parallel is then reduced, and increased back as the latency drops. (0 to disable)")
+	cmd.Flags().Int64Var(&minBatchSize, "min-batch-size", 0,
+		"minimum number of rows in the last batch of a data file. If filling up a batch would leave a smaller last batch, "+
+			"the batch is cut early and the rest of the file makes the last batch, as long as it fits in a batch. "+
+			"The batches never exceed --batch-size and --batch-size-bytes. (0 to disable)")
 	cmd.Flags().IntVar(&tconf.Parallelism, "parallel-jobs", -1,
 		"number of parallel copy command jobs to target database. "+
 			"By default, voyager will try if it can determine the total number of cores N and use N/2 as parallel jobs. "+
@@ -167,10 +282,168 @@ func registerImportDataFlags(cmd *cobra.Command) {
 		"true - to truncate splits after importing\n"+
 			"false - to not truncate splits after importing (required for debugging)")
 	cmd.Flags().MarkHidden("truncate-splits")
+	cmd.Flags().BoolVar(&keepSplitFiles, "keep-split-files", false,
+		"retain the batch files (splits) of the data files after importing them, e.g. for auditing or reproducing an issue, "+
+			"instead of truncating them. The batches cleaned by --start-clean are moved to the archived_splits dir next to them "+
+			"instead of being deleted. The batch files take as much disk space as the data files imported, "+
+			"and more with every --start-clean, hence consider --split-files-dir")
+	cmd.Flags().StringVar(&splitFilesDir, "split-files-dir", "",
+		"directory to write the batch files (splits) to, e.g. on a volume other than that of the export-dir. "+
+			"A resumed import must use the same directory (default: the import data state in the export-dir)")
 
 	cmd.Flags().StringVar(&importType, "import-type", SNAPSHOT_ONLY,
 		fmt.Sprintf("import type: %s, %s, %s", SNAPSHOT_ONLY, CHANGES_ONLY, SNAPSHOT_AND_CHANGES))
 
+	cmd.Flags().StringVar(&vsnGapDetectionMode, "vsn-gap-detection", VSN_GAP_DETECTION_DISABLED,
+		fmt.Sprintf("action to take when a gap in the sequence of streamed events is detected: %s, %s, %s",
+			VSN_GAP_DETECTION_DISABLED, VSN_GAP_DETECTION_WARN, VSN_GAP_DETECTION_ABORT))
+
+	cmd.Flags().IntVar(&NUM_EVENT_CHANNELS, "num-event-channels", NUM_EVENT_CHANNELS,
+		"number of channels (each applied to the target on its own connection) across which the streamed events are distributed "+
+			"by their primary key. Must not be changed on resuming the import (overrides the NUM_EVENT_CHANNELS env var)")
+	cmd.Flags().IntVar(&EVENT_CHANNEL_SIZE, "event-channel-size", EVENT_CHANNEL_SIZE,
+		"number of streamed events buffered in each event channel. Must be at least --max-events-per-batch "+
+			"(overrides the EVENT_CHANNEL_SIZE env var)")
+	cmd.Flags().IntVar(&MAX_EVENTS_PER_BATCH, "max-events-per-batch", MAX_EVENTS_PER_BATCH,
+		"maximum number of streamed events applied to the target in one batch (overrides the MAX_EVENTS_PER_BATCH env var)")
+
+	cmd.Flags().IntVar(&maxEventsPerSecond, "max-events-per-second", 0,
+		"maximum number of streamed events applied to the target per second, across all the event channels, "+
+			"e.g. to leave room for the other traffic on the target. 0 for no limit")
+
+	cmd.Flags().IntVar(&EVENT_BATCH_MAX_RETRY_COUNT, "max-retries-per-event-batch", EVENT_BATCH_MAX_RETRY_COUNT,
+		"number of retries, with an exponential backoff, of a batch of streamed events failed with a deadlock or a serialization "+
+			"failure (or due to its data with --dead-letter-dir) (overrides the EVENT_BATCH_MAX_RETRY_COUNT env var)")
+	cmd.Flags().StringVar(&deadLetterDir, "dead-letter-dir", "",
+		"directory to write a batch of streamed events which still fails due to its data (a data exception or a constraint "+
+			"violation on YugabyteDB) after --max-retries-per-event-batch, or an event whose values fail to convert, to, as a JSON "+
+			"file of the exported events to reprocess later, and continue with the next events instead of aborting. The other "+
+			"errors, e.g. of the connection to the target, still abort the streaming. The later events on the same rows can "+
+			"fail or apply differently while the dead-lettered events are not reprocessed")
+
+	cmd.Flags().DurationVar(&MAX_INTERVAL_BETWEEN_BATCHES, "max-interval-between-batches", MAX_INTERVAL_BETWEEN_BATCHES,
+		fmt.Sprintf("maximum time to wait for more events before applying a batch of streamed events (e.g. 500ms, 2s). "+
+			"Must be between %s and %s", MIN_ALLOWED_INTERVAL_BETWEEN_BATCHES, MAX_ALLOWED_INTERVAL_BETWEEN_BATCHES))
+
+	cmd.Flags().DurationVar(&streamingCheckpointInterval, "streaming-checkpoint-interval", 0,
+		"interval at which the progress of streaming changes is checkpointed within a queue segment (e.g. 1m), "+
+			"so that a restart skips the events up to the checkpoint. 0 disables the checkpoints")
+
+	cmd.Flags().StringVar(&unknownTablePolicy, "on-unknown-table", UNKNOWN_TABLE_ERROR,
+		fmt.Sprintf("action to take on the streamed events of a table which is not part of the migration: "+
+			"%s (skip its events), %s (abort), %s (create the table from the exported schema, YugabyteDB only)",
+			UNKNOWN_TABLE_SKIP, UNKNOWN_TABLE_ERROR, UNKNOWN_TABLE_AUTO_CREATE))
+
+	cmd.Flags().StringVar(&minTargetDBVersion, "min-target-db-version", "",
+		"lowest version of the target db to import into (e.g. 2.18 for YugabyteDB, 19 for Oracle). "+
+			"For YugabyteDB, it is the YB version, not the PG version")
+	cmd.Flags().StringVar(&maxTargetDBVersion, "max-target-db-version", "",
+		"highest version of the target db to import into; compared up to the given components, i.e. 2.18 allows 2.18.x")
+	cmd.Flags().BoolVar(&skipTargetDBVersionCheck, "skip-target-db-version-check", false,
+		"only warn, rather than abort, when the target db version is outside --min-target-db-version and --max-target-db-version")
+
+	cmd.Flags().IntVar(&postDataParallelism, "post-data-parallelism", 4,
+		"number of connections on which the post data statements (setting the resume values of the sequences) are run in parallel")
+
+	cmd.Flags().BoolVar(&noSplitFiles, "no-split-files", false,
+		"(YugabyteDB only) stream the batches to COPY from memory instead of writing them as split files to the export-dir, "+
+			"to save disk space. An interrupted import then resumes each file from the offset up to which all its batches are "+
+			"imported, re-reading the rest of the file; it must be resumed with --no-split-files and the same --batch-size")
+
+	cmd.Flags().StringSliceVar(&columnMapSpecs, "column-map", nil,
+		"comma separated (or repeated) mappings of the exported columns of the snapshot data files and the streamed changes "+
+			"to the target columns, each either <table>.<source_col>:<target_col> to import the column into a differently named column, "+
+			"or <table>.<source_col>:- to not import it (the key columns of the streamed changes can't be dropped). "+
+			"The other columns are imported into the columns of the same name")
+
+	cmd.Flags().StringSliceVar(&customTypeConverterSpecs, "custom-type-converters", nil,
+		"comma separated (or repeated) <type>:<converter_type> entries to convert the values of a custom type exported by debezium "+
+			"(e.g. custom.Wkb) as those of a type known to voyager (e.g. io.debezium.data.geometry.Geometry), in the snapshot "+
+			"and in the streamed changes. A known type can be given as well, to override its converter")
+
+	cmd.Flags().StringSliceVar(&redactColumnSpecs, "redact-columns", nil,
+		"comma separated (or repeated) <table>.<column> entries of the exported columns whose values are not imported, "+
+			"e.g. for PII. Their non-null values are replaced by --redact-placeholder, or by NULL if it is not set, "+
+			"in the snapshot and in the streamed changes. The key columns of the streamed changes are not redacted")
+	cmd.Flags().StringVar(&redactPlaceholder, "redact-placeholder", "",
+		"value imported in place of the non-null values of the columns in --redact-columns (default NULL)")
+
+	cmd.Flags().StringSliceVar(&copyOptionSpecs, "copy-options", nil,
+		"comma separated (or repeated) <key>=<value> options appended to the WITH clause of the COPY command importing "+
+			"the batches into YugabyteDB, e.g. FREEZE=true. Only the options FREEZE, DISABLE_FK_CHECK (boolean) "+
+			"and ENCODING (name) are allowed. The options are not checked beyond that, "+
+			"those not supported by the server (or in the context of the import) fail the COPY")
+
+	cmd.Flags().StringArrayVar(&lineTransformerSpecs, "line-transformer", nil,
+		"transformer applied to each data line before its values are converted, either a regex replacement "+
+			"s/<regex>/<replacement>/ (any character after `s` can be the delimiter) or the name of a registered transformer. "+
+			"Can be repeated; the transformers are applied in the given order, to the data lines only (not the CSV header)")
+
+	cmd.Flags().BoolVar(&showOverallProgress, "show-overall-progress", false,
+		"display the overall progress of the migration combining the snapshot and the streaming phases, "+
+			"and write it to <export-dir>/metainfo/overall_progress_<destination>.json. "+
+			"For snapshot-and-changes, each phase weighs 50% (override with the OVERALL_PROGRESS_SNAPSHOT_WEIGHT env var)")
+
+	cmd.Flags().Int64Var(&eventSpillMaxSizeMB, "event-spill-max-size-mb", 0,
+		"maximum total size (in MB) of the streamed events spilled to disk when the event channels are full, "+
+			"so that reading the queue is not held up by slow channels. 0 disables the spillover")
+	cmd.Flags().StringVar(&eventSpillDir, "event-spill-dir", "",
+		"directory to spill the streamed events to (default <export-dir>/metainfo/event_spill_<destination>)")
+	cmd.Flags().IntVar(&maxInFlightSegments, "max-in-flight-segments", 1,
+		"maximum number of queue segments being streamed at a time. With more than 1, the next segment is read "+
+			"and dispatched while the events of the previous ones are still being applied")
+	cmd.Flags().BoolVar(&verifyRowCounts, "verify-row-counts", false,
+		"after the snapshot is imported, verify that the row counts of the imported tables match the exported row counts, "+
+			"and exit with an error if any of them differ. For live migration, it runs before the changes are streamed")
+	cmd.Flags().BoolVar(&strictRowCounts, "strict-row-counts", false,
+		"abort the import if the rows affected by the import of a batch differ from the rows of the batch "+
+			"(e.g. the rows discarded by a trigger), instead of warning about it. The mismatches are listed in the import summary")
+	cmd.Flags().BoolVar(&truncateTables, "truncate-tables", false,
+		"with --start-clean, truncate the tables which are not empty before importing the data into them, "+
+			"instead of asking whether to import on top of their rows (which --yes answers with yes)")
+	cmd.Flags().Int64Var(&maxRowsPerTable, "max-rows-per-table", 0,
+		"import only the first rows of each table, up to this number, e.g. for validating the migration pipeline with a sample "+
+			"of the data. The import is PARTIAL. Re-running the import with a different value resumes the tables from where "+
+			"they stopped. It can't be used with --verify-row-counts (0 to import all the rows)")
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", "",
+		"address (e.g. :9100) to serve the live migration stats on, at the /metrics path in the Prometheus format. "+
+			"The stats are not served if not set")
+	cmd.Flags().Int64Var(&resumeFromVsn, "resume-from-vsn", -1,
+		"re-stream the changes after this VSN, overriding the last applied VSN of the event channels which are past it. "+
+			"Events applied earlier are applied again, which duplicates the effect of the non-idempotent ones (-1 to resume "+
+			"from where each channel left off)")
+
+	cmd.Flags().BoolVar(&tconf.SqlldrDirectPath, "oracle-sqlldr-direct-path", true,
+		"(Oracle only) use direct path load in sqlldr. Direct path is much faster than the conventional path "+
+			"but locks the table and marks the indexes unusable on errors")
+	cmd.Flags().IntVar(&tconf.SqlldrRows, "oracle-sqlldr-rows", 0,
+		"(Oracle only) sqlldr ROWS option: number of rows per commit (conventional path) or per data save (direct path). "+
+			"Smaller values let a failed batch be resumed from the last commit, also after a restart, at the cost of throughput. "+
+			"(0 for sqlldr default)")
+	cmd.Flags().IntVar(&tconf.SqlldrBindSize, "oracle-sqlldr-bind-size", 0,
+		"(Oracle only) sqlldr BINDSIZE option: size in bytes of the bind array for conventional path load. "+
+			"Larger values reduce round trips at the cost of memory. (0 for sqlldr default)")
+	cmd.Flags().BoolVar(&tconf.SqlldrParallel, "oracle-sqlldr-parallel", false,
+		"(Oracle only) sqlldr PARALLEL option: allow multiple direct path loads into the same table concurrently. "+
+			"Indexes are not maintained during parallel direct path loads and must be rebuilt afterwards")
+
+	cmd.Flags().IntVar(&tconf.OracleLoaderParallelism, "oracle-loader-parallelism", 0,
+		"(Oracle only) maximum number of sqlldr processes running concurrently. Defaults to the number of parallel jobs")
+
+	cmd.Flags().BoolVar(&tconf.KeepRejects, "keep-rejects", false,
+		"(Oracle only) keep the sqlldr bad and log files of the batches with rejected rows, even with --start-clean")
+
+	cmd.Flags().StringSliceVar(&sqlldrNoNullIfColumns, "oracle-sqlldr-no-nullif-columns", nil,
+		"(Oracle only) comma separated list of <table>.<column> loaded without the NULLIF clause, so that the null marker "+
+			"of the data files (\\N for text, an empty field for CSV, or --null-string) is not loaded as NULL into them")
+	cmd.Flags().BoolVar(&tconf.SqlldrInsertFallback, "oracle-insert-fallback", false,
+		"(Oracle only) import data using INSERT statements if sqlldr is not available, instead of failing. "+
+			"This is much slower than sqlldr")
+
+	cmd.Flags().StringVar(&tconf.ApplyStatementMode, "apply-statement-mode", tgtdb.APPLY_STATEMENT_MODE_PER_ROW,
+		fmt.Sprintf("how the insert events of a batch are applied on the target db during live migration: "+
+			"%s (one INSERT per event), %s (one INSERT for consecutive events of a table), %s (COPY for consecutive events of a table, YugabyteDB only)",
+			tgtdb.APPLY_STATEMENT_MODE_PER_ROW, tgtdb.APPLY_STATEMENT_MODE_MULTI_ROW, tgtdb.APPLY_STATEMENT_MODE_COPY))
 }
 
 func registerImportSchemaFlags(cmd *cobra.Command) {
@@ -182,9 +455,36 @@ func registerImportSchemaFlags(cmd *cobra.Command) {
 		"If set, objects will be imported in the order specified with the --object-list flag (default false)")
 	cmd.Flags().BoolVar(&flagPostImportData, "post-import-data", false,
 		"If set, creates indexes, foreign-keys, and triggers in target db")
+	cmd.Flags().BoolVar(&postSnapshotIndexes, "post-snapshot-indexes", false,
+		"same as --post-import-data, except that the indexes of INDEXES_table.sql are created concurrently "+
+			"on --index-creation-parallelism connections, and the time taken by each of them is reported")
+	cmd.Flags().IntVar(&indexCreationParallelism, "index-creation-parallelism", 4,
+		"number of indexes created at a time with --post-snapshot-indexes")
 	cmd.Flags().BoolVar(&tconf.IgnoreIfExists, "ignore-exist", false,
 		"true - to ignore errors if object already exists\n"+
 			"false - throw those errors to the standard output (default false)")
+	cmd.Flags().StringArrayVar(&extraIdempotentStmtPatterns, "ignore-exist-stmt-pattern", nil,
+		fmt.Sprintf("regex matching the whole of a statement whose \"already exists\" error is to be ignored even without --ignore-exist "+
+			"(case-insensitive; can be repeated). Always ignored: %q", DEFAULT_IDEMPOTENT_STMT_PATTERNS))
+	cmd.Flags().DurationVar(&ddlTimeout, "ddl-timeout", 0,
+		"timeout for each schema statement (e.g. 10m), set as the statement_timeout of the session. A statement which times out "+
+			"is cancelled on the server and retried up to "+fmt.Sprint(DDL_MAX_RETRY_COUNT)+" times "+
+			"(override with the DDL_TIMEOUT_MAX_RETRY_COUNT env var) before it is reported as failed, "+
+			"i.e. a statement which keeps timing out runs "+fmt.Sprint(DDL_MAX_RETRY_COUNT+1)+" times. "+
+			"0 for no timeout. --ddl-statement-timeout is an alias")
+	cmd.Flags().DurationVar(&indexDDLTimeout, "index-ddl-timeout", 0,
+		"timeout for each CREATE INDEX statement, which usually takes much longer than the other statements. "+
+			"It is retried as per --ddl-timeout, and an index left INVALID by a timed out statement is dropped. Defaults to --ddl-timeout")
+	cmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "ddl-statement-timeout" {
+			name = "ddl-timeout"
+		}
+		return pflag.NormalizedName(name)
+	})
+	cmd.Flags().StringVar(&allowedDDLTypesFlag, "allowed-ddl-types", "",
+		"comma separated list of the statement types (leading keywords, e.g. CREATE,ALTER,COMMENT) allowed to run on the target. "+
+			"Statements of other types (e.g. DROP or GRANT) are not executed and are written to <export-dir>/schema/disallowed.sql "+
+			"for manual review. The SET and SELECT statements setting up the session are always run. (default: all types)")
 	cmd.Flags().BoolVar(&flagRefreshMViews, "refresh-mviews", false,
 		"If set, refreshes the materialised views on target during post import data phase (default false)")
 	cmd.Flags().BoolVar(&enableOrafce, "enable-orafce", true,
@@ -197,6 +497,8 @@ func validateTargetPortRange() {
 			tconf.Port = ORACLE_DEFAULT_PORT
 		} else if tconf.TargetDBType == YUGABYTEDB {
 			tconf.Port = YUGABYTEDB_YSQL_DEFAULT_PORT
+		} else if tconf.TargetDBType == MYSQL {
+			tconf.Port = MYSQL_DEFAULT_PORT
 		}
 		return
 	}
@@ -207,6 +509,14 @@ func validateTargetPortRange() {
 }
 
 func validateTargetSchemaFlag() {
+	if tconf.TargetDBType == MYSQL {
+		// the database is the schema in MySQL
+		if tconf.Schema != "" && tconf.Schema != tconf.DBName {
+			utils.ErrExit("Error: --target-db-schema is not applicable for MySQL, the tables are imported into --target-db-name")
+		}
+		tconf.Schema = tconf.DBName
+		return
+	}
 	if tconf.Schema == "" {
 		if tconf.TargetDBType == YUGABYTEDB {
 			tconf.Schema = YUGABYTEDB_DEFAULT_SCHEMA
@@ -262,24 +572,25 @@ func checkOrSetDefaultTargetSSLMode() {
 
 func validateBatchSizeFlag(numLinesInASplit int64) {
 	if batchSize == -1 {
-		if tconf.TargetDBType == ORACLE {
-			batchSize = DEFAULT_BATCH_SIZE_ORACLE
-		} else {
-			batchSize = DEFAULT_BATCH_SIZE_YUGABYTEDB
-		}
-		return
+		batchSize = getMaxBatchSize()
+	} else if numLinesInASplit > getMaxBatchSize() {
+		utils.ErrExit("Error: Invalid batch size %v. The batch size cannot be greater than %v", numLinesInASplit, getMaxBatchSize())
 	}
 
-	var defaultBatchSize int64
-	if tconf.TargetDBType == ORACLE {
-		defaultBatchSize = DEFAULT_BATCH_SIZE_ORACLE
-	} else {
-		defaultBatchSize = DEFAULT_BATCH_SIZE_YUGABYTEDB
+	if batchSizeBytes < 0 {
+		utils.ErrExit("Error: Invalid batch size bytes %v. It must not be negative", batchSizeBytes)
 	}
+	if minBatchSize < 0 || (minBatchSize > 0 && minBatchSize >= batchSize) {
+		utils.ErrExit("Error: Invalid min batch size %v. The min batch size must be between 0 and %v", minBatchSize, batchSize-1)
+	}
+}
 
-	if numLinesInASplit > defaultBatchSize {
-		utils.ErrExit("Error: Invalid batch size %v. The batch size cannot be greater than %v", numLinesInASplit, defaultBatchSize)
+// getMaxBatchSize returns the default (and the maximum allowed) number of rows in a batch for the target db.
+func getMaxBatchSize() int64 {
+	if tconf.TargetDBType == ORACLE {
+		return DEFAULT_BATCH_SIZE_ORACLE
 	}
+	return DEFAULT_BATCH_SIZE_YUGABYTEDB
 }
 
 func validateTargetDBType() {
@@ -299,3 +610,320 @@ func validateImportType() {
 		utils.ErrExit("Error: Invalid import-type: %q. Supported import types are: %s", importType, validExportTypes)
 	}
 }
+
+func validateVsnGapDetectionFlag() {
+	vsnGapDetectionMode = strings.ToLower(vsnGapDetectionMode)
+	if !slices.Contains(validVsnGapDetectionModes, vsnGapDetectionMode) {
+		utils.ErrExit("Error: Invalid vsn-gap-detection: %q. Supported values are: %s", vsnGapDetectionMode, validVsnGapDetectionModes)
+	}
+}
+
+func validateImportOrderFlag() {
+	importOrder = strings.ToLower(importOrder)
+	if !slices.Contains(validImportOrders, importOrder) {
+		utils.ErrExit("Error: Invalid import-order: %q. Supported values are: %s", importOrder, validImportOrders)
+	}
+}
+
+func validateTableParallelismFlag() {
+	for tableName, parallelism := range tableParallelism {
+		if parallelism < 1 {
+			utils.ErrExit("Error: Invalid table-parallelism of table %s: %d. It must be at least 1", tableName, parallelism)
+		}
+	}
+}
+
+func validateSplitFilesDirFlag() {
+	if splitFilesDir == "" {
+		return
+	}
+	if noSplitFiles {
+		utils.ErrExit("Error: --split-files-dir can't be used with --no-split-files")
+	}
+	var err error
+	splitFilesDir, err = filepath.Abs(splitFilesDir)
+	if err != nil {
+		utils.ErrExit("Error: Invalid split-files-dir %q: %s", splitFilesDir, err)
+	}
+	if !utils.FileOrFolderExists(splitFilesDir) {
+		utils.ErrExit("Error: split-files-dir %q doesn't exist", splitFilesDir)
+	}
+}
+
+func validateTruncateTablesFlag() {
+	if truncateTables && !startClean {
+		utils.ErrExit("Error: --truncate-tables can only be used with --start-clean")
+	}
+}
+
+func validateDisableConstraintsDuringLoadFlag() {
+	if !tconf.DisableConstraintsDuringLoad {
+		return
+	}
+	if tconf.TargetDBType != YUGABYTEDB {
+		utils.ErrExit("Error: --disable-constraints-during-load is supported only for YugabyteDB")
+	}
+}
+
+func validateMaxRowsPerTableFlag() {
+	if maxRowsPerTable < 0 {
+		utils.ErrExit("Error: Invalid max-rows-per-table: %d. It must not be negative", maxRowsPerTable)
+	}
+	if maxRowsPerTable > 0 && verifyRowCounts {
+		utils.ErrExit("Error: --verify-row-counts can't be used with --max-rows-per-table, as only a part of the rows is imported")
+	}
+}
+
+func validateTableImportOrderFlags() {
+	if tableImportOrder != "" && reorderByDependencies {
+		utils.ErrExit("Error: Only one of --table-import-order and --reorder-by-dependencies is allowed")
+	}
+	if (tableImportOrder != "" || reorderByDependencies) && (importOrder == IMPORT_ORDER_LARGEST_FIRST || importOrder == IMPORT_ORDER_SMALLEST_FIRST) {
+		utils.ErrExit("Error: --import-order %s can't be used with --table-import-order or --reorder-by-dependencies", importOrder)
+	}
+}
+
+func validateOnPrimaryKeyConflictFlag() {
+	onPrimaryKeyConflict = strings.ToLower(onPrimaryKeyConflict)
+	if !slices.Contains(validOnPrimaryKeyConflicts, onPrimaryKeyConflict) {
+		utils.ErrExit("Error: Invalid on-primary-key-conflict: %q. Supported values are: %s", onPrimaryKeyConflict, validOnPrimaryKeyConflicts)
+	}
+	if onPrimaryKeyConflict != tgtdb.ON_PRIMARY_KEY_CONFLICT_ERROR && tconf.TargetDBType != YUGABYTEDB {
+		utils.ErrExit("Error: --on-primary-key-conflict %s is supported only for YugabyteDB", onPrimaryKeyConflict)
+	}
+}
+
+func validateCopyRetryFlags() {
+	if copyMaxRetries < 0 {
+		utils.ErrExit("Error: Invalid copy-max-retries: %d. It must not be negative", copyMaxRetries)
+	}
+	copyRetryBackoff = strings.ToLower(copyRetryBackoff)
+	if !slices.Contains(validCopyRetryBackoffs, copyRetryBackoff) {
+		utils.ErrExit("Error: Invalid copy-retry-backoff: %q. Supported values are: %s", copyRetryBackoff, validCopyRetryBackoffs)
+	}
+}
+
+func validateTargetDBVersionFlags() {
+	for flagName, version := range map[string]string{"min-target-db-version": minTargetDBVersion, "max-target-db-version": maxTargetDBVersion} {
+		if version == "" {
+			continue
+		}
+		_, err := tgtdb.ParseVersion(version)
+		if err != nil {
+			utils.ErrExit("Error: Invalid %s %q: %s", flagName, version, err)
+		}
+	}
+	if minTargetDBVersion != "" && maxTargetDBVersion != "" {
+		minVersion, _ := tgtdb.ParseVersion(minTargetDBVersion)
+		maxVersion, _ := tgtdb.ParseVersion(maxTargetDBVersion)
+		if tgtdb.CompareVersions(minVersion, maxVersion) > 0 {
+			utils.ErrExit("Error: --min-target-db-version %s is higher than --max-target-db-version %s", minTargetDBVersion, maxTargetDBVersion)
+		}
+	}
+}
+
+func validatePostDataParallelismFlag() {
+	if postDataParallelism < 1 {
+		utils.ErrExit("Error: Invalid post-data-parallelism: %d. It must be at least 1", postDataParallelism)
+	}
+}
+
+func validateColumnMapFlag() {
+	var err error
+	columnMaps, err = parseColumnMap(columnMapSpecs)
+	if err != nil {
+		utils.ErrExit("Error: Invalid column-map: %s", err)
+	}
+}
+
+func validateCustomTypeConvertersFlag() {
+	var err error
+	customTypeConverters, err = parseCustomTypeConverters(customTypeConverterSpecs)
+	if err != nil {
+		utils.ErrExit("Error: Invalid custom-type-converters: %s", err)
+	}
+}
+
+func validateSourceDBTypeFlag() {
+	if expectedSourceDBType == "" {
+		return
+	}
+	expectedSourceDBType = strings.ToLower(expectedSourceDBType)
+	if !slices.Contains(supportedSourceDBTypes, expectedSourceDBType) {
+		utils.ErrExit("Error: Invalid source-db-type: %q. Supported source db types are: %s", expectedSourceDBType, supportedSourceDBTypes)
+	}
+	err := checkSourceDBType(expectedSourceDBType, ExtractMetaInfo(exportDir).SourceDBType)
+	if err != nil {
+		utils.ErrExit("Error: %s of export-dir %q. Check that the export-dir is the one of the intended migration", err, exportDir)
+	}
+}
+
+// checkSourceDBType checks the source db type recorded in the metainfo of the export-dir against the expected one.
+func checkSourceDBType(expected string, actual string) error {
+	if actual == "" {
+		return fmt.Errorf("source-db-type %q can't be verified, as the source db type is not recorded in the metainfo", expected)
+	}
+	if !strings.EqualFold(expected, actual) {
+		return fmt.Errorf("source-db-type %q doesn't match the source db type %q", expected, actual)
+	}
+	return nil
+}
+
+func validateSchemaMapFlag() {
+	var err error
+	tconf.SchemaMap, err = parseSchemaMap(schemaMapSpecs)
+	if err != nil {
+		utils.ErrExit("Error: Invalid schema-map: %s", err)
+	}
+}
+
+func validateCopyOptionsFlag() {
+	var err error
+	copyOptions, err = tgtdb.ParseCopyOptions(copyOptionSpecs)
+	if err != nil {
+		utils.ErrExit("Error: Invalid copy-options: %s", err)
+	}
+	// the target db type is not set for import data file, which imports into YugabyteDB
+	if len(copyOptions) > 0 && tconf.TargetDBType != "" && tconf.TargetDBType != YUGABYTEDB {
+		utils.ErrExit("Error: --copy-options is supported only for YugabyteDB")
+	}
+}
+
+func validateRedactColumnsFlag() {
+	var err error
+	redactColumns, err = parseRedactColumns(redactColumnSpecs)
+	if err != nil {
+		utils.ErrExit("Error: Invalid redact-columns: %s", err)
+	}
+	if redactPlaceholder != "" && len(redactColumns) == 0 {
+		utils.ErrExit("Error: --redact-placeholder is applicable only with --redact-columns")
+	}
+}
+
+// The --delimiter, --quote-char and --escape-char of import data override the values in the data file descriptor,
+// and must be single-byte characters as the COPY command takes only those.
+func validateDataFileOverrideFlags(cmd *cobra.Command) {
+	for flagName, value := range map[string]*string{"delimiter": &delimiter, "quote-char": &quoteChar, "escape-char": &escapeChar} {
+		if !cmd.Flags().Changed(flagName) {
+			continue
+		}
+		resolvedValue, ok := interpreteEscapeSequences(*value)
+		if !ok {
+			utils.ErrExit("Error: Invalid %s: %q. It must be a single-byte character, e.g. ',' or '\\t'", flagName, *value)
+		}
+		*value = resolvedValue
+	}
+}
+
+func validateLineTransformerFlag() {
+	var err error
+	lineTransformerChain, err = datafile.NewLineTransformerChain(lineTransformerSpecs)
+	if err != nil {
+		utils.ErrExit("Error: Invalid line-transformer: %s", err)
+	}
+}
+
+func validateStreamingFlags() {
+	if MAX_INTERVAL_BETWEEN_BATCHES < MIN_ALLOWED_INTERVAL_BETWEEN_BATCHES || MAX_INTERVAL_BETWEEN_BATCHES > MAX_ALLOWED_INTERVAL_BETWEEN_BATCHES {
+		utils.ErrExit("Error: Invalid max-interval-between-batches: %s. It must be between %s and %s",
+			MAX_INTERVAL_BETWEEN_BATCHES, MIN_ALLOWED_INTERVAL_BETWEEN_BATCHES, MAX_ALLOWED_INTERVAL_BETWEEN_BATCHES)
+	}
+	if streamingCheckpointInterval < 0 {
+		utils.ErrExit("Error: Invalid streaming-checkpoint-interval: %s. It must not be negative", streamingCheckpointInterval)
+	}
+	unknownTablePolicy = strings.ToLower(unknownTablePolicy)
+	if !slices.Contains(validUnknownTablePolicies, unknownTablePolicy) {
+		utils.ErrExit("Error: Invalid on-unknown-table: %q. Supported values are: %s", unknownTablePolicy, validUnknownTablePolicies)
+	}
+	if unknownTablePolicy == UNKNOWN_TABLE_AUTO_CREATE && tconf.TargetDBType != YUGABYTEDB {
+		utils.ErrExit("Error: --on-unknown-table %s is supported only for YugabyteDB, the exported schema is not in the %s dialect",
+			UNKNOWN_TABLE_AUTO_CREATE, tconf.TargetDBType)
+	}
+	if OVERALL_PROGRESS_SNAPSHOT_WEIGHT < 0 || OVERALL_PROGRESS_SNAPSHOT_WEIGHT > 100 {
+		utils.ErrExit("Error: Invalid OVERALL_PROGRESS_SNAPSHOT_WEIGHT: %d. It must be between 0 and 100", OVERALL_PROGRESS_SNAPSHOT_WEIGHT)
+	}
+	if eventSpillMaxSizeMB < 0 {
+		utils.ErrExit("Error: Invalid event-spill-max-size-mb: %d. It must not be negative", eventSpillMaxSizeMB)
+	}
+	if eventSpillDir != "" && eventSpillMaxSizeMB == 0 {
+		utils.ErrExit("Error: --event-spill-dir requires --event-spill-max-size-mb to be set")
+	}
+	if maxInFlightSegments < 1 {
+		utils.ErrExit("Error: Invalid max-in-flight-segments: %d. It must be at least 1", maxInFlightSegments)
+	}
+	if resumeFromVsn < -1 {
+		utils.ErrExit("Error: Invalid resume-from-vsn: %d. It must not be negative", resumeFromVsn)
+	}
+	if resumeFromVsn >= 0 {
+		if !changeStreamingIsEnabled(importType) {
+			utils.ErrExit("Error: --resume-from-vsn is applicable only when the changes are streamed")
+		}
+		if !utils.AskPrompt(fmt.Sprintf("Changes after VSN %d already applied on the target will be applied again, "+
+			"which can duplicate the effect of non-idempotent operations. Do you want to continue", resumeFromVsn)) {
+			utils.ErrExit("Aborting import.")
+		}
+	}
+	if NUM_EVENT_CHANNELS < 1 {
+		utils.ErrExit("Error: Invalid num-event-channels: %d. It must be at least 1", NUM_EVENT_CHANNELS)
+	}
+	if MAX_EVENTS_PER_BATCH < 1 {
+		utils.ErrExit("Error: Invalid max-events-per-batch: %d. It must be at least 1", MAX_EVENTS_PER_BATCH)
+	}
+	if EVENT_CHANNEL_SIZE < MAX_EVENTS_PER_BATCH {
+		utils.ErrExit("Error: Invalid event-channel-size: %d. It must be at least max-events-per-batch (%d)", EVENT_CHANNEL_SIZE, MAX_EVENTS_PER_BATCH)
+	}
+	if maxEventsPerSecond < 0 {
+		utils.ErrExit("Error: Invalid max-events-per-second: %d. It must not be negative", maxEventsPerSecond)
+	}
+	if EVENT_BATCH_MAX_RETRY_COUNT < 0 {
+		utils.ErrExit("Error: Invalid max-retries-per-event-batch: %d. It must not be negative", EVENT_BATCH_MAX_RETRY_COUNT)
+	}
+	if deadLetterDir != "" {
+		var err error
+		deadLetterDir, err = filepath.Abs(deadLetterDir)
+		if err != nil {
+			utils.ErrExit("Error: Invalid dead-letter-dir %q: %s", deadLetterDir, err)
+		}
+		if !utils.FileOrFolderExists(deadLetterDir) {
+			utils.ErrExit("Error: dead-letter-dir %q doesn't exist", deadLetterDir)
+		}
+	}
+}
+
+func validateApplyStatementModeFlag() {
+	validApplyStatementModes := []string{tgtdb.APPLY_STATEMENT_MODE_PER_ROW, tgtdb.APPLY_STATEMENT_MODE_MULTI_ROW, tgtdb.APPLY_STATEMENT_MODE_COPY}
+	tconf.ApplyStatementMode = strings.ToLower(tconf.ApplyStatementMode)
+	if !slices.Contains(validApplyStatementModes, tconf.ApplyStatementMode) {
+		utils.ErrExit("Error: Invalid apply-statement-mode: %q. Supported values are: %s", tconf.ApplyStatementMode, validApplyStatementModes)
+	}
+	if tconf.ApplyStatementMode == tgtdb.APPLY_STATEMENT_MODE_COPY && tconf.TargetDBType != YUGABYTEDB {
+		utils.ErrExit("Error: apply-statement-mode %q is not supported for target db type %s", tconf.ApplyStatementMode, tconf.TargetDBType)
+	}
+}
+
+func validateSqlldrFlags() {
+	for _, tableColumn := range sqlldrNoNullIfColumns {
+		table, column, found := strings.Cut(tableColumn, ".")
+		if !found || table == "" || column == "" {
+			utils.ErrExit("Error: Invalid oracle-sqlldr-no-nullif-columns entry %q. It must be of the form <table>.<column>", tableColumn)
+		}
+	}
+	if tconf.OracleLoaderParallelism < 0 {
+		utils.ErrExit("Error: --oracle-loader-parallelism must be greater than or equal to 0")
+	}
+	if tconf.SqlldrRows < 0 || tconf.SqlldrBindSize < 0 {
+		utils.ErrExit("Error: --oracle-sqlldr-rows and --oracle-sqlldr-bind-size must be non-negative")
+	}
+	if tconf.SqlldrDirectPath && tconf.SqlldrBindSize > 0 {
+		utils.ErrExit("Error: --oracle-sqlldr-bind-size is applicable only to conventional path load (--oracle-sqlldr-direct-path=false)")
+	}
+	if !tconf.SqlldrDirectPath && tconf.SqlldrParallel {
+		utils.ErrExit("Error: --oracle-sqlldr-parallel is applicable only to direct path load (--oracle-sqlldr-direct-path=true)")
+	}
+	if noSplitFiles && tconf.TargetDBType == ORACLE {
+		utils.ErrExit("Error: --no-split-files is not supported for Oracle, sqlldr loads the batches from files")
+	}
+	if strictTypeCheck && tconf.TargetDBType != YUGABYTEDB {
+		utils.ErrExit("Error: --strict-type-check is supported only for YugabyteDB")
+	}
+}
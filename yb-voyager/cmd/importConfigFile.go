@@ -0,0 +1,159 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+var importConfigFile string
+
+// flags which can't be set in the config file
+var nonConfigurableFlags = []string{"config-file", "export-dir", "help"}
+
+/*
+applyConfigFile sets the flags of the command which are not given on the command line to the values in the
+config file, a JSON (.json) or YAML (.yaml/.yml) object keyed by the flag names, e.g.
+
+	target-db-host: 10.0.0.1
+	batch-size: 20000
+	table-list: [orders, customers]
+	table-parallelism: {orders: 16}
+
+The lists are set as comma separated values, and the maps as comma separated key=value pairs. Unknown keys are
+reported, so that a typo isn't ignored.
+*/
+func applyConfigFile(cmd *cobra.Command, configFilePath string) error {
+	config, err := readConfigFile(configFilePath)
+	if err != nil {
+		return err
+	}
+	var unknownKeys []string
+	for key := range config {
+		if cmd.Flags().Lookup(key) == nil || slices.Contains(nonConfigurableFlags, key) {
+			unknownKeys = append(unknownKeys, key)
+		}
+	}
+	if len(unknownKeys) > 0 {
+		slices.Sort(unknownKeys)
+		return fmt.Errorf("unknown keys %v in config file %q, the keys must be the names of the flags of the command "+
+			"(except %v)", unknownKeys, configFilePath, nonConfigurableFlags)
+	}
+	keys := lo.Keys(config)
+	slices.Sort(keys)
+	for _, key := range keys {
+		flag := cmd.Flags().Lookup(key)
+		if flag.Changed {
+			log.Infof("flag %s given on the command line overrides the config file", key)
+			continue
+		}
+		values, err := configValueToFlagValues(config[key], flag.Value.Type())
+		if err != nil {
+			return fmt.Errorf("invalid value of %s in config file %q: %w", key, configFilePath, err)
+		}
+		for _, value := range values {
+			err = cmd.Flags().Set(key, value)
+			if err != nil {
+				return fmt.Errorf("invalid value of %s in config file %q: %w", key, configFilePath, err)
+			}
+		}
+	}
+	return nil
+}
+
+func readConfigFile(configFilePath string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(configFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("read config file %q: %w", configFilePath, err)
+	}
+	config := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(configFilePath)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&config)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &config)
+	default:
+		return nil, fmt.Errorf("config file %q must be a .json, .yaml or .yml file", configFilePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse config file %q: %w", configFilePath, err)
+	}
+	return config, nil
+}
+
+// configValueToFlagValues returns the values to set the flag of the given type to. Each of the elements of a list
+// is set separately for the slice and array flags, which append them.
+func configValueToFlagValues(value interface{}, flagType string) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, fmt.Errorf("no value")
+	case []interface{}:
+		var elems []string
+		for _, elem := range v {
+			s, err := configScalarToString(elem)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, s)
+		}
+		if strings.HasSuffix(flagType, "Slice") || strings.HasSuffix(flagType, "Array") {
+			return elems, nil
+		}
+		return []string{strings.Join(elems, ",")}, nil
+	case map[string]interface{}:
+		keys := lo.Keys(v)
+		slices.Sort(keys)
+		var pairs []string
+		for _, key := range keys {
+			s, err := configScalarToString(v[key])
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, fmt.Sprintf("%s=%s", key, s))
+		}
+		return []string{strings.Join(pairs, ",")}, nil
+	default:
+		s, err := configScalarToString(v)
+		if err != nil {
+			return nil, err
+		}
+		return []string{s}, nil
+	}
+}
+
+func configScalarToString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool, int, int64, uint64, float64, json.Number:
+		return fmt.Sprintf("%v", v), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v", value)
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyConfigFile(t *testing.T) {
+	var host, tableList string
+	var batchSize int64
+	var useUpsert bool
+	var parallelism map[string]int
+	var specs []string
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringVar(&host, "target-db-host", "127.0.0.1", "")
+		cmd.Flags().Int64Var(&batchSize, "batch-size", -1, "")
+		cmd.Flags().BoolVar(&useUpsert, "enable-upsert", false, "")
+		cmd.Flags().StringVar(&tableList, "table-list", "", "")
+		cmd.Flags().StringToIntVar(&parallelism, "table-parallelism", nil, "")
+		cmd.Flags().StringSliceVar(&specs, "column-map", nil, "")
+		return cmd
+	}
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "import.yaml")
+	assert.NoError(t, os.WriteFile(yamlPath, []byte(`
+target-db-host: 10.0.0.1
+batch-size: 20000
+enable-upsert: true
+table-list: [orders, Customers]
+table-parallelism: {Orders: 16, items: 2}
+column-map: ["orders.a:b", "orders.c:-"]
+`), 0644))
+	cmd := newCmd()
+	assert.NoError(t, cmd.ParseFlags([]string{"--batch-size", "500"}))
+	assert.NoError(t, applyConfigFile(cmd, yamlPath))
+	assert.Equal(t, "10.0.0.1", host)
+	assert.Equal(t, int64(500), batchSize) // the command line takes precedence
+	assert.True(t, useUpsert)
+	assert.Equal(t, "orders,Customers", tableList)
+	assert.Equal(t, map[string]int{"Orders": 16, "items": 2}, parallelism)
+	assert.Equal(t, []string{"orders.a:b", "orders.c:-"}, specs)
+
+	jsonPath := filepath.Join(dir, "import.json")
+	assert.NoError(t, os.WriteFile(jsonPath, []byte(`{"target-db-host": "10.0.0.2", "batch-size": 1000}`), 0644))
+	cmd = newCmd()
+	assert.NoError(t, applyConfigFile(cmd, jsonPath))
+	assert.Equal(t, "10.0.0.2", host)
+	assert.Equal(t, int64(1000), batchSize)
+
+	assert.NoError(t, os.WriteFile(jsonPath, []byte(`{"target-db-hots": "10.0.0.2", "batch-size": "x"}`), 0644))
+	err := applyConfigFile(newCmd(), jsonPath)
+	assert.ErrorContains(t, err, "unknown keys [target-db-hots]")
+	assert.NoError(t, os.WriteFile(jsonPath, []byte(`{"batch-size": "x"}`), 0644))
+	err = applyConfigFile(newCmd(), jsonPath)
+	assert.ErrorContains(t, err, "invalid value of batch-size")
+	tomlPath := filepath.Join(dir, "import.toml")
+	assert.NoError(t, os.WriteFile(tomlPath, []byte(`batch-size = 1000`), 0644))
+	err = applyConfigFile(newCmd(), tomlPath)
+	assert.ErrorContains(t, err, "must be a .json, .yaml or .yml file")
+}
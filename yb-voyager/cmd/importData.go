@@ -17,21 +17,28 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/fatih/color"
+	"github.com/gosuri/uitable"
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
+	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
 	"github.com/sourcegraph/conc/pool"
 	"github.com/spf13/cobra"
+	"github.com/tebeka/atexit"
 	"golang.org/x/exp/slices"
+	"golang.org/x/term"
 
 	"github.com/yugabyte/yb-voyager/yb-voyager/src/callhome"
 	"github.com/yugabyte/yb-voyager/yb-voyager/src/datafile"
@@ -44,15 +51,53 @@ import (
 
 var metaInfoDirName = META_INFO_DIR_NAME
 var batchSize = int64(0)
-var batchImportPool *pool.Pool
+var batchSizeBytes = int64(0)
+var minBatchSize = int64(0)
+var importThrottler *ImportThrottler
 var tablesProgressMetadata map[string]*utils.TableProgressMetadata
 var importDestinationType string
 
 // stores the data files description in a struct
 var dataFileDescriptor *datafile.Descriptor
 var truncateSplits bool                            // to truncate *.D splits after import
+var keepSplitFiles bool                            // to retain all the batch files, overriding truncateSplits
+var splitFilesDir string                           // dir for the batch files, instead of the import data state in the export dir
 var TableToColumnNames = make(map[string][]string) // map of table name to columnNames
 var valueConverter dbzm.ValueConverter
+var minTargetDBVersion, maxTargetDBVersion string
+var skipTargetDBVersionCheck bool
+var postDataParallelism int
+var sqlldrNoNullIfColumns []string // <table>.<column>
+var sqlStmtsMutex sync.Mutex       // guards failedSqlStmts and defferedSqlStmts while statements are run in parallel
+var noSplitFiles bool              // hold the batches in memory instead of writing them to the import data state dir
+var lineTransformerSpecs []string
+var lineTransformerChain datafile.LineTransformerChain
+var skipMissingTables bool
+var maxValueSizeBytes int64 // 0 to disable the check
+var restartFileAfterBatchFailures int
+var copyOptionSpecs []string // <key>=<value>
+var copyOptions []string     // in the form of the WITH clause of COPY
+
+// seconds to wait before resuming the import with changed settings when the prompts are disabled with --yes
+var settingsChangeGracePeriodSec int
+var restartedFiles []string // files re-imported from the beginning, reported at the end of the import
+var restartedFilesMutex sync.Mutex
+var maxTablesInParallel int
+var verifyChecksums bool
+var tableParallelism map[string]int // number of batches imported at a time, by the table name as given in --table-parallelism
+
+// Batches which failed after all the retries, by the data file and table, when restartFileAfterBatchFailures is set.
+var failedBatches = make(map[string][]error)
+var failedBatchesMutex sync.Mutex
+var importOrder string
+var copyMaxRetries int
+var copyRetryBackoff string
+var onPrimaryKeyConflict string
+var truncateTables bool
+var strictRowCounts bool
+var skippedMissingTables []string     // tables skipped by --skip-missing-tables, reported at the end of the import
+var skippedMissingTaskTables []string // names of the skipped missing tables as in their tasks, and in the streamed events
+var expectedSourceDBType string       // --source-db-type, checked against the source db type of the export-dir
 
 var importDataCmd = &cobra.Command{
 	Use:   "data",
@@ -60,8 +105,35 @@ var importDataCmd = &cobra.Command{
 	Long:  `This command will import the data exported from the source database into YugabyteDB database.`,
 
 	PreRun: func(cmd *cobra.Command, args []string) {
+		if importConfigFile != "" {
+			err := applyConfigFile(cmd, importConfigFile)
+			if err != nil {
+				utils.ErrExit("Error: %s", err)
+			}
+		}
 		validateImportFlags(cmd)
+		validateSourceDBTypeFlag()
+		validateSchemaMapFlag()
 		validateImportType()
+		validateVsnGapDetectionFlag()
+		validateApplyStatementModeFlag()
+		validateSqlldrFlags()
+		validateStreamingFlags()
+		validateTargetDBVersionFlags()
+		validateLineTransformerFlag()
+		validatePostDataParallelismFlag()
+		validateImportOrderFlag()
+		validateCopyRetryFlags()
+		validateOnPrimaryKeyConflictFlag()
+		validateTableImportOrderFlags()
+		validateTableParallelismFlag()
+		validateColumnMapFlag()
+		validateCustomTypeConvertersFlag()
+		validateRedactColumnsFlag()
+		validateCopyOptionsFlag()
+		validateDisableConstraintsDuringLoadFlag()
+		validateDataFileOverrideFlags(cmd)
+		validateProgressOutputFlags()
 	},
 	Run: importDataCommandFn,
 }
@@ -74,12 +146,71 @@ func importDataCommandFn(cmd *cobra.Command, args []string) {
 	sqlname.SourceDBType = sourceDBType
 	dataStore = datastore.NewDataStore(filepath.Join(exportDir, "data"))
 	dataFileDescriptor = datafile.OpenDescriptor(exportDir)
+	overrideDataFileDescriptor(cmd)
 	quoteTableNameIfRequired()
+	loadTableSourceSchemas()
 	importFileTasks := discoverFilesToImport()
 	importFileTasks = applyTableListFilter(importFileTasks)
+	if verifyChecksums {
+		verifyDataFileChecksums(importFileTasks)
+	}
 	importData(importFileTasks)
 }
 
+// overrideDataFileDescriptor applies the --delimiter, --quote-char, --escape-char and --null-string flags
+// to the data file descriptor of the export dir.
+func overrideDataFileDescriptor(cmd *cobra.Command) {
+	if cmd.Flags().Changed("delimiter") {
+		dataFileDescriptor.Delimiter = delimiter
+	}
+	if cmd.Flags().Changed("null-string") {
+		dataFileDescriptor.NullString = nullString
+	}
+	for _, flagName := range []string{"quote-char", "escape-char"} {
+		if cmd.Flags().Changed(flagName) && dataFileDescriptor.FileFormat != datafile.CSV {
+			utils.ErrExit("Error: --%s is only applicable to the CSV data files, the exported data files are of %q format",
+				flagName, dataFileDescriptor.FileFormat)
+		}
+	}
+	if cmd.Flags().Changed("quote-char") {
+		dataFileDescriptor.QuoteChar = quoteChar[0]
+	}
+	if cmd.Flags().Changed("escape-char") {
+		dataFileDescriptor.EscapeChar = escapeChar[0]
+	}
+	log.Infof("data file descriptor after the overrides: delimiter %q, quote char %q, escape char %q, null string %q",
+		dataFileDescriptor.Delimiter, dataFileDescriptor.QuoteChar, dataFileDescriptor.EscapeChar, dataFileDescriptor.NullString)
+}
+
+// verifyDataFileChecksums fails if any of the data files doesn't match the checksum recorded in the data file
+// descriptor at the export, e.g. due to a truncated transfer of the export-dir.
+func verifyDataFileChecksums(tasks []*ImportFileTask) {
+	if dataFileDescriptor.ChecksumAlgorithm == "" {
+		utils.PrintAndLog("WARNING: skipping the checksum verification, the data was exported without the checksums of the data files")
+		return
+	}
+	if dataFileDescriptor.ChecksumAlgorithm != datafile.CHECKSUM_ALGORITHM_SHA256 {
+		utils.ErrExit("Error: unsupported checksum algorithm %q in the data file descriptor", dataFileDescriptor.ChecksumAlgorithm)
+	}
+	utils.PrintAndLog("verifying the checksums of the data files...")
+	for _, task := range tasks {
+		fileEntry := dataFileDescriptor.GetFileEntry(task.FilePath, task.TableName)
+		if fileEntry == nil || fileEntry.Checksum == "" {
+			utils.PrintAndLog("WARNING: skipping the checksum verification of %q, its checksum is not recorded", task.FilePath)
+			continue
+		}
+		checksum, err := datafile.ComputeChecksum(task.FilePath)
+		if err != nil {
+			utils.ErrExit("Error: verify checksum of data file of table %s: %s", task.TableName, err)
+		}
+		if checksum != fileEntry.Checksum {
+			utils.ErrExit("Error: checksum of data file %q of table %s doesn't match the one recorded at the export. "+
+				"The file may be truncated or modified", task.FilePath, task.TableName)
+		}
+		log.Infof("verified the %s checksum %s of %q", dataFileDescriptor.ChecksumAlgorithm, checksum, task.FilePath)
+	}
+}
+
 type ImportFileTask struct {
 	ID        int
 	FilePath  string
@@ -123,43 +254,84 @@ func discoverFilesToImport() []*ImportFileTask {
 	return result
 }
 
+// restoreSequences restores the sequences on the target, printing the outcome for each of them.
+// It exits after the summary if any of them failed.
+func restoreSequences(sequencesLastVal map[string]int64) {
+	results := tdb.RestoreSequences(lo.MapKeys(sequencesLastVal, func(_ int64, sequenceName string) string {
+		return tconf.GetTargetTableName(sequenceName)
+	}))
+	if len(results) == 0 {
+		return
+	}
+	uiTable := uitable.New()
+	headerfmt := color.New(color.FgGreen, color.Underline).SprintFunc()
+	uiTable.AddRow(headerfmt("SEQUENCE"), headerfmt("RESTORED VALUE"), headerfmt("STATUS"))
+	var failedSequences []string
+	for _, result := range results {
+		status := color.GreenString("DONE")
+		if result.Err != nil {
+			log.Errorf("failed to restore sequence %s: %s", result.SequenceName, result.Err)
+			status = color.RedString("FAILED: %s", result.Err)
+			failedSequences = append(failedSequences, result.SequenceName)
+		}
+		uiTable.AddRow(result.SequenceName, result.LastValue, status)
+	}
+	fmt.Printf("\nRestored sequences:\n\n")
+	fmt.Println(uiTable)
+	fmt.Println()
+	if len(failedSequences) > 0 {
+		utils.ErrExit("failed to restore %d of %d sequences: %v", len(failedSequences), len(results), failedSequences)
+	}
+}
+
 func applyTableListFilter(importFileTasks []*ImportFileTask) []*ImportFileTask {
 	result := []*ImportFileTask{}
-	includeList := utils.CsvStringToSlice(tconf.TableList)
-	log.Infof("includeList: %v", includeList)
-	excludeList := utils.CsvStringToSlice(tconf.ExcludeTableList)
-	log.Infof("excludeList: %v", excludeList)
-
 	allTables := make([]string, 0, len(importFileTasks))
 	for _, task := range importFileTasks {
 		allTables = append(allTables, task.TableName)
 	}
+	allTables = lo.Uniq(allTables)
 	slices.Sort(allTables)
 	log.Infof("allTables: %v", allTables)
 
-	checkUnknownTableNames := func(tableNames []string, listName string) {
+	// The entries of the lists can be glob or regex patterns (see utils.NamePattern), expanded against allTables.
+	expandTableList := func(patterns []string, listName string) []string {
+		patternToTableNames, err := utils.ExpandNamePatterns(patterns, allTables)
+		if err != nil {
+			utils.ErrExit("Invalid pattern in the %s list: %s", listName, err)
+		}
+		tableNames := make([]string, 0)
 		unknownTableNames := make([]string, 0)
-		for _, tableName := range tableNames {
-			if !slices.Contains(allTables, tableName) {
-				unknownTableNames = append(unknownTableNames, tableName)
+		for _, pattern := range patterns {
+			matchedTableNames := patternToTableNames[pattern]
+			if len(matchedTableNames) == 0 {
+				unknownTableNames = append(unknownTableNames, pattern)
+			} else if len(matchedTableNames) > 1 || matchedTableNames[0] != pattern {
+				log.Infof("%s list pattern %q expanded to: %v", listName, pattern, matchedTableNames)
 			}
+			tableNames = append(tableNames, matchedTableNames...)
 		}
 		if len(unknownTableNames) > 0 {
-			utils.PrintAndLog("Unknown table names in the %s list: %v", listName, unknownTableNames)
+			utils.PrintAndLog("Unknown table names (or patterns matching no table) in the %s list: %v", listName, unknownTableNames)
 			utils.PrintAndLog("Valid table names are: %v", allTables)
 			utils.ErrExit("Table names are case-sensitive. Please fix the table names in the %s list and retry.", listName)
 		}
+		return lo.Uniq(tableNames)
 	}
-	checkUnknownTableNames(includeList, "include")
-	checkUnknownTableNames(excludeList, "exclude")
+	includeList := expandTableList(utils.CsvStringToSlice(tconf.TableList), "include")
+	log.Infof("includeList: %v", includeList)
+	excludeList := expandTableList(utils.CsvStringToSlice(tconf.ExcludeTableList), "exclude")
+	log.Infof("excludeList: %v", excludeList)
 
 	for _, task := range importFileTasks {
 		if len(includeList) > 0 && !slices.Contains(includeList, task.TableName) {
 			log.Infof("Skipping table %q (fileName: %s) as it is not in the include list", task.TableName, task.FilePath)
+			skippedFilteredTables = append(skippedFilteredTables, task.TableName)
 			continue
 		}
 		if len(excludeList) > 0 && slices.Contains(excludeList, task.TableName) {
 			log.Infof("Skipping table %q (fileName: %s) as it is in the exclude list", task.TableName, task.FilePath)
+			skippedFilteredTables = append(skippedFilteredTables, task.TableName)
 			continue
 		}
 		result = append(result, task)
@@ -168,13 +340,16 @@ func applyTableListFilter(importFileTasks []*ImportFileTask) []*ImportFileTask {
 }
 
 func importData(importFileTasks []*ImportFileTask) {
+	startTime := time.Now()
 	err := retrieveMigrationUUID(exportDir)
 	if err != nil {
 		utils.ErrExit("failed to get migration UUID: %w", err)
 	}
 	payload := callhome.GetPayload(exportDir, migrationUUID)
 	tconf.Schema = strings.ToLower(tconf.Schema)
+	startImportTracing()
 
+	tconf.SourceDBType = sourceDBType
 	tdb = tgtdb.NewTargetDB(&tconf)
 	err = tdb.Init()
 	if err != nil {
@@ -188,18 +363,22 @@ func importData(importFileTasks []*ImportFileTask) {
 		importDestinationType = FF_DB
 	}
 
+	registerCustomTypeConverters(tdb)
 	valueConverter, err = dbzm.NewValueConverter(exportDir, tdb)
 	if err != nil {
 		utils.ErrExit("Failed to create value converter: %s", err)
 	}
+	checkDataTypeCompatibility(importFileTasks)
 	err = tdb.InitConnPool()
 	if err != nil {
 		utils.ErrExit("Failed to initialize the target DB connection pool: %s", err)
 	}
+	tdb.SetApplicationName(tconf.GetApplicationName(migrationUUID, tgtdb.APPLICATION_PHASE_SNAPSHOT))
 
 	targetDBVersion := tdb.GetVersion()
 
 	fmt.Printf("%s version: %s\n", tconf.TargetDBType, targetDBVersion)
+	checkTargetDBVersion(targetDBVersion)
 
 	payload.TargetDBVersion = targetDBVersion
 	//payload.NodeCount = len(tconfs) // TODO: Figure out way to populate NodeCount.
@@ -213,54 +392,145 @@ func importData(importFileTasks []*ImportFileTask) {
 	if err != nil {
 		utils.ErrExit("Failed to initialize meta db: %s", err)
 	}
+	if importDestinationType == FF_DB {
+		checkFallForwardSwitchoverNotDone()
+	}
 
+	if skipMissingTables {
+		importFileTasks = filterMissingTables(importFileTasks)
+	}
+
+	if batchSizeBytes > tdb.MaxBatchSizeInBytes() {
+		utils.PrintAndLog("--batch-size-bytes %d is capped at %d, the maximum batch size in bytes for %s",
+			batchSizeBytes, tdb.MaxBatchSizeInBytes(), tconf.TargetDBType)
+	}
 	utils.PrintAndLog("import of data in %q database started", tconf.DBName)
+	importFileTasks = reorderImportFileTasks(importFileTasks)
 	var pendingTasks, completedTasks []*ImportFileTask
 	state := NewImportDataState(exportDir)
+	if importDestinationType == FF_DB && !startClean {
+		migrated, err := state.MigrateLegacyFallForwardState(tconf.TargetDBType)
+		if err != nil {
+			utils.ErrExit("Failed to migrate the import data state of the fall forward database: %s", err)
+		}
+		if migrated {
+			utils.PrintAndLog("Migrated the import data state of the fall forward database to its own dir.")
+		}
+	}
+	err = state.InitStateDir()
+	if err != nil {
+		utils.ErrExit("Failed to initialize the import data state: %s", err)
+	}
 	if startClean {
 		cleanImportState(state, importFileTasks)
+		setSplitFilesDir(state)
 		pendingTasks = importFileTasks
+		saveImportSettings(state)
 	} else {
+		checkSplitFilesDir(state)
+		checkImportSettings(state)
 		pendingTasks, completedTasks, err = classifyTasks(state, importFileTasks)
 		if err != nil {
 			utils.ErrExit("Failed to classify tasks: %s", err)
 		}
 		utils.PrintAndLog("Already imported tables: %v", importFileTasksToTableNames(completedTasks))
 	}
+	rowLimiter, err = NewTableRowLimiter(state, importFileTasks, maxRowsPerTable)
+	if err != nil {
+		utils.ErrExit("Failed to prepare the --max-rows-per-table limit: %s", err)
+	}
+	if maxRowsPerTable > 0 {
+		color.Yellow("Importing at most %d rows of each table (--max-rows-per-table). This is a PARTIAL import of the data.", maxRowsPerTable)
+		log.Warnf("partial import: at most %d rows of each table are imported (--max-rows-per-table)", maxRowsPerTable)
+	}
+	tableSummaries, err := getTableImportSummaries(state, importFileTasks)
+	if err != nil {
+		utils.ErrExit("Failed to get the imported row counts: %s", err)
+	}
+	rowsImportedBefore := getTotalImportedRows(tableSummaries)
+	if showOverallProgress {
+		overallProgressTracker = NewOverallProgressTracker(importFileTasks)
+		for _, task := range completedTasks {
+			overallProgressTracker.AddSnapshotProgressAmount(getTotalProgressAmount(task))
+		}
+		overallProgressTracker.StartReporting()
+	}
 
+	if importDestinationType == FF_DB && importType == CHANGES_ONLY && len(pendingTasks) > 0 {
+		utils.ErrExit("Snapshot import into the fall forward database is not complete for tables: %v. Run 'fall-forward setup' first.",
+			importFileTasksToTableNames(pendingTasks))
+	}
 	if len(pendingTasks) == 0 {
 		utils.PrintAndLog("All the tables are already imported, nothing left to import\n")
 	} else {
-		utils.PrintAndLog("Tables to import: %v", importFileTasksToTableNames(pendingTasks))
+		pendingTableNames := importFileTasksToTableNames(pendingTasks)
+		if len(pendingTasks) > len(pendingTableNames) {
+			utils.PrintAndLog("Tables to import: %v (%d files)", pendingTableNames, len(pendingTasks))
+		} else {
+			utils.PrintAndLog("Tables to import: %v", pendingTableNames)
+		}
 		prepareTableToColumns(pendingTasks) //prepare the tableToColumns map in case of debezium
+		prepareColumnMappings(pendingTasks)
+		excludeGeneratedColumns(pendingTasks)
+		prepareColumnRedactions(pendingTasks)
 		poolSize := tconf.Parallelism * 2
+		tableToPoolSize := getTableToPoolSize(importFileTasks)
 		progressReporter := NewImportDataProgressReporter(disablePb)
+		if progressOutput == PROGRESS_OUTPUT_JSON {
+			progressReporter.EnableProgressFile(progressFilePath)
+		}
+		if overallProgressTracker != nil {
+			progressReporter.SetOverallProgressFn(overallProgressTracker.String)
+		}
+		importThrottler = NewImportThrottler(throttleLatencyThresholdSec, tconf.Parallelism, progressReporter)
+		if importThrottler.enabled() {
+			utils.PrintAndLog("throttling the import when the batch latency exceeds %d seconds", throttleLatencyThresholdSec)
+		}
 		for _, task := range pendingTasks {
-			// The code can produce `poolSize` number of batches at a time. But, it can consume only
-			// `parallelism` number of batches at a time.
-			batchImportPool = pool.New().WithMaxGoroutines(poolSize)
-
-			totalProgressAmount := getTotalProgressAmount(task)
-			progressReporter.ImportFileStarted(task, totalProgressAmount)
-			importedProgressAmount := getImportedProgressAmount(task, state)
-			progressReporter.AddProgressAmount(task, importedProgressAmount)
-			updateProgressFn := func(progressAmount int64) {
-				progressReporter.AddProgressAmount(task, progressAmount)
-			}
-			importFile(state, task, updateProgressFn)
-			batchImportPool.Wait()                // Wait for the file import to finish.
-			progressReporter.FileImportDone(task) // Remove the progress-bar for the file.
+			progressReporter.AddFileToImport(task, getTotalProgressAmount(task))
+		}
+		// The files are imported `maxTablesInParallel` at a time, in the order of the pending tasks.
+		tasksPool := pool.New().WithMaxGoroutines(maxTablesInParallel)
+		for _, task := range pendingTasks {
+			task := task
+			tasksPool.Go(func() {
+				importTask(state, task, progressReporter, lo.ValueOr(tableToPoolSize, task.TableName, poolSize))
+			})
+		}
+		tasksPool.Wait()
+		if !disablePb {
+			// Let the progress bars render their final state. There is nothing to settle without them.
+			time.Sleep(time.Second * 2)
 		}
-		time.Sleep(time.Second * 2)
+	}
+	if verifyRowCounts {
+		// Once the snapshot is imported, the rows change with the streamed events. The streaming can have
+		// started in an earlier run only if no snapshot tasks were left for this one.
+		if changeStreamingIsEnabled(importType) && len(pendingTasks) == 0 {
+			utils.PrintAndLog("skipping the row count verification, the snapshot was imported before this run and the changes may have been streamed since")
+		} else {
+			verifySnapshotRowCounts(importFileTasks)
+		}
+	}
+	if tconf.DisableConstraintsDuringLoad && validateForeignKeys && len(pendingTasks) > 0 {
+		validateForeignKeysAfterLoad(importFileTasks)
 	}
 
 	callhome.PackAndSendPayload(exportDir)
 	if !dbzm.IsDebeziumForDataExport(exportDir) {
-		executePostImportDataSqls()
+		executePostImportDataSqls(state)
 	} else {
 		if changeStreamingIsEnabled(importType) {
 			color.Blue("streaming changes to target DB...")
 			err = streamChanges()
+			if errors.Is(err, errStreamingStopped) {
+				utils.PrintAndLog("Stopped streaming changes. Re-run the command to resume from where it stopped.")
+				atexit.Exit(0)
+			}
+			if errors.Is(err, errStreamingStoppedForSwitchover) {
+				utils.PrintAndLog("Stopped streaming changes for the switchover to the fall forward database.")
+				atexit.Exit(0)
+			}
 			if err != nil {
 				utils.ErrExit("Failed to stream changes from source DB: %s", err)
 			}
@@ -272,16 +542,74 @@ func importData(importFileTasks []*ImportFileTask) {
 		if err != nil {
 			utils.ErrExit("failed to read export status for restore sequences: %s", err)
 		}
-		err = tdb.RestoreSequences(status.Sequences)
-
-		if err != nil {
-			utils.ErrExit("failed to restore sequences: %s", err)
-		}
+		restoreSequences(status.Sequences)
 	}
 
+	if overallProgressTracker != nil {
+		overallProgressTracker.Report()
+	}
+	if len(restartedFiles) > 0 {
+		utils.PrintAndLog("Files re-imported from the beginning after batch failures: %v", restartedFiles)
+	}
+	if len(skippedMissingTables) > 0 {
+		utils.PrintAndLog("Skipped tables missing on the target: %v", skippedMissingTables)
+	}
+	writeImportDataSummary(state, importFileTasks, rowsImportedBefore, startTime)
+	endImportTracing()
 	fmt.Printf("\nImport data complete.\n")
 }
 
+// filterMissingTables drops the tasks of the tables which don't exist on the target db.
+// It runs after the --table-list/--exclude-table-list filter, hence only the selected tables are checked.
+func filterMissingTables(importFileTasks []*ImportFileTask) []*ImportFileTask {
+	missingTables := tdb.GetMissingTables(lo.Uniq(getTargetTableNames(importFileTasksToTableNames(importFileTasks))))
+	if len(missingTables) == 0 {
+		return importFileTasks
+	}
+	utils.PrintAndLog("skipping the tables missing on the target: %v", missingTables)
+	skippedMissingTables = missingTables
+	return lo.Filter(importFileTasks, func(task *ImportFileTask, _ int) bool {
+		if slices.Contains(missingTables, getTargetTableName(task.TableName)) {
+			skippedMissingTaskTables = append(skippedMissingTaskTables, task.TableName)
+			return false
+		}
+		return true
+	})
+}
+
+// checkTargetDBVersion refuses to import into a target db whose version is outside
+// the range given by --min-target-db-version and --max-target-db-version.
+func checkTargetDBVersion(targetDBVersion string) {
+	if minTargetDBVersion == "" && maxTargetDBVersion == "" {
+		return
+	}
+	version, err := tgtdb.ParseVersion(targetDBVersion)
+	if err != nil {
+		utils.ErrExit("Failed to parse the target db version for the version check: %s", err)
+	}
+	var msg string
+	if minTargetDBVersion != "" {
+		minVersion, _ := tgtdb.ParseVersion(minTargetDBVersion) // validated in PreRun
+		if tgtdb.CompareVersions(version, minVersion) < 0 {
+			msg = fmt.Sprintf("target db version %s is lower than --min-target-db-version %s", targetDBVersion, minTargetDBVersion)
+		}
+	}
+	if maxTargetDBVersion != "" {
+		maxVersion, _ := tgtdb.ParseVersion(maxTargetDBVersion)
+		if tgtdb.CompareVersions(version, maxVersion) > 0 {
+			msg = fmt.Sprintf("target db version %s is higher than --max-target-db-version %s", targetDBVersion, maxTargetDBVersion)
+		}
+	}
+	if msg == "" {
+		return
+	}
+	if skipTargetDBVersionCheck {
+		utils.PrintAndLog("WARNING: %s. Continuing as --skip-target-db-version-check is set", msg)
+		return
+	}
+	utils.ErrExit("Error: %s. Use --skip-target-db-version-check to import anyway", msg)
+}
+
 func getTotalProgressAmount(task *ImportFileTask) int64 {
 	fileEntry := dataFileDescriptor.GetFileEntry(task.FilePath, task.TableName)
 	if fileEntry == nil {
@@ -328,7 +656,18 @@ func classifyTasks(state *ImportDataState, tasks []*ImportFileTask) (pendingTask
 		}
 		switch fileImportState {
 		case FILE_IMPORT_COMPLETED:
-			completedTasks = append(completedTasks, task)
+			limitChanged, err := isFileImportLimitChanged(state, task)
+			if err != nil {
+				return nil, nil, fmt.Errorf("get the max rows of the file import: %w", err)
+			}
+			if limitChanged {
+				// imported up to the --max-rows-per-table of an earlier run
+				utils.PrintAndLog("resuming the import of %q into table %s, as --max-rows-per-table has changed since it was imported",
+					task.FilePath, task.TableName)
+				inProgressTasks = append(inProgressTasks, task)
+			} else {
+				completedTasks = append(completedTasks, task)
+			}
 		case FILE_IMPORT_IN_PROGRESS:
 			inProgressTasks = append(inProgressTasks, task)
 		case FILE_IMPORT_NOT_STARTED:
@@ -338,15 +677,61 @@ func classifyTasks(state *ImportDataState, tasks []*ImportFileTask) (pendingTask
 		}
 	}
 	// Start with in-progress tasks, followed by not-started tasks.
+	sortImportFileTasks(inProgressTasks, importOrder, getTotalProgressAmount)
+	sortImportFileTasks(notStartedTasks, importOrder, getTotalProgressAmount)
 	return append(inProgressTasks, notStartedTasks...), completedTasks, nil
 }
 
+// sortImportFileTasks orders the tasks as per --import-order. The tasks are discovered in the order
+// of the data file descriptor, which is retained for the tasks of the same size.
+func sortImportFileTasks(tasks []*ImportFileTask, order string, sizeFn func(*ImportFileTask) int64) {
+	switch order {
+	case IMPORT_ORDER_LARGEST_FIRST:
+		slices.SortStableFunc(tasks, func(a, b *ImportFileTask) bool { return sizeFn(a) > sizeFn(b) })
+	case IMPORT_ORDER_SMALLEST_FIRST:
+		slices.SortStableFunc(tasks, func(a, b *ImportFileTask) bool { return sizeFn(a) < sizeFn(b) })
+	}
+}
+
+func setSplitFilesDir(state *ImportDataState) {
+	err := state.SetSplitFilesDir(splitFilesDir)
+	if err != nil {
+		utils.ErrExit("failed to record the split files dir: %s", err)
+	}
+	if splitFilesDir != "" {
+		utils.PrintAndLog("the batch files are written to %q", splitFilesDir)
+	}
+}
+
+// checkSplitFilesDir checks that a resumed import uses the --split-files-dir of the earlier runs, under which their batches are.
+func checkSplitFilesDir(state *ImportDataState) {
+	prevSplitFilesDir, err := state.GetSplitFilesDir()
+	if err != nil {
+		utils.ErrExit("failed to get the split files dir of the earlier runs: %s", err)
+	}
+	if prevSplitFilesDir == splitFilesDir {
+		return
+	}
+	tableNames, err := state.discoverTableNames()
+	if err == nil && len(tableNames) > 0 {
+		utils.ErrExit("Error: --split-files-dir %q differs from %q of the earlier runs of the import. "+
+			"Resume the import with the same --split-files-dir, or start afresh with --start-clean.", splitFilesDir, prevSplitFilesDir)
+	}
+	setSplitFilesDir(state) // nothing imported yet
+}
+
 func cleanImportState(state *ImportDataState, tasks []*ImportFileTask) {
 	tableNames := importFileTasksToTableNames(tasks)
-	nonEmptyTableNames := tdb.GetNonEmptyTables(tableNames)
-	if len(nonEmptyTableNames) > 0 {
+	nonEmptyTableNames := tdb.GetNonEmptyTables(getTargetTableNames(tableNames))
+	if len(nonEmptyTableNames) > 0 && truncateTables {
+		err := tdb.TruncateTables(nonEmptyTableNames)
+		if err != nil {
+			utils.ErrExit("failed to truncate the non-empty tables: %s", err)
+		}
+		utils.PrintAndLog("Truncated the non-empty tables: %s", strings.Join(nonEmptyTableNames, ", "))
+	} else if len(nonEmptyTableNames) > 0 {
 		utils.PrintAndLog("Following tables are not empty. "+
-			"TRUNCATE them before importing data with --start-clean.\n%s",
+			"TRUNCATE them (or use --truncate-tables) before importing data with --start-clean.\n%s",
 			strings.Join(nonEmptyTableNames, ", "))
 		yes := utils.AskPrompt("Do you want to continue without truncating these tables?")
 		if !yes {
@@ -361,8 +746,18 @@ func cleanImportState(state *ImportDataState, tasks []*ImportFileTask) {
 		}
 	}
 
+	checkpoint, err := state.GetPostDataCheckpoint()
+	if err == nil {
+		err = checkpoint.Reset()
+	}
+	if err != nil {
+		utils.ErrExit("failed to clean the post data checkpoint: %s", err)
+	}
+
 	sqlldrDir := filepath.Join(exportDir, "sqlldr")
-	if utils.FileOrFolderExists(sqlldrDir) {
+	if tconf.KeepRejects {
+		log.Infof("keeping the sqlldr directory %q as --keep-rejects is set", sqlldrDir)
+	} else if utils.FileOrFolderExists(sqlldrDir) {
 		err := os.RemoveAll(sqlldrDir)
 		if err != nil {
 			utils.ErrExit("failed to remove sqlldr directory %q: %s", sqlldrDir, err)
@@ -370,19 +765,141 @@ func cleanImportState(state *ImportDataState, tasks []*ImportFileTask) {
 	}
 }
 
+/*
+getTableToPoolSize returns the number of batches imported at a time for the tables in --table-parallelism.
+Each of the batches holds a connection while it is imported, so the number is capped at --parallel-jobs,
+which is the size of the connection pool shared by all the tables.
+*/
+func getTableToPoolSize(tasks []*ImportFileTask) map[string]int {
+	result := make(map[string]int)
+	if len(tableParallelism) == 0 {
+		return result
+	}
+	tableNames := importFileTasksToTableNames(tasks)
+	for name, poolSize := range tableParallelism {
+		matchedTableNames, err := matchTableNames([]string{name}, tableNames)
+		if err != nil {
+			utils.ErrExit("Error: Invalid table-parallelism: %s", err)
+		}
+		if poolSize > tconf.Parallelism {
+			utils.PrintAndLog("parallelism of table %s is limited to %d, the number of parallel jobs", matchedTableNames[0], tconf.Parallelism)
+			poolSize = tconf.Parallelism
+		}
+		result[matchedTableNames[0]] = poolSize
+	}
+	log.Infof("parallelism of the tables: %v", result)
+	return result
+}
+
+func importTask(state *ImportDataState, task *ImportFileTask, progressReporter *ImportDataProgressReporter, poolSize int) {
+	// The code can produce `poolSize` number of batches of the file at a time. But, it can consume only
+	// `parallelism` number of batches at a time, of all the files being imported.
+	batchImportPool := pool.New().WithMaxGoroutines(poolSize)
+
+	if noSplitFiles {
+		err := state.DiscardUncommittedBatches(task.FilePath, task.TableName)
+		if err != nil {
+			utils.ErrExit("discarding uncommitted batches of table %q: %s", task.TableName, err)
+		}
+	}
+	startTableSpan(task)
+	totalProgressAmount := getTotalProgressAmount(task)
+	progressReporter.ImportFileStarted(task, totalProgressAmount)
+	importedProgressAmount := getImportedProgressAmount(task, state)
+	progressReporter.ImportFileResumed(task, importedProgressAmount)
+	if overallProgressTracker != nil {
+		overallProgressTracker.AddSnapshotProgressAmount(importedProgressAmount)
+	}
+	updateProgressFn := func(progressAmount int64) {
+		progressReporter.AddProgressAmount(task, progressAmount)
+		if overallProgressTracker != nil {
+			overallProgressTracker.AddSnapshotProgressAmount(progressAmount)
+		}
+	}
+	importFile(state, task, updateProgressFn, batchImportPool)
+	batchImportPool.Wait() // Wait for the file import to finish.
+	if restartFileImportIfRequired(state, task) {
+		batchImportPool = pool.New().WithMaxGoroutines(poolSize)
+		importFile(state, task, updateProgressFn, batchImportPool)
+		batchImportPool.Wait()
+		if errs := takeFailedBatches(task.FilePath, task.TableName); len(errs) > 0 {
+			utils.ErrExit("import %q into %s failed even after restarting it: %s", task.FilePath, task.TableName, errs[0])
+		}
+	}
+	progressReporter.FileImportDone(task) // Remove the progress-bar for the file.
+	endTableSpan(task)
+}
+
+func getImportSettings() *ImportSettings {
+	return &ImportSettings{
+		TargetDBType:     tconf.TargetDBType,
+		DBName:           tconf.DBName,
+		Schema:           tconf.Schema,
+		TableList:        tconf.TableList,
+		ExcludeTableList: tconf.ExcludeTableList,
+		EnableUpsert:     tconf.EnableUpsert,
+		NoSplitFiles:     noSplitFiles,
+		BatchSize:        batchSize,
+		BatchSizeBytes:   batchSizeBytes,
+		MinBatchSize:     minBatchSize,
+	}
+}
+
+func saveImportSettings(state *ImportDataState) {
+	err := state.SaveImportSettings(getImportSettings())
+	if err != nil {
+		utils.ErrExit("failed to save the import settings: %s", err)
+	}
+}
+
+// checkImportSettings asks for a confirmation before resuming the import with the settings changed since the last run.
+func checkImportSettings(state *ImportDataState) {
+	prevSettings, err := state.GetImportSettings()
+	if err != nil {
+		utils.ErrExit("failed to read the settings of the last import: %s", err)
+	}
+	if prevSettings != nil {
+		batchingChanges := getImportSettings().BatchingDiff(prevSettings)
+		if len(batchingChanges) > 0 {
+			utils.ErrExit("The following settings have changed since the last run of the import:\n  %s\n"+
+				"The import with --no-split-files must be resumed with the same settings of the batches. "+
+				"Rerun with the earlier settings, or use --start-clean to start the import afresh.", strings.Join(batchingChanges, "\n  "))
+		}
+		changes := getImportSettings().Diff(prevSettings)
+		if len(changes) > 0 {
+			utils.PrintAndLog("The following settings have changed since the last run of the import:\n  %s",
+				strings.Join(changes, "\n  "))
+			utils.PrintAndLog("Resuming with them may import the data inconsistently. Use --start-clean to start the import afresh.")
+			if utils.DoNotPrompt {
+				utils.PrintAndLog("Resuming the import with the changed settings in %d seconds (Ctrl-C to abort)...", settingsChangeGracePeriodSec)
+				time.Sleep(time.Duration(settingsChangeGracePeriodSec) * time.Second)
+			} else if !term.IsTerminal(int(os.Stdin.Fd())) {
+				utils.ErrExit("Aborting import as the settings have changed. Rerun with --yes to resume with the changed settings.")
+			} else if !utils.AskPrompt("Do you want to resume the import with the changed settings") {
+				utils.ErrExit("Aborting import.")
+			}
+		}
+	}
+	saveImportSettings(state)
+}
+
 func getImportBatchArgsProto(tableName, filePath string) *tgtdb.ImportBatchArgs {
 	columns := TableToColumnNames[tableName]
-	columns, err := tdb.IfRequiredQuoteColumnNames(tableName, columns)
+	if mapping := tableToColumnMapping[tableName]; mapping != nil {
+		columns = mapping.TargetColumns
+	}
+	columns, err := tdb.IfRequiredQuoteColumnNames(getTargetTableName(tableName), columns)
 	if err != nil {
 		utils.ErrExit("if required quote column names: %s", err)
 	}
 	// If `columns` is unset at this point, no attribute list is passed in the COPY command.
 	fileFormat := dataFileDescriptor.FileFormat
-	if fileFormat == datafile.SQL {
+	if fileFormat == datafile.SQL || fileFormat == datafile.PARQUET {
+		// the rows of the parquet files are converted to the TEXT format, see datafile.ParquetDataFile
 		fileFormat = datafile.TEXT
 	}
 	importBatchArgsProto := &tgtdb.ImportBatchArgs{
-		TableName:  tableName,
+		TableName:  getTargetTableName(tableName),
 		Columns:    columns,
 		FileFormat: fileFormat,
 		Delimiter:  dataFileDescriptor.Delimiter,
@@ -390,12 +907,21 @@ func getImportBatchArgsProto(tableName, filePath string) *tgtdb.ImportBatchArgs
 		QuoteChar:  dataFileDescriptor.QuoteChar,
 		EscapeChar: dataFileDescriptor.EscapeChar,
 		NullString: dataFileDescriptor.NullString,
+
+		OnPrimaryKeyConflict: onPrimaryKeyConflict,
+		CopyOptions:          copyOptions,
+	}
+	for _, tableColumn := range sqlldrNoNullIfColumns {
+		table, column, _ := strings.Cut(tableColumn, ".")
+		if strings.EqualFold(table, tableName) {
+			importBatchArgsProto.NoNullIfColumns = append(importBatchArgsProto.NoNullIfColumns, column)
+		}
 	}
 	log.Infof("ImportBatchArgs: %v", spew.Sdump(importBatchArgsProto))
 	return importBatchArgsProto
 }
 
-func importFile(state *ImportDataState, task *ImportFileTask, updateProgressFn func(int64)) {
+func importFile(state *ImportDataState, task *ImportFileTask, updateProgressFn func(int64), batchImportPool *pool.Pool) {
 
 	origDataFile := task.FilePath
 	importBatchArgsProto := getImportBatchArgsProto(task.TableName, task.FilePath)
@@ -405,24 +931,34 @@ func importFile(state *ImportDataState, task *ImportFileTask, updateProgressFn f
 	if err != nil {
 		utils.ErrExit("preparing for file import: %s", err)
 	}
+	err = state.SaveImportBatchArgs(task.FilePath, task.TableName, importBatchArgsProto)
+	if err != nil {
+		utils.ErrExit("saving the import batch args of table %q: %s", task.TableName, err)
+	}
 	log.Infof("Collect all interrupted/remaining splits.")
 	pendingBatches, lastBatchNumber, lastOffset, fileFullySplit, err := state.Recover(task.FilePath, task.TableName)
 	if err != nil {
 		utils.ErrExit("recovering state for table %q: %s", task.TableName, err)
 	}
 	for _, batch := range pendingBatches {
-		submitBatch(batch, updateProgressFn, importBatchArgsProto)
+		submitBatch(batchImportPool, batch, updateProgressFn, importBatchArgsProto)
 	}
 	if !fileFullySplit {
-		splitFilesForTable(state, origDataFile, task.TableName, lastBatchNumber, lastOffset, updateProgressFn, importBatchArgsProto)
+		splitFilesForTable(state, origDataFile, task.TableName, lastBatchNumber, lastOffset, updateProgressFn, importBatchArgsProto, batchImportPool)
 	}
 }
 
 func splitFilesForTable(state *ImportDataState, filePath string, t string,
-	lastBatchNumber int64, lastOffset int64, updateProgressFn func(int64), importBatchArgsProto *tgtdb.ImportBatchArgs) {
+	lastBatchNumber int64, lastOffset int64, updateProgressFn func(int64), importBatchArgsProto *tgtdb.ImportBatchArgs,
+	batchImportPool *pool.Pool) {
 	log.Infof("Split data file %q: tableName=%q, largestSplit=%v, largestOffset=%v", filePath, t, lastBatchNumber, lastOffset)
 	batchNum := lastBatchNumber + 1
 	numLinesTaken := lastOffset
+	// set again if the splitting stops at --max-rows-per-table in this run
+	err := state.SetFileImportMaxRows(filePath, t, 0)
+	if err != nil {
+		utils.ErrExit("clearing the max rows of the import of table %q: %s", t, err)
+	}
 
 	reader, err := dataStore.Open(filePath)
 	if err != nil {
@@ -441,13 +977,71 @@ func splitFilesForTable(state *ImportDataState, filePath string, t string,
 		utils.ErrExit("skipping line for offset=%d: %v", lastOffset, err)
 	}
 
+	var rowTypeChecker *RowTypeChecker
+	if strictTypeCheck {
+		rowTypeChecker, err = NewRowTypeChecker(t, importBatchArgsProto.Columns)
+		if err != nil {
+			utils.ErrExit("preparing strict type check for table %q: %s", t, err)
+		}
+	}
+
 	var readLineErr error = nil
 	var line string
 	var batchWriter *BatchWriter
+	numRejectedLines := 0
 	header := ""
 	if dataFileDescriptor.HasHeader {
 		header = dataFile.GetHeader()
 	}
+	convertLine := func(line string) (string, error) {
+		convertedLine, err := lineTransformerChain.Transform(t, line)
+		if err == nil {
+			convertedLine, err = valueConverter.ConvertRow(t, TableToColumnNames[t], convertedLine) // can't use importBatchArgsProto.Columns as to use case insenstiive column names
+		}
+		if err == nil {
+			convertedLine, err = tableToColumnRedaction[t].redactRowValues(convertedLine)
+		}
+		if err == nil {
+			// the values are converted as per the exported columns, and then aligned with importBatchArgsProto.Columns
+			convertedLine = tableToColumnMapping[t].dropRowValues(convertedLine)
+		}
+		if err == nil {
+			err = checkValueSizes(convertedLine)
+		}
+		if err == nil {
+			err = rowTypeChecker.Check(convertedLine)
+		}
+		return convertedLine, err
+	}
+	lineReader := NewLineReader(dataFile, convertLine, numConversionWorkers)
+	defer lineReader.Stop()
+	maxBatchBytes := getMaxBatchSizeInBytes()
+	// The bytes of the lines in the current batch. The lines are read ahead of the batch with the
+	// conversion workers, hence the bytes read from the data file can't be used.
+	batchBytesRead := dataFile.GetBytesRead()
+	// Lines read ahead of the current batch to decide whether it should be cut before the trailing
+	// lines of the file (see `minBatchSize`). They are consumed before reading further.
+	var lookahead []*splitLine
+	var lookaheadBytes int64
+	readAheadToEnd := false
+	cutBeforeTail := false
+	// whether the splitting stopped at --max-rows-per-table before the end of the file
+	limitReached := false
+	nextLine := func() *splitLine {
+		var next *splitLine
+		if len(lookahead) == 0 {
+			next = lineReader.Next()
+		} else {
+			next = lookahead[0]
+			lookahead = lookahead[1:]
+			lookaheadBytes -= next.numBytes
+		}
+		batchBytesRead += next.numBytes
+		return next
+	}
+	batchBytes := func() int64 {
+		return batchBytesRead
+	}
 	for readLineErr == nil {
 
 		if batchWriter == nil {
@@ -464,71 +1058,221 @@ func splitFilesForTable(state *ImportDataState, filePath string, t string,
 			}
 		}
 
-		line, readLineErr = dataFile.NextLine()
+		var next *splitLine
+		taken := rowLimiter.Take(t)
+		if taken {
+			next = nextLine()
+		} else {
+			next = &splitLine{err: io.EOF}
+			limitReached = true
+		}
+		line, readLineErr = next.line, next.err
 		if readLineErr == nil || (readLineErr == io.EOF && line != "") {
 			// handling possible case: last dataline(i.e. EOF) but no newline char at the end
 			numLinesTaken += 1
 		}
 		if line != "" {
-			table := batchWriter.tableName
-			line, err = valueConverter.ConvertRow(table, TableToColumnNames[table], line) // can't use importBatchArgsProto.Columns as to use case insenstiive column names
+			convertedLine, err := next.converted, next.convertErr
+			if errors.Is(err, tgtdb.ErrUnparseableValue) || errors.Is(err, datafile.ErrRejectLine) ||
+				errors.Is(err, tgtdb.ErrLossyValue) || errors.Is(err, tgtdb.ErrValueTooLarge) {
+				log.Warnf("rejecting line number=%d for table %q in file %s: %s", numLinesTaken, t, filePath, err)
+				err = state.RecordRejectedRow(filePath, t, line, err.Error())
+				if err != nil {
+					utils.ErrExit("recording rejected line number=%d for table %q: %s", numLinesTaken, t, err)
+				}
+				convertedLine, err = "", nil
+				numRejectedLines++
+			}
 			if err != nil {
 				utils.ErrExit("transforming line number=%d for table %q in file %s: %s", batchWriter.NumRecordsWritten+1, t, filePath, err)
 			}
+			line = convertedLine
+		}
+		if taken && line == "" {
+			rowLimiter.GiveBack(t)
 		}
 		err = batchWriter.WriteRecord(line)
 		if err != nil {
 			utils.ErrExit("Write to batch %d: %s", batchNum, err)
 		}
-		if batchWriter.NumRecordsWritten == batchSize ||
-			dataFile.GetBytesRead() >= tdb.MaxBatchSizeInBytes() ||
+		if minBatchSize > 0 && readLineErr == nil && !readAheadToEnd &&
+			batchWriter.NumRecordsWritten == batchSize-minBatchSize {
+			// Read ahead to check whether filling up the batch would leave a last batch smaller than `minBatchSize`.
+			for int64(len(lookahead)) < 2*minBatchSize && !readAheadToEnd {
+				aheadLine := lineReader.Next()
+				lookahead = append(lookahead, aheadLine)
+				lookaheadBytes += aheadLine.numBytes
+				readAheadToEnd = aheadLine.err != nil
+			}
+			if readAheadToEnd && lookahead[len(lookahead)-1].err == io.EOF {
+				tailRows := int64(len(lookahead))
+				if lookahead[len(lookahead)-1].line == "" {
+					tailRows--
+				}
+				cutBeforeTail = isCutBeforeTail(batchWriter.NumRecordsWritten, tailRows, lookaheadBytes, maxBatchBytes)
+				if cutBeforeTail {
+					log.Infof("cutting batch %d of %q before the last %d lines", batchNum, filePath, tailRows)
+				}
+			}
+		}
+		if cutBeforeTail || batchWriter.NumRecordsWritten == batchSize || batchBytes() >= maxBatchBytes ||
 			readLineErr != nil {
+			cutBeforeTail = false
 
 			isLastBatch := false
 			if readLineErr == io.EOF {
-				isLastBatch = true
+				isLastBatch = !limitReached
 			} else if readLineErr != nil {
 				utils.ErrExit("read line from data file %q: %s", filePath, readLineErr)
 			}
+			if limitReached && batchWriter.NumRecordsWritten == 0 {
+				err = batchWriter.Discard()
+				if err != nil {
+					utils.ErrExit("discarding empty batch %d: %s", batchNum, err)
+				}
+				break
+			}
 
 			offsetEnd := numLinesTaken
-			batch, err := batchWriter.Done(isLastBatch, offsetEnd, dataFile.GetBytesRead())
+			batch, err := batchWriter.Done(isLastBatch, offsetEnd, batchBytes())
 			if err != nil {
 				utils.ErrExit("finalizing batch %d: %s", batchNum, err)
 			}
 			batchWriter = nil
-			dataFile.ResetBytesRead()
-			submitBatch(batch, updateProgressFn, importBatchArgsProto)
+			// bytes of the lines read ahead are accounted in the next batch, as they are consumed.
+			batchBytesRead = 0
+			submitBatch(batchImportPool, batch, updateProgressFn, importBatchArgsProto)
 
 			if !isLastBatch {
 				batchNum += 1
 			}
 		}
 	}
+	if limitReached {
+		err = state.SetFileImportMaxRows(filePath, t, maxRowsPerTable)
+		if err != nil {
+			utils.ErrExit("recording the max rows of the import of table %q: %s", t, err)
+		}
+		utils.PrintAndLog("PARTIAL import of table %q: stopped at line %d of %q, as --max-rows-per-table %d is reached",
+			t, numLinesTaken, filePath, maxRowsPerTable)
+	}
+	if numRejectedLines > 0 {
+		utils.PrintAndLog("%d rows of table %q with values that can't be imported are written to %s",
+			numRejectedLines, t, state.GetRejectedRowsFilePath(filePath, t))
+	}
 	log.Infof("splitFilesForTable: done splitting data file %q for table %q", filePath, t)
 }
 
-func executePostImportDataSqls() {
+/*
+executePostImportDataSqls runs the statements of postdata.sql, which set the resume values of the sequences.
+They are independent of each other and are run in parallel (--post-data-parallelism) after the session
+setup statements (SET/SELECT) of the file are run on each connection. The statements executed successfully
+are checkpointed in the import data state so that a re-run skips them.
+*/
+func executePostImportDataSqls(state *ImportDataState) {
 	sequenceFilePath := filepath.Join(exportDir, "data", "postdata.sql")
-	if utils.FileOrFolderExists(sequenceFilePath) {
-		fmt.Printf("setting resume value for sequences %10s\n", "")
-		executeSqlFile(sequenceFilePath, "SEQUENCE", func(_, _ string) bool { return false })
+	if !utils.FileOrFolderExists(sequenceFilePath) {
+		return
 	}
+	fmt.Printf("setting resume value for sequences %10s\n", "")
+	checkpoint, err := state.GetPostDataCheckpoint()
+	if err != nil {
+		utils.ErrExit("loading the post data checkpoint: %s", err)
+	}
+	var sessionStmts, stmts []sqlInfo
+	for _, sqlInfo := range createSqlStrInfoArray(sequenceFilePath, "SEQUENCE") {
+		if isSetOrSelectStmt(sqlInfo.stmt) {
+			sessionStmts = append(sessionStmts, sqlInfo)
+		} else if checkpoint.IsExecuted(sqlInfo.formattedStmt) {
+			log.Infof("skipping already executed post data statement: %s", sqlInfo.stmt)
+		} else {
+			stmts = append(stmts, sqlInfo)
+		}
+	}
+	if len(stmts) == 0 {
+		log.Infof("all the post data statements are already executed")
+		return
+	}
+
+	stmtsCh := make(chan sqlInfo, len(stmts))
+	for _, sqlInfo := range stmts {
+		stmtsCh <- sqlInfo
+	}
+	close(stmtsCh)
+	workers := pool.New().WithMaxGoroutines(postDataParallelism)
+	for i := 0; i < postDataParallelism && i < len(stmts); i++ {
+		workers.Go(func() {
+			var conn *pgx.Conn
+			defer func() {
+				if conn != nil {
+					conn.Close(context.Background())
+				}
+			}()
+			for sqlInfo := range stmtsCh {
+				if conn == nil {
+					conn = newTargetConn()
+					for _, sessionStmt := range sessionStmts {
+						err := executeSqlStmtWithRetries(&conn, sessionStmt, "SEQUENCE")
+						if err != nil {
+							utils.ErrExit("run session setup statement %q of post data: %s", sessionStmt.stmt, err)
+						}
+					}
+				}
+				err := executeSqlStmtWithRetries(&conn, sqlInfo, "SEQUENCE")
+				if err != nil {
+					conn.Close(context.Background())
+					conn = nil
+					continue
+				}
+				err = checkpoint.MarkExecuted(sqlInfo.formattedStmt)
+				if err != nil {
+					utils.ErrExit("checkpointing post data statement: %s", err)
+				}
+			}
+		})
+	}
+	workers.Wait()
 }
 
-func submitBatch(batch *Batch, updateProgressFn func(int64), importBatchArgsProto *tgtdb.ImportBatchArgs) {
+func isSetOrSelectStmt(stmt string) bool {
+	return strings.HasPrefix(strings.ToUpper(stmt), "SET ") || strings.HasPrefix(strings.ToUpper(stmt), "SELECT ")
+}
+
+// checkValueSizes returns an error wrapping tgtdb.ErrValueTooLarge if any value of the row
+// is larger than --max-value-size-bytes.
+func checkValueSizes(row string) error {
+	if maxValueSizeBytes <= 0 || int64(len(row)) <= maxValueSizeBytes {
+		return nil // none of the values can be larger than the row
+	}
+	values, err := splitRowValues(row)
+	if err != nil {
+		return nil // malformed rows are reported by the target db
+	}
+	for i, value := range values {
+		if int64(len(value)) > maxValueSizeBytes {
+			return fmt.Errorf("%w: value of column %d is %d bytes, larger than --max-value-size-bytes %d",
+				tgtdb.ErrValueTooLarge, i+1, len(value), maxValueSizeBytes)
+		}
+	}
+	return nil
+}
+
+func submitBatch(batchImportPool *pool.Pool, batch *Batch, updateProgressFn func(int64), importBatchArgsProto *tgtdb.ImportBatchArgs) {
 	batchImportPool.Go(func() {
-		// There are `poolSize` number of competing go-routines trying to invoke COPY.
+		// There are `poolSize` number of competing go-routines (per file being imported) trying to invoke COPY.
 		// But the `connPool` will allow only `parallelism` number of connections to be
 		// used at a time. Thus limiting the number of concurrent COPYs to `parallelism`.
+		importThrottler.Acquire()
+		startTime := time.Now()
 		importBatch(batch, importBatchArgsProto)
+		importThrottler.Release(time.Since(startTime))
 		if reportProgressInBytes {
 			updateProgressFn(batch.ByteCount)
 		} else {
 			updateProgressFn(batch.RecordCount)
 		}
 	})
-	log.Infof("Queued batch: %s", spew.Sdump(batch))
+	batch.logger().Infof("Queued batch: %s", spew.Sdump(batch))
 }
 
 func importBatch(batch *Batch, importBatchArgsProto *tgtdb.ImportBatchArgs) {
@@ -536,80 +1280,196 @@ func importBatch(batch *Batch, importBatchArgsProto *tgtdb.ImportBatchArgs) {
 	if err != nil {
 		utils.ErrExit("marking batch %d as pending: %s", batch.Number, err)
 	}
-	log.Infof("Importing %q", batch.FilePath)
+	logger := batch.logger()
+	logger.Infof("Importing %q", batch.FilePath)
 
 	importBatchArgs := *importBatchArgsProto
 	importBatchArgs.FilePath = batch.FilePath
 	importBatchArgs.RowsPerTransaction = batch.OffsetEnd - batch.OffsetStart
 
+	span := getTableSpan(batch.BaseFilePath, batch.TableName).StartChild("import batch")
+	defer span.End()
+	span.SetAttribute("batch_number", batch.Number)
+	span.SetAttribute("rows", batch.RecordCount)
+	span.SetAttribute("bytes", batch.ByteCount)
+
 	var rowsAffected int64
+	var attemptStart time.Time
 	sleepIntervalSec := 0
-	for attempt := 0; attempt < COPY_MAX_RETRY_COUNT; attempt++ {
+	for attempt := 0; attempt <= copyMaxRetries; attempt++ { // the first attempt and then the retries
+		attemptStart = time.Now()
 		rowsAffected, err = tdb.ImportBatch(batch, &importBatchArgs, exportDir)
-		if err == nil || tdb.IsNonRetryableCopyError(err) {
+		span.SetAttribute("retries", attempt)
+		if err == nil || tdb.IsNonRetryableCopyError(err) || attempt == copyMaxRetries {
 			break
 		}
-		log.Warnf("COPY FROM file %q: %s", batch.FilePath, err)
-		sleepIntervalSec += 10
-		if sleepIntervalSec > MAX_SLEEP_SECOND {
-			sleepIntervalSec = MAX_SLEEP_SECOND
-		}
-		log.Infof("sleep for %d seconds before retrying the file %s (attempt %d)",
-			sleepIntervalSec, batch.FilePath, attempt)
+		logger.Warnf("COPY FROM file %q: %s", batch.FilePath, err)
+		sleepIntervalSec = getCopyRetrySleepIntervalSec(sleepIntervalSec, copyRetryBackoff)
+		logger.Infof("sleep for %d seconds (%s backoff) before retrying the file %s (retry %d of %d)",
+			sleepIntervalSec, copyRetryBackoff, batch.FilePath, attempt+1, copyMaxRetries)
 		time.Sleep(time.Duration(sleepIntervalSec) * time.Second)
 	}
-	log.Infof("%q => %d rows affected", batch.FilePath, rowsAffected)
+	logger.Infof("%q => %d rows affected", batch.FilePath, rowsAffected)
+	span.SetAttribute("rows_affected", rowsAffected)
+	if err != nil && restartFileAfterBatchFailures > 0 {
+		// the file is restarted or the import is aborted once all the batches of the file are done
+		logger.Errorf("import %q into %s: %s", batch.FilePath, batch.TableName, err)
+		span.SetError(err)
+		recordFailedBatch(batch, err)
+		return
+	}
 	if err != nil {
-		utils.ErrExit("import %q into %s: %s", batch.FilePath, batch.TableName, err)
+		span.SetError(err)
+		span.End()
+		utils.ErrExit("import %q into %s: %s\nThe batch can be retried alone with `import data retry-batch --table %s --batch %d`",
+			batch.FilePath, batch.TableName, err, batch.TableName, batch.Number)
+	}
+	if !batch.alreadyImported {
+		// the batches imported by an earlier run take no time, and would skew the latencies
+		recordBatchLatency(time.Since(attemptStart), rowsAffected, batch.ByteCount)
 	}
 	err = batch.MarkDone()
 	if err != nil {
 		utils.ErrExit("marking batch %q as done: %s", batch.FilePath, err)
 	}
+	checkBatchRowsAffected(batch, rowsAffected)
 }
 
-func newTargetConn() *pgx.Conn {
-	conn, err := pgx.Connect(context.Background(), tconf.GetConnectionUri())
-	if err != nil {
-		utils.WaitChannel <- 1
-		<-utils.WaitChannel
-		utils.ErrExit("connect to target db: %s", err)
+type RowCountMismatch struct {
+	TableName    string `json:"table_name"`
+	FilePath     string `json:"file_path"`
+	BatchNumber  int64  `json:"batch_number"`
+	ExpectedRows int64  `json:"expected_rows"`
+	RowsAffected int64  `json:"rows_affected"`
+}
+
+// Batches whose rows affected by the import differ from their rows, reported in the import summary.
+var rowCountMismatches []*RowCountMismatch
+var rowCountMismatchesMutex sync.Mutex
+
+/*
+checkBatchRowsAffected compares the rows affected by the import of the batch with its rows, as the rows can be lost
+silently, e.g. when they are discarded by a trigger. A mismatch is warned about, or aborts the import with
+--strict-row-counts. The batch is already imported, hence it isn't imported again when the import is resumed.
+*/
+func checkBatchRowsAffected(batch *Batch, rowsAffected int64) {
+	if onPrimaryKeyConflict == tgtdb.ON_PRIMARY_KEY_CONFLICT_SKIP {
+		return // the conflicting rows are skipped
+	}
+	// the offsets also count the lines of the data file rejected or dropped by the line transformers
+	expectedRows := batch.RecordCount
+	if rowsAffected == expectedRows {
+		return
 	}
+	rowCountMismatchesMutex.Lock()
+	rowCountMismatches = append(rowCountMismatches, &RowCountMismatch{
+		TableName:    batch.TableName,
+		FilePath:     batch.BaseFilePath,
+		BatchNumber:  batch.Number,
+		ExpectedRows: expectedRows,
+		RowsAffected: rowsAffected,
+	})
+	rowCountMismatchesMutex.Unlock()
+	msg := fmt.Sprintf("import of batch %d of table %s (%q) affected %d rows, expected %d",
+		batch.Number, batch.TableName, batch.FilePath, rowsAffected, expectedRows)
+	if strictRowCounts {
+		utils.ErrExit("%s", msg)
+	}
+	batch.logger().Warn(msg)
+}
 
-	setTargetSchema(conn)
-	return conn
+// isCutBeforeTail tells whether a batch of `batchRows` rows should be cut before the `tailRows` rows (of `tailBytes`
+// bytes) left in the data file. Filling up the batch to `batchSize` would leave fewer than `minBatchSize` rows for the
+// last batch, and the batch can't take them too. The rest of the file then makes the last batch, if it fits in one.
+func isCutBeforeTail(batchRows, tailRows, tailBytes, maxBatchBytes int64) bool {
+	lastBatchRows := batchRows + tailRows - batchSize
+	return lastBatchRows > 0 && lastBatchRows < minBatchSize && tailRows <= batchSize && tailBytes < maxBatchBytes
 }
 
-// TODO: Eventually get rid of this function in favour of TargetYugabyteDB.setTargetSchema().
-func setTargetSchema(conn *pgx.Conn) {
-	if sourceDBType == POSTGRESQL || tconf.Schema == YUGABYTEDB_DEFAULT_SCHEMA {
-		// For PG, schema name is already included in the object name.
-		// No need to set schema if importing in the default schema.
-		return
+// getMaxBatchSizeInBytes returns the size at which a batch is cut, --batch-size-bytes if it is within the limit of the target db.
+func getMaxBatchSizeInBytes() int64 {
+	if batchSizeBytes > 0 && batchSizeBytes < tdb.MaxBatchSizeInBytes() {
+		return batchSizeBytes
 	}
-	checkSchemaExistsQuery := fmt.Sprintf("SELECT count(schema_name) FROM information_schema.schemata WHERE schema_name = '%s'", tconf.Schema)
-	var cntSchemaName int
+	return tdb.MaxBatchSizeInBytes()
+}
 
-	if err := conn.QueryRow(context.Background(), checkSchemaExistsQuery).Scan(&cntSchemaName); err != nil {
-		utils.ErrExit("run query %q on target %q to check schema exists: %s", checkSchemaExistsQuery, tconf.Host, err)
-	} else if cntSchemaName == 0 {
-		utils.ErrExit("schema '%s' does not exist in target", tconf.Schema)
+// getCopyRetrySleepIntervalSec returns the sleep before the next retry of a COPY, given the previous one (0 for the first retry).
+func getCopyRetrySleepIntervalSec(prevSleepIntervalSec int, backoff string) int {
+	var sleepIntervalSec int
+	if backoff == COPY_BACKOFF_EXPONENTIAL && prevSleepIntervalSec > 0 {
+		sleepIntervalSec = prevSleepIntervalSec * 2
+	} else {
+		sleepIntervalSec = prevSleepIntervalSec + COPY_RETRY_SLEEP_STEP_SECOND
+	}
+	if sleepIntervalSec > MAX_SLEEP_SECOND {
+		sleepIntervalSec = MAX_SLEEP_SECOND
 	}
+	return sleepIntervalSec
+}
+
+func recordFailedBatch(batch *Batch, err error) {
+	failedBatchesMutex.Lock()
+	defer failedBatchesMutex.Unlock()
+	key := batch.BaseFilePath + batch.TableName
+	failedBatches[key] = append(failedBatches[key], fmt.Errorf("batch %d: %w", batch.Number, err))
+}
+
+func takeFailedBatches(filePath, tableName string) []error {
+	failedBatchesMutex.Lock()
+	defer failedBatchesMutex.Unlock()
+	errs := failedBatches[filePath+tableName]
+	delete(failedBatches, filePath+tableName)
+	return errs
+}
 
-	setSchemaQuery := fmt.Sprintf("SET SCHEMA '%s'", tconf.Schema)
-	_, err := conn.Exec(context.Background(), setSchemaQuery)
+// restartFileImportIfRequired cleans the local state of the file for it to be imported again from the beginning,
+// if --restart-file-after-batch-failures batches of the file failed. It aborts the import if fewer batches failed.
+// The batches imported successfully are recorded in the target db, and are skipped in the second attempt.
+func restartFileImportIfRequired(state *ImportDataState, task *ImportFileTask) bool {
+	errs := takeFailedBatches(task.FilePath, task.TableName)
+	if len(errs) == 0 {
+		return false
+	}
+	if len(errs) < restartFileAfterBatchFailures {
+		utils.ErrExit("import %q into %s: %s", task.FilePath, task.TableName, errs[0])
+	}
+	utils.PrintAndLog("Restarting the import of %q into %s from the beginning as %d of its batches failed. First failure: %s",
+		task.FilePath, task.TableName, len(errs), errs[0])
+	for _, err := range errs {
+		log.Errorf("failed batch of %q: %s", task.FilePath, err)
+	}
+	err := state.CleanLocalState(task.FilePath, task.TableName)
 	if err != nil {
-		utils.ErrExit("run query %q on target %q: %s", setSchemaQuery, tconf.Host, err)
+		utils.ErrExit("cleaning the import state of %q for restart: %s", task.FilePath, err)
 	}
+	restartedFilesMutex.Lock()
+	restartedFiles = append(restartedFiles, fmt.Sprintf("%s (%s)", task.FilePath, task.TableName))
+	restartedFilesMutex.Unlock()
+	return true
+}
 
-	if sourceDBType == ORACLE && enableOrafce {
-		// append oracle schema in the search_path for orafce
-		updateSearchPath := `SELECT set_config('search_path', current_setting('search_path') || ', oracle', false)`
-		_, err := conn.Exec(context.Background(), updateSearchPath)
-		if err != nil {
-			utils.ErrExit("unable to update search_path for orafce extension: %v", err)
-		}
+func newTargetConn() *pgx.Conn {
+	conn, err := pgx.Connect(context.Background(), tconf.GetConnectionUri())
+	if err != nil {
+		utils.WaitChannel <- 1
+		<-utils.WaitChannel
+		utils.ErrExit("connect to target db: %s", err)
+	}
+
+	targetDB := tdb
+	if targetDB == nil { // e.g. in import schema, which doesn't initialize the target db
+		targetDB = tgtdb.NewTargetDB(&tconf)
+	}
+	err = targetDB.SetTargetSchema(conn)
+	if err != nil {
+		utils.ErrExit("set target schema: %s", err)
 	}
+	err = tgtdb.SetApplicationName(conn, tconf.GetApplicationName(migrationUUID, tgtdb.APPLICATION_PHASE_DDL))
+	if err != nil {
+		log.Warnf("failed to set application_name: %s", err)
+	}
+	return conn
 }
 
 func dropIdx(conn *pgx.Conn, idxName string) {
@@ -636,11 +1496,15 @@ func executeSqlFile(file string, objType string, skipFn func(string, string) boo
 			conn = newTargetConn()
 		}
 
-		setOrSelectStmt := strings.HasPrefix(strings.ToUpper(sqlInfo.stmt), "SET ") ||
-			strings.HasPrefix(strings.ToUpper(sqlInfo.stmt), "SELECT ")
+		setOrSelectStmt := isSetOrSelectStmt(sqlInfo.stmt)
 		if !setOrSelectStmt && skipFn != nil && skipFn(objType, sqlInfo.stmt) {
 			continue
 		}
+		if !setOrSelectStmt && !isAllowedStmtType(sqlInfo.stmt) {
+			log.Infof("not executing statement of type %s, which is not allowed: %s", getStmtType(sqlInfo.stmt), sqlInfo.stmt)
+			disallowedSqlStmts = append(disallowedSqlStmts, sqlInfo.formattedStmt)
+			continue
+		}
 
 		err := executeSqlStmtWithRetries(&conn, sqlInfo, objType)
 		if err != nil {
@@ -650,6 +1514,54 @@ func executeSqlFile(file string, objType string, skipFn func(string, string) boo
 	}
 }
 
+// getDDLTimeout returns the timeout for a statement of the given object type, 0 for no timeout.
+func getDDLTimeout(objType string) time.Duration {
+	if (objType == "INDEX" || objType == "PARTITION_INDEX") && indexDDLTimeout > 0 {
+		return indexDDLTimeout
+	}
+	return ddlTimeout
+}
+
+// setStatementTimeout sets the statement_timeout of the session, after which the server cancels a statement.
+// 0 disables the timeout.
+func setStatementTimeout(conn *pgx.Conn, timeout time.Duration) error {
+	_, err := conn.Exec(context.Background(), fmt.Sprintf("SET statement_timeout = %d", timeout.Milliseconds()))
+	return err
+}
+
+// isStatementTimeout checks if the statement was cancelled by the server on statement_timeout,
+// or by pgx on the context deadline.
+func isStatementTimeout(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == QUERY_CANCELED_ERR_CODE
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// cleanupTimedOutIndex drops the INVALID index which a cancelled CREATE INDEX can leave behind.
+// It returns true if the index is valid, i.e. the statement completed just as the timeout fired.
+func cleanupTimedOutIndex(conn *pgx.Conn, sqlInfo sqlInfo) bool {
+	fullyQualifiedObjName, err := getIndexName(sqlInfo.stmt, sqlInfo.objName)
+	if err != nil {
+		utils.ErrExit("extract qualified index name from DDL [%v]: %v", sqlInfo.stmt, err)
+	}
+	var isValid bool
+	query := "SELECT indisvalid FROM pg_index WHERE indexrelid = to_regclass($1)"
+	err = conn.QueryRow(context.Background(), query, fullyQualifiedObjName).Scan(&isValid)
+	if err == pgx.ErrNoRows {
+		return false
+	} else if err != nil {
+		utils.ErrExit("check if index %q is valid: %v", fullyQualifiedObjName, err)
+	}
+	if isValid {
+		log.Infof("index %q is valid, the statement completed as it timed out", fullyQualifiedObjName)
+		return true
+	}
+	dropIdx(conn, fullyQualifiedObjName)
+	return false
+}
+
 func getIndexName(sqlQuery string, indexName string) (string, error) {
 	// Return the index name itself if it is aleady qualified with schema name
 	if len(strings.Split(indexName, ".")) == 2 {
@@ -661,7 +1573,7 @@ func getIndexName(sqlQuery string, indexName string) (string, error) {
 	for index, part := range parts {
 		if strings.EqualFold(part, "ON") {
 			tableName := parts[index+1]
-			schemaName := getTargetSchemaName(tableName)
+			schemaName := tconf.GetTargetSchemaName(tableName)
 			return fmt.Sprintf("%s.%s", schemaName, indexName), nil
 		}
 	}
@@ -671,20 +1583,51 @@ func getIndexName(sqlQuery string, indexName string) (string, error) {
 func executeSqlStmtWithRetries(conn **pgx.Conn, sqlInfo sqlInfo, objType string) error {
 	var err error
 	log.Infof("On %s run query:\n%s\n", tconf.Host, sqlInfo.formattedStmt)
+	numTimeouts := 0
+	numRetries := 0
 	for retryCount := 0; retryCount <= DDL_MAX_RETRY_COUNT; retryCount++ {
 		if retryCount > 0 { // Not the first iteration.
+			numRetries = retryCount
 			log.Infof("Sleep for 5 seconds before retrying for %dth time", retryCount)
 			time.Sleep(time.Second * 5)
 			log.Infof("RETRYING DDL: %q", sqlInfo.stmt)
 		}
-		_, err = (*conn).Exec(context.Background(), sqlInfo.formattedStmt)
+		timeout := getDDLTimeout(objType)
+		ctx, cancel := context.Background(), context.CancelFunc(func() {})
+		if ddlTimeout > 0 || indexDDLTimeout > 0 {
+			// set on every statement, as the timeout depends on the object type
+			err = setStatementTimeout(*conn, timeout)
+			if err != nil {
+				log.Warnf("failed to set statement_timeout: %s", err)
+			}
+		}
+		if timeout > 0 {
+			// in case the server doesn't cancel the statement, e.g. if it is unreachable
+			ctx, cancel = context.WithTimeout(ctx, timeout+DDL_TIMEOUT_GRACE_PERIOD)
+		}
+		_, err = (*conn).Exec(ctx, sqlInfo.formattedStmt)
+		cancel()
 		if err == nil {
 			utils.PrintSqlStmtIfDDL(sqlInfo.stmt, utils.GetObjectFileName(filepath.Join(exportDir, "schema"), objType))
 			return nil
 		}
 
 		log.Errorf("DDL Execution Failed for %q: %s", sqlInfo.formattedStmt, err)
-		if strings.Contains(strings.ToLower(err.Error()), "conflicts with higher priority transaction") {
+		if isStatementTimeout(err) {
+			numTimeouts++
+			err = fmt.Errorf("statement timed out after %s: %w", timeout, err)
+			(*conn).Close(context.Background())
+			*conn = newTargetConn()
+			if objType == "INDEX" || objType == "PARTITION_INDEX" {
+				if cleanupTimedOutIndex(*conn, sqlInfo) {
+					utils.PrintSqlStmtIfDDL(sqlInfo.stmt, utils.GetObjectFileName(filepath.Join(exportDir, "schema"), objType))
+					return nil
+				}
+			}
+			if numTimeouts <= DDL_TIMEOUT_MAX_RETRY_COUNT {
+				continue
+			}
+		} else if strings.Contains(strings.ToLower(err.Error()), "conflicts with higher priority transaction") {
 			// creating fresh connection
 			(*conn).Close(context.Background())
 			*conn = newTargetConn()
@@ -706,12 +1649,16 @@ func executeSqlStmtWithRetries(conn **pgx.Conn, sqlInfo sqlInfo, objType string)
 			continue
 		} else if missingRequiredSchemaObject(err) {
 			log.Infof("deffering execution of SQL: %s", sqlInfo.formattedStmt)
-			defferedSqlStmts = append(defferedSqlStmts, sqlInfo)
+			sqlStmtsMutex.Lock()
+			defferedSqlStmts = append(defferedSqlStmts, deferredSqlStmt{sqlInfo: sqlInfo, objType: objType})
+			sqlStmtsMutex.Unlock()
 		} else if isAlreadyExists(err.Error()) {
-			// pg_dump generates `CREATE SCHEMA public;` in the schemas.sql. Because the `public`
-			// schema already exists on the target YB db, the create schema statement fails with
-			// "already exists" error. Ignore the error.
-			if tconf.IgnoreIfExists || strings.EqualFold(strings.Trim(sqlInfo.stmt, " \n"), "CREATE SCHEMA public;") {
+			// Some statements, like the `CREATE SCHEMA public;` generated by pg_dump, are known to fail with
+			// "already exists" error on the target. Ignore the error for them.
+			if tconf.IgnoreIfExists {
+				err = nil
+			} else if isIdempotentStmt(sqlInfo.stmt) {
+				log.Infof("ignoring already exists error for idempotent statement: %s", sqlInfo.stmt)
 				err = nil
 			}
 		}
@@ -725,8 +1672,9 @@ func executeSqlStmtWithRetries(conn **pgx.Conn, sqlInfo sqlInfo, objType string)
 			color.Red(fmt.Sprintf("%s\n", err.Error()))
 			if tconf.ContinueOnError {
 				log.Infof("appending stmt to failedSqlStmts list: %s\n", utils.GetSqlStmtToPrint(sqlInfo.stmt))
-				errString := "/*\n" + err.Error() + "\n*/\n"
-				failedSqlStmts = append(failedSqlStmts, errString+sqlInfo.formattedStmt)
+				sqlStmtsMutex.Lock()
+				failedSqlStmts = append(failedSqlStmts, newFailedSqlStmt(sqlInfo, objType, err, numRetries))
+				sqlStmtsMutex.Unlock()
 			} else {
 				utils.ErrExit("error: %s\n", err)
 			}
@@ -735,15 +1683,6 @@ func executeSqlStmtWithRetries(conn **pgx.Conn, sqlInfo sqlInfo, objType string)
 	return err
 }
 
-// TODO: This function is a duplicate of the one in tgtdb/yb.go. Consolidate the two.
-func getTargetSchemaName(tableName string) string {
-	parts := strings.Split(tableName, ".")
-	if len(parts) == 2 {
-		return parts[0]
-	}
-	return tconf.Schema // default set to "public"
-}
-
 func prepareTableToColumns(tasks []*ImportFileTask) {
 	for _, task := range tasks {
 		table := task.TableName
@@ -806,4 +1745,37 @@ func init() {
 	registerCommonGlobalFlags(importDataCmd)
 	registerCommonImportFlags(importDataCmd)
 	registerImportDataFlags(importDataCmd)
+
+	importDataCmd.Flags().StringVar(&importConfigFile, "config-file", "",
+		"path of a JSON (.json) or YAML (.yaml/.yml) file with the values of the flags of the command, keyed by the flag names "+
+			"(e.g. target-db-host: localhost). The flags given on the command line take precedence over the file. "+
+			"The export-dir must be given on the command line")
+	importDataCmd.Flags().StringVar(&expectedSourceDBType, "source-db-type", "",
+		fmt.Sprintf("type of the source database the export-dir is expected to be exported from: %s. "+
+			"The import fails if the export-dir is of a different source database, e.g. when the wrong export-dir is given", supportedSourceDBTypes))
+	importDataCmd.Flags().StringSliceVar(&schemaMapSpecs, "schema-map", nil,
+		"target schema of the tables of a source schema, as <source_schema>:<target_schema>, instead of --target-db-schema. "+
+			"Can be repeated, or given as comma separated entries. The target schemas must exist. The source schema of the tables "+
+			"exported without debezium is not known, hence they are imported into --target-db-schema unless qualified with the schema. "+
+			"The import fails if a source schema has none of the exported tables")
+	importDataCmd.Flags().BoolVar(&tconf.DisableConstraintsDuringLoad, "disable-constraints-during-load", false,
+		"(YugabyteDB only) load the tables in any order with the foreign key checks and the triggers skipped by the import "+
+			"sessions (session_replication_role=replica), failing if the target doesn't support it, and then check the foreign "+
+			"keys of the imported tables for the violating rows (unless --validate-foreign-keys=false). The violations are "+
+			"reported in the import data summary. The constraints are not altered, and are enforced for the other sessions")
+	importDataCmd.Flags().BoolVar(&validateForeignKeys, "validate-foreign-keys", true,
+		"with --disable-constraints-during-load, check the foreign keys of the imported tables for the violating rows after the load")
+	importDataCmd.Flags().BoolVar(&verifyChecksums, "verify-checksums", false,
+		"verify the checksums of the data files recorded at the export before importing them, "+
+			"e.g. to detect the files truncated while transferring the export-dir")
+
+	// import data file has its own flags for these, which define the data file descriptor
+	importDataCmd.Flags().StringVar(&delimiter, "delimiter", "",
+		"character used as delimiter in the rows of the data files, overriding the one in the data file descriptor of the export-dir")
+	importDataCmd.Flags().StringVar(&quoteChar, "quote-char", "",
+		"character used to quote the values in the CSV data files, overriding the one in the data file descriptor of the export-dir")
+	importDataCmd.Flags().StringVar(&escapeChar, "escape-char", "",
+		"escape character of the CSV data files, overriding the one in the data file descriptor of the export-dir")
+	importDataCmd.Flags().StringVar(&nullString, "null-string", "",
+		"string that represents null value in the data files, overriding the one in the data file descriptor of the export-dir")
 }
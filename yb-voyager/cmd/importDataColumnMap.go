@@ -0,0 +1,279 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/datafile"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+const DROP_COLUMN = "-"
+
+var columnMapSpecs []string
+
+// Target column (or DROP_COLUMN) of the mapped source columns, by the table name as given in --column-map.
+var columnMaps map[string]map[string]string
+
+// Mapping of the exported columns to the target columns, by the table name, for the tables in --column-map.
+var tableToColumnMapping = make(map[string]*ColumnMapping)
+
+type ColumnMapping struct {
+	// target columns of the columns which are not dropped, in the order of the exported columns
+	TargetColumns []string
+	// whether each exported column is kept, nil if none of them is dropped
+	Keep []bool
+}
+
+// parseColumnMap parses the `<table>.<source_col>:<target_col>` (or `<table>.<source_col>:-` to drop the column)
+// entries of --column-map. The table name can be qualified with the schema name.
+func parseColumnMap(specs []string) (map[string]map[string]string, error) {
+	result := make(map[string]map[string]string)
+	for _, spec := range specs {
+		source, target, found := strings.Cut(spec, ":")
+		source, target = strings.TrimSpace(source), strings.TrimSpace(target)
+		i := strings.LastIndex(source, ".")
+		if !found || i <= 0 || i == len(source)-1 || target == "" {
+			return nil, fmt.Errorf("entry %q must be of the form <table>.<source_col>:<target_col> or <table>.<source_col>:%s", spec, DROP_COLUMN)
+		}
+		tableName, sourceColumn := source[:i], source[i+1:]
+		if result[tableName] == nil {
+			result[tableName] = make(map[string]string)
+		}
+		if _, ok := result[tableName][sourceColumn]; ok {
+			return nil, fmt.Errorf("column %s of table %s is mapped more than once", sourceColumn, tableName)
+		}
+		result[tableName][sourceColumn] = target
+	}
+	return result, nil
+}
+
+// getColumnMapping applies the column map of a table to its exported columns. All the mapped columns must be exported.
+func getColumnMapping(exportedColumns []string, columnMap map[string]string) (*ColumnMapping, error) {
+	var unknownColumns []string
+	for sourceColumn := range columnMap {
+		if !lo.ContainsBy(exportedColumns, func(c string) bool { return columnNamesMatch(c, sourceColumn) }) {
+			unknownColumns = append(unknownColumns, sourceColumn)
+		}
+	}
+	if len(unknownColumns) > 0 {
+		return nil, fmt.Errorf("columns %v are not in the exported columns %v", unknownColumns, exportedColumns)
+	}
+
+	mapping := &ColumnMapping{}
+	keep := make([]bool, len(exportedColumns))
+	for i, column := range exportedColumns {
+		target := column
+		for sourceColumn, targetColumn := range columnMap {
+			if columnNamesMatch(column, sourceColumn) {
+				target = targetColumn
+			}
+		}
+		if target == DROP_COLUMN {
+			continue
+		}
+		keep[i] = true
+		mapping.TargetColumns = append(mapping.TargetColumns, target)
+	}
+	if len(mapping.TargetColumns) == 0 {
+		return nil, fmt.Errorf("all the columns are dropped")
+	}
+	if len(mapping.TargetColumns) < len(exportedColumns) {
+		mapping.Keep = keep
+	}
+	return mapping, nil
+}
+
+// prepareColumnMappings resolves the column maps of the tables being imported, against their exported columns.
+func prepareColumnMappings(tasks []*ImportFileTask) {
+	tableNames := importFileTasksToTableNames(tasks)
+	for mapTableName, columnMap := range columnMaps {
+		tableName, found := lo.Find(tableNames, func(t string) bool { return tableNamesMatch(mapTableName, t) })
+		if !found {
+			log.Infof("table %s of --column-map is not being imported", mapTableName)
+			continue
+		}
+		if len(TableToColumnNames[tableName]) == 0 {
+			utils.ErrExit("Error: --column-map can't be applied to table %s as its exported columns are not known "+
+				"(the data file has no header)", tableName)
+		}
+		mapping, err := getColumnMapping(TableToColumnNames[tableName], columnMap)
+		if err != nil {
+			utils.ErrExit("Error: Invalid column-map for table %s: %s", tableName, err)
+		}
+		log.Infof("columns of table %s are mapped to %v", tableName, mapping.TargetColumns)
+		tableToColumnMapping[tableName] = mapping
+	}
+}
+
+// excludeGeneratedColumns drops the generated columns of the target tables from the columns imported into them,
+// as their values can't be given in the COPY.
+func excludeGeneratedColumns(tasks []*ImportFileTask) {
+	for _, tableName := range importFileTasksToTableNames(tasks) {
+		if len(TableToColumnNames[tableName]) == 0 {
+			continue // no attribute list is passed in the COPY, see getImportBatchArgsProto
+		}
+		generatedColumns, err := tdb.GetGeneratedColumns(getTargetTableName(tableName))
+		if err != nil {
+			utils.ErrExit("fetching the generated columns of table %s: %s", tableName, err)
+		}
+		mapping, excludedColumns := excludeColumns(tableToColumnMapping[tableName], TableToColumnNames[tableName], generatedColumns)
+		if len(excludedColumns) == 0 {
+			continue
+		}
+		if len(mapping.TargetColumns) == 0 {
+			utils.ErrExit("Error: all the imported columns of table %s are generated columns on the target: %v", tableName, excludedColumns)
+		}
+		utils.PrintAndLog("generated columns %v of table %s are excluded from the import", excludedColumns, tableName)
+		tableToColumnMapping[tableName] = mapping
+	}
+}
+
+// excludeColumns drops the given target columns from the mapping of the exported columns (the columns are
+// imported as they are if it is nil). It returns the new mapping along with the target columns dropped from it.
+func excludeColumns(mapping *ColumnMapping, exportedColumns []string, columns []string) (*ColumnMapping, []string) {
+	targetColumns, keep := exportedColumns, lo.Map(exportedColumns, func(_ string, _ int) bool { return true })
+	if mapping != nil {
+		targetColumns = mapping.TargetColumns
+		if mapping.Keep != nil {
+			keep = mapping.Keep
+		}
+	}
+	result := &ColumnMapping{Keep: make([]bool, len(keep))}
+	var excludedColumns []string
+	j := 0 // index of the target column of the next kept exported column
+	for i := range keep {
+		if !keep[i] {
+			continue
+		}
+		target := targetColumns[j]
+		j++
+		if lo.ContainsBy(columns, func(c string) bool { return columnNamesMatch(c, target) }) {
+			excludedColumns = append(excludedColumns, target)
+			continue
+		}
+		result.Keep[i] = true
+		result.TargetColumns = append(result.TargetColumns, target)
+	}
+	if len(excludedColumns) == 0 {
+		return mapping, nil
+	}
+	return result, excludedColumns
+}
+
+/*
+mapEventColumns applies the column map of the table to the fields and the key of a streamed event, renaming the
+mapped columns and removing the dropped ones, as the columns of the snapshot are. A dropped key column fails the
+event, as the row to update or delete can't be identified without it.
+*/
+func mapEventColumns(event *tgtdb.Event, tableName string) error {
+	mapTableName, found := lo.FindKeyBy(columnMaps, func(t string, _ map[string]string) bool { return tableNamesMatch(t, tableName) })
+	if !found {
+		return nil
+	}
+	mapColumns := func(m map[string]*string, isKey bool) (map[string]*string, error) {
+		if m == nil {
+			return nil, nil
+		}
+		result := make(map[string]*string, len(m))
+		for column, value := range m {
+			target := column
+			for sourceColumn, targetColumn := range columnMaps[mapTableName] {
+				if columnNamesMatch(column, sourceColumn) {
+					target = targetColumn
+				}
+			}
+			if target == DROP_COLUMN {
+				if isKey {
+					return nil, fmt.Errorf("key column %s is dropped by the column-map", column)
+				}
+				continue
+			}
+			result[target] = value
+		}
+		return result, nil
+	}
+	var err error
+	event.Fields, err = mapColumns(event.Fields, false)
+	if err != nil {
+		return err
+	}
+	event.Key, err = mapColumns(event.Key, true)
+	return err
+}
+
+// dropRowValues removes the values of the dropped columns from a row of the data file (after the value conversion).
+func (m *ColumnMapping) dropRowValues(row string) string {
+	if m == nil || m.Keep == nil {
+		return row
+	}
+	values, delimiter := splitRowFields(row)
+	if len(values) != len(m.Keep) {
+		return row // malformed rows are reported by the target db
+	}
+	return strings.Join(lo.Filter(values, func(_ string, i int) bool { return m.Keep[i] }), delimiter)
+}
+
+// columnNamesMatch compares the column names case insensitively, with or without the quotes.
+func columnNamesMatch(a, b string) bool {
+	return strings.EqualFold(strings.Trim(a, `"`), strings.Trim(b, `"`))
+}
+
+// splitRowFields splits a row of the data file (after the value conversion) into its fields as they are, returning
+// them with the delimiter to join them back with.
+func splitRowFields(row string) ([]string, string) {
+	delimiter := dataFileDescriptor.Delimiter
+	if delimiter == "" {
+		delimiter = "\t"
+	}
+	if dataFileDescriptor.FileFormat == datafile.CSV {
+		return splitCSVRowFields(row, delimiter[0], dataFileDescriptor.QuoteChar, dataFileDescriptor.EscapeChar), delimiter
+	}
+	return datafile.SplitTextRowFields(row, delimiter[0]), delimiter
+}
+
+// splitCSVRowFields splits a CSV row at the delimiters outside the quotes, retaining the fields as they are,
+// so that they can be joined back without changing the quoting.
+func splitCSVRowFields(row string, delimiter byte, quoteChar byte, escapeChar byte) []string {
+	if quoteChar == 0 {
+		quoteChar = '"'
+	}
+	if escapeChar == 0 {
+		escapeChar = quoteChar
+	}
+	var fields []string
+	inQuotes := false
+	start := 0
+	for i := 0; i < len(row); i++ {
+		switch {
+		case inQuotes && row[i] == escapeChar && escapeChar != quoteChar && i+1 < len(row):
+			i++ // the escaped character
+		case row[i] == quoteChar:
+			// a doubled quote within the quotes closes and reopens them
+			inQuotes = !inQuotes
+		case !inQuotes && row[i] == delimiter:
+			fields = append(fields, row[start:i])
+			start = i + 1
+		}
+	}
+	return append(fields, row[start:])
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/datafile"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+)
+
+func TestMapEventColumns(t *testing.T) {
+	assert := assert.New(t)
+	defer func(m map[string]map[string]string) { columnMaps = m }(columnMaps)
+	var err error
+	columnMaps, err = parseColumnMap([]string{"public.orders.cust_id:customer_id", "public.orders.legacy:-"})
+	assert.NoError(err)
+	str := func(s string) *string { return &s }
+
+	event := &tgtdb.Event{
+		Op:     "u",
+		Key:    map[string]*string{"id": str("1")},
+		Fields: map[string]*string{"CUST_ID": str("7"), "legacy": nil, "total": str("10")},
+	}
+	assert.NoError(mapEventColumns(event, "public.orders"))
+	assert.Equal(map[string]*string{"id": str("1")}, event.Key)
+	assert.Equal(map[string]*string{"customer_id": str("7"), "total": str("10")}, event.Fields)
+
+	// the events of the other tables are left as they are
+	event = &tgtdb.Event{Op: "c", Fields: map[string]*string{"cust_id": str("7")}}
+	assert.NoError(mapEventColumns(event, "public.items"))
+	assert.Equal(map[string]*string{"cust_id": str("7")}, event.Fields)
+
+	event = &tgtdb.Event{Op: "d", Key: map[string]*string{"legacy": str("1")}}
+	assert.ErrorContains(mapEventColumns(event, "orders"), "key column legacy is dropped")
+}
+
+func TestColumnMapping(t *testing.T) {
+	assert := assert.New(t)
+	columnMaps, err := parseColumnMap([]string{"public.orders.cust_id:customer_id", "public.orders.legacy:-", "items.Name : item_name"})
+	assert.NoError(err)
+	assert.Equal(map[string]map[string]string{
+		"public.orders": {"cust_id": "customer_id", "legacy": DROP_COLUMN},
+		"items":         {"Name": "item_name"},
+	}, columnMaps)
+	for _, spec := range []string{"orders:customer_id", "orders.cust_id", "orders.cust_id:", ".cust_id:id", "orders.:id"} {
+		_, err = parseColumnMap([]string{spec})
+		assert.Error(err, spec)
+	}
+	_, err = parseColumnMap([]string{"orders.a:b", "orders.a:-"})
+	assert.ErrorContains(err, "mapped more than once")
+
+	mapping, err := getColumnMapping([]string{"id", "cust_id", "legacy", "total"}, columnMaps["public.orders"])
+	assert.NoError(err)
+	assert.Equal([]string{"id", "customer_id", "total"}, mapping.TargetColumns)
+	assert.Equal([]bool{true, true, false, true}, mapping.Keep)
+	mapping, err = getColumnMapping([]string{"id", `"name"`}, columnMaps["items"])
+	assert.NoError(err)
+	assert.Equal([]string{"id", "item_name"}, mapping.TargetColumns)
+	assert.Nil(mapping.Keep)
+	_, err = getColumnMapping([]string{"id", "total"}, columnMaps["public.orders"])
+	assert.ErrorContains(err, "not in the exported columns")
+	_, err = getColumnMapping([]string{"legacy"}, map[string]string{"legacy": DROP_COLUMN})
+	assert.ErrorContains(err, "all the columns are dropped")
+
+	defer func(d *datafile.Descriptor) { dataFileDescriptor = d }(dataFileDescriptor)
+	mapping = &ColumnMapping{Keep: []bool{true, false, true}}
+	dataFileDescriptor = &datafile.Descriptor{FileFormat: datafile.TEXT}
+	assert.Equal("1\t3", mapping.dropRowValues("1\t2\t3"))
+	assert.Equal("1\t2", mapping.dropRowValues("1\t2")) // malformed rows are left as they are
+	dataFileDescriptor = &datafile.Descriptor{FileFormat: datafile.CSV, Delimiter: ",", QuoteChar: '"', EscapeChar: '"'}
+	assert.Equal(`1,"c,""d"""`, mapping.dropRowValues(`1,"a,b","c,""d"""`))
+	dataFileDescriptor = &datafile.Descriptor{FileFormat: datafile.CSV, Delimiter: ",", QuoteChar: '\'', EscapeChar: '\\'}
+	assert.Equal(`'x\',y',z`, mapping.dropRowValues(`'x\',y','dropped\'',z`))
+}
+
+func TestExcludeGeneratedColumns(t *testing.T) {
+	assert := assert.New(t)
+	exportedColumns := []string{"id", "price", "qty", "total", "note"}
+	// total is GENERATED ALWAYS AS (price * qty) STORED on the target
+	mapping, excludedColumns := excludeColumns(nil, exportedColumns, []string{"TOTAL"})
+	assert.Equal([]string{"total"}, excludedColumns)
+	assert.Equal([]string{"id", "price", "qty", "note"}, mapping.TargetColumns)
+	assert.Equal([]bool{true, true, true, false, true}, mapping.Keep)
+	defer func(d *datafile.Descriptor) { dataFileDescriptor = d }(dataFileDescriptor)
+	dataFileDescriptor = &datafile.Descriptor{FileFormat: datafile.TEXT}
+	assert.Equal("1\t2.5\t4\tnote", mapping.dropRowValues("1\t2.5\t4\t10.0\tnote"))
+
+	mapping, excludedColumns = excludeColumns(nil, exportedColumns, []string{"other"})
+	assert.Nil(excludedColumns)
+	assert.Nil(mapping)
+
+	// along with the columns mapped by --column-map
+	columnMapping, err := getColumnMapping(exportedColumns, map[string]string{"price": "unit_price", "note": DROP_COLUMN})
+	assert.NoError(err)
+	mapping, excludedColumns = excludeColumns(columnMapping, exportedColumns, []string{"total"})
+	assert.Equal([]string{"total"}, excludedColumns)
+	assert.Equal([]string{"id", "unit_price", "qty"}, mapping.TargetColumns)
+	assert.Equal([]bool{true, true, true, false, false}, mapping.Keep)
+	mapping, excludedColumns = excludeColumns(columnMapping, exportedColumns, nil)
+	assert.Nil(excludedColumns)
+	assert.Same(columnMapping, mapping)
+}
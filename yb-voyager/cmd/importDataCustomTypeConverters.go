@@ -0,0 +1,68 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/dbzm"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+var customTypeConverterSpecs []string
+
+// Type whose converter converts the values of the custom type, by the custom type name.
+var customTypeConverters map[string]string
+
+// parseCustomTypeConverters parses the `<type>:<converter_type>` entries of --custom-type-converters.
+func parseCustomTypeConverters(specs []string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, spec := range specs {
+		typeName, converterType, found := strings.Cut(spec, ":")
+		typeName, converterType = strings.TrimSpace(typeName), strings.TrimSpace(converterType)
+		if !found || typeName == "" || converterType == "" {
+			return nil, fmt.Errorf("entry %q must be of the form <type>:<converter_type>", spec)
+		}
+		if _, ok := result[typeName]; ok {
+			return nil, fmt.Errorf("type %s is given more than once", typeName)
+		}
+		result[typeName] = converterType
+	}
+	return result, nil
+}
+
+// registerCustomTypeConverters registers the converters of the target db for the custom types, with
+// dbzm.RegisterConverter, so that the value converter created after it picks them up.
+func registerCustomTypeConverters(tdb tgtdb.TargetDB) {
+	suite := tdb.GetDebeziumValueConverterSuite()
+	for typeName, converterType := range customTypeConverters {
+		fn, ok := suite[converterType]
+		if !ok {
+			converterTypes := lo.Keys(suite)
+			sort.Strings(converterTypes)
+			utils.ErrExit("Error: Invalid custom-type-converters: no converter of type %q for the target db. Supported types are: %v",
+				converterType, converterTypes)
+		}
+		log.Infof("values of type %s are converted as those of type %s", typeName, converterType)
+		dbzm.RegisterConverter(typeName, fn)
+	}
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCustomTypeConverters(t *testing.T) {
+	assert := assert.New(t)
+	converters, err := parseCustomTypeConverters([]string{"custom.Wkb:io.debezium.data.geometry.Geometry", " custom.Money : STRING"})
+	assert.NoError(err)
+	assert.Equal(map[string]string{"custom.Wkb": "io.debezium.data.geometry.Geometry", "custom.Money": "STRING"}, converters)
+	for _, spec := range []string{"custom.Wkb", "custom.Wkb:", ":STRING"} {
+		_, err = parseCustomTypeConverters([]string{spec})
+		assert.Error(err, spec)
+	}
+	_, err = parseCustomTypeConverters([]string{"custom.Wkb:BYTES", "custom.Wkb:STRING"})
+	assert.ErrorContains(err, "given more than once")
+}
@@ -42,7 +42,7 @@ var (
 	fileTableMapping      string
 	hasHeader             bool
 	importFileTasks       []*ImportFileTask
-	supportedFileFormats  = []string{datafile.CSV, datafile.TEXT}
+	supportedFileFormats  = []string{datafile.CSV, datafile.TEXT, datafile.PARQUET}
 	fileOpts              string
 	escapeChar            string
 	quoteChar             string
@@ -155,11 +155,18 @@ func checkImportDataFileFlags(cmd *cobra.Command) {
 	fileFormat = strings.ToLower(fileFormat)
 	checkFileFormat()
 	checkDataDirFlag()
+	checkParquetFileFlags()
 	setDefaultForDelimiter()
 	checkDelimiterFlag()
 	checkHasHeader()
 	checkAndParseEscapeAndQuoteChar()
 	setDefaultForNullString()
+	validateMaxRowsPerTableFlag()
+	validateTruncateTablesFlag()
+	validateSplitFilesDirFlag()
+	validateRedactColumnsFlag()
+	validateCopyOptionsFlag()
+	validateCopyRetryFlags()
 	validateTargetPassword(cmd)
 }
 
@@ -177,6 +184,19 @@ func checkFileFormat() {
 	}
 }
 
+// The rows of the parquet files are imported as TEXT, with the default delimiter and null string.
+func checkParquetFileFlags() {
+	if fileFormat != datafile.PARQUET {
+		return
+	}
+	if delimiter != "" {
+		utils.ErrExit("ERROR: --delimiter flag is invalid for %q format", fileFormat)
+	}
+	if nullString != "" {
+		utils.ErrExit("ERROR: --null-string flag is invalid for %q format", fileFormat)
+	}
+}
+
 func checkDataDirFlag() {
 	if dataDir == "" {
 		utils.ErrExit(`Error: required flag "data-dir" not set`)
@@ -190,6 +210,12 @@ func checkDataDirFlag() {
 	} else if strings.HasPrefix(dataDir, "https://") {
 		az.ValidateObjectURL(dataDir)
 		return
+	} else if strings.HasPrefix(dataDir, az.AZBLOB_URL_PREFIX) {
+		err := az.ValidateObjectURL(dataDir)
+		if err != nil {
+			utils.ErrExit("Error: Invalid data-dir %q: %s", dataDir, err)
+		}
+		return
 	}
 	if !utils.FileOrFolderExists(dataDir) {
 		utils.ErrExit("data-dir: %s doesn't exists!!", dataDir)
@@ -286,7 +312,7 @@ func setDefaultForNullString() {
 	switch fileFormat {
 	case datafile.CSV:
 		nullString = ""
-	case datafile.TEXT:
+	case datafile.TEXT, datafile.PARQUET:
 		nullString = "\\N"
 	default:
 		panic("unsupported file format")
@@ -300,7 +326,7 @@ func setDefaultForDelimiter() {
 	switch fileFormat {
 	case datafile.CSV:
 		delimiter = `,`
-	case datafile.TEXT:
+	case datafile.TEXT, datafile.PARQUET:
 		delimiter = `\t`
 	default:
 		panic("unsupported file format")
@@ -347,7 +373,9 @@ func init() {
 			"Note: data-dir can be a local directory or a cloud storage URL\n"+
 			"\tfor AWS S3, e.g. s3://<bucket-name>/<path-to-data-dir>\n"+
 			"\tfor GCS buckets, e.g. gs://<bucket-name>/<path-to-data-dir>\n"+
-			"\tfor Azure blob storage, e.g. https://<account_name>.blob.core.windows.net/<container_name>/<path-to-data-dir>")
+			"\tfor Azure blob storage, e.g. https://<account_name>.blob.core.windows.net/<container_name>/<path-to-data-dir>\n"+
+			"\t\tor azblob://<container_name>/<path-to-data-dir> with the account name in the AZURE_STORAGE_ACCOUNT env var\n"+
+			"The credentials are picked up from the default credential chain of the respective cloud SDK.")
 	err := importDataFileCmd.MarkFlagRequired("data-dir")
 	if err != nil {
 		utils.ErrExit("mark 'data-dir' flag required: %v", err)
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpreteEscapeSequences(t *testing.T) {
+	assert := assert.New(t)
+	for value, expected := range map[string]string{",": ",", `\t`: "\t", `\\`: `\`, "'": "'"} {
+		resolvedValue, ok := interpreteEscapeSequences(value)
+		assert.True(ok, value)
+		assert.Equal(expected, resolvedValue)
+	}
+	for _, value := range []string{"", "ab", "é", `\u00e9`, `\x`} {
+		_, ok := interpreteEscapeSequences(value)
+		assert.False(ok, value)
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+var validateForeignKeys bool
+
+// Foreign keys of the imported tables violated by their rows, as found after the load with --disable-constraints-during-load.
+var foreignKeyViolations []*tgtdb.ForeignKeyViolation
+
+/*
+validateForeignKeysAfterLoad checks the foreign keys of the imported tables for the rows loaded without a referenced row,
+as the import sessions skip the foreign key checks (and the triggers) with session_replication_role set to replica.
+The constraints themselves are never disabled, so there is nothing to re-enable on the target: the other sessions
+enforce them all along. The violations are reported in the import data summary rather than failing the import.
+*/
+func validateForeignKeysAfterLoad(tasks []*ImportFileTask) {
+	tableNames := getTargetTableNames(lo.Uniq(importFileTasksToTableNames(tasks)))
+	utils.PrintAndLog("validating the foreign keys of the imported tables")
+	violations, err := tdb.GetForeignKeyViolations(tableNames)
+	if err != nil {
+		utils.ErrExit("validating the foreign keys of the imported tables: %s", err)
+	}
+	for _, violation := range violations {
+		log.Warnf("foreign key %s of table %s is violated by %d rows, e.g. %v",
+			violation.ConstraintName, violation.TableName, violation.NumRows, violation.SampleRows)
+	}
+	foreignKeyViolations = violations
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsCutBeforeTail(t *testing.T) {
+	assert := assert.New(t)
+	prevBatchSize, prevMinBatchSize := batchSize, minBatchSize
+	defer func() { batchSize, minBatchSize = prevBatchSize, prevMinBatchSize }()
+	batchSize, minBatchSize = 100, 10
+	maxBatchBytes := int64(1000)
+
+	// the rest fits in the batch
+	assert.False(isCutBeforeTail(90, 10, 100, maxBatchBytes))
+	// filling up the batch would leave a last batch of 5 rows, the rest makes a batch of 15 rows instead
+	assert.True(isCutBeforeTail(90, 15, 150, maxBatchBytes))
+	// the last batch would have enough rows
+	assert.False(isCutBeforeTail(90, 20, 200, maxBatchBytes))
+	// the rest doesn't fit in a batch by its bytes
+	assert.False(isCutBeforeTail(90, 15, maxBatchBytes, maxBatchBytes))
+	// the rest doesn't fit in a batch by its rows
+	batchSize, minBatchSize = 10, 8
+	assert.True(isCutBeforeTail(2, 10, 100, maxBatchBytes))
+	assert.False(isCutBeforeTail(2, 11, 110, maxBatchBytes))
+}
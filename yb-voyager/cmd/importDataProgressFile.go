@@ -0,0 +1,175 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+const PROGRESS_FILE_UPDATE_INTERVAL = 5 * time.Second
+
+var progressOutput string
+var progressFilePath string
+
+// ImportProgress is the document written to --progress-file with --progress-output json.
+type ImportProgress struct {
+	UpdatedAt string           `json:"updated_at"`
+	Tables    []*TableProgress `json:"tables"`
+}
+
+// TableProgress is the progress of the import of a data file. The amounts are in rows, or in bytes
+// (for import data file) as given by `Unit`.
+type TableProgress struct {
+	TableName  string  `json:"table_name"`
+	FilePath   string  `json:"file_path"`
+	Unit       string  `json:"unit"`
+	Imported   int64   `json:"imported"`
+	Total      int64   `json:"total"` // 0 if not known
+	Percent    float64 `json:"percent"`
+	EtaSeconds int64   `json:"eta_seconds"` // -1 if not known
+	Status     string  `json:"status"`
+}
+
+type fileProgressState struct {
+	task      *ImportFileTask
+	startTime time.Time
+	// progress amount already imported in the earlier runs, excluded from the rate for the ETA
+	resumedAmount int64
+	done          bool
+}
+
+func validateProgressOutputFlags() {
+	switch progressOutput {
+	case PROGRESS_OUTPUT_BAR:
+		if progressFilePath != "" {
+			utils.ErrExit("Error: --progress-file requires --progress-output %s", PROGRESS_OUTPUT_JSON)
+		}
+	case PROGRESS_OUTPUT_JSON:
+		if progressFilePath == "" {
+			utils.ErrExit("Error: --progress-output %s requires --progress-file", PROGRESS_OUTPUT_JSON)
+		}
+	default:
+		utils.ErrExit("Error: Invalid progress-output: %q. Supported values are: %s", progressOutput, validProgressOutputs)
+	}
+}
+
+// EnableProgressFile makes the reporter write the progress of the files to `path` periodically, and whenever a file is imported.
+func (pr *ImportDataProgressReporter) EnableProgressFile(path string) {
+	pr.Lock()
+	pr.progressFilePath = path
+	pr.fileProgressStates = make(map[int]*fileProgressState)
+	pr.Unlock()
+	go func() {
+		for range time.Tick(PROGRESS_FILE_UPDATE_INTERVAL) {
+			pr.writeProgressFile()
+		}
+	}()
+}
+
+// The callers hold the lock.
+func (pr *ImportDataProgressReporter) getFileProgressState(task *ImportFileTask) *fileProgressState {
+	if pr.fileProgressStates == nil {
+		return nil
+	}
+	state, ok := pr.fileProgressStates[task.ID]
+	if !ok {
+		state = &fileProgressState{task: task, startTime: time.Now()}
+		pr.fileProgressStates[task.ID] = state
+	}
+	return state
+}
+
+func (pr *ImportDataProgressReporter) writeProgressFile() {
+	pr.Lock()
+	if pr.progressFilePath == "" {
+		pr.Unlock()
+		return
+	}
+	progress := pr.getImportProgress(time.Now())
+	pr.Unlock()
+
+	pr.progressFileMutex.Lock()
+	defer pr.progressFileMutex.Unlock()
+	bytes, err := json.MarshalIndent(progress, "", "    ")
+	if err != nil {
+		log.Errorf("marshal import progress: %s", err)
+		return
+	}
+	// written to a temporary file and renamed, so that the readers never see a partially written file
+	tmpPath := filepath.Join(filepath.Dir(pr.progressFilePath), "."+filepath.Base(pr.progressFilePath)+".tmp")
+	err = os.WriteFile(tmpPath, bytes, 0644)
+	if err == nil {
+		err = os.Rename(tmpPath, pr.progressFilePath)
+	}
+	if err != nil {
+		log.Errorf("write import progress to %q: %s", pr.progressFilePath, err)
+	}
+}
+
+// The callers hold the lock.
+func (pr *ImportDataProgressReporter) getImportProgress(now time.Time) *ImportProgress {
+	unit := "rows"
+	if reportProgressInBytes {
+		unit = "bytes"
+	}
+	progress := &ImportProgress{UpdatedAt: now.Format(time.RFC3339), Tables: []*TableProgress{}}
+	for id, state := range pr.fileProgressStates {
+		tableProgress := &TableProgress{
+			TableName:  state.task.TableName,
+			FilePath:   state.task.FilePath,
+			Unit:       unit,
+			Imported:   pr.currProgressAmount[id],
+			Total:      pr.totalProgressAmount[id],
+			EtaSeconds: -1,
+			Status:     "in-progress",
+		}
+		if state.done {
+			tableProgress.Status = "done"
+		}
+		if tableProgress.Total > 0 {
+			tableProgress.Percent = float64(tableProgress.Imported) * 100 / float64(tableProgress.Total)
+			tableProgress.EtaSeconds = getEtaSeconds(now.Sub(state.startTime), tableProgress.Imported-state.resumedAmount,
+				tableProgress.Total-tableProgress.Imported)
+		}
+		progress.Tables = append(progress.Tables, tableProgress)
+	}
+	slices.SortFunc(progress.Tables, func(a, b *TableProgress) bool {
+		if a.TableName != b.TableName {
+			return a.TableName < b.TableName
+		}
+		return a.FilePath < b.FilePath
+	})
+	return progress
+}
+
+// getEtaSeconds estimates the time to import the `remaining` amount at the rate `imported` was imported in `elapsed`.
+func getEtaSeconds(elapsed time.Duration, imported, remaining int64) int64 {
+	if remaining <= 0 {
+		return 0
+	}
+	if imported <= 0 || elapsed <= 0 {
+		return -1
+	}
+	return int64(elapsed.Seconds() * float64(remaining) / float64(imported))
+}
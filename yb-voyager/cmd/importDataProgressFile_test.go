@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEtaSeconds(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(int64(30), getEtaSeconds(10*time.Second, 100, 300))
+	assert.Equal(int64(0), getEtaSeconds(10*time.Second, 100, 0))
+	assert.Equal(int64(-1), getEtaSeconds(10*time.Second, 0, 300)) // nothing imported in this run yet
+	assert.Equal(int64(-1), getEtaSeconds(0, 100, 300))
+}
@@ -18,6 +18,7 @@ package cmd
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/vbauerster/mpb/v8"
@@ -25,35 +26,79 @@ import (
 	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
 )
 
+/*
+ImportDataProgressReporter reports the progress of the import of the files. The progress of all the files of a table
+(e.g. of a table exported in many chunks) is shown on a single progress bar of the table, while the progress file
+has the progress of each file.
+*/
 type ImportDataProgressReporter struct {
 	sync.Mutex
 	disablePb           bool
 	progress            *mpb.Progress
-	progressBars        map[int]*mpb.Bar
+	progressBars        map[string]*mpb.Bar // by the table name
 	totalProgressAmount map[int]int64
+	currProgressAmount  map[int]int64
+	throttleStatus      atomic.Value // string; read by the progress bar decorators while rendering
+	overallProgressFn   func() string
+
+	// of the files added to the import, by the table name
+	tableTotalProgressAmount map[string]int64
+	tableNumPendingFiles     map[string]int
+
+	// with --progress-output json
+	progressFilePath   string
+	fileProgressStates map[int]*fileProgressState
+	progressFileMutex  sync.Mutex // serializes the writes of the progress file
 }
 
 func NewImportDataProgressReporter(disablePb bool) *ImportDataProgressReporter {
 	pr := &ImportDataProgressReporter{
 		disablePb:           disablePb,
 		progress:            mpb.New(),
-		progressBars:        make(map[int]*mpb.Bar),
+		progressBars:        make(map[string]*mpb.Bar),
 		totalProgressAmount: make(map[int]int64),
+		currProgressAmount:  make(map[int]int64),
+
+		tableTotalProgressAmount: make(map[string]int64),
+		tableNumPendingFiles:     make(map[string]int),
 	}
 	return pr
 }
 
+// AddFileToImport adds the file to the total progress of its table. All the files of a table must be added
+// before the import of any of them starts, so that the progress bar of the table doesn't complete early.
+func (pr *ImportDataProgressReporter) AddFileToImport(task *ImportFileTask, totalProgressAmount int64) {
+	pr.Lock()
+	defer pr.Unlock()
+	pr.addFileToImport(task, totalProgressAmount)
+}
+
+func (pr *ImportDataProgressReporter) addFileToImport(task *ImportFileTask, totalProgressAmount int64) {
+	if _, ok := pr.totalProgressAmount[task.ID]; ok {
+		return
+	}
+	pr.totalProgressAmount[task.ID] = totalProgressAmount
+	pr.tableTotalProgressAmount[task.TableName] += totalProgressAmount
+	pr.tableNumPendingFiles[task.TableName]++
+}
+
 func (pr *ImportDataProgressReporter) ImportFileStarted(task *ImportFileTask, totalProgressAmount int64) {
 	pr.Lock()
 	defer pr.Unlock()
 
+	pr.addFileToImport(task, totalProgressAmount)
+	pr.currProgressAmount[task.ID] = 0
+	pr.getFileProgressState(task)
 	if pr.disablePb {
 		fmt.Printf("File %s: import started\n", task.FilePath)
 		return
 	}
 	log.Infof("Import started for file %s, total progress: %v", task.FilePath, totalProgressAmount)
+	if pr.progressBars[task.TableName] != nil {
+		return // another file of the table
+	}
 
-	bar := pr.progress.AddBar(totalProgressAmount,
+	bar := pr.progress.AddBar(pr.tableTotalProgressAmount[task.TableName],
 		mpb.BarFillerClearOnComplete(),
 		mpb.BarRemoveOnComplete(),
 		mpb.PrependDecorators(
@@ -66,30 +111,126 @@ func (pr *ImportDataProgressReporter) ImportFileStarted(task *ImportFileTask, to
 			decor.OnComplete(
 				decor.AverageETA(decor.ET_STYLE_GO), "",
 			),
+			decor.Any(func(decor.Statistics) string {
+				status, _ := pr.throttleStatus.Load().(string)
+				if status == "" {
+					return ""
+				}
+				return fmt.Sprintf(" [throttled: %s]", status)
+			}),
+			decor.Any(func(decor.Statistics) string {
+				if pr.overallProgressFn == nil {
+					return ""
+				}
+				return fmt.Sprintf(" [overall: %s]", pr.overallProgressFn())
+			}),
 		),
 	)
-	pr.progressBars[task.ID] = bar
-	pr.totalProgressAmount[task.ID] = totalProgressAmount
+	pr.progressBars[task.TableName] = bar
+}
+
+// ImportFileResumed tells the user how much of the file was imported in the earlier runs before adding it to the progress.
+func (pr *ImportDataProgressReporter) ImportFileResumed(task *ImportFileTask, importedProgressAmount int64) {
+	if importedProgressAmount <= 0 {
+		return
+	}
+	pr.Lock()
+	totalProgressAmount := pr.totalProgressAmount[task.ID]
+	if state := pr.getFileProgressState(task); state != nil {
+		state.resumedAmount = clampProgressAmount(0, totalProgressAmount, importedProgressAmount)
+	}
+	pr.Unlock()
+	utils.PrintAndLog("%s", getResumeMessage(task.TableName, importedProgressAmount, totalProgressAmount, reportProgressInBytes))
+	pr.AddProgressAmount(task, importedProgressAmount)
 }
 
 func (pr *ImportDataProgressReporter) AddProgressAmount(task *ImportFileTask, progressAmount int64) {
 	pr.Lock()
 	defer pr.Unlock()
 
+	progressAmount = clampProgressAmount(pr.currProgressAmount[task.ID], pr.totalProgressAmount[task.ID], progressAmount)
+	pr.currProgressAmount[task.ID] += progressAmount
 	if pr.disablePb {
 		return
 	}
-	progressBar := pr.progressBars[task.ID]
+	progressBar := pr.progressBars[task.TableName]
 	progressBar.IncrInt64(progressAmount)
 }
 
+// clampProgressAmount limits the progress amount to be added so that the progress never exceeds the total,
+// which can otherwise happen as the amounts are accounted at batch boundaries. A non-positive total is unknown.
+func clampProgressAmount(currProgressAmount, totalProgressAmount, progressAmount int64) int64 {
+	if totalProgressAmount <= 0 || currProgressAmount+progressAmount <= totalProgressAmount {
+		return progressAmount
+	}
+	if currProgressAmount >= totalProgressAmount {
+		return 0
+	}
+	return totalProgressAmount - currProgressAmount
+}
+
+func getResumeMessage(tableName string, importedProgressAmount, totalProgressAmount int64, inBytes bool) string {
+	unit := "rows"
+	if inBytes {
+		unit = "bytes"
+	}
+	if totalProgressAmount <= 0 {
+		return fmt.Sprintf("Table %s: resuming import (%d %s already imported)", tableName, importedProgressAmount, unit)
+	}
+	if importedProgressAmount > totalProgressAmount {
+		importedProgressAmount = totalProgressAmount
+	}
+	percentage := float64(importedProgressAmount) * 100 / float64(totalProgressAmount)
+	return fmt.Sprintf("Table %s: resuming from %.2f%% (%d of %d %s already imported)",
+		tableName, percentage, importedProgressAmount, totalProgressAmount, unit)
+}
+
 func (pr *ImportDataProgressReporter) FileImportDone(task *ImportFileTask) {
+	defer pr.writeProgressFile() // after releasing the lock
 	pr.Lock()
 	defer pr.Unlock()
+	if state := pr.getFileProgressState(task); state != nil {
+		state.done = true
+	}
+	remainingProgressAmount := pr.totalProgressAmount[task.ID] - pr.currProgressAmount[task.ID]
+	if remainingProgressAmount > 0 {
+		pr.currProgressAmount[task.ID] = pr.totalProgressAmount[task.ID]
+	}
+	pr.tableNumPendingFiles[task.TableName]--
+	tableImportDone := pr.tableNumPendingFiles[task.TableName] <= 0
 	if pr.disablePb {
+		if !tableImportDone {
+			utils.PrintAndLog("Table %s: import of file %s completed", task.TableName, task.FilePath)
+			return
+		}
 		utils.PrintAndLog("Table %s: import completed", task.TableName)
+		if pr.overallProgressFn != nil {
+			utils.PrintAndLog("Overall progress: %s", pr.overallProgressFn())
+		}
 		return
 	}
-	progressBar := pr.progressBars[task.ID]
-	progressBar.SetCurrent(pr.totalProgressAmount[task.ID])
+	progressBar := pr.progressBars[task.TableName]
+	if tableImportDone {
+		progressBar.SetCurrent(pr.tableTotalProgressAmount[task.TableName])
+	} else if remainingProgressAmount > 0 {
+		progressBar.IncrInt64(remainingProgressAmount)
+	}
+}
+
+// UpdateThrottleStatus records the latest decision taken by the ImportThrottler. An empty status means that
+// the import is no longer throttled.
+func (pr *ImportDataProgressReporter) UpdateThrottleStatus(status string) {
+	pr.throttleStatus.Store(status)
+	if pr.disablePb {
+		if status == "" {
+			fmt.Printf("Import throttling ended\n")
+		} else {
+			fmt.Printf("Import throttling: %s\n", status)
+		}
+	}
+}
+
+// SetOverallProgressFn shows the overall progress of the migration, as returned by fn, along with the progress of each file.
+func (pr *ImportDataProgressReporter) SetOverallProgressFn(fn func() string) {
+	pr.overallProgressFn = fn
 }
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vbauerster/mpb/v8"
+)
+
+func TestImportDataProgressReporterTableFiles(t *testing.T) {
+	pr := NewImportDataProgressReporter(false)
+	pr.progress = mpb.New(mpb.WithOutput(io.Discard))
+	tasks := []*ImportFileTask{
+		{ID: 0, FilePath: "/data/orders_1.csv", TableName: "orders"},
+		{ID: 1, FilePath: "/data/orders_2.csv", TableName: "orders"},
+		{ID: 2, FilePath: "/data/items.csv", TableName: "items"},
+	}
+	for _, task := range tasks {
+		pr.AddFileToImport(task, 100)
+	}
+	pr.ImportFileStarted(tasks[0], 100)
+	pr.ImportFileStarted(tasks[1], 100)
+	pr.ImportFileStarted(tasks[2], 100)
+	// one progress bar for all the files of a table
+	assert.Len(t, pr.progressBars, 2)
+	ordersBar := pr.progressBars["orders"]
+
+	pr.AddProgressAmount(tasks[0], 60)
+	pr.AddProgressAmount(tasks[1], 30)
+	assert.Equal(t, int64(90), ordersBar.Current())
+	pr.FileImportDone(tasks[0])
+	assert.Equal(t, int64(130), ordersBar.Current())
+	assert.False(t, ordersBar.Completed())
+	pr.FileImportDone(tasks[1])
+	assert.Equal(t, int64(200), ordersBar.Current())
+	assert.Equal(t, int64(100), pr.currProgressAmount[tasks[1].ID])
+	pr.FileImportDone(tasks[2])
+	pr.progress.Wait()
+}
+
+func TestClampProgressAmount(t *testing.T) {
+	assert := assert.New(t)
+	testcases := []struct {
+		curr, total, amount int64
+		expected            int64
+	}{
+		{0, 100, 40, 40},
+		{40, 100, 60, 60},
+		{90, 100, 20, 10},  // last batch overshoots the total
+		{100, 100, 5, 0},   // already complete
+		{120, 100, 5, 0},   // already-imported amount exceeded the total
+		{0, 0, 30, 30},     // unknown total
+		{50, -1, 30, 30},   // unknown total
+		{0, 100, 150, 100}, // resumed amount exceeds the total
+	}
+	for _, tc := range testcases {
+		assert.Equal(tc.expected, clampProgressAmount(tc.curr, tc.total, tc.amount), "%+v", tc)
+	}
+}
+
+func TestResumeAccounting(t *testing.T) {
+	assert := assert.New(t)
+	total, curr := int64(1000), int64(0)
+	// already imported in earlier runs, followed by the remaining batches, the last one rounded up
+	for _, amount := range []int64{600, 200, 150, 100} {
+		curr += clampProgressAmount(curr, total, amount)
+	}
+	assert.Equal(total, curr)
+
+	assert.Equal("Table foo: resuming from 60.00% (600 of 1000 rows already imported)", getResumeMessage("foo", 600, 1000, false))
+	assert.Equal("Table foo: resuming from 100.00% (1000 of 1000 bytes already imported)", getResumeMessage("foo", 1200, 1000, true))
+	assert.Equal("Table foo: resuming import (600 rows already imported)", getResumeMessage("foo", 600, -1, false))
+}
@@ -0,0 +1,181 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/datafile"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+var redactColumnSpecs []string
+var redactPlaceholder string
+
+// Redacted columns by the table name as given in --redact-columns.
+var redactColumns map[string][]string
+
+// Redacted exported columns by the table name, for the tables in --redact-columns.
+var tableToColumnRedaction = make(map[string]*ColumnRedaction)
+
+type ColumnRedaction struct {
+	// whether each exported column is redacted
+	Redact []bool
+	// the value written in place of the non-null values of the redacted columns
+	Value string
+}
+
+// parseRedactColumns parses the `<table>.<column>` entries of --redact-columns. The table name can be
+// qualified with the schema name.
+func parseRedactColumns(specs []string) (map[string][]string, error) {
+	result := make(map[string][]string)
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		i := strings.LastIndex(spec, ".")
+		if i <= 0 || i == len(spec)-1 {
+			return nil, fmt.Errorf("entry %q must be of the form <table>.<column>", spec)
+		}
+		tableName, column := spec[:i], spec[i+1:]
+		if !lo.Contains(result[tableName], column) {
+			result[tableName] = append(result[tableName], column)
+		}
+	}
+	return result, nil
+}
+
+// getColumnRedaction marks the redacted columns among the exported columns. All of them must be exported.
+func getColumnRedaction(exportedColumns []string, columns []string, value string) (*ColumnRedaction, error) {
+	redaction := &ColumnRedaction{Redact: make([]bool, len(exportedColumns)), Value: value}
+	for _, column := range columns {
+		i := slices.IndexFunc(exportedColumns, func(c string) bool { return columnNamesMatch(c, column) })
+		if i == -1 {
+			return nil, fmt.Errorf("column %s is not in the exported columns %v", column, exportedColumns)
+		}
+		redaction.Redact[i] = true
+	}
+	return redaction, nil
+}
+
+// prepareColumnRedactions resolves the redacted columns of the tables being imported, against their exported columns.
+func prepareColumnRedactions(tasks []*ImportFileTask) {
+	tableNames := importFileTasksToTableNames(tasks)
+	for redactTableName, columns := range redactColumns {
+		tableName, found := lo.Find(tableNames, func(t string) bool { return tableNamesMatch(redactTableName, t) })
+		if !found {
+			log.Infof("table %s of --redact-columns is not being imported", redactTableName)
+			continue
+		}
+		if len(TableToColumnNames[tableName]) == 0 {
+			utils.ErrExit("Error: --redact-columns can't be applied to table %s as its exported columns are not known "+
+				"(the data file has no header)", tableName)
+		}
+		value, err := formatRedactPlaceholder(redactPlaceholder)
+		if err != nil {
+			utils.ErrExit("Error: Invalid redact-placeholder: %s", err)
+		}
+		redaction, err := getColumnRedaction(TableToColumnNames[tableName], columns, value)
+		if err != nil {
+			utils.ErrExit("Error: Invalid redact-columns for table %s: %s", tableName, err)
+		}
+		utils.PrintAndLog("redacting the columns %v of table %s", columns, tableName)
+		tableToColumnRedaction[tableName] = redaction
+	}
+}
+
+// formatRedactPlaceholder returns the placeholder as a value of the data files: the null value if it is
+// empty, otherwise quoted (CSV) or escaped (TEXT) so that it is read as a single value by COPY.
+func formatRedactPlaceholder(placeholder string) (string, error) {
+	if placeholder == "" {
+		return getNullValue(), nil
+	}
+	if dataFileDescriptor.FileFormat == datafile.CSV {
+		quoteChar := `"`
+		if dataFileDescriptor.QuoteChar != 0 {
+			quoteChar = string(dataFileDescriptor.QuoteChar)
+		}
+		return quoteChar + strings.ReplaceAll(placeholder, quoteChar, quoteChar+quoteChar) + quoteChar, nil
+	}
+	delimiter := dataFileDescriptor.Delimiter
+	if delimiter == "" {
+		delimiter = "\t"
+	}
+	if strings.Contains(placeholder, delimiter) {
+		return "", fmt.Errorf("the placeholder %q contains the delimiter of the data files", placeholder)
+	}
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`).Replace(placeholder), nil
+}
+
+// getNullValue returns the representation of the null value in the data files.
+func getNullValue() string {
+	if dataFileDescriptor.NullString != "" {
+		return dataFileDescriptor.NullString
+	}
+	if dataFileDescriptor.FileFormat == datafile.CSV {
+		return ""
+	}
+	return `\N`
+}
+
+// redactRowValues replaces the non-null values of the redacted columns in a row of the data file (after the value
+// conversion). The rows whose values can't be aligned with the exported columns are rejected, instead of being
+// imported without the redaction.
+func (r *ColumnRedaction) redactRowValues(row string) (string, error) {
+	if r == nil {
+		return row, nil
+	}
+	values, delimiter := splitRowFields(row)
+	if len(values) != len(r.Redact) {
+		return "", fmt.Errorf("redact columns: the row has %d values instead of %d: %w", len(values), len(r.Redact), datafile.ErrRejectLine)
+	}
+	for i, value := range values {
+		if r.Redact[i] && !isNullValue(value) {
+			values[i] = r.Value
+		}
+	}
+	return strings.Join(values, delimiter), nil
+}
+
+/*
+redactEventValues replaces the non-null values of the redacted columns of the table among the fields of a streamed
+event, after the value conversion, formatted as the string values of the event are. An empty placeholder is NULL,
+as in the data files. The key of the event is left as it is, as it identifies the row to update or delete.
+*/
+func redactEventValues(event *tgtdb.Event, tableName string, formatIfRequired bool) error {
+	redactTableName, found := lo.FindKeyBy(redactColumns, func(t string, _ []string) bool { return tableNamesMatch(t, tableName) })
+	if !found {
+		return nil
+	}
+	var placeholder *string
+	if redactPlaceholder != "" {
+		value, err := tdb.GetDebeziumValueConverterSuite()["STRING"](redactPlaceholder, formatIfRequired)
+		if err != nil {
+			return fmt.Errorf("format redact placeholder: %w", err)
+		}
+		placeholder = &value
+	}
+	for column, value := range event.Fields {
+		if value != nil && lo.ContainsBy(redactColumns[redactTableName], func(c string) bool { return columnNamesMatch(c, column) }) {
+			event.Fields[column] = placeholder
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/datafile"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+)
+
+func TestColumnRedaction(t *testing.T) {
+	assert := assert.New(t)
+	redactColumns, err := parseRedactColumns([]string{"public.users.email", "public.users.ssn", "public.users.email", "items.Secret"})
+	assert.NoError(err)
+	assert.Equal(map[string][]string{"public.users": {"email", "ssn"}, "items": {"Secret"}}, redactColumns)
+	for _, spec := range []string{"users", ".email", "users."} {
+		_, err = parseRedactColumns([]string{spec})
+		assert.Error(err, spec)
+	}
+	_, err = getColumnRedaction([]string{"id", "email"}, redactColumns["public.users"], "")
+	assert.ErrorContains(err, "not in the exported columns")
+
+	defer func(d *datafile.Descriptor) { dataFileDescriptor = d }(dataFileDescriptor)
+	exportedColumns := []string{"id", `"EMAIL"`, "name", "ssn"}
+	dataFileDescriptor = &datafile.Descriptor{FileFormat: datafile.TEXT}
+	value, err := formatRedactPlaceholder("")
+	assert.NoError(err)
+	redaction, err := getColumnRedaction(exportedColumns, redactColumns["public.users"], value)
+	assert.NoError(err)
+	assert.Equal([]bool{false, true, false, true}, redaction.Redact)
+	row, err := redaction.redactRowValues("1\ta@b.com\tfoo\t\\N")
+	assert.NoError(err)
+	assert.Equal("1\t\\N\tfoo\t\\N", row)
+	redaction.Value, err = formatRedactPlaceholder(`redacted\x`)
+	assert.NoError(err)
+	row, err = redaction.redactRowValues("2\t\\N\tbar\t123-45-6789")
+	assert.NoError(err)
+	assert.Equal("2\t\\N\tbar\tredacted\\\\x", row) // nulls are left as they are
+	_, err = formatRedactPlaceholder("a\tb")
+	assert.ErrorContains(err, "contains the delimiter")
+	// the rows which can't be aligned with the exported columns are rejected
+	_, err = redaction.redactRowValues("3\tfoo")
+	assert.ErrorIs(err, datafile.ErrRejectLine)
+	// a delimiter escaped with a backslash is within the value
+	dataFileDescriptor = &datafile.Descriptor{FileFormat: datafile.TEXT, Delimiter: "|"}
+	row, err = redaction.redactRowValues(`4|a\|b@c.com|foo\\|\N`)
+	assert.NoError(err)
+	assert.Equal(`4|redacted\\x|foo\\|\N`, row)
+
+	dataFileDescriptor = &datafile.Descriptor{FileFormat: datafile.CSV, Delimiter: ",", QuoteChar: '"', EscapeChar: '"'}
+	redaction.Value, err = formatRedactPlaceholder(`say "x", y`)
+	assert.NoError(err)
+	row, err = redaction.redactRowValues(`1,"a,b@c.com",foo,`)
+	assert.NoError(err)
+	assert.Equal(`1,"say ""x"", y",foo,`, row)
+	redaction.Value, err = formatRedactPlaceholder("")
+	assert.NoError(err)
+	row, err = redaction.redactRowValues(`1,"",foo,123`)
+	assert.NoError(err)
+	assert.Equal(`1,,foo,`, row) // the quoted empty string is not null
+}
+
+func TestRedactEventValues(t *testing.T) {
+	assert := assert.New(t)
+	defer func(tdb_ tgtdb.TargetDB, columns map[string][]string, placeholder string) {
+		tdb, redactColumns, redactPlaceholder = tdb_, columns, placeholder
+	}(tdb, redactColumns, redactPlaceholder)
+	tdb = tgtdb.NewTargetDB(&tgtdb.TargetConf{TargetDBType: YUGABYTEDB})
+	redactColumns = map[string][]string{"public.users": {"email", "ssn"}}
+	redactPlaceholder = "it's redacted"
+	newEvent := func() *tgtdb.Event {
+		return &tgtdb.Event{Op: "u", TableName: "users",
+			Key:    map[string]*string{"email": lo.ToPtr("'a@b.com'")},
+			Fields: map[string]*string{"email": lo.ToPtr("'c@d.com'"), `"SSN"`: nil, "name": lo.ToPtr("'foo'")}}
+	}
+
+	event := newEvent()
+	assert.NoError(redactEventValues(event, "users", true))
+	assert.Equal("'it''s redacted'", *event.Fields["email"])
+	assert.Nil(event.Fields[`"SSN"`]) // nulls are left as they are
+	assert.Equal("'foo'", *event.Fields["name"])
+	assert.Equal("'a@b.com'", *event.Key["email"])
+
+	event = newEvent()
+	assert.NoError(redactEventValues(event, "users", false))
+	assert.Equal("it's redacted", *event.Fields["email"])
+
+	redactPlaceholder = ""
+	event = newEvent()
+	assert.NoError(redactEventValues(event, "users", true))
+	assert.Nil(event.Fields["email"])
+
+	event = newEvent()
+	assert.NoError(redactEventValues(event, "orders", true))
+	assert.Equal("'c@d.com'", *event.Fields["email"])
+}
@@ -0,0 +1,134 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+var retryBatchTableName string
+var retryBatchFilePath string
+var retryBatchNumber int64
+
+var importDataRetryBatchCmd = &cobra.Command{
+	Use:   "retry-batch",
+	Short: "Retry the import of a batch which failed during import data",
+	Long: "Import a batch of a table which failed during import data again, e.g. after fixing the problem with it. " +
+		"The import data resumes the import of the rest of the table when it is run again.",
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		validateImportFlags(cmd)
+		if retryBatchTableName == "" {
+			utils.ErrExit(`Error: required flag "table" not set`)
+		}
+		if !cmd.Flags().Changed("batch") {
+			utils.ErrExit(`Error: required flag "batch" not set`)
+		}
+		if retryBatchNumber < 0 {
+			utils.ErrExit("Error: Invalid batch: %d. It must not be negative", retryBatchNumber)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		retryBatch()
+	},
+}
+
+func init() {
+	importDataCmd.AddCommand(importDataRetryBatchCmd)
+	registerCommonImportFlags(importDataRetryBatchCmd)
+	importDataRetryBatchCmd.Flags().StringVar(&retryBatchTableName, "table", "",
+		"name of the table of the batch")
+	importDataRetryBatchCmd.Flags().Int64Var(&retryBatchNumber, "batch", -1,
+		fmt.Sprintf("number of the batch, as in its file name batch::<number>.* in the import data state (%d for the last batch of a data file)", LAST_SPLIT_NUM))
+	importDataRetryBatchCmd.Flags().StringVar(&retryBatchFilePath, "file", "",
+		"path of the data file of the batch, required only if the table has more than one data file")
+}
+
+func retryBatch() {
+	err := retrieveMigrationUUID(exportDir)
+	if err != nil {
+		utils.ErrExit("failed to get migration UUID: %w", err)
+	}
+	tconf.Schema = strings.ToLower(tconf.Schema)
+	tconf.SourceDBType = ExtractMetaInfo(exportDir).SourceDBType
+	if tconf.TargetDBType == YUGABYTEDB {
+		importDestinationType = TARGET_DB
+	} else {
+		importDestinationType = FF_DB
+	}
+
+	state := NewImportDataState(exportDir)
+	batch, err := state.GetBatch(retryBatchTableName, retryBatchFilePath, retryBatchNumber)
+	if err != nil {
+		utils.ErrExit("Error: %s", err)
+	}
+	if batch.IsDone() {
+		utils.ErrExit("Error: batch %d of table %s (%q) is already imported", batch.Number, batch.TableName, batch.FilePath)
+	}
+	if batch.RecordCount > 0 && batch.isEmptyMarker() {
+		utils.ErrExit("Error: batch %q was held in memory as the import was started with --no-split-files, and can't be retried alone. "+
+			"Resume the import with --no-split-files", batch.FilePath)
+	}
+	args, err := state.GetImportBatchArgs(batch.BaseFilePath, batch.TableName)
+	if err != nil {
+		utils.ErrExit("Failed to get the arguments of the import of the batch: %s", err)
+	}
+	argsJson, err := json.MarshalIndent(args, "", "    ")
+	if err != nil {
+		utils.ErrExit("marshal import batch args: %s", err)
+	}
+	utils.PrintAndLog("retrying batch %d of table %s (%q) of data file %q with the import batch args:\n%s",
+		batch.Number, batch.TableName, batch.FilePath, batch.BaseFilePath, argsJson)
+
+	tdb = tgtdb.NewTargetDB(&tconf)
+	err = tdb.Init()
+	if err != nil {
+		utils.ErrExit("Failed to initialize the target DB: %s", err)
+	}
+	defer tdb.Finalize()
+	err = tdb.InitConnPool()
+	if err != nil {
+		utils.ErrExit("Failed to initialize the target DB connection pool: %s", err)
+	}
+
+	if !batch.IsInterrupted() {
+		err = batch.MarkPending()
+		if err != nil {
+			utils.ErrExit("marking batch %d as pending: %s", batch.Number, err)
+		}
+	}
+	importBatchArgs := *args
+	importBatchArgs.FilePath = batch.FilePath
+	importBatchArgs.RowsPerTransaction = batch.OffsetEnd - batch.OffsetStart
+	rowsAffected, err := tdb.ImportBatch(batch, &importBatchArgs, exportDir)
+	if err != nil {
+		utils.ErrExit("import %q into %s: %s", batch.FilePath, batch.TableName, err)
+	}
+	err = batch.MarkDone()
+	if err != nil {
+		utils.ErrExit("marking batch %q as done: %s", batch.FilePath, err)
+	}
+	checkBatchRowsAffected(batch, rowsAffected)
+	utils.PrintAndLog("batch %d of table %s is imported, %d rows affected. Run import data to resume the import of the rest of the data.",
+		batch.Number, batch.TableName, rowsAffected)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"sync"
+)
+
+var maxRowsPerTable int64
+
+// Limits the rows of each table to --max-rows-per-table, nil if the import isn't limited.
+var rowLimiter *TableRowLimiter
+
+/*
+TableRowLimiter hands out the rows of the tables up to a limit, for importing only the first rows of each table
+(e.g. for validating a migration pipeline). The files of a table share its limit, hence they may get different
+rows of it when they are split in parallel.
+*/
+type TableRowLimiter struct {
+	mu      sync.Mutex
+	maxRows int64
+	taken   map[string]int64
+}
+
+// NewTableRowLimiter returns a limiter of the tables of the tasks, accounting for the rows in the batches
+// generated by the earlier runs.
+func NewTableRowLimiter(state *ImportDataState, tasks []*ImportFileTask, maxRows int64) (*TableRowLimiter, error) {
+	if maxRows == 0 {
+		return nil, nil
+	}
+	l := &TableRowLimiter{maxRows: maxRows, taken: make(map[string]int64)}
+	for _, task := range tasks {
+		batches, err := state.GetAllBatches(task.FilePath, task.TableName)
+		if err != nil {
+			return nil, fmt.Errorf("get batches of %q for table %s: %w", task.FilePath, task.TableName, err)
+		}
+		for _, batch := range batches {
+			l.taken[task.TableName] += batch.RecordCount
+		}
+	}
+	return l, nil
+}
+
+// Take reserves a row of the table, returning false if the limit of the table is reached.
+func (l *TableRowLimiter) Take(tableName string) bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.taken[tableName] >= l.maxRows {
+		return false
+	}
+	l.taken[tableName]++
+	return true
+}
+
+// GiveBack releases a row reserved with Take which wasn't imported (end of the file or a rejected row).
+func (l *TableRowLimiter) GiveBack(tableName string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.taken[tableName]--
+}
+
+// isFileImportLimitChanged reports whether the file, imported up to --max-rows-per-table in an earlier run,
+// has to be resumed as the limit is different in this run.
+func isFileImportLimitChanged(state *ImportDataState, task *ImportFileTask) (bool, error) {
+	maxRows, err := state.GetFileImportMaxRows(task.FilePath, task.TableName)
+	if err != nil {
+		return false, err
+	}
+	return maxRows > 0 && maxRows != maxRowsPerTable, nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableRowLimiter(t *testing.T) {
+	exportDir := t.TempDir()
+	state := NewImportDataState(exportDir)
+	task := &ImportFileTask{ID: 0, FilePath: filepath.Join(exportDir, "data", "foo_data.sql"), TableName: "public.foo"}
+	assert.NoError(t, state.PrepareForFileImport(task.FilePath, task.TableName))
+	// a batch of 100 rows imported by an earlier run
+	batchFilePath := filepath.Join(state.getFileStateDir(task.FilePath, task.TableName), "batch::1.100.100.1000.D")
+	assert.NoError(t, os.WriteFile(batchFilePath, nil, 0644))
+
+	limiter, err := NewTableRowLimiter(state, []*ImportFileTask{task}, 0)
+	assert.NoError(t, err)
+	assert.Nil(t, limiter)
+	assert.True(t, limiter.Take(task.TableName))
+
+	limiter, err = NewTableRowLimiter(state, []*ImportFileTask{task}, 102)
+	assert.NoError(t, err)
+	assert.True(t, limiter.Take(task.TableName))
+	assert.True(t, limiter.Take(task.TableName))
+	assert.False(t, limiter.Take(task.TableName))
+	limiter.GiveBack(task.TableName)
+	assert.True(t, limiter.Take(task.TableName))
+	assert.True(t, limiter.Take("public.bar"))
+
+	// the batch generation stopped at the limit
+	fileImportState, err := state.GetFileImportState(task.FilePath, task.TableName)
+	assert.NoError(t, err)
+	assert.Equal(t, FILE_IMPORT_IN_PROGRESS, fileImportState)
+	assert.NoError(t, state.SetFileImportMaxRows(task.FilePath, task.TableName, 100))
+	fileImportState, err = state.GetFileImportState(task.FilePath, task.TableName)
+	assert.NoError(t, err)
+	assert.Equal(t, FILE_IMPORT_COMPLETED, fileImportState)
+
+	maxRowsPerTable = 100
+	defer func() { maxRowsPerTable = 0 }()
+	limitChanged, err := isFileImportLimitChanged(state, task)
+	assert.NoError(t, err)
+	assert.False(t, limitChanged)
+	maxRowsPerTable = 0
+	limitChanged, err = isFileImportLimitChanged(state, task)
+	assert.NoError(t, err)
+	assert.True(t, limitChanged)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/datafile"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/dbzm"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+var schemaMapSpecs []string
+
+// Source schema (or database for MySQL) of the unqualified table names, as recorded in the export status.
+var tableToSourceSchema map[string]string
+
+// parseSchemaMap parses the `<source_schema>:<target_schema>` entries of --schema-map. The names are case-insensitive.
+func parseSchemaMap(specs []string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, spec := range specs {
+		source, target, found := strings.Cut(spec, ":")
+		source = strings.ToLower(strings.Trim(strings.TrimSpace(source), `"`))
+		target = strings.ToLower(strings.Trim(strings.TrimSpace(target), `"`))
+		if !found || source == "" || target == "" || strings.Contains(source, ".") || strings.Contains(target, ".") {
+			return nil, fmt.Errorf("entry %q must be of the form <source_schema>:<target_schema>", spec)
+		}
+		if _, ok := result[source]; ok {
+			return nil, fmt.Errorf("source schema %s is mapped more than once", source)
+		}
+		result[source] = target
+	}
+	return result, nil
+}
+
+// loadTableSourceSchemas records the source schemas of the tables from the export status of the data exported
+// with debezium. The tables of the data exported otherwise are named without the schema, and are imported into
+// the target schema unless they are qualified. Each source schema of the schema map must have some of the tables.
+func loadTableSourceSchemas() {
+	if len(tconf.SchemaMap) == 0 {
+		return
+	}
+	status, err := dbzm.ReadExportStatus(filepath.Join(exportDir, "data", "export_status.json"))
+	if err != nil {
+		utils.ErrExit("Failed to read the export status for the schema map: %s", err)
+	}
+	if status == nil {
+		log.Infof("export status not found, the schema map applies only to the qualified table names")
+	} else {
+		tableToSourceSchema = make(map[string]string)
+		for _, table := range status.Tables {
+			schemaName := table.SchemaName
+			if schemaName == "" {
+				schemaName = table.DatabaseName
+			}
+			if sourceDBType == POSTGRESQL && schemaName != "public" {
+				continue // qualified with the schema name
+			}
+			tableToSourceSchema[quoteIdentifierIfRequired(table.TableName)] = schemaName
+		}
+	}
+	tableNames := lo.Map(dataFileDescriptor.DataFileList, func(entry *datafile.FileEntry, _ int) string { return entry.TableName })
+	err = checkSchemaMapMatchesTables(tableNames)
+	if err != nil {
+		utils.ErrExit("Error: Invalid schema-map: %s", err)
+	}
+}
+
+// checkSchemaMapMatchesTables returns an error for a source schema of the schema map with none of the tables,
+// e.g. as the source schema of the unqualified table names is known only for the data exported with debezium.
+func checkSchemaMapMatchesTables(tableNames []string) error {
+	sourceSchemas := lo.Keys(tconf.SchemaMap)
+	slices.Sort(sourceSchemas)
+	for _, sourceSchema := range sourceSchemas {
+		matches := lo.ContainsBy(tableNames, func(tableName string) bool {
+			schemaName, _, found := strings.Cut(tableName, ".")
+			if !found {
+				schemaName, found = tableToSourceSchema[tableName]
+			}
+			return found && strings.EqualFold(strings.Trim(schemaName, `"`), sourceSchema)
+		})
+		if !matches {
+			return fmt.Errorf("none of the exported tables is known to be of the source schema %s "+
+				"(the source schema of the unqualified table names is known only for the data exported with debezium)", sourceSchema)
+		}
+	}
+	return nil
+}
+
+// getTargetTableName returns the name of the target table of a table being imported, as per --schema-map.
+func getTargetTableName(tableName string) string {
+	if sourceSchema, ok := tableToSourceSchema[tableName]; ok {
+		if _, mapped := tconf.MapSourceSchema(sourceSchema); mapped {
+			tableName = sourceSchema + "." + tableName
+		}
+	}
+	return tconf.GetTargetTableName(tableName)
+}
+
+func getTargetTableNames(tableNames []string) []string {
+	result := make([]string, 0, len(tableNames))
+	for _, tableName := range tableNames {
+		result = append(result, getTargetTableName(tableName))
+	}
+	return result
+}
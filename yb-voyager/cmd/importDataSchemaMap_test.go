@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+)
+
+func TestSchemaMap(t *testing.T) {
+	assert := assert.New(t)
+	schemaMap, err := parseSchemaMap([]string{"HR:hr_v2", ` "Finance" : ledger `})
+	assert.NoError(err)
+	assert.Equal(map[string]string{"hr": "hr_v2", "finance": "ledger"}, schemaMap)
+	_, err = parseSchemaMap([]string{"hr:hr_v2", "HR:hr_v3"})
+	assert.ErrorContains(err, "mapped more than once")
+	for _, spec := range []string{"hr", "hr:", ":hr_v2", "hr.employees:hr_v2"} {
+		_, err = parseSchemaMap([]string{spec})
+		assert.ErrorContains(err, "must be of the form", spec)
+	}
+
+	savedTconf, savedTableToSourceSchema := tconf, tableToSourceSchema
+	defer func() { tconf, tableToSourceSchema = savedTconf, savedTableToSourceSchema }()
+	tconf = tgtdb.TargetConf{Schema: "public", SchemaMap: schemaMap}
+	tableToSourceSchema = map[string]string{"employees": "HR", "orders": "SALES"}
+	assert.Equal("hr_v2.employees", getTargetTableName("employees"))
+	assert.Equal("orders", getTargetTableName("orders"))
+	assert.Equal("ledger.accounts", getTargetTableName("finance.accounts"))
+	assert.Equal([]string{"hr_v2.employees", "audit"}, getTargetTableNames([]string{"employees", "audit"}))
+
+	// every source schema must have some of the tables
+	assert.NoError(checkSchemaMapMatchesTables([]string{"employees", "finance.accounts"}))
+	assert.ErrorContains(checkSchemaMapMatchesTables([]string{"employees", "orders"}), "source schema finance")
+	tableToSourceSchema = nil
+	assert.ErrorContains(checkSchemaMapMatchesTables([]string{"employees", "finance.accounts"}), "source schema hr")
+
+	// the names are mapped once, also with a chain of schemas
+	tconf.SchemaMap = map[string]string{"a": "b", "b": "c"}
+	assert.Equal("b.foo", getTargetTableName("a.foo"))
+	assert.Equal("c.foo", getTargetTableName("b.foo"))
+}
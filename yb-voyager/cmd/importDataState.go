@@ -17,16 +17,23 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"crypto/sha1"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
 	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
 	"golang.org/x/exp/slices"
 )
 
@@ -36,20 +43,210 @@ const (
 
 /*
 metainfo/import_data_state/table::<table_name>/file::<base_name>:<path_hash>/
+(metainfo/ff_import_data_state/... for the fall forward database)
 
 	link -> dataFile
 	batch::<batch_num>.<offset_end>.<record_count>.<byte_count>.<state>
+	rejected_rows
+	rejected_rows_reasons
+	import_batch_args.json (arguments of the import of the batches, for `import data retry-batch`)
+	max_rows (--max-rows-per-table up to which the file is imported, if the import stopped at it)
+
+metainfo/import_data_state/postdata_executed (checkpoint of the statements of postdata.sql)
+metainfo/import_data_state/separate_ff_state (marks the state dirs created since the fall forward database has its own)
+metainfo/import_data_state/import_settings.json (settings of the last run, see ImportSettings)
+metainfo/import_data_state/split_files_dir (--split-files-dir of the import, if set)
+
+With --split-files-dir, the batch files (and their temporary files) are kept under <split_files_dir>/import_data_state/
+in the same layout, instead of in the file state dirs.
+With --keep-split-files, the batches cleaned for a fresh import of a file are moved to
+archived_splits/<time of the clean>/ under the root of the batch files, instead of being deleted.
 */
 type ImportDataState struct {
 	exportDir string
 	stateDir  string
+	batchDir  string // root of the batch files, stateDir unless --split-files-dir is set
+	createdAt time.Time
 }
 
 func NewImportDataState(exportDir string) *ImportDataState {
-	return &ImportDataState{
+	// The target and the fall forward databases are loaded independently (and possibly in parallel)
+	// from the same export dir, so each of them tracks the progress of its import separately.
+	stateDirName := "import_data_state"
+	if importDestinationType == FF_DB {
+		stateDirName = "ff_import_data_state"
+	}
+	s := &ImportDataState{
 		exportDir: exportDir,
-		stateDir:  filepath.Join(exportDir, "metainfo", "import_data_state"),
+		stateDir:  filepath.Join(exportDir, "metainfo", stateDirName),
+		createdAt: time.Now(),
+	}
+	s.batchDir = s.stateDir
+	splitFilesDir, err := s.GetSplitFilesDir()
+	if err != nil {
+		log.Warnf("ignoring the split files dir of the import data state: %s", err)
+	} else if splitFilesDir != "" {
+		s.batchDir = filepath.Join(splitFilesDir, stateDirName)
+	}
+	return s
+}
+
+func (s *ImportDataState) getSplitFilesDirRecordPath() string {
+	return filepath.Join(s.stateDir, "split_files_dir")
+}
+
+// GetSplitFilesDir returns the --split-files-dir recorded for the import, "" if it isn't set.
+func (s *ImportDataState) GetSplitFilesDir() (string, error) {
+	bytes, err := os.ReadFile(s.getSplitFilesDirRecordPath())
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read %q: %w", s.getSplitFilesDirRecordPath(), err)
+	}
+	return strings.TrimSpace(string(bytes)), nil
+}
+
+// SetSplitFilesDir records the --split-files-dir for the import and keeps the batch files under it from now on.
+// It must be set before any file import is started, as the batches of the earlier runs are looked up only under it.
+func (s *ImportDataState) SetSplitFilesDir(splitFilesDir string) error {
+	recordPath := s.getSplitFilesDirRecordPath()
+	if splitFilesDir == "" {
+		err := os.Remove(recordPath)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove %q: %w", recordPath, err)
+		}
+		s.batchDir = s.stateDir
+		return nil
+	}
+	err := os.MkdirAll(s.stateDir, 0755)
+	if err != nil {
+		return fmt.Errorf("create %q: %w", s.stateDir, err)
+	}
+	err = os.WriteFile(recordPath, []byte(splitFilesDir), 0644)
+	if err != nil {
+		return fmt.Errorf("write %q: %w", recordPath, err)
+	}
+	s.batchDir = filepath.Join(splitFilesDir, filepath.Base(s.stateDir))
+	return nil
+}
+
+// Before the fall forward database had its own state dir, both the imports tracked their progress in import_data_state.
+// The state dirs created since are marked, to tell the legacy ones apart.
+const SEPARATE_FF_STATE_MARKER = "separate_ff_state"
+
+// InitStateDir creates the state dir, if it doesn't exist yet.
+func (s *ImportDataState) InitStateDir() error {
+	if utils.FileOrFolderExists(s.stateDir) {
+		return nil
+	}
+	err := os.MkdirAll(s.stateDir, 0755)
+	if err != nil {
+		return fmt.Errorf("create %q: %w", s.stateDir, err)
+	}
+	markerPath := filepath.Join(s.stateDir, SEPARATE_FF_STATE_MARKER)
+	err = os.WriteFile(markerPath, nil, 0644)
+	if err != nil {
+		return fmt.Errorf("write %q: %w", markerPath, err)
+	}
+	return nil
+}
+
+// MigrateLegacyFallForwardState seeds the state of the fall forward database with the legacy import_data_state,
+// which the import into the fall forward database used along with the import into the target database. The legacy
+// state is migrated only if the settings saved in it record `ffDBType` as the type of the database imported into,
+// as the progress of the two imports can't be told apart otherwise. It returns false if there is nothing to migrate.
+func (s *ImportDataState) MigrateLegacyFallForwardState(ffDBType string) (bool, error) {
+	legacyState := &ImportDataState{exportDir: s.exportDir, stateDir: filepath.Join(s.exportDir, "metainfo", "import_data_state")}
+	if s.stateDir == legacyState.stateDir || utils.FileOrFolderExists(s.stateDir) ||
+		!utils.FileOrFolderExists(legacyState.stateDir) ||
+		utils.FileOrFolderExists(filepath.Join(legacyState.stateDir, SEPARATE_FF_STATE_MARKER)) {
+		return false, nil
+	}
+	settings, err := legacyState.GetImportSettings()
+	if err != nil {
+		return false, err
+	}
+	if settings == nil || settings.TargetDBType != ffDBType {
+		recordedDBType := "no database"
+		if settings != nil {
+			recordedDBType = fmt.Sprintf("%q", settings.TargetDBType)
+		}
+		return false, fmt.Errorf("the state in %q records %s as the database imported into, not the fall forward database %q. "+
+			"Its progress can't be attributed to the fall forward database, rerun with --start-clean to import afresh",
+			legacyState.stateDir, recordedDBType, ffDBType)
+	}
+	splitFilesDir, err := legacyState.GetSplitFilesDir()
+	if err != nil {
+		return false, err
+	}
+	if splitFilesDir != "" {
+		legacyBatchDir := filepath.Join(splitFilesDir, filepath.Base(legacyState.stateDir))
+		batchDir := filepath.Join(splitFilesDir, filepath.Base(s.stateDir))
+		if utils.FileOrFolderExists(legacyBatchDir) {
+			err = linkOrCopyTree(legacyBatchDir, batchDir)
+			if err != nil {
+				return false, err
+			}
+		}
+		s.batchDir = batchDir
+	}
+	// the state dir is copied last, so that an interrupted migration is redone on the next run
+	err = linkOrCopyTree(legacyState.stateDir, s.stateDir+".tmp")
+	if err == nil {
+		err = os.Rename(s.stateDir+".tmp", s.stateDir)
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// linkOrCopyTree recreates the dir tree `src` at `dst`, hard linking the files where possible. The batch files are
+// never modified in place, but only renamed or deleted, hence the linked files can be shared between the two trees.
+func linkOrCopyTree(src, dst string) error {
+	err := os.RemoveAll(dst)
+	if err != nil {
+		return fmt.Errorf("remove %q: %w", dst, err)
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, strings.TrimPrefix(path, src))
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, 0755)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		}
+		if os.Link(path, target) == nil {
+			return nil
+		}
+		return copyFile(path, target)
+	})
+}
+
+func copyFile(src, dst string) error {
+	r, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	w, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	if err != nil {
+		w.Close()
+		return err
 	}
+	return w.Close()
 }
 
 func (s *ImportDataState) PrepareForFileImport(filePath, tableName string) error {
@@ -67,6 +264,14 @@ func (s *ImportDataState) PrepareForFileImport(filePath, tableName string) error
 	if err != nil && !os.IsExist(err) {
 		return fmt.Errorf("error while creating symlink %q -> %q: %w", symlinkPath, filePath, err)
 	}
+	fileBatchDir := s.getFileBatchDir(filePath, tableName)
+	if fileBatchDir != fileStateDir {
+		log.Infof("Creating %q.", fileBatchDir)
+		err = os.MkdirAll(fileBatchDir, 0755)
+		if err != nil {
+			return fmt.Errorf("error while creating %q: %w", fileBatchDir, err)
+		}
+	}
 	return nil
 }
 
@@ -111,6 +316,14 @@ func (s *ImportDataState) GetFileImportState(filePath, tableName string) (FileIm
 			batchGenerationCompleted = true
 		}
 	}
+	if doneCount == len(batches) && !batchGenerationCompleted {
+		// The batch generation stops at --max-rows-per-table.
+		maxRows, err := s.GetFileImportMaxRows(filePath, tableName)
+		if err != nil {
+			return FILE_IMPORT_STATE_UNKNOWN, err
+		}
+		batchGenerationCompleted = maxRows > 0
+	}
 	if doneCount == len(batches) && batchGenerationCompleted {
 		return FILE_IMPORT_COMPLETED, nil
 	}
@@ -120,6 +333,105 @@ func (s *ImportDataState) GetFileImportState(filePath, tableName string) (FileIm
 	return FILE_IMPORT_IN_PROGRESS, nil
 }
 
+// SaveImportBatchArgs records the arguments with which the batches of the file are imported.
+func (s *ImportDataState) SaveImportBatchArgs(filePath, tableName string, args *tgtdb.ImportBatchArgs) error {
+	argsFilePath := filepath.Join(s.getFileStateDir(filePath, tableName), "import_batch_args.json")
+	bytes, err := json.MarshalIndent(args, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshal import batch args: %w", err)
+	}
+	err = os.WriteFile(argsFilePath, bytes, 0644)
+	if err != nil {
+		return fmt.Errorf("write %q: %w", argsFilePath, err)
+	}
+	return nil
+}
+
+func (s *ImportDataState) GetImportBatchArgs(filePath, tableName string) (*tgtdb.ImportBatchArgs, error) {
+	argsFilePath := filepath.Join(s.getFileStateDir(filePath, tableName), "import_batch_args.json")
+	bytes, err := os.ReadFile(argsFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("read %q: %w", argsFilePath, err)
+	}
+	args := &tgtdb.ImportBatchArgs{}
+	err = json.Unmarshal(bytes, args)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal %q: %w", argsFilePath, err)
+	}
+	return args, nil
+}
+
+// GetBatch returns the batch of the given number of the table, along with the data file it belongs to.
+// The batch numbers are unique within a data file, hence the data file must be given if the table has more than one.
+func (s *ImportDataState) GetBatch(tableName string, filePath string, batchNumber int64) (*Batch, error) {
+	filePaths, err := s.discoverTableFiles(tableName)
+	if err != nil {
+		return nil, fmt.Errorf("discover data files of table %s: %w", tableName, err)
+	}
+	if filePath != "" {
+		if !slices.Contains(filePaths, filePath) {
+			return nil, fmt.Errorf("data file %q is not imported into table %s, the data files of the table are: %v", filePath, tableName, filePaths)
+		}
+		filePaths = []string{filePath}
+	}
+	var result []*Batch
+	for _, filePath := range filePaths {
+		batches, err := s.GetAllBatches(filePath, tableName)
+		if err != nil {
+			return nil, fmt.Errorf("get batches of %q: %w", filePath, err)
+		}
+		for _, batch := range batches {
+			if batch.Number == batchNumber {
+				result = append(result, batch)
+			}
+		}
+	}
+	switch len(result) {
+	case 0:
+		return nil, fmt.Errorf("batch %d of table %s not found", batchNumber, tableName)
+	case 1:
+		return result[0], nil
+	default:
+		return nil, fmt.Errorf("more than one data file of table %s has batch %d, specify the data file: %v",
+			tableName, batchNumber, filePaths)
+	}
+}
+
+// SetFileImportMaxRows records that the batches of the file are generated only up to the given --max-rows-per-table,
+// which completes the import of the file. 0 clears it.
+func (s *ImportDataState) SetFileImportMaxRows(filePath, tableName string, maxRows int64) error {
+	maxRowsFilePath := filepath.Join(s.getFileStateDir(filePath, tableName), "max_rows")
+	if maxRows == 0 {
+		err := os.Remove(maxRowsFilePath)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove %q: %w", maxRowsFilePath, err)
+		}
+		return nil
+	}
+	err := os.WriteFile(maxRowsFilePath, []byte(strconv.FormatInt(maxRows, 10)), 0644)
+	if err != nil {
+		return fmt.Errorf("write %q: %w", maxRowsFilePath, err)
+	}
+	return nil
+}
+
+// GetFileImportMaxRows returns the --max-rows-per-table the import of the file stopped at, 0 if it didn't.
+func (s *ImportDataState) GetFileImportMaxRows(filePath, tableName string) (int64, error) {
+	maxRowsFilePath := filepath.Join(s.getFileStateDir(filePath, tableName), "max_rows")
+	bytes, err := os.ReadFile(maxRowsFilePath)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read %q: %w", maxRowsFilePath, err)
+	}
+	maxRows, err := strconv.ParseInt(strings.TrimSpace(string(bytes)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %q: %w", maxRowsFilePath, err)
+	}
+	return maxRows, nil
+}
+
 func (s *ImportDataState) Recover(filePath, tableName string) ([]*Batch, int64, int64, bool, error) {
 	var pendingBatches []*Batch
 
@@ -147,19 +459,53 @@ func (s *ImportDataState) Recover(filePath, tableName string) ([]*Batch, int64,
 			lastOffset = batch.OffsetEnd
 		}
 		if !batch.IsDone() {
+			if batch.RecordCount > 0 && batch.isEmptyMarker() {
+				return nil, 0, 0, false, fmt.Errorf("batch %q was not written to disk as the import was started with --no-split-files. "+
+					"Resume the import with --no-split-files", batch.FilePath)
+			}
 			pendingBatches = append(pendingBatches, batch)
 		}
 	}
 	return pendingBatches, lastBatchNumber, lastOffset, fileFullySplit, nil
 }
 
+/*
+DiscardUncommittedBatches prepares the file for resuming an import with --no-split-files. The batches held in memory
+by the earlier run are lost, so the import restarts from the offset up to which all the batches are imported.
+The batches after that offset are discarded, including the ones already imported, which are re-created with the
+same numbers and offsets (given the same batch size) when the file is split again and then skipped as already imported.
+*/
+func (s *ImportDataState) DiscardUncommittedBatches(filePath, tableName string) error {
+	batches, err := s.GetAllBatches(filePath, tableName)
+	if err != nil {
+		return fmt.Errorf("error while getting all batches for %s: %w", tableName, err)
+	}
+	// Each batch starts where the previous one ends.
+	slices.SortFunc(batches, func(b1, b2 *Batch) bool { return b1.OffsetEnd < b2.OffsetEnd })
+	committedOffset := int64(0)
+	discarded := 0
+	for _, batch := range batches {
+		if batch.IsDone() && discarded == 0 {
+			committedOffset = batch.OffsetEnd
+			continue
+		}
+		err = batch.Delete()
+		if err != nil {
+			return err
+		}
+		discarded++
+	}
+	if discarded > 0 {
+		log.Infof("discarded %d batches of table %q after the committed offset %d of file %q", discarded, tableName, committedOffset, filePath)
+	}
+	return nil
+}
+
 func (s *ImportDataState) Clean(filePath string, tableName string) error {
 	log.Infof("Cleaning import data state for table %q.", tableName)
-	fileStateDir := s.getFileStateDir(filePath, tableName)
-	log.Infof("Removing %q.", fileStateDir)
-	err := os.RemoveAll(fileStateDir)
+	err := s.CleanLocalState(filePath, tableName)
 	if err != nil {
-		return fmt.Errorf("error while removing %q: %w", fileStateDir, err)
+		return err
 	}
 
 	err = tdb.CleanFileImportState(filePath, tableName)
@@ -169,6 +515,35 @@ func (s *ImportDataState) Clean(filePath string, tableName string) error {
 	return nil
 }
 
+// CleanLocalState removes the batches of the file, but keeps the record of the batches imported into the target db.
+// Hence, the batches imported earlier are skipped when the file is split again with the same batch size.
+// With --keep-split-files, the batches are archived instead.
+func (s *ImportDataState) CleanLocalState(filePath string, tableName string) error {
+	fileStateDir := s.getFileStateDir(filePath, tableName)
+	fileBatchDir := s.getFileBatchDir(filePath, tableName)
+	if keepSplitFiles && utils.FileOrFolderExists(fileBatchDir) {
+		archiveDir := filepath.Join(s.batchDir, "archived_splits", s.createdAt.Format("20060102T150405"),
+			filepath.Base(s.getTableStateDir(tableName)), filepath.Base(fileBatchDir))
+		log.Infof("Archiving %q to %q.", fileBatchDir, archiveDir)
+		err := os.MkdirAll(filepath.Dir(archiveDir), 0755)
+		if err != nil {
+			return fmt.Errorf("error while creating %q: %w", filepath.Dir(archiveDir), err)
+		}
+		err = os.Rename(fileBatchDir, archiveDir)
+		if err != nil {
+			return fmt.Errorf("error while archiving %q to %q: %w", fileBatchDir, archiveDir, err)
+		}
+	}
+	for _, dir := range lo.Uniq([]string{fileBatchDir, fileStateDir}) {
+		log.Infof("Removing %q.", dir)
+		err := os.RemoveAll(dir)
+		if err != nil {
+			return fmt.Errorf("error while removing %q: %w", dir, err)
+		}
+	}
+	return nil
+}
+
 func (s *ImportDataState) GetImportedRowCount(filePath, tableName string) (int64, error) {
 	batches, err := s.GetCompletedBatches(filePath, tableName)
 	if err != nil {
@@ -209,6 +584,105 @@ func (s *ImportDataState) DiscoverTableToFilesMapping() (map[string][]string, er
 	return result, nil
 }
 
+// GetPostDataCheckpoint returns the checkpoint of the postdata.sql statements executed on the target.
+func (s *ImportDataState) GetPostDataCheckpoint() (*SqlStmtCheckpoint, error) {
+	err := os.MkdirAll(s.stateDir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("create %q: %w", s.stateDir, err)
+	}
+	return NewSqlStmtCheckpoint(filepath.Join(s.stateDir, "postdata_executed"))
+}
+
+// ImportSettings are the settings of an import run which the import can't be resumed with a different value of.
+type ImportSettings struct {
+	TargetDBType     string `json:"target_db_type"`
+	DBName           string `json:"db_name"`
+	Schema           string `json:"schema"`
+	TableList        string `json:"table_list"`
+	ExcludeTableList string `json:"exclude_table_list"`
+	EnableUpsert     bool   `json:"enable_upsert"`
+	NoSplitFiles     bool   `json:"no_split_files"`
+	BatchSize        int64  `json:"batch_size"`
+	BatchSizeBytes   int64  `json:"batch_size_bytes"`
+	MinBatchSize     int64  `json:"min_batch_size"`
+}
+
+// Diff returns the settings changed from `prev`, one per line.
+func (settings *ImportSettings) Diff(prev *ImportSettings) []string {
+	var result []string
+	diff := func(name string, prevValue, value interface{}) {
+		if prevValue != value {
+			result = append(result, fmt.Sprintf("%s: %q -> %q", name, fmt.Sprint(prevValue), fmt.Sprint(value)))
+		}
+	}
+	diff("target-db-type", prev.TargetDBType, settings.TargetDBType)
+	diff("target-db-name", prev.DBName, settings.DBName)
+	diff("target-db-schema", prev.Schema, settings.Schema)
+	diff("table-list", prev.TableList, settings.TableList)
+	diff("exclude-table-list", prev.ExcludeTableList, settings.ExcludeTableList)
+	diff("enable-upsert", prev.EnableUpsert, settings.EnableUpsert)
+	return result
+}
+
+// BatchingDiff returns the changes from `prev` of the settings which split the files into batches, if either of
+// the runs uses --no-split-files. Without the split files, the batches after the imported ones are split again
+// on resuming, and must be the same as in the earlier run, for the batches imported by it to be recognized.
+func (settings *ImportSettings) BatchingDiff(prev *ImportSettings) []string {
+	if prev.BatchSize == 0 || (!prev.NoSplitFiles && !settings.NoSplitFiles) { // saved by an older version, or with split files
+		return nil
+	}
+	var result []string
+	diff := func(name string, prevValue, value interface{}) {
+		if prevValue != value {
+			result = append(result, fmt.Sprintf("%s: %q -> %q", name, fmt.Sprint(prevValue), fmt.Sprint(value)))
+		}
+	}
+	diff("no-split-files", prev.NoSplitFiles, settings.NoSplitFiles)
+	diff("batch-size", prev.BatchSize, settings.BatchSize)
+	diff("batch-size-bytes", prev.BatchSizeBytes, settings.BatchSizeBytes)
+	diff("min-batch-size", prev.MinBatchSize, settings.MinBatchSize)
+	return result
+}
+
+func (s *ImportDataState) getImportSettingsFilePath() string {
+	return filepath.Join(s.stateDir, "import_settings.json")
+}
+
+// GetImportSettings returns the settings saved by the last run, or nil if there was no run.
+func (s *ImportDataState) GetImportSettings() (*ImportSettings, error) {
+	filePath := s.getImportSettingsFilePath()
+	bytes, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %q: %w", filePath, err)
+	}
+	settings := &ImportSettings{}
+	err = json.Unmarshal(bytes, settings)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal %q: %w", filePath, err)
+	}
+	return settings, nil
+}
+
+func (s *ImportDataState) SaveImportSettings(settings *ImportSettings) error {
+	err := os.MkdirAll(s.stateDir, 0755)
+	if err != nil {
+		return fmt.Errorf("create %q: %w", s.stateDir, err)
+	}
+	bytes, err := json.MarshalIndent(settings, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshal import settings: %w", err)
+	}
+	filePath := s.getImportSettingsFilePath()
+	err = os.WriteFile(filePath, bytes, 0644)
+	if err != nil {
+		return fmt.Errorf("write %q: %w", filePath, err)
+	}
+	return nil
+}
+
 func (s *ImportDataState) NewBatchWriter(filePath, tableName string, batchNumber int64) *BatchWriter {
 	return &BatchWriter{
 		state:       s,
@@ -223,21 +697,21 @@ func (s *ImportDataState) getBatches(filePath, tableName string, states string)
 	// empty result: import started but no batches created yet.
 	result := []*Batch{}
 
-	fileStateDir := s.getFileStateDir(filePath, tableName)
-	// Check if the fileStateDir exists.
-	_, err := os.Stat(fileStateDir)
+	fileBatchDir := s.getFileBatchDir(filePath, tableName)
+	// Check if the fileBatchDir exists.
+	_, err := os.Stat(fileBatchDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			log.Infof("fileStateDir %q does not exist", fileStateDir)
+			log.Infof("fileBatchDir %q does not exist", fileBatchDir)
 			return nil, nil
 		}
-		return nil, fmt.Errorf("stat %q: %s", fileStateDir, err)
+		return nil, fmt.Errorf("stat %q: %s", fileBatchDir, err)
 	}
 
-	// Find regular files in the `fileStateDir` whose name starts with "batch::"
-	files, err := os.ReadDir(fileStateDir)
+	// Find regular files in the `fileBatchDir` whose name starts with "batch::"
+	files, err := os.ReadDir(fileBatchDir)
 	if err != nil {
-		return nil, fmt.Errorf("read dir %q: %s", fileStateDir, err)
+		return nil, fmt.Errorf("read dir %q: %s", fileBatchDir, err)
 	}
 	for _, file := range files {
 		if file.Type().IsRegular() && strings.HasPrefix(file.Name(), "batch::") {
@@ -251,7 +725,7 @@ func (s *ImportDataState) getBatches(filePath, tableName string, states string)
 			batch := &Batch{
 				SchemaName:   "",
 				TableName:    tableName,
-				FilePath:     filepath.Join(fileStateDir, file.Name()),
+				FilePath:     filepath.Join(fileBatchDir, file.Name()),
 				BaseFilePath: filePath,
 				Number:       batchNum,
 				OffsetStart:  offsetEnd - recordCount,
@@ -294,6 +768,35 @@ func parseBatchFileName(fileName string) (batchNum, offsetEnd, recordCount, byte
 	return batchNum, offsetEnd, recordCount, byteCount, state, nil
 }
 
+// RecordRejectedRow appends a row of the data file which could not be imported to the
+// `rejected_rows` file in the state dir of the data file, and the reason of its rejection
+// to the `rejected_rows_reasons` file at the same line number.
+func (s *ImportDataState) RecordRejectedRow(filePath, tableName, row, reason string) error {
+	rejectsFilePath := s.GetRejectedRowsFilePath(filePath, tableName)
+	err := appendLineToFile(rejectsFilePath, row)
+	if err != nil {
+		return err
+	}
+	return appendLineToFile(rejectsFilePath+"_reasons", strings.ReplaceAll(reason, "\n", " "))
+}
+
+func appendLineToFile(filePath, line string) error {
+	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open %q: %w", filePath, err)
+	}
+	defer f.Close()
+	_, err = f.WriteString(line + "\n")
+	if err != nil {
+		return fmt.Errorf("write to %q: %w", filePath, err)
+	}
+	return nil
+}
+
+func (s *ImportDataState) GetRejectedRowsFilePath(filePath, tableName string) string {
+	return filepath.Join(s.getFileStateDir(filePath, tableName), "rejected_rows")
+}
+
 //============================================================================
 
 func (s *ImportDataState) getTableStateDir(tableName string) string {
@@ -307,6 +810,15 @@ func (s *ImportDataState) getFileStateDir(filePath, tableName string) string {
 	return fmt.Sprintf("%s/file::%s::%s", s.getTableStateDir(tableName), baseName, hash)
 }
 
+// getFileBatchDir returns the dir of the batch files of the file, which is its file state dir unless --split-files-dir is set.
+func (s *ImportDataState) getFileBatchDir(filePath, tableName string) string {
+	fileStateDir := s.getFileStateDir(filePath, tableName)
+	if s.batchDir == s.stateDir {
+		return fileStateDir
+	}
+	return filepath.Join(s.batchDir, strings.TrimPrefix(fileStateDir, s.stateDir))
+}
+
 func computePathHash(filePath, exportDir string) string {
 	// If filePath starts with exportDir, then this is a case of
 	// import files output by the `export data` command. Stripping the exportDir
@@ -364,13 +876,21 @@ type BatchWriter struct {
 	NumRecordsWritten      int64
 	flagFirstRecordWritten bool
 
-	outFile *os.File
-	w       *bufio.Writer
+	outFileName string
+	outFile     *os.File
+	buf         *bytes.Buffer // holds the batch instead of outFile with --no-split-files
+	w           *bufio.Writer
 }
 
 func (bw *BatchWriter) Init() error {
-	fileStateDir := bw.state.getFileStateDir(bw.filePath, bw.tableName)
-	currTmpFileName := fmt.Sprintf("%s/tmp::%v", fileStateDir, bw.batchNumber)
+	fileBatchDir := bw.state.getFileBatchDir(bw.filePath, bw.tableName)
+	currTmpFileName := fmt.Sprintf("%s/tmp::%v", fileBatchDir, bw.batchNumber)
+	bw.outFileName = currTmpFileName
+	if noSplitFiles {
+		bw.buf = &bytes.Buffer{}
+		bw.w = bufio.NewWriterSize(bw.buf, 4*MB)
+		return nil
+	}
 	log.Infof("current temp file: %s", currTmpFileName)
 	outFile, err := os.Create(currTmpFileName)
 	if err != nil {
@@ -384,7 +904,7 @@ func (bw *BatchWriter) Init() error {
 func (bw *BatchWriter) WriteHeader(header string) error {
 	_, err := bw.w.WriteString(header + "\n")
 	if err != nil {
-		return fmt.Errorf("write header to %q: %s", bw.outFile.Name(), err)
+		return fmt.Errorf("write header to %q: %s", bw.outFileName, err)
 	}
 	return nil
 }
@@ -397,42 +917,68 @@ func (bw *BatchWriter) WriteRecord(record string) error {
 	if bw.flagFirstRecordWritten {
 		_, err = bw.w.WriteString("\n")
 		if err != nil {
-			return fmt.Errorf("write to %q: %s", bw.outFile.Name(), err)
+			return fmt.Errorf("write to %q: %s", bw.outFileName, err)
 		}
 	}
 	_, err = bw.w.WriteString(record)
 	if err != nil {
-		return fmt.Errorf("write record to %q: %s", bw.outFile.Name(), err)
+		return fmt.Errorf("write record to %q: %s", bw.outFileName, err)
 	}
 	bw.NumRecordsWritten++
 	bw.flagFirstRecordWritten = true
 	return nil
 }
 
-func (bw *BatchWriter) Done(isLastBatch bool, offsetEnd int64, byteCount int64) (*Batch, error) {
-	err := bw.w.Flush()
+// Discard removes the batch being written, e.g. when it has no records.
+func (bw *BatchWriter) Discard() error {
+	if bw.outFile == nil {
+		return nil
+	}
+	err := bw.outFile.Close()
 	if err != nil {
-		return nil, fmt.Errorf("flush %q: %s", bw.outFile.Name(), err)
+		return fmt.Errorf("close %q: %s", bw.outFileName, err)
 	}
-	tmpFileName := bw.outFile.Name()
-	err = bw.outFile.Close()
+	err = os.Remove(bw.outFileName)
 	if err != nil {
-		return nil, fmt.Errorf("close %q: %s", bw.outFile.Name(), err)
+		return fmt.Errorf("remove %q: %s", bw.outFileName, err)
 	}
+	return nil
+}
 
+func (bw *BatchWriter) Done(isLastBatch bool, offsetEnd int64, byteCount int64) (*Batch, error) {
+	err := bw.w.Flush()
+	if err != nil {
+		return nil, fmt.Errorf("flush %q: %s", bw.outFileName, err)
+	}
 	batchNumber := bw.batchNumber
 	if isLastBatch {
 		batchNumber = LAST_SPLIT_NUM
 	}
-	fileStateDir := bw.state.getFileStateDir(bw.filePath, bw.tableName)
+	fileBatchDir := bw.state.getFileBatchDir(bw.filePath, bw.tableName)
 	batchFilePath := fmt.Sprintf("%s/batch::%d.%d.%d.%d.C",
-		fileStateDir, batchNumber, offsetEnd, bw.NumRecordsWritten, byteCount)
-	log.Infof("Renaming %q to %q", tmpFileName, batchFilePath)
-	err = os.Rename(tmpFileName, batchFilePath)
-	if err != nil {
-		return nil, fmt.Errorf("rename %q to %q: %s", tmpFileName, batchFilePath, err)
+		fileBatchDir, batchNumber, offsetEnd, bw.NumRecordsWritten, byteCount)
+	var data []byte
+	if bw.buf != nil {
+		// Only the (empty) batch file is created, to track the state of the batch.
+		data = bw.buf.Bytes()
+		err = os.WriteFile(batchFilePath, nil, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("create %q: %s", batchFilePath, err)
+		}
+	} else {
+		tmpFileName := bw.outFileName
+		err = bw.outFile.Close()
+		if err != nil {
+			return nil, fmt.Errorf("close %q: %s", bw.outFileName, err)
+		}
+		log.Infof("Renaming %q to %q", tmpFileName, batchFilePath)
+		err = os.Rename(tmpFileName, batchFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("rename %q to %q: %s", tmpFileName, batchFilePath, err)
+		}
 	}
 	batch := &Batch{
+		data:         data,
 		SchemaName:   "",
 		TableName:    bw.tableName,
 		FilePath:     batchFilePath,
@@ -460,12 +1006,24 @@ type Batch struct {
 	ByteCount           int64
 	TmpConnectionString string
 	Interrupted         bool
+
+	data            []byte // contents of the batch held in memory with --no-split-files
+	alreadyImported bool   // whether the batch was found imported by an earlier run, when importing it
 }
 
-func (batch *Batch) Open() (*os.File, error) {
+func (batch *Batch) Open() (io.ReadCloser, error) {
+	if batch.data != nil {
+		return io.NopCloser(bytes.NewReader(batch.data)), nil
+	}
 	return os.Open(batch.FilePath)
 }
 
+// isEmptyMarker reports whether the batch file only tracks the state of a batch which was held in memory.
+func (batch *Batch) isEmptyMarker() bool {
+	info, err := os.Stat(batch.FilePath)
+	return err == nil && info.Size() == 0
+}
+
 func (batch *Batch) Delete() error {
 	err := os.RemoveAll(batch.FilePath)
 	if err != nil {
@@ -509,18 +1067,19 @@ func (batch *Batch) MarkDone() error {
 		return fmt.Errorf("rename %q => %q: %w", inProgressFilePath, doneFilePath, err)
 	}
 
-	if truncateSplits {
+	if truncateSplits && !keepSplitFiles {
 		err = os.Truncate(doneFilePath, 0)
 		if err != nil {
 			log.Warnf("truncate file %q: %s", doneFilePath, err)
 		}
 	}
 	batch.FilePath = doneFilePath
+	batch.data = nil
 	return nil
 }
 
 func (batch *Batch) GetQueryIsBatchAlreadyImported() string {
-	schemaName := getTargetSchemaName(batch.TableName)
+	schemaName := tconf.GetTargetSchemaName(batch.TableName)
 	query := fmt.Sprintf(
 		"SELECT rows_imported FROM %s "+
 			"WHERE data_file_name = '%s' AND batch_number = %d AND schema_name = '%s' AND table_name = '%s'",
@@ -531,7 +1090,7 @@ func (batch *Batch) GetQueryIsBatchAlreadyImported() string {
 
 func (batch *Batch) GetQueryToRecordEntryInDB(rowsAffected int64) string {
 	// Record an entry in ${BATCH_METADATA_TABLE_NAME}, that the split is imported.
-	schemaName := getTargetSchemaName(batch.TableName)
+	schemaName := tconf.GetTargetSchemaName(batch.TableName)
 	cmd := fmt.Sprintf(
 		`INSERT INTO %s (data_file_name, batch_number, schema_name, table_name, rows_imported)
 			VALUES ('%s', %d, '%s', '%s', %v)`,
@@ -544,10 +1103,43 @@ func (batch *Batch) GetFilePath() string {
 	return batch.FilePath
 }
 
+func (batch *Batch) GetBaseFilePath() string {
+	return batch.BaseFilePath
+}
+
+func (batch *Batch) GetBatchNumber() int64 {
+	return batch.Number
+}
+
 func (batch *Batch) GetTableName() string {
 	return batch.TableName
 }
 
+func (batch *Batch) GetRecordCount() int64 {
+	return batch.RecordCount
+}
+
+// GetCorrelationID identifies the batch in the logs by its table, data file and number,
+// as the batches of the different data files of a table are numbered independently.
+func (batch *Batch) GetCorrelationID() string {
+	return fmt.Sprintf("%s:%s:%d", batch.TableName, filepath.Base(batch.BaseFilePath), batch.Number)
+}
+
+// logger returns the log entry to log the import of the batch with.
+func (batch *Batch) logger() *log.Entry {
+	return log.WithField(tgtdb.LOG_FIELD_BATCH_ID, batch.GetCorrelationID())
+}
+
+// RecordRejectedRow records a row of the batch rejected by the target db.
+func (batch *Batch) RecordRejectedRow(row string, reason string) error {
+	return NewImportDataState(exportDir).RecordRejectedRow(batch.BaseFilePath, batch.TableName, row, reason)
+}
+
+// RecordAlreadyImported records that the batch was found imported by an earlier run.
+func (batch *Batch) RecordAlreadyImported() {
+	batch.alreadyImported = true
+}
+
 func (batch *Batch) getInProgressFilePath() string {
 	return batch.FilePath[0:len(batch.FilePath)-1] + "P" // *.C -> *.P
 }
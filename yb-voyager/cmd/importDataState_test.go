@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/datafile"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+func TestMigrateLegacyFallForwardState(t *testing.T) {
+	assert := assert.New(t)
+	exportDir := t.TempDir()
+	prevImportDestinationType := importDestinationType
+	defer func() { importDestinationType = prevImportDestinationType }()
+
+	// progress of the imports tracked by an older version, in the state dir shared by them
+	importDestinationType = TARGET_DB
+	legacyState := NewImportDataState(exportDir)
+	filePath := filepath.Join(exportDir, "data", "foo_data.sql")
+	assert.NoError(legacyState.PrepareForFileImport(filePath, "public.foo"))
+	batchFileName := "batch::1.100.100.1000.D"
+	assert.NoError(os.WriteFile(filepath.Join(legacyState.getFileStateDir(filePath, "public.foo"), batchFileName), []byte("1\n"), 0644))
+
+	importDestinationType = FF_DB
+	state := NewImportDataState(exportDir)
+	// without the settings of the last run, the progress can't be attributed to either import
+	migrated, err := state.MigrateLegacyFallForwardState(ORACLE)
+	assert.ErrorContains(err, "--start-clean")
+	assert.False(migrated)
+	assert.NoDirExists(state.stateDir)
+
+	// the last run imported into the target database
+	assert.NoError(legacyState.SaveImportSettings(&ImportSettings{TargetDBType: YUGABYTEDB}))
+	migrated, err = state.MigrateLegacyFallForwardState(ORACLE)
+	assert.ErrorContains(err, "--start-clean")
+	assert.False(migrated)
+	assert.NoDirExists(state.stateDir)
+
+	assert.NoError(legacyState.SaveImportSettings(&ImportSettings{TargetDBType: ORACLE}))
+	migrated, err = state.MigrateLegacyFallForwardState(ORACLE)
+	assert.NoError(err)
+	assert.True(migrated)
+	batches, err := state.GetCompletedBatches(filePath, "public.foo")
+	assert.NoError(err)
+	assert.Len(batches, 1)
+	// the target import keeps its state
+	assert.FileExists(filepath.Join(legacyState.getFileStateDir(filePath, "public.foo"), batchFileName))
+
+	migrated, err = state.MigrateLegacyFallForwardState(ORACLE)
+	assert.NoError(err)
+	assert.False(migrated)
+
+	// the state dir of the target import created by this version has nothing to migrate
+	exportDir = t.TempDir()
+	importDestinationType = TARGET_DB
+	assert.NoError(NewImportDataState(exportDir).InitStateDir())
+	importDestinationType = FF_DB
+	migrated, err = NewImportDataState(exportDir).MigrateLegacyFallForwardState(ORACLE)
+	assert.NoError(err)
+	assert.False(migrated)
+}
+
+func TestImportSettingsDiff(t *testing.T) {
+	assert := assert.New(t)
+	prev := &ImportSettings{TargetDBType: "yugabytedb", DBName: "db", Schema: "public", TableList: "t1,t2"}
+	settings := *prev
+	assert.Empty(settings.Diff(prev))
+
+	settings.Schema = "s1"
+	settings.TableList = ""
+	settings.EnableUpsert = true
+	assert.Equal([]string{
+		`target-db-schema: "public" -> "s1"`,
+		`table-list: "t1,t2" -> ""`,
+		`enable-upsert: "false" -> "true"`,
+	}, settings.Diff(prev))
+	assert.Empty(settings.BatchingDiff(prev))
+
+	prev.BatchSize, prev.NoSplitFiles = 20000, true
+	settings = *prev
+	assert.Empty(settings.BatchingDiff(prev))
+	settings.BatchSize = 10000
+	assert.Equal([]string{`batch-size: "20000" -> "10000"`}, settings.BatchingDiff(prev))
+	settings.BatchSizeBytes = 100 * 1024 * 1024
+	assert.Equal([]string{`batch-size: "20000" -> "10000"`, `batch-size-bytes: "0" -> "104857600"`}, settings.BatchingDiff(prev))
+	settings.BatchSizeBytes = 0
+	settings.NoSplitFiles = false
+	assert.Equal([]string{`no-split-files: "true" -> "false"`, `batch-size: "20000" -> "10000"`}, settings.BatchingDiff(prev))
+	// the batches are kept in the split files
+	prev.NoSplitFiles = false
+	assert.Empty(settings.BatchingDiff(prev))
+}
+
+func TestGetBatch(t *testing.T) {
+	exportDir := t.TempDir()
+	state := NewImportDataState(exportDir)
+	filePath1 := filepath.Join(exportDir, "data", "foo_1.csv")
+	filePath2 := filepath.Join(exportDir, "data", "foo_2.csv")
+	batchFiles := map[string][]string{
+		filePath1: {"batch::1.100.100.1000.P", "batch::0.150.50.500.C"},
+		filePath2: {"batch::1.100.100.1000.D"},
+	}
+	for filePath, batchFileNames := range batchFiles {
+		assert.NoError(t, state.PrepareForFileImport(filePath, "public.foo"))
+		for _, batchFileName := range batchFileNames {
+			assert.NoError(t, os.WriteFile(filepath.Join(state.getFileStateDir(filePath, "public.foo"), batchFileName), nil, 0644))
+		}
+	}
+
+	batch, err := state.GetBatch("public.foo", "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, filePath1, batch.BaseFilePath)
+	assert.True(t, batch.IsNotStarted())
+	_, err = state.GetBatch("public.foo", "", 1)
+	assert.ErrorContains(t, err, "more than one data file")
+	batch, err = state.GetBatch("public.foo", filePath2, 1)
+	assert.NoError(t, err)
+	assert.True(t, batch.IsDone())
+	_, err = state.GetBatch("public.foo", "", 5)
+	assert.ErrorContains(t, err, "not found")
+	_, err = state.GetBatch("public.foo", filepath.Join(exportDir, "data", "bar.csv"), 1)
+	assert.ErrorContains(t, err, "is not imported into table public.foo")
+
+	args := &tgtdb.ImportBatchArgs{TableName: "public.foo", Columns: []string{"id", "name"}, FileFormat: datafile.CSV,
+		Delimiter: ",", QuoteChar: '"', HasHeader: true}
+	assert.NoError(t, state.SaveImportBatchArgs(filePath1, "public.foo", args))
+	savedArgs, err := state.GetImportBatchArgs(filePath1, "public.foo")
+	assert.NoError(t, err)
+	assert.Equal(t, args, savedArgs)
+}
+
+func TestSplitFilesDir(t *testing.T) {
+	exportDir, splitsDir := t.TempDir(), t.TempDir()
+	state := NewImportDataState(exportDir)
+	assert.NoError(t, state.SetSplitFilesDir(splitsDir))
+	filePath := filepath.Join(exportDir, "data", "foo_data.sql")
+	assert.NoError(t, state.PrepareForFileImport(filePath, "public.foo"))
+
+	// the batches are written to and recovered from the split files dir, also by a new state of the import
+	batchWriter := state.NewBatchWriter(filePath, "public.foo", 1)
+	assert.NoError(t, batchWriter.Init())
+	assert.NoError(t, batchWriter.WriteRecord("1\tfoo"))
+	batch, err := batchWriter.Done(false, 1, 5)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(batch.FilePath, filepath.Join(splitsDir, "import_data_state")))
+	pendingBatches, lastBatchNumber, lastOffset, fileFullySplit, err := NewImportDataState(exportDir).Recover(filePath, "public.foo")
+	assert.NoError(t, err)
+	assert.Len(t, pendingBatches, 1)
+	assert.Equal(t, batch.FilePath, pendingBatches[0].FilePath)
+	assert.Equal(t, int64(1), lastBatchNumber)
+	assert.Equal(t, int64(1), lastOffset)
+	assert.False(t, fileFullySplit)
+
+	// the cleaned batches are archived with --keep-split-files
+	keepSplitFiles = true
+	defer func() { keepSplitFiles = false }()
+	assert.NoError(t, state.CleanLocalState(filePath, "public.foo"))
+	assert.False(t, utils.FileOrFolderExists(batch.FilePath))
+	archivedBatches, err := filepath.Glob(filepath.Join(splitsDir, "import_data_state", "archived_splits", "*", "table::public.foo", "file::*", "batch::*"))
+	assert.NoError(t, err)
+	assert.Len(t, archivedBatches, 1)
+	batches, err := state.GetAllBatches(filePath, "public.foo")
+	assert.NoError(t, err)
+	assert.Nil(t, batches)
+}
+
+func TestBatchCorrelationID(t *testing.T) {
+	batch := &Batch{Number: 12, TableName: "public.orders", BaseFilePath: "/export/data/orders_data.sql"}
+	assert.Equal(t, "public.orders:orders_data.sql:12", batch.GetCorrelationID())
+	assert.Equal(t, "public.orders:orders_data.sql:12", batch.logger().Data[tgtdb.LOG_FIELD_BATCH_ID])
+}
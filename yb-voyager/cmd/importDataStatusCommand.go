@@ -16,6 +16,7 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -27,6 +28,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/gosuri/uitable"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
 
 	"github.com/yugabyte/yb-voyager/yb-voyager/src/datafile"
 	"github.com/yugabyte/yb-voyager/yb-voyager/src/datastore"
@@ -39,6 +41,10 @@ var importDataStatusCmd = &cobra.Command{
 
 	Run: func(cmd *cobra.Command, args []string) {
 		validateExportDirFlag()
+		importDataStatusOutput = strings.ToLower(importDataStatusOutput)
+		if !slices.Contains(validStatusOutputs, importDataStatusOutput) {
+			utils.ErrExit("Error: Invalid output: %q. Supported values are: %s", importDataStatusOutput, validStatusOutputs)
+		}
 		err := runImportDataStatusCmd()
 		if err != nil {
 			utils.ErrExit("error: %s\n", err)
@@ -46,18 +52,42 @@ var importDataStatusCmd = &cobra.Command{
 	},
 }
 
+var importDataStatusOutput string
+
 func init() {
 	importDataCmd.AddCommand(importDataStatusCmd)
+	importDataStatusCmd.Flags().StringVar(&importDataStatusOutput, "output", STATUS_OUTPUT_TABLE,
+		fmt.Sprintf("format of the status: %s or %s", STATUS_OUTPUT_TABLE, STATUS_OUTPUT_JSON))
 }
 
 // totalCount and importedCount store row-count for import data command and byte-count for import data file command.
 type tableMigStatusOutputRow struct {
 	tableName          string
 	fileName           string
+	filePath           string
 	status             string
+	importState        FileImportState
 	totalCount         int64
 	importedCount      int64
 	percentageComplete float64
+	importedRows       int64
+	importedBytes      int64
+	lastBatchNumber    int64
+}
+
+// importDataStatusJSONRow is the status of the import of a data file in the json output.
+type importDataStatusJSONRow struct {
+	TableName          string          `json:"table_name"`
+	FilePath           string          `json:"file_path"`
+	Status             string          `json:"status"`
+	ImportState        FileImportState `json:"file_import_state"`
+	TotalCount         int64           `json:"total_count"`
+	CountUnit          string          `json:"count_unit"` // rows or bytes
+	ImportedCount      int64           `json:"imported_count"`
+	PercentageComplete float64         `json:"percentage_complete"`
+	ImportedRows       int64           `json:"imported_rows"`
+	ImportedBytes      int64           `json:"imported_bytes"`
+	LastBatchNumber    int64           `json:"last_batch_number"`
 }
 
 // Note that the `import data status` is running in a separate process. It won't have access to the in-memory state
@@ -76,24 +106,31 @@ func runImportDataStatusCmd() error {
 	if err != nil {
 		return fmt.Errorf("prepare import data status table: %w", err)
 	}
+	if importDataStatusOutput == STATUS_OUTPUT_JSON {
+		return printImportDataStatusJSON(table)
+	}
 	uiTable := uitable.New()
 	headerfmt := color.New(color.FgGreen, color.Underline).SprintFunc()
 	for i, row := range table {
 		perc := fmt.Sprintf("%.2f", row.percentageComplete)
 		if reportProgressInBytes {
 			if i == 0 {
-				uiTable.AddRow(headerfmt("TABLE"), headerfmt("FILE"), headerfmt("STATUS"), headerfmt("TOTAL SIZE"), headerfmt("IMPORTED SIZE"), headerfmt("PERCENTAGE"))
+				uiTable.AddRow(headerfmt("TABLE"), headerfmt("FILE"), headerfmt("STATUS"), headerfmt("IMPORT STATE"), headerfmt("TOTAL SIZE"), headerfmt("IMPORTED SIZE"),
+					headerfmt("IMPORTED ROWS"), headerfmt("PERCENTAGE"), headerfmt("LAST BATCH"))
 			}
 			// case of importDataFileCommand where file size is available not row counts
 			totalCount := utils.HumanReadableByteCount(row.totalCount)
 			importedCount := utils.HumanReadableByteCount(row.importedCount)
-			uiTable.AddRow(row.tableName, row.fileName, row.status, totalCount, importedCount, perc)
+			uiTable.AddRow(row.tableName, row.fileName, row.status, row.importState.displayName(), totalCount, importedCount,
+				row.importedRows, perc, row.lastBatchNumber)
 		} else {
 			if i == 0 {
-				uiTable.AddRow(headerfmt("TABLE"), headerfmt("FILE"), headerfmt("STATUS"), headerfmt("TOTAL ROWS"), headerfmt("IMPORTED ROWS"), headerfmt("PERCENTAGE"))
+				uiTable.AddRow(headerfmt("TABLE"), headerfmt("FILE"), headerfmt("STATUS"), headerfmt("IMPORT STATE"), headerfmt("TOTAL ROWS"), headerfmt("IMPORTED ROWS"),
+					headerfmt("IMPORTED SIZE"), headerfmt("PERCENTAGE"), headerfmt("LAST BATCH"))
 			}
 			// case of importData where row counts is available
-			uiTable.AddRow(row.tableName, row.fileName, row.status, row.totalCount, row.importedCount, perc)
+			uiTable.AddRow(row.tableName, row.fileName, row.status, row.importState.displayName(), row.totalCount, row.importedCount,
+				utils.HumanReadableByteCount(row.importedBytes), perc, row.lastBatchNumber)
 		}
 	}
 
@@ -106,6 +143,40 @@ func runImportDataStatusCmd() error {
 	return nil
 }
 
+func printImportDataStatusJSON(table []*tableMigStatusOutputRow) error {
+	countUnit := "rows"
+	if reportProgressInBytes {
+		countUnit = "bytes"
+	}
+	jsonRows := make([]*importDataStatusJSONRow, 0, len(table))
+	for _, row := range table {
+		jsonRows = append(jsonRows, &importDataStatusJSONRow{
+			TableName:          row.tableName,
+			FilePath:           row.filePath,
+			Status:             row.status,
+			ImportState:        row.importState,
+			TotalCount:         row.totalCount,
+			CountUnit:          countUnit,
+			ImportedCount:      row.importedCount,
+			PercentageComplete: row.percentageComplete,
+			ImportedRows:       row.importedRows,
+			ImportedBytes:      row.importedBytes,
+			LastBatchNumber:    row.lastBatchNumber,
+		})
+	}
+	bytes, err := json.MarshalIndent(jsonRows, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshal import data status: %w", err)
+	}
+	fmt.Println(string(bytes))
+	return nil
+}
+
+// displayName returns the state without the FILE_IMPORT_ prefix, e.g. IN_PROGRESS.
+func (state FileImportState) displayName() string {
+	return strings.TrimPrefix(string(state), "FILE_IMPORT_")
+}
+
 func prepareDummyDescriptor(state *ImportDataState) (*datafile.Descriptor, error) {
 	var dataFileDescriptor datafile.Descriptor
 
@@ -149,20 +220,38 @@ func prepareImportDataStatusTable() ([]*tableMigStatusOutputRow, error) {
 		}
 	}
 
+	for _, dataFile := range dataFileDescriptor.DataFileList {
+		reportProgressInBytes = reportProgressInBytes || dataFile.RowCount == -1
+	}
 	for _, dataFile := range dataFileDescriptor.DataFileList {
 		var totalCount, importedCount int64
-		var err error
 
-		reportProgressInBytes = reportProgressInBytes || dataFile.RowCount == -1
+		importedRows, err := state.GetImportedRowCount(dataFile.FilePath, dataFile.TableName)
+		if err != nil {
+			return nil, fmt.Errorf("compute imported data size: %w", err)
+		}
+		importedBytes, err := state.GetImportedByteCount(dataFile.FilePath, dataFile.TableName)
+		if err != nil {
+			return nil, fmt.Errorf("compute imported data size: %w", err)
+		}
 		if reportProgressInBytes {
-			totalCount = dataFile.FileSize
-			importedCount, err = state.GetImportedByteCount(dataFile.FilePath, dataFile.TableName)
+			totalCount, importedCount = dataFile.FileSize, importedBytes
 		} else {
-			totalCount = dataFile.RowCount
-			importedCount, err = state.GetImportedRowCount(dataFile.FilePath, dataFile.TableName)
+			totalCount, importedCount = dataFile.RowCount, importedRows
 		}
+		importState, err := state.GetFileImportState(dataFile.FilePath, dataFile.TableName)
 		if err != nil {
-			return nil, fmt.Errorf("compute imported data size: %w", err)
+			return nil, fmt.Errorf("get import state of %q: %w", dataFile.FilePath, err)
+		}
+		batches, err := state.GetAllBatches(dataFile.FilePath, dataFile.TableName)
+		if err != nil {
+			return nil, fmt.Errorf("get batches of %q: %w", dataFile.FilePath, err)
+		}
+		var lastBatchNumber int64
+		for _, batch := range batches {
+			if batch.Number > lastBatchNumber {
+				lastBatchNumber = batch.Number
+			}
 		}
 		var perc float64
 		if totalCount != 0 {
@@ -179,11 +268,16 @@ func prepareImportDataStatusTable() ([]*tableMigStatusOutputRow, error) {
 		}
 		row := &tableMigStatusOutputRow{
 			fileName:           path.Base(dataFile.FilePath),
+			filePath:           dataFile.FilePath,
 			tableName:          dataFile.TableName,
 			status:             status,
+			importState:        importState,
 			totalCount:         totalCount,
 			importedCount:      importedCount,
 			percentageComplete: perc,
+			importedRows:       importedRows,
+			importedBytes:      importedBytes,
+			lastBatchNumber:    lastBatchNumber,
 		}
 		table = append(table, row)
 	}
@@ -0,0 +1,247 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gosuri/uitable"
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+var skippedFilteredTables []string // tables skipped by --table-list/--exclude-table-list, reported in the import summary
+
+type ImportDataSummary struct {
+	MigrationUUID        string                `json:"migration_uuid"`
+	StartTime            string                `json:"start_time"`
+	EndTime              string                `json:"end_time"`
+	ElapsedSeconds       float64               `json:"elapsed_seconds"`
+	Tables               []*TableImportSummary `json:"tables"`
+	TotalRows            int64                 `json:"total_rows"`
+	TotalBytes           int64                 `json:"total_bytes"`
+	RowsImportedInRun    int64                 `json:"rows_imported_in_run"`
+	AvgRowsPerSecond     float64               `json:"avg_rows_per_second"`
+	SkippedTables        []string              `json:"skipped_tables"`
+	SkippedMissingTables []string              `json:"skipped_missing_tables"`
+	MaxRowsPerTable      int64                 `json:"max_rows_per_table,omitempty"` // set for a partial import
+	RowCountMismatches   []*RowCountMismatch   `json:"row_count_mismatches"`
+	BatchLatency         *BatchLatencyStats    `json:"batch_latency,omitempty"` // nil if no batch is imported in the run
+	// found after the load with --disable-constraints-during-load
+	ForeignKeyViolations []*tgtdb.ForeignKeyViolation `json:"foreign_key_violations,omitempty"`
+}
+
+type TableImportSummary struct {
+	TableName     string `json:"table_name"`
+	NumFiles      int    `json:"num_files"`
+	ImportedRows  int64  `json:"imported_rows"`
+	ImportedBytes int64  `json:"imported_bytes"`
+}
+
+// BatchLatencyStats summarizes the durations of the COPY of the batches imported in the run, e.g. to tune the batch size.
+type BatchLatencyStats struct {
+	NumBatches int     `json:"num_batches"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P90Seconds float64 `json:"p90_seconds"`
+	P99Seconds float64 `json:"p99_seconds"`
+	MaxSeconds float64 `json:"max_seconds"`
+	// throughput of a single batch, i.e. the rows and bytes of the batches over the total duration of their COPY
+	RowsPerSecond  float64 `json:"rows_per_second"`
+	BytesPerSecond float64 `json:"bytes_per_second"`
+}
+
+type batchLatency struct {
+	duration time.Duration
+	rows     int64
+	bytes    int64
+}
+
+// of the batches imported in this run, excluding the failed attempts
+var batchLatencies []batchLatency
+var batchLatenciesMutex sync.Mutex
+
+func recordBatchLatency(duration time.Duration, rows int64, bytes int64) {
+	batchLatenciesMutex.Lock()
+	defer batchLatenciesMutex.Unlock()
+	batchLatencies = append(batchLatencies, batchLatency{duration: duration, rows: rows, bytes: bytes})
+}
+
+// getBatchLatencyStats returns nil if there are no batches.
+func getBatchLatencyStats(latencies []batchLatency) *BatchLatencyStats {
+	if len(latencies) == 0 {
+		return nil
+	}
+	durations := lo.Map(latencies, func(l batchLatency, _ int) time.Duration { return l.duration })
+	slices.Sort(durations)
+	// nearest-rank percentile
+	percentile := func(p int) float64 {
+		rank := (p*len(durations) + 99) / 100
+		return durations[lo.Max([]int{rank, 1})-1].Seconds()
+	}
+	stats := &BatchLatencyStats{
+		NumBatches: len(durations),
+		P50Seconds: percentile(50),
+		P90Seconds: percentile(90),
+		P99Seconds: percentile(99),
+		MaxSeconds: durations[len(durations)-1].Seconds(),
+	}
+	totalSeconds := lo.Sum(durations).Seconds()
+	if totalSeconds > 0 {
+		stats.RowsPerSecond = float64(lo.SumBy(latencies, func(l batchLatency) int64 { return l.rows })) / totalSeconds
+		stats.BytesPerSecond = float64(lo.SumBy(latencies, func(l batchLatency) int64 { return l.bytes })) / totalSeconds
+	}
+	return stats
+}
+
+// getTableImportSummaries returns the rows and bytes imported so far into each of the tables of the tasks,
+// in the order of the tasks.
+func getTableImportSummaries(state *ImportDataState, tasks []*ImportFileTask) ([]*TableImportSummary, error) {
+	var result []*TableImportSummary
+	tableToSummary := make(map[string]*TableImportSummary)
+	for _, task := range tasks {
+		summary, ok := tableToSummary[task.TableName]
+		if !ok {
+			summary = &TableImportSummary{TableName: task.TableName}
+			tableToSummary[task.TableName] = summary
+			result = append(result, summary)
+		}
+		rowCount, err := state.GetImportedRowCount(task.FilePath, task.TableName)
+		if err != nil {
+			return nil, err
+		}
+		byteCount, err := state.GetImportedByteCount(task.FilePath, task.TableName)
+		if err != nil {
+			return nil, err
+		}
+		summary.NumFiles++
+		summary.ImportedRows += rowCount
+		summary.ImportedBytes += byteCount
+	}
+	return result, nil
+}
+
+func getTotalImportedRows(tables []*TableImportSummary) int64 {
+	return lo.SumBy(tables, func(t *TableImportSummary) int64 { return t.ImportedRows })
+}
+
+// newImportDataSummary summarizes the import run which started at startTime, when rowsBefore rows
+// were already imported by the earlier runs.
+func newImportDataSummary(tables []*TableImportSummary, rowsBefore int64, startTime, endTime time.Time) *ImportDataSummary {
+	summary := &ImportDataSummary{
+		StartTime:            startTime.Format(time.RFC3339),
+		EndTime:              endTime.Format(time.RFC3339),
+		ElapsedSeconds:       endTime.Sub(startTime).Seconds(),
+		Tables:               tables,
+		TotalRows:            getTotalImportedRows(tables),
+		TotalBytes:           lo.SumBy(tables, func(t *TableImportSummary) int64 { return t.ImportedBytes }),
+		SkippedTables:        lo.Uniq(skippedFilteredTables),
+		SkippedMissingTables: skippedMissingTables,
+		MaxRowsPerTable:      maxRowsPerTable,
+		RowCountMismatches:   rowCountMismatches,
+		ForeignKeyViolations: foreignKeyViolations,
+	}
+	batchLatenciesMutex.Lock()
+	summary.BatchLatency = getBatchLatencyStats(batchLatencies)
+	batchLatenciesMutex.Unlock()
+	summary.RowsImportedInRun = summary.TotalRows - rowsBefore
+	if summary.ElapsedSeconds > 0 {
+		summary.AvgRowsPerSecond = float64(summary.RowsImportedInRun) / summary.ElapsedSeconds
+	}
+	return summary
+}
+
+func (s *ImportDataSummary) Print() {
+	uiTable := uitable.New()
+	headerfmt := color.New(color.FgGreen, color.Underline).SprintFunc()
+	uiTable.AddRow(headerfmt("TABLE"), headerfmt("FILES"), headerfmt("IMPORTED ROWS"), headerfmt("IMPORTED SIZE"))
+	for _, table := range s.Tables {
+		uiTable.AddRow(table.TableName, table.NumFiles, table.ImportedRows, utils.HumanReadableByteCount(table.ImportedBytes))
+	}
+	fmt.Printf("\nImport data summary:\n\n")
+	fmt.Print(uiTable)
+	fmt.Printf("\n\n")
+	fmt.Printf("Tables imported: %d\n", len(s.Tables))
+	fmt.Printf("Total rows: %d\n", s.TotalRows)
+	fmt.Printf("Total size: %s\n", utils.HumanReadableByteCount(s.TotalBytes))
+	fmt.Printf("Elapsed time: %s\n", time.Duration(s.ElapsedSeconds*float64(time.Second)).Round(time.Second))
+	fmt.Printf("Rows imported in this run: %d (%.2f rows/sec)\n", s.RowsImportedInRun, s.AvgRowsPerSecond)
+	if s.BatchLatency != nil {
+		fmt.Printf("Batch COPY latency (%d batches): p50 %.2fs, p90 %.2fs, p99 %.2fs, max %.2fs (%.2f rows/sec, %s/sec per batch)\n",
+			s.BatchLatency.NumBatches, s.BatchLatency.P50Seconds, s.BatchLatency.P90Seconds, s.BatchLatency.P99Seconds,
+			s.BatchLatency.MaxSeconds, s.BatchLatency.RowsPerSecond, utils.HumanReadableByteCount(int64(s.BatchLatency.BytesPerSecond)))
+	}
+	if s.MaxRowsPerTable > 0 {
+		fmt.Printf("PARTIAL import: at most %d rows of each table are imported (--max-rows-per-table)\n", s.MaxRowsPerTable)
+	}
+	if len(s.SkippedTables) > 0 {
+		fmt.Printf("Tables skipped by the table list filters: %v\n", s.SkippedTables)
+	}
+	if len(s.RowCountMismatches) > 0 {
+		mismatchTable := uitable.New()
+		mismatchTable.AddRow(headerfmt("TABLE"), headerfmt("FILE"), headerfmt("BATCH"), headerfmt("EXPECTED ROWS"), headerfmt("ROWS AFFECTED"))
+		for _, m := range s.RowCountMismatches {
+			mismatchTable.AddRow(m.TableName, m.FilePath, m.BatchNumber, m.ExpectedRows, m.RowsAffected)
+		}
+		color.Yellow("\nBatches whose rows affected by the import differ from their rows:\n")
+		fmt.Println(mismatchTable)
+	}
+	if len(s.ForeignKeyViolations) > 0 {
+		violationTable := uitable.New()
+		violationTable.AddRow(headerfmt("TABLE"), headerfmt("FOREIGN KEY"), headerfmt("REFERENCED TABLE"), headerfmt("ROWS"), headerfmt("SAMPLE ROWS"))
+		for _, v := range s.ForeignKeyViolations {
+			violationTable.AddRow(v.TableName, v.ConstraintName, v.RefTableName, v.NumRows, strings.Join(v.SampleRows, " "))
+		}
+		color.Red("\nForeign keys violated by the rows imported with --disable-constraints-during-load:\n")
+		fmt.Println(violationTable)
+	}
+}
+
+// writeImportDataSummary prints the summary of the import run and records it in the reports dir of the export dir.
+func writeImportDataSummary(state *ImportDataState, tasks []*ImportFileTask, rowsBefore int64, startTime time.Time) {
+	tables, err := getTableImportSummaries(state, tasks)
+	if err != nil {
+		utils.ErrExit("get the imported row counts for the import summary: %s", err)
+	}
+	summary := newImportDataSummary(tables, rowsBefore, startTime, time.Now())
+	summary.MigrationUUID = migrationUUID.String()
+	summary.Print()
+
+	reportDir := filepath.Join(exportDir, "reports")
+	err = os.MkdirAll(reportDir, 0755)
+	if err != nil {
+		utils.ErrExit("create reports dir %q: %s", reportDir, err)
+	}
+	jsonBytes, err := json.MarshalIndent(summary, "", "    ")
+	if err != nil {
+		utils.ErrExit("marshal import data summary: %s", err)
+	}
+	jsonPath := filepath.Join(reportDir, "import_data_summary.json")
+	err = os.WriteFile(jsonPath, jsonBytes, 0644)
+	if err != nil {
+		utils.ErrExit("write import data summary to %q: %s", jsonPath, err)
+	}
+	utils.PrintAndLog("import data summary written to %q", jsonPath)
+}
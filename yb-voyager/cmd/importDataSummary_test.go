@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+)
+
+func TestNewImportDataSummary(t *testing.T) {
+	tables := []*TableImportSummary{
+		{TableName: "public.foo", NumFiles: 2, ImportedRows: 300, ImportedBytes: 3000},
+		{TableName: "public.bar", NumFiles: 1, ImportedRows: 100, ImportedBytes: 500},
+	}
+	skippedFilteredTables = []string{"public.baz", "public.baz", "public.qux"}
+	defer func() { skippedFilteredTables = nil }()
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	summary := newImportDataSummary(tables, 150, startTime, startTime.Add(50*time.Second))
+	assert.Equal(t, int64(400), summary.TotalRows)
+	assert.Equal(t, int64(3500), summary.TotalBytes)
+	assert.Equal(t, float64(50), summary.ElapsedSeconds)
+	assert.Equal(t, int64(250), summary.RowsImportedInRun)
+	assert.Equal(t, float64(5), summary.AvgRowsPerSecond)
+	assert.Equal(t, []string{"public.baz", "public.qux"}, summary.SkippedTables)
+
+	summary = newImportDataSummary(tables, 0, startTime, startTime)
+	assert.Equal(t, float64(0), summary.AvgRowsPerSecond)
+	assert.Nil(t, summary.ForeignKeyViolations)
+
+	foreignKeyViolations = []*tgtdb.ForeignKeyViolation{
+		{ConstraintName: "foo_bar_fk", TableName: "public.foo", RefTableName: "public.bar", NumRows: 2, SampleRows: []string{"(1)", "(2)"}},
+	}
+	defer func() { foreignKeyViolations = nil }()
+	summary = newImportDataSummary(tables, 0, startTime, startTime)
+	assert.Equal(t, foreignKeyViolations, summary.ForeignKeyViolations)
+}
+
+func TestGetBatchLatencyStats(t *testing.T) {
+	assert.Nil(t, getBatchLatencyStats(nil))
+
+	var latencies []batchLatency
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, batchLatency{duration: time.Duration(i) * time.Second, rows: 101, bytes: 202})
+	}
+	stats := getBatchLatencyStats(latencies)
+	assert.Equal(t, 100, stats.NumBatches)
+	assert.Equal(t, float64(50), stats.P50Seconds)
+	assert.Equal(t, float64(90), stats.P90Seconds)
+	assert.Equal(t, float64(99), stats.P99Seconds)
+	assert.Equal(t, float64(100), stats.MaxSeconds)
+	assert.Equal(t, float64(2), stats.RowsPerSecond)
+	assert.Equal(t, float64(4), stats.BytesPerSecond)
+
+	stats = getBatchLatencyStats([]batchLatency{{duration: 0, rows: 10, bytes: 100}})
+	assert.Equal(t, 1, stats.NumBatches)
+	assert.Equal(t, float64(0), stats.P99Seconds)
+	assert.Equal(t, float64(0), stats.RowsPerSecond)
+}
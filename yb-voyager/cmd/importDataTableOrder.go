@@ -0,0 +1,172 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+var tableImportOrder string
+var reorderByDependencies bool
+
+var referencesRegex = regexp.MustCompile(`(?i)\bREFERENCES\s+([a-zA-Z0-9_."]+)`)
+
+// getPinnedTableImportOrder returns the tables listed by --table-import-order, either comma separated
+// or one per line in the file at the given path.
+func getPinnedTableImportOrder() ([]string, error) {
+	if !utils.FileOrFolderExists(tableImportOrder) {
+		return utils.CsvStringToSlice(tableImportOrder), nil
+	}
+	bytes, err := os.ReadFile(tableImportOrder)
+	if err != nil {
+		return nil, fmt.Errorf("read table import order file %q: %w", tableImportOrder, err)
+	}
+	var tableNames []string
+	for _, line := range strings.Split(string(bytes), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tableNames = append(tableNames, line)
+	}
+	return tableNames, nil
+}
+
+// reorderImportFileTasks applies --table-import-order or --reorder-by-dependencies to the tasks.
+func reorderImportFileTasks(tasks []*ImportFileTask) []*ImportFileTask {
+	var orderedTableNames []string
+	switch {
+	case tableImportOrder != "":
+		pinnedTableNames, err := getPinnedTableImportOrder()
+		if err != nil {
+			utils.ErrExit("Error: %s", err)
+		}
+		orderedTableNames, err = matchTableNames(pinnedTableNames, importFileTasksToTableNames(tasks))
+		if err != nil {
+			utils.ErrExit("Error: Invalid table-import-order: %s", err)
+		}
+	case reorderByDependencies:
+		tableFilePath := utils.GetObjectFilePath(filepath.Join(exportDir, "schema"), "TABLE")
+		if !utils.FileOrFolderExists(tableFilePath) {
+			utils.PrintAndLog("exported schema file %q not found, the tables are imported in their natural order", tableFilePath)
+			return tasks
+		}
+		tableNames := importFileTasksToTableNames(tasks)
+		var cyclicTableNames []string
+		orderedTableNames, cyclicTableNames = getTableDependencyOrder(tableNames, getForeignKeyDependencies(tableFilePath, tableNames))
+		if len(cyclicTableNames) > 0 {
+			utils.PrintAndLog("tables with cyclic foreign key dependencies are imported in their natural order: %v", cyclicTableNames)
+		}
+	default:
+		return tasks
+	}
+	tasks = pinImportFileTasks(tasks, orderedTableNames)
+	log.Infof("import order of the tables: %v", importFileTasksToTableNames(tasks))
+	return tasks
+}
+
+// matchTableNames returns the names of the tables matching the given names, in the same order.
+func matchTableNames(names []string, tableNames []string) ([]string, error) {
+	var result, unknownNames []string
+	for _, name := range names {
+		tableName, found := lo.Find(tableNames, func(tableName string) bool { return tableNamesMatch(name, tableName) })
+		if !found {
+			unknownNames = append(unknownNames, name)
+			continue
+		}
+		if !slices.Contains(result, tableName) {
+			result = append(result, tableName)
+		}
+	}
+	if len(unknownNames) > 0 {
+		return nil, fmt.Errorf("unknown table names %v, valid table names are: %v", unknownNames, tableNames)
+	}
+	return result, nil
+}
+
+// pinImportFileTasks moves the tasks of the given tables to the front in the given order. The tasks of
+// the other tables keep their order after them, as do the tasks of the same table.
+func pinImportFileTasks(tasks []*ImportFileTask, orderedTableNames []string) []*ImportFileTask {
+	result := make([]*ImportFileTask, 0, len(tasks))
+	for _, tableName := range orderedTableNames {
+		for _, task := range tasks {
+			if task.TableName == tableName {
+				result = append(result, task)
+			}
+		}
+	}
+	for _, task := range tasks {
+		if !slices.Contains(orderedTableNames, task.TableName) {
+			result = append(result, task)
+		}
+	}
+	return result
+}
+
+// getForeignKeyDependencies returns the tables referenced by the foreign keys of each of the given tables,
+// from the CREATE TABLE and ALTER TABLE statements in the table.sql of the exported schema.
+func getForeignKeyDependencies(tableFilePath string, tableNames []string) map[string][]string {
+	dependencies := make(map[string][]string)
+	for _, sqlInfo := range createSqlStrInfoArray(tableFilePath, "TABLE") {
+		objName := sqlInfo.objName
+		if objName == "" {
+			if matches := alterTableRegex.FindStringSubmatch(sqlInfo.stmt); matches != nil {
+				objName = matches[1]
+			}
+		}
+		tableName, found := lo.Find(tableNames, func(tableName string) bool { return objName != "" && tableNamesMatch(objName, tableName) })
+		if !found {
+			continue
+		}
+		for _, matches := range referencesRegex.FindAllStringSubmatch(sqlInfo.stmt, -1) {
+			referencedTableName, found := lo.Find(tableNames, func(t string) bool { return tableNamesMatch(matches[1], t) })
+			if found && referencedTableName != tableName && !slices.Contains(dependencies[tableName], referencedTableName) {
+				dependencies[tableName] = append(dependencies[tableName], referencedTableName)
+			}
+		}
+	}
+	return dependencies
+}
+
+/*
+getTableDependencyOrder orders the tables such that the tables referenced by the foreign keys of a table
+come before it. Among the tables whose referenced tables are all ordered, the natural order is retained.
+The tables which are part of (or depend on) a cycle can't be ordered, and are returned separately.
+*/
+func getTableDependencyOrder(tableNames []string, dependencies map[string][]string) (ordered []string, cyclic []string) {
+	remaining := slices.Clone(tableNames)
+	for len(remaining) > 0 {
+		i := slices.IndexFunc(remaining, func(tableName string) bool {
+			return lo.Every(ordered, dependencies[tableName])
+		})
+		if i == -1 {
+			return ordered, remaining
+		}
+		ordered = append(ordered, remaining[i])
+		remaining = slices.Delete(remaining, i, i+1)
+	}
+	return ordered, nil
+}
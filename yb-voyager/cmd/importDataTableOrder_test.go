@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPinImportFileTasks(t *testing.T) {
+	assert := assert.New(t)
+	var tasks []*ImportFileTask
+	for i, name := range []string{"a", "b", "c", "b", "d"} {
+		tasks = append(tasks, &ImportFileTask{ID: i, TableName: name})
+	}
+	tasks = pinImportFileTasks(tasks, []string{"d", "b"})
+	assert.Equal([]string{"d", "b", "b", "a", "c"}, lo.Map(tasks, func(task *ImportFileTask, _ int) string { return task.TableName }))
+	assert.Equal([]int{4, 1, 3, 0, 2}, lo.Map(tasks, func(task *ImportFileTask, _ int) int { return task.ID }))
+
+	tableNames, err := matchTableNames([]string{`"Orders"`, "public.customers", "Orders"}, []string{"public.customers", "public.orders"})
+	assert.NoError(err)
+	assert.Equal([]string{"public.orders", "public.customers"}, tableNames)
+	_, err = matchTableNames([]string{"customers", "items"}, []string{"public.customers", "public.orders"})
+	assert.ErrorContains(err, "unknown table names [items]")
+}
+
+func TestTableDependencyOrder(t *testing.T) {
+	assert := assert.New(t)
+	tableFilePath := filepath.Join(t.TempDir(), "table.sql")
+	schema := `CREATE TABLE public.order_items (
+    id integer NOT NULL,
+    order_id integer REFERENCES public.orders(id),
+    item_id integer
+);
+
+CREATE TABLE public.orders (
+    id integer NOT NULL,
+    customer_id integer
+);
+
+CREATE TABLE public.customers (
+    id integer NOT NULL
+);
+
+CREATE TABLE public.items (
+    id integer NOT NULL
+);
+
+ALTER TABLE ONLY public.orders
+    ADD CONSTRAINT orders_customer_id_fkey FOREIGN KEY (customer_id) REFERENCES public.customers(id);
+
+ALTER TABLE ONLY public.order_items
+    ADD CONSTRAINT order_items_item_id_fkey FOREIGN KEY (item_id) REFERENCES public.items(id);
+`
+	assert.NoError(os.WriteFile(tableFilePath, []byte(schema), 0644))
+	tableNames := []string{"public.order_items", "public.orders", "public.customers", "public.items"}
+	dependencies := getForeignKeyDependencies(tableFilePath, tableNames)
+	assert.Equal(map[string][]string{
+		"public.order_items": {"public.orders", "public.items"},
+		"public.orders":      {"public.customers"},
+	}, dependencies)
+
+	ordered, cyclic := getTableDependencyOrder(tableNames, dependencies)
+	assert.Equal([]string{"public.customers", "public.orders", "public.items", "public.order_items"}, ordered)
+	assert.Empty(cyclic)
+
+	// a and b reference each other, c depends on the cycle
+	ordered, cyclic = getTableDependencyOrder([]string{"a", "b", "c", "d"}, map[string][]string{"a": {"b"}, "b": {"a"}, "c": {"a"}})
+	assert.Equal([]string{"d"}, ordered)
+	assert.Equal([]string{"a", "b", "c"}, cyclic)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+// Batch latency (in seconds) above which the target is considered to be under pressure. 0 disables throttling.
+var throttleLatencyThresholdSec int
+
+// Duration for which the import is paused when the target is under pressure even with a single batch in flight.
+var THROTTLE_PAUSE_DURATION_SEC int
+
+func init() {
+	THROTTLE_PAUSE_DURATION_SEC = utils.GetEnvAsInt("THROTTLE_PAUSE_DURATION_SEC", 30)
+}
+
+/*
+ImportThrottler adapts the number of batches imported concurrently to the pressure on the target db.
+The pressure is observed as the (exponentially weighted) moving average of the batch import latencies:
+  - above the threshold, the number of concurrent batches is reduced by one, down to a single batch.
+    With a single batch in flight, the import is paused for a while before the next batch.
+  - below 80% of the threshold, the number of concurrent batches is increased by one, up to `parallelism`.
+*/
+type ImportThrottler struct {
+	sync.Mutex
+	cond *sync.Cond
+
+	latencyThreshold time.Duration
+	maxInFlight      int
+	allowedInFlight  int
+	inFlight         int
+	avgLatency       time.Duration
+	pauseUntil       time.Time
+	throttled        bool // whether the allowed concurrency is reduced, or the import is paused
+
+	progressReporter *ImportDataProgressReporter
+}
+
+func NewImportThrottler(latencyThresholdSec int, parallelism int, progressReporter *ImportDataProgressReporter) *ImportThrottler {
+	t := &ImportThrottler{
+		latencyThreshold: time.Duration(latencyThresholdSec) * time.Second,
+		maxInFlight:      parallelism,
+		allowedInFlight:  parallelism,
+		progressReporter: progressReporter,
+	}
+	t.cond = sync.NewCond(&t.Mutex)
+	return t
+}
+
+func (t *ImportThrottler) enabled() bool {
+	return t != nil && t.latencyThreshold > 0
+}
+
+// Acquire blocks until a batch can be imported without exceeding the allowed concurrency.
+func (t *ImportThrottler) Acquire() {
+	if !t.enabled() {
+		return
+	}
+	t.Lock()
+	for t.inFlight >= t.allowedInFlight {
+		t.cond.Wait()
+	}
+	t.inFlight++
+	pauseFor := time.Until(t.pauseUntil)
+	t.Unlock()
+	if pauseFor > 0 {
+		time.Sleep(pauseFor)
+	}
+}
+
+// Release records the latency of a batch import and adjusts the allowed concurrency accordingly.
+func (t *ImportThrottler) Release(latency time.Duration) {
+	if !t.enabled() {
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	defer t.cond.Broadcast()
+	t.inFlight--
+
+	if t.avgLatency == 0 {
+		t.avgLatency = latency
+	} else {
+		t.avgLatency = (t.avgLatency*7 + latency*3) / 10
+	}
+	changed := true
+	switch true {
+	case t.avgLatency > t.latencyThreshold && t.allowedInFlight > 1:
+		t.allowedInFlight--
+	case t.avgLatency > t.latencyThreshold:
+		t.pauseUntil = time.Now().Add(time.Duration(THROTTLE_PAUSE_DURATION_SEC) * time.Second)
+		log.Infof("target under pressure with a single batch in flight: pausing import until %s", t.pauseUntil)
+	case t.avgLatency < t.latencyThreshold*8/10 && t.allowedInFlight < t.maxInFlight:
+		t.allowedInFlight++
+	default:
+		changed = false
+	}
+	if t.allowedInFlight == t.maxInFlight && time.Now().After(t.pauseUntil) {
+		// the status is cleared once the target recovers, rather than showing the last decision
+		if t.throttled {
+			t.throttled = false
+			log.Infof("import throttling ended: batch latency %.1fs (threshold %s)", t.avgLatency.Seconds(), t.latencyThreshold)
+			t.progressReporter.UpdateThrottleStatus("")
+		}
+		return
+	}
+	t.throttled = true
+	if !changed {
+		return
+	}
+	status := fmt.Sprintf("batch latency %.1fs (threshold %s), parallel batches %d/%d",
+		t.avgLatency.Seconds(), t.latencyThreshold, t.allowedInFlight, t.maxInFlight)
+	log.Infof("import throttling: %s", status)
+	t.progressReporter.UpdateThrottleStatus(status)
+}
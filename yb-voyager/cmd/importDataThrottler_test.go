@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportThrottlerClearsStatus(t *testing.T) {
+	assert := assert.New(t)
+	defer func(d int) { THROTTLE_PAUSE_DURATION_SEC = d }(THROTTLE_PAUSE_DURATION_SEC)
+	THROTTLE_PAUSE_DURATION_SEC = 0
+	progressReporter := NewImportDataProgressReporter(true)
+	throttler := NewImportThrottler(1, 2, progressReporter)
+	throttleStatus := func() string {
+		status, _ := progressReporter.throttleStatus.Load().(string)
+		return status
+	}
+
+	throttler.Acquire()
+	throttler.Acquire()
+	throttler.Release(2 * time.Second)
+	assert.Equal(1, throttler.allowedInFlight)
+	assert.Contains(throttleStatus(), "parallel batches 1/2")
+
+	// the average latency goes down to 1.4s, 0.98s and then 0.686s, below 80% of the threshold
+	for _, allowedInFlight := range []int{1, 1, 2} {
+		throttler.Release(0)
+		throttler.Acquire()
+		assert.Equal(allowedInFlight, throttler.allowedInFlight)
+		if allowedInFlight < 2 {
+			assert.NotEmpty(throttleStatus())
+		}
+	}
+	assert.Empty(throttleStatus())
+	assert.False(throttler.throttled)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/gosuri/uitable"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/dbzm"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+// checkDataTypeCompatibility reports the exported columns of the tables being imported whose values may not
+// convert cleanly, and aborts the import on finding any with --strict-type-check. Nothing is changed on the target.
+// Only the data exported with debezium is checked, as the types of the exported columns are known only for it.
+func checkDataTypeCompatibility(tasks []*ImportFileTask) {
+	if !dbzm.IsDebeziumForDataExport(exportDir) {
+		utils.PrintAndLog("WARNING: the data types of the exported columns are not checked, " +
+			"as the data was not exported with debezium")
+		return
+	}
+	tableNames := importFileTasksToTableNames(tasks)
+	tableNameToColumns := make(map[string][]string)
+	for tableName, columns := range dataFileDescriptor.TableNameToExportedColumns {
+		if slices.Contains(tableNames, tableName) {
+			tableNameToColumns[tableName] = columns
+		}
+	}
+	issues := valueConverter.GetTypeCompatibilityIssues(tableNameToColumns)
+	if len(issues) == 0 {
+		log.Infof("no data type compatibility issues found in the exported columns")
+		return
+	}
+	printTypeCompatibilityIssues(issues)
+	if strictTypeCheck {
+		utils.ErrExit("Error: %d columns may not be imported as they are exported (--strict-type-check). "+
+			"Please handle them manually and retry", len(issues))
+	}
+}
+
+func printTypeCompatibilityIssues(issues []*dbzm.TypeCompatibilityIssue) {
+	uiTable := uitable.New()
+	uiTable.Wrap = true
+	uiTable.MaxColWidth = 60
+	headerfmt := color.New(color.FgGreen, color.Underline).SprintFunc()
+	uiTable.AddRow(headerfmt("TABLE"), headerfmt("COLUMN"), headerfmt("TYPE"), headerfmt("REASON"))
+	for _, issue := range issues {
+		uiTable.AddRow(issue.TableName, issue.ColumnName, issue.Type, issue.Reason)
+		log.Warnf("data type compatibility: column %s of table %s of type %s: %s", issue.ColumnName, issue.TableName, issue.Type, issue.Reason)
+	}
+	color.Yellow("\nColumns whose values may not convert cleanly on import:\n")
+	fmt.Println(uiTable)
+	fmt.Println()
+}
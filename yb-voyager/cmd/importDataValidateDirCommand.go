@@ -0,0 +1,166 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/gosuri/uitable"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/datafile"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/datastore"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/dbzm"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+var importDataValidateDirCmd = &cobra.Command{
+	Use:   "validate-dir",
+	Short: "Check that the export-dir has everything required to import the data",
+	Long: "Check the metainfo, the completion of the data export, the export status, the data file descriptor and the data files " +
+		"of the export-dir, printing a checklist. It exits with an error if any of the required items is missing. " +
+		"The target database is not connected to.",
+
+	Run: func(cmd *cobra.Command, args []string) {
+		validateExportDirFlag()
+		checks := runExportDirChecks(exportDir)
+		printExportDirChecks(checks)
+		failedChecks := lo.Filter(checks, func(check *exportDirCheck, _ int) bool { return check.err != nil })
+		if len(failedChecks) > 0 {
+			utils.ErrExit("Error: export-dir %q can't be imported, %d of the %d checks failed", exportDir, len(failedChecks), len(checks))
+		}
+		utils.PrintAndLog("export-dir %q is ready to be imported", exportDir)
+	},
+}
+
+func init() {
+	importDataCmd.AddCommand(importDataValidateDirCmd)
+}
+
+type exportDirCheck struct {
+	name   string
+	detail string
+	err    error // nil if the check passed
+}
+
+// runExportDirChecks checks the items of the export-dir which the import data needs. The checks of the data
+// file descriptor and the data files are failed, rather than run, if the descriptor can't be loaded.
+func runExportDirChecks(exportDir string) []*exportDirCheck {
+	var checks []*exportDirCheck
+	check := func(name string, fn func() (string, error)) {
+		detail, err := fn()
+		checks = append(checks, &exportDirCheck{name: name, detail: detail, err: err})
+	}
+
+	metaInfoDirPath := filepath.Join(exportDir, META_INFO_DIR_NAME)
+	check("metainfo", func() (string, error) {
+		if !utils.FileOrFolderExists(metaInfoDirPath) {
+			return "", fmt.Errorf("%q not found", metaInfoDirPath)
+		}
+		sourceDBType := ExtractMetaInfo(exportDir).SourceDBType
+		if sourceDBType == "" {
+			return "", fmt.Errorf("source db type is not recorded in %q", metaInfoDirPath)
+		}
+		return fmt.Sprintf("source db type: %s", sourceDBType), nil
+	})
+	check("export data done", func() (string, error) {
+		flagFilePath := filepath.Join(metaInfoDirPath, "flags", "exportDataDone")
+		if !utils.FileOrFolderExists(flagFilePath) {
+			return "", fmt.Errorf("%q not found, the data export is not complete", flagFilePath)
+		}
+		return "", nil
+	})
+	check("export status", func() (string, error) {
+		statusFilePath := filepath.Join(exportDir, "data", "export_status.json")
+		status, err := dbzm.ReadExportStatus(statusFilePath)
+		if err != nil {
+			return "", err
+		}
+		if status == nil {
+			// only the data exported with debezium has the export status
+			return "not found, not required for the data exported without debezium", nil
+		}
+		return fmt.Sprintf("%d tables, %d sequences", len(status.Tables), len(status.Sequences)), nil
+	})
+
+	var dfd *datafile.Descriptor
+	check("data file descriptor", func() (string, error) {
+		dfdFilePath := exportDir + datafile.DESCRIPTOR_PATH
+		bytes, err := os.ReadFile(dfdFilePath)
+		if err != nil {
+			return "", fmt.Errorf("read %q: %w", dfdFilePath, err)
+		}
+		if !json.Valid(bytes) {
+			return "", fmt.Errorf("%q is not valid json", dfdFilePath)
+		}
+		dfd = datafile.OpenDescriptor(exportDir)
+		return fmt.Sprintf("file format: %s", dfd.FileFormat), nil
+	})
+	check("voyager version", func() (string, error) {
+		if dfd == nil {
+			return "", fmt.Errorf("data file descriptor not loaded")
+		}
+		if dfd.DataFileList == nil {
+			return "", fmt.Errorf("the data is exported using older version of Voyager, use the matching version to import the data")
+		}
+		return "", nil
+	})
+	check("data files", func() (string, error) {
+		if dfd == nil {
+			return "", fmt.Errorf("data file descriptor not loaded")
+		}
+		dataStore := datastore.NewDataStore(filepath.Join(exportDir, "data"))
+		var missingFilePaths []string
+		for _, fileEntry := range dfd.DataFileList {
+			_, err := dataStore.FileSize(fileEntry.FilePath)
+			if err != nil {
+				log.Infof("data file %q of table %s not found: %s", fileEntry.FilePath, fileEntry.TableName, err)
+				missingFilePaths = append(missingFilePaths, fileEntry.FilePath)
+			}
+		}
+		if len(missingFilePaths) > 0 {
+			return "", fmt.Errorf("%d of the %d data files not found: %s", len(missingFilePaths), len(dfd.DataFileList),
+				strings.Join(missingFilePaths, ", "))
+		}
+		return fmt.Sprintf("%d data files", len(dfd.DataFileList)), nil
+	})
+	return checks
+}
+
+func printExportDirChecks(checks []*exportDirCheck) {
+	uiTable := uitable.New()
+	uiTable.Wrap = true
+	uiTable.MaxColWidth = 80
+	headerfmt := color.New(color.FgGreen, color.Underline).SprintFunc()
+	uiTable.AddRow(headerfmt("CHECK"), headerfmt("STATUS"), headerfmt("DETAILS"))
+	for _, check := range checks {
+		if check.err != nil {
+			uiTable.AddRow(check.name, color.RedString("FAIL"), check.err)
+		} else {
+			uiTable.AddRow(check.name, color.GreenString("PASS"), check.detail)
+		}
+	}
+	fmt.Println()
+	fmt.Println(uiTable)
+	fmt.Println()
+}
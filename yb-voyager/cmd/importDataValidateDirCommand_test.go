@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunExportDirChecks(t *testing.T) {
+	assert := assert.New(t)
+	exportDir := t.TempDir()
+	writeFile := func(name string, content string) {
+		filePath := filepath.Join(exportDir, name)
+		assert.NoError(os.MkdirAll(filepath.Dir(filePath), 0755))
+		assert.NoError(os.WriteFile(filePath, []byte(content), 0644))
+	}
+	failedChecks := func() []string {
+		checks := runExportDirChecks(exportDir)
+		assert.Equal(6, len(checks))
+		return lo.FilterMap(checks, func(check *exportDirCheck, _ int) (string, bool) { return check.name, check.err != nil })
+	}
+	assert.Equal([]string{"metainfo", "export data done", "data file descriptor", "voyager version", "data files"}, failedChecks())
+
+	writeFile("metainfo/schema/source-db-postgresql", "")
+	writeFile("metainfo/flags/exportDataDone", "")
+	writeFile("metainfo/dataFileDescriptor.json", `{"FileFormat": "text"}`)
+	assert.Equal([]string{"voyager version"}, failedChecks())
+
+	writeFile("metainfo/dataFileDescriptor.json",
+		`{"FileFormat": "text", "FileList": [{"FilePath": "t1_data.sql", "TableName": "t1"}, {"FilePath": "t2_data.sql", "TableName": "t2"}]}`)
+	writeFile("data/t1_data.sql", "1\n")
+	assert.Equal([]string{"data files"}, failedChecks())
+
+	writeFile("data/t2_data.sql", "2\n")
+	writeFile("data/export_status.json", "{")
+	assert.Equal([]string{"export status"}, failedChecks())
+
+	writeFile("data/export_status.json", `{"tables": [], "sequences": {}}`)
+	assert.Empty(failedChecks())
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/gosuri/uitable"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/datafile"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+var verifyRowCounts bool
+
+// getExpectedRowCounts returns the rows exported for each table of the tasks, i.e. after the --table-list and
+// --exclude-table-list filtering. Tables with a file whose row count is not known (import data file) are left out.
+func getExpectedRowCounts(importFileTasks []*ImportFileTask, fileEntries []*datafile.FileEntry) (map[string]int64, []string) {
+	taskFilePaths := lo.SliceToMap(importFileTasks, func(task *ImportFileTask) (string, bool) { return task.FilePath, true })
+	expectedRowCounts := make(map[string]int64)
+	var unknownTables []string
+	for _, fileEntry := range fileEntries {
+		if !taskFilePaths[fileEntry.FilePath] || slices.Contains(unknownTables, fileEntry.TableName) {
+			continue
+		}
+		if fileEntry.RowCount < 0 {
+			unknownTables = append(unknownTables, fileEntry.TableName)
+			delete(expectedRowCounts, fileEntry.TableName)
+			continue
+		}
+		expectedRowCounts[fileEntry.TableName] += fileEntry.RowCount
+	}
+	return expectedRowCounts, unknownTables
+}
+
+// verifySnapshotRowCounts compares the rows of the imported tables on the target db with the exported rows,
+// and exits with an error if any of them differ.
+func verifySnapshotRowCounts(importFileTasks []*ImportFileTask) {
+	expectedRowCounts, unknownTables := getExpectedRowCounts(importFileTasks, dataFileDescriptor.DataFileList)
+	if len(unknownTables) > 0 {
+		utils.PrintAndLog("skipping the row count verification of the tables with unknown exported row counts: %v", unknownTables)
+	}
+	tableNames := lo.Keys(expectedRowCounts)
+	slices.Sort(tableNames)
+	utils.PrintAndLog("verifying the row counts of %d tables", len(tableNames))
+
+	table := uitable.New()
+	headerfmt := color.New(color.FgGreen, color.Underline).SprintFunc()
+	table.AddRow(headerfmt("TABLE"), headerfmt("EXPORTED ROWS"), headerfmt("IMPORTED ROWS"))
+	var mismatchedTables []string
+	for _, tableName := range tableNames {
+		actualRowCount, err := tdb.GetRowCount(getTargetTableName(tableName))
+		if err != nil {
+			utils.ErrExit("verify row count of table %q: %s", tableName, err)
+		}
+		log.Infof("table %q: exported rows %d, imported rows %d", tableName, expectedRowCounts[tableName], actualRowCount)
+		if actualRowCount != expectedRowCounts[tableName] {
+			mismatchedTables = append(mismatchedTables, tableName)
+			table.AddRow(tableName, expectedRowCounts[tableName], actualRowCount)
+		}
+	}
+	if len(mismatchedTables) == 0 {
+		utils.PrintAndLog("row counts of all the tables match the exported row counts")
+		return
+	}
+	fmt.Print("\n")
+	fmt.Println(table)
+	fmt.Print("\n")
+	utils.ErrExit("row counts of %d tables don't match the exported row counts: %v", len(mismatchedTables), mismatchedTables)
+}
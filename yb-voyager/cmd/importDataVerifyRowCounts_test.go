@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/datafile"
+)
+
+func TestGetExpectedRowCounts(t *testing.T) {
+	assert := assert.New(t)
+	fileEntries := []*datafile.FileEntry{
+		{FilePath: "/data/t1_1.sql", TableName: "t1", RowCount: 10},
+		{FilePath: "/data/t1_2.sql", TableName: "t1", RowCount: 5},
+		{FilePath: "/data/t2.sql", TableName: "t2", RowCount: 7},
+		{FilePath: "/data/t3.sql", TableName: "t3", RowCount: -1},
+		{FilePath: "/data/t4.sql", TableName: "t4", RowCount: 3},
+	}
+	tasks := []*ImportFileTask{ // t2 is filtered out
+		{FilePath: "/data/t1_1.sql", TableName: "t1"},
+		{FilePath: "/data/t1_2.sql", TableName: "t1"},
+		{FilePath: "/data/t3.sql", TableName: "t3"},
+		{FilePath: "/data/t4.sql", TableName: "t4"},
+	}
+	expectedRowCounts, unknownTables := getExpectedRowCounts(tasks, fileEntries)
+	assert.Equal(map[string]int64{"t1": 15, "t4": 3}, expectedRowCounts)
+	assert.Equal([]string{"t3"}, unknownTables)
+}
@@ -17,13 +17,18 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/fatih/color"
 	"github.com/jackc/pgx/v4"
+	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/slices"
@@ -39,6 +44,17 @@ var importSchemaCmd = &cobra.Command{
 
 	PreRun: func(cmd *cobra.Command, args []string) {
 		validateImportFlags(cmd)
+		validateIdempotentStmtPatterns()
+		validateAllowedDDLTypesFlag()
+		if ddlTimeout < 0 || indexDDLTimeout < 0 {
+			utils.ErrExit("Error: --ddl-timeout (--ddl-statement-timeout) and --index-ddl-timeout must not be negative")
+		}
+		if indexCreationParallelism < 1 {
+			utils.ErrExit("Error: Invalid index-creation-parallelism: %d. It must be at least 1", indexCreationParallelism)
+		}
+		if postSnapshotIndexes {
+			flagPostImportData = true
+		}
 	},
 
 	Run: func(cmd *cobra.Command, args []string) {
@@ -58,6 +74,16 @@ func init() {
 var flagPostImportData bool
 var importObjectsInStraightOrder bool
 var flagRefreshMViews bool
+var ddlTimeout, indexDDLTimeout time.Duration
+var allowedDDLTypesFlag string
+var allowedDDLTypes []string    // statement types (leading keywords) allowed to run; all if empty
+var disallowedSqlStmts []string // statements skipped as their type is not allowed, for manual review
+
+// number of times a statement which timed out is retried before it is reported as failed
+var DDL_TIMEOUT_MAX_RETRY_COUNT = utils.GetEnvAsInt("DDL_TIMEOUT_MAX_RETRY_COUNT", DDL_MAX_RETRY_COUNT)
+
+// time for which the client waits beyond the statement_timeout for the server to cancel the statement
+var DDL_TIMEOUT_GRACE_PERIOD = utils.GetEnvAsDuration("DDL_TIMEOUT_GRACE_PERIOD", 30*time.Second, time.Second)
 
 func importSchema() {
 	err := retrieveMigrationUUID(exportDir)
@@ -65,6 +91,7 @@ func importSchema() {
 		utils.ErrExit("failed to get migration UUID: %w", err)
 	}
 	tconf.Schema = strings.ToLower(tconf.Schema)
+	tconf.SourceDBType = sourceDBType
 
 	conn, err := pgx.Connect(context.Background(), tconf.GetConnectionUri())
 	if err != nil {
@@ -149,7 +176,15 @@ func importSchema() {
 	importDefferedStatements()
 	log.Info("Schema import is complete.")
 
-	dumpStatements(failedSqlStmts, filepath.Join(exportDir, "schema", "failed.sql"))
+	dumpStatements(lo.Map(failedSqlStmts, func(stmt *failedSqlStmt, _ int) string { return stmt.String() }),
+		filepath.Join(exportDir, "schema", "failed.sql"))
+	dumpFailedSqlStmtsSummary(failedSqlStmts, filepath.Join(exportDir, "schema", "failed_summary.json"))
+	disallowedStmtsFilePath := filepath.Join(exportDir, "schema", "disallowed.sql")
+	dumpStatements(disallowedSqlStmts, disallowedStmtsFilePath)
+	if len(disallowedSqlStmts) > 0 {
+		utils.PrintAndLog("%d statements were not executed as their type is not in --allowed-ddl-types. Review them in %s",
+			len(disallowedSqlStmts), disallowedStmtsFilePath)
+	}
 
 	if flagPostImportData {
 		if flagRefreshMViews {
@@ -191,6 +226,29 @@ func dumpStatements(stmts []string, filePath string) {
 	log.Info(msg)
 }
 
+// dumpFailedSqlStmtsSummary writes the failed statements with their object type, error and retry count as JSON,
+// for the tools deciding which objects to fix.
+func dumpFailedSqlStmtsSummary(stmts []*failedSqlStmt, filePath string) {
+	if len(stmts) == 0 {
+		if utils.FileOrFolderExists(filePath) {
+			err := os.Remove(filePath)
+			if err != nil {
+				utils.ErrExit("remove file: %v", err)
+			}
+		}
+		return
+	}
+	bytes, err := json.MarshalIndent(stmts, "", "    ")
+	if err != nil {
+		utils.ErrExit("marshal failed sql statements: %v", err)
+	}
+	err = os.WriteFile(filePath, bytes, 0644)
+	if err != nil {
+		utils.ErrExit("failed writing in file %s: %v", filePath, err)
+	}
+	log.Infof("summary of the failed sql statements written to %q", filePath)
+}
+
 func installOrafce(conn *pgx.Conn) {
 	utils.PrintAndLog("Installing Orafce extension in target YugabyteDB")
 	_, err := conn.Exec(context.Background(), "CREATE EXTENSION IF NOT EXISTS orafce")
@@ -326,6 +384,70 @@ func missingRequiredSchemaObject(err error) bool {
 	return strings.Contains(err.Error(), "does not exist")
 }
 
+/*
+Statements matching any of these patterns are expected to fail with an "already exists" error on a fresh target,
+and the error is ignored for them even without --ignore-exist. The patterns are matched against the whole
+statement, ignoring the case and the surrounding whitespace. Users can add to them with --ignore-exist-stmt-pattern.
+*/
+var DEFAULT_IDEMPOTENT_STMT_PATTERNS = []string{
+	// pg_dump generates `CREATE SCHEMA public;` in the schemas.sql, and the `public` schema already exists on YB.
+	`CREATE\s+SCHEMA\s+public\s*;`,
+}
+
+var extraIdempotentStmtPatterns []string
+var idempotentStmtRegexps []*regexp.Regexp
+
+func validateIdempotentStmtPatterns() {
+	idempotentStmtRegexps = nil
+	for _, pattern := range append(DEFAULT_IDEMPOTENT_STMT_PATTERNS, extraIdempotentStmtPatterns...) {
+		re, err := regexp.Compile(`(?is)^\s*(?:` + pattern + `)\s*$`)
+		if err != nil {
+			utils.ErrExit("Error: Invalid ignore-exist-stmt-pattern %q: %s", pattern, err)
+		}
+		idempotentStmtRegexps = append(idempotentStmtRegexps, re)
+	}
+}
+
+// isIdempotentStmt reports whether an "already exists" error of the statement can be safely ignored.
+func isIdempotentStmt(stmt string) bool {
+	if idempotentStmtRegexps == nil {
+		validateIdempotentStmtPatterns()
+	}
+	for _, re := range idempotentStmtRegexps {
+		if re.MatchString(stmt) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateAllowedDDLTypesFlag() {
+	allowedDDLTypes = nil
+	for _, stmtType := range strings.Split(allowedDDLTypesFlag, ",") {
+		stmtType = strings.ToUpper(strings.TrimSpace(stmtType))
+		if stmtType == "" {
+			continue
+		}
+		if strings.ContainsAny(stmtType, " \t;") {
+			utils.ErrExit("Error: Invalid allowed-ddl-types %q: each type must be a single keyword, e.g. CREATE", stmtType)
+		}
+		allowedDDLTypes = append(allowedDDLTypes, stmtType)
+	}
+}
+
+// getStmtType returns the leading keyword of the statement, e.g. CREATE, ALTER, GRANT.
+func getStmtType(stmt string) string {
+	fields := strings.FieldsFunc(stmt, func(c rune) bool { return unicode.IsSpace(c) || c == ';' || c == '(' })
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
+
+func isAllowedStmtType(stmt string) bool {
+	return len(allowedDDLTypes) == 0 || slices.Contains(allowedDDLTypes, getStmtType(stmt))
+}
+
 func isAlreadyExists(errString string) bool {
 	alreadyExistsErrors := []string{"already exists",
 		"multiple primary keys",
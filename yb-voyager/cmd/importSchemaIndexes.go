@@ -0,0 +1,141 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gosuri/uitable"
+	"github.com/jackc/pgx/v4"
+	log "github.com/sirupsen/logrus"
+	"github.com/sourcegraph/conc/pool"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+var postSnapshotIndexes bool
+var indexCreationParallelism int
+
+// indexCreationResult is the outcome of a CREATE INDEX statement run by createIndexesConcurrently.
+type indexCreationResult struct {
+	indexName string
+	duration  time.Duration
+	err       error
+}
+
+/*
+splitIndexFileStmts splits the statements of an index file into the session setup statements (SET/SELECT),
+which are run on each connection, and the statements creating the indexes. The statements skipped by skipFn
+are left out, and those whose type isn't allowed are returned separately.
+*/
+func splitIndexFileStmts(sqlInfoArr []sqlInfo, objType string, skipFn func(string, string) bool) (sessionStmts, indexStmts, disallowedStmts []sqlInfo) {
+	for _, sqlInfo := range sqlInfoArr {
+		switch {
+		case isSetOrSelectStmt(sqlInfo.stmt):
+			sessionStmts = append(sessionStmts, sqlInfo)
+		case skipFn != nil && skipFn(objType, sqlInfo.stmt):
+			continue
+		case !isAllowedStmtType(sqlInfo.stmt):
+			disallowedStmts = append(disallowedStmts, sqlInfo)
+		default:
+			indexStmts = append(indexStmts, sqlInfo)
+		}
+	}
+	return sessionStmts, indexStmts, disallowedStmts
+}
+
+/*
+createIndexesConcurrently runs the CREATE INDEX statements of the file (INDEXES_table.sql) on --index-creation-parallelism
+connections, after the data is imported (--post-snapshot-indexes). Each statement is run with executeSqlStmtWithRetries,
+which retries it on the schema version mismatch (dropping the INVALID index left behind) and reports its failure.
+The time taken by each index is reported at the end.
+*/
+func createIndexesConcurrently(file string, objType string, skipFn func(string, string) bool) {
+	log.Infof("Create the indexes of %q on target %q with %d connections", file, tconf.Host, indexCreationParallelism)
+	sessionStmts, indexStmts, disallowedStmts := splitIndexFileStmts(createSqlStrInfoArray(file, objType), objType, skipFn)
+	for _, sqlInfo := range disallowedStmts {
+		log.Infof("not executing statement of type %s, which is not allowed: %s", getStmtType(sqlInfo.stmt), sqlInfo.stmt)
+		disallowedSqlStmts = append(disallowedSqlStmts, sqlInfo.formattedStmt)
+	}
+	if len(indexStmts) == 0 {
+		return
+	}
+
+	stmtsCh := make(chan sqlInfo, len(indexStmts))
+	for _, sqlInfo := range indexStmts {
+		stmtsCh <- sqlInfo
+	}
+	close(stmtsCh)
+	var results []*indexCreationResult
+	var resultsMutex sync.Mutex
+	workers := pool.New().WithMaxGoroutines(indexCreationParallelism)
+	for i := 0; i < indexCreationParallelism && i < len(indexStmts); i++ {
+		workers.Go(func() {
+			var conn *pgx.Conn
+			defer func() {
+				if conn != nil {
+					conn.Close(context.Background())
+				}
+			}()
+			for sqlInfo := range stmtsCh {
+				if conn == nil {
+					conn = newTargetConn()
+					for _, sessionStmt := range sessionStmts {
+						err := executeSqlStmtWithRetries(&conn, sessionStmt, objType)
+						if err != nil {
+							utils.ErrExit("run session setup statement %q of %q: %s", sessionStmt.stmt, file, err)
+						}
+					}
+				}
+				start := time.Now()
+				err := executeSqlStmtWithRetries(&conn, sqlInfo, objType)
+				result := &indexCreationResult{indexName: sqlInfo.objName, duration: time.Since(start), err: err}
+				log.Infof("index %s: created in %s, err: %v", result.indexName, result.duration, err)
+				resultsMutex.Lock()
+				results = append(results, result)
+				resultsMutex.Unlock()
+				if err != nil {
+					conn.Close(context.Background())
+					conn = nil
+				}
+			}
+		})
+	}
+	workers.Wait()
+	printIndexCreationResults(results)
+}
+
+func printIndexCreationResults(results []*indexCreationResult) {
+	uiTable := uitable.New()
+	headerfmt := color.New(color.FgGreen, color.Underline).SprintFunc()
+	uiTable.AddRow(headerfmt("INDEX"), headerfmt("TIME TAKEN"), headerfmt("STATUS"))
+	for _, result := range results {
+		status := color.GreenString("DONE")
+		if result.err != nil && missingRequiredSchemaObject(result.err) {
+			status = color.YellowString("DEFERRED")
+		} else if result.err != nil {
+			status = color.RedString("FAILED")
+		}
+		uiTable.AddRow(result.indexName, result.duration.Round(time.Millisecond), status)
+	}
+	fmt.Printf("\nCreated indexes:\n\n")
+	fmt.Println(uiTable)
+	fmt.Println()
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitIndexFileStmts(t *testing.T) {
+	assert := assert.New(t)
+	allowedDDLTypes = nil
+	sqlInfoArr := lo.Map([]string{
+		"SET search_path = public;",
+		"CREATE INDEX idx1 ON t1 (c1);",
+		"CREATE UNIQUE INDEX idx2 ON t1 (c2);",
+		"SELECT pg_catalog.set_config('search_path', '', false);",
+		"CREATE INDEX idx3 ON t2 (c1);",
+	}, func(stmt string, _ int) sqlInfo { return sqlInfo{stmt: stmt, formattedStmt: stmt} })
+	skipFn := func(objType, stmt string) bool { return strings.Contains(stmt, "UNIQUE INDEX") }
+
+	sessionStmts, indexStmts, disallowedStmts := splitIndexFileStmts(sqlInfoArr, "INDEX", skipFn)
+	assert.Equal([]sqlInfo{sqlInfoArr[0], sqlInfoArr[3]}, sessionStmts)
+	assert.Equal([]sqlInfo{sqlInfoArr[1], sqlInfoArr[4]}, indexStmts)
+	assert.Empty(disallowedStmts)
+
+	allowedDDLTypes = []string{"ALTER"}
+	defer func() { allowedDDLTypes = nil }()
+	_, indexStmts, disallowedStmts = splitIndexFileStmts(sqlInfoArr, "INDEX", skipFn)
+	assert.Empty(indexStmts)
+	assert.Equal([]sqlInfo{sqlInfoArr[1], sqlInfoArr[4]}, disallowedStmts)
+}
@@ -26,8 +26,36 @@ import (
 	"golang.org/x/exp/slices"
 )
 
-var defferedSqlStmts []sqlInfo
-var failedSqlStmts []string
+var defferedSqlStmts []deferredSqlStmt
+var failedSqlStmts []*failedSqlStmt
+
+// deferredSqlStmt is a statement which failed as the objects it requires didn't exist yet, to be retried at the end.
+type deferredSqlStmt struct {
+	sqlInfo
+	objType string
+}
+
+// failedSqlStmt is a statement which failed with --continue-on-error, reported in failed.sql and failed_summary.json.
+type failedSqlStmt struct {
+	Stmt       string `json:"statement"`
+	ObjectType string `json:"object_type"`
+	Error      string `json:"error"`
+	RetryCount int    `json:"retry_count"`
+}
+
+func newFailedSqlStmt(sqlInfo sqlInfo, objType string, err error, retryCount int) *failedSqlStmt {
+	return &failedSqlStmt{
+		Stmt:       sqlInfo.formattedStmt,
+		ObjectType: objType,
+		Error:      err.Error(),
+		RetryCount: retryCount,
+	}
+}
+
+// String returns the statement with the error in a comment before it, as written to failed.sql.
+func (stmt *failedSqlStmt) String() string {
+	return "/*\n" + stmt.Error + "\n*/\n" + stmt.Stmt
+}
 
 func importSchemaInternal(exportDir string, importObjectList []string,
 	skipFn func(string, string) bool) {
@@ -37,6 +65,10 @@ func importSchemaInternal(exportDir string, importObjectList []string,
 		if !utils.FileOrFolderExists(importObjectFilePath) {
 			continue
 		}
+		if postSnapshotIndexes && importObjectType == "INDEX" {
+			createIndexesConcurrently(importObjectFilePath, importObjectType, skipFn)
+			continue
+		}
 		executeSqlFile(importObjectFilePath, importObjectType, skipFn)
 	}
 
@@ -73,8 +105,7 @@ func importDefferedStatements() {
 				log.Infof("failed retry of deffered stmt: %s\n%v", utils.GetSqlStmtToPrint(defferedSqlStmts[j].stmt), err)
 				// fails to execute in final attempt
 				if i == maxIterations {
-					errString := "/*\n" + err.Error() + "\n*/\n"
-					failedSqlStmts = append(failedSqlStmts, errString+defferedSqlStmts[j].formattedStmt)
+					failedSqlStmts = append(failedSqlStmts, newFailedSqlStmt(defferedSqlStmts[j].sqlInfo, defferedSqlStmts[j].objType, err, i))
 				}
 				conn.Close(context.Background())
 				conn = newTargetConn()
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDumpFailedSqlStmtsSummary(t *testing.T) {
+	assert := assert.New(t)
+	filePath := filepath.Join(t.TempDir(), "failed_summary.json")
+	stmt := newFailedSqlStmt(sqlInfo{stmt: "CREATE INDEX i1 ON t1(c1);", formattedStmt: "CREATE INDEX i1\nON t1(c1);"},
+		"INDEX", fmt.Errorf(`relation "t1" does not exist`), 2)
+	assert.Equal("/*\nrelation \"t1\" does not exist\n*/\nCREATE INDEX i1\nON t1(c1);", stmt.String())
+
+	dumpFailedSqlStmtsSummary([]*failedSqlStmt{stmt}, filePath)
+	bytes, err := os.ReadFile(filePath)
+	assert.NoError(err)
+	assert.JSONEq(`[{"statement": "CREATE INDEX i1\nON t1(c1);", "object_type": "INDEX",
+		"error": "relation \"t1\" does not exist", "retry_count": 2}]`, string(bytes))
+
+	dumpFailedSqlStmtsSummary(nil, filePath)
+	assert.NoFileExists(filePath)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"sync"
+
+	"github.com/tebeka/atexit"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tracing"
+)
+
+var otelEndpoint string
+
+// The spans of the import form a single trace: the root span of the migration, with a child span
+// per table (file) imported and a grandchild span per batch, and a child span for the streaming
+// with a grandchild span per event batch. All of them are nil, i.e. no-op, unless --otel-endpoint is set.
+var importSpan *tracing.Span
+var streamingSpan *tracing.Span
+var tableSpans = make(map[string]*tracing.Span) // key: data file path + table name
+var tableSpansMutex sync.Mutex
+
+func startImportTracing() {
+	if otelEndpoint == "" {
+		return
+	}
+	tracing.Init(otelEndpoint)
+	importSpan = tracing.StartSpan("import data")
+	importSpan.SetAttribute("migration_uuid", migrationUUID.String())
+	importSpan.SetAttribute("target_db_type", tconf.TargetDBType)
+	importSpan.SetAttribute("import_type", importType)
+	// the spans which are still open on an error exit are exported as well
+	atexit.Register(endImportTracing)
+}
+
+// endImportTracing ends the open spans and exports them. It is called at the end of the import, and on the exit
+// through atexit, e.g. on an error; the normal return from the command doesn't run the atexit handlers.
+func endImportTracing() {
+	streamingSpan.End()
+	tableSpansMutex.Lock()
+	for _, span := range tableSpans {
+		span.End()
+	}
+	tableSpansMutex.Unlock()
+	importSpan.End()
+	tracing.Shutdown()
+}
+
+func startTableSpan(task *ImportFileTask) {
+	span := importSpan.StartChild("import table")
+	span.SetAttribute("table_name", task.TableName)
+	span.SetAttribute("file_path", task.FilePath)
+	tableSpansMutex.Lock()
+	defer tableSpansMutex.Unlock()
+	tableSpans[task.FilePath+task.TableName] = span
+}
+
+func getTableSpan(filePath, tableName string) *tracing.Span {
+	tableSpansMutex.Lock()
+	defer tableSpansMutex.Unlock()
+	return tableSpans[filePath+tableName]
+}
+
+func endTableSpan(task *ImportFileTask) {
+	tableSpansMutex.Lock()
+	defer tableSpansMutex.Unlock()
+	key := task.FilePath + task.TableName
+	tableSpans[key].End()
+	delete(tableSpans, key)
+}
@@ -1,9 +1,15 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgconn"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
 )
 
 func TestIsDataLine(t *testing.T) {
@@ -29,3 +35,93 @@ func TestIsDataLine(t *testing.T) {
 		assert.Equal(testcases[i].expected, isDataLine(testcases[i].line, "postgresql", &insideCopyStmt), "%q", testcases[i].line)
 	}
 }
+
+func TestSortImportFileTasks(t *testing.T) {
+	assert := assert.New(t)
+	sizes := map[string]int64{"a": 10, "b": 30, "c": 20, "d": 30}
+	sizeFn := func(task *ImportFileTask) int64 { return sizes[task.TableName] }
+	tableNames := func(tasks []*ImportFileTask) []string {
+		var names []string
+		for _, task := range tasks {
+			names = append(names, task.TableName)
+		}
+		return names
+	}
+	testcases := []struct {
+		order    string
+		expected []string
+	}{
+		{IMPORT_ORDER_INPROGRESS_FIRST, []string{"a", "b", "c", "d"}},
+		{IMPORT_ORDER_DESCRIPTOR, []string{"a", "b", "c", "d"}},
+		{IMPORT_ORDER_LARGEST_FIRST, []string{"b", "d", "c", "a"}},
+		{IMPORT_ORDER_SMALLEST_FIRST, []string{"a", "c", "b", "d"}},
+	}
+	for _, tc := range testcases {
+		var tasks []*ImportFileTask
+		for _, name := range []string{"a", "b", "c", "d"} {
+			tasks = append(tasks, &ImportFileTask{TableName: name})
+		}
+		sortImportFileTasks(tasks, tc.order, sizeFn)
+		assert.Equal(tc.expected, tableNames(tasks), tc.order)
+	}
+}
+
+func TestGetCopyRetrySleepIntervalSec(t *testing.T) {
+	assert := assert.New(t)
+	sleeps := func(backoff string) []int {
+		var result []int
+		sleepIntervalSec := 0
+		for i := 0; i < 6; i++ {
+			sleepIntervalSec = getCopyRetrySleepIntervalSec(sleepIntervalSec, backoff)
+			result = append(result, sleepIntervalSec)
+		}
+		return result
+	}
+	assert.Equal([]int{10, 20, 30, 40, 50, 60}, sleeps(COPY_BACKOFF_LINEAR))
+	assert.Equal([]int{10, 20, 40, 60, 60, 60}, sleeps(COPY_BACKOFF_EXPONENTIAL))
+}
+
+func TestGetTableToPoolSize(t *testing.T) {
+	assert := assert.New(t)
+	defer func(m map[string]int, parallelism int) { tableParallelism, tconf.Parallelism = m, parallelism }(tableParallelism, tconf.Parallelism)
+	tasks := []*ImportFileTask{
+		{ID: 0, FilePath: "orders_1.csv", TableName: "public.orders"},
+		{ID: 1, FilePath: "orders_2.csv", TableName: "public.orders"},
+		{ID: 2, FilePath: "countries.csv", TableName: "public.countries"},
+		{ID: 3, FilePath: "items.csv", TableName: "public.items"},
+	}
+	tconf.Parallelism = 8
+
+	tableParallelism = nil
+	assert.Empty(getTableToPoolSize(tasks))
+
+	tableParallelism = map[string]int{"orders": 16, "public.countries": 1}
+	assert.Equal(map[string]int{"public.orders": 8, "public.countries": 1}, getTableToPoolSize(tasks))
+}
+
+func TestCheckBatchRowsAffected(t *testing.T) {
+	defer func() { rowCountMismatches = nil }()
+	batch := &Batch{TableName: "public.foo", BaseFilePath: "/data/foo.csv", Number: 3, OffsetStart: 100, OffsetEnd: 200, RecordCount: 95}
+	checkBatchRowsAffected(batch, 95)
+	assert.Empty(t, rowCountMismatches)
+	checkBatchRowsAffected(batch, 98)
+	assert.Equal(t, []*RowCountMismatch{
+		{TableName: "public.foo", FilePath: "/data/foo.csv", BatchNumber: 3, ExpectedRows: 95, RowsAffected: 98},
+	}, rowCountMismatches)
+
+	onPrimaryKeyConflict = tgtdb.ON_PRIMARY_KEY_CONFLICT_SKIP
+	defer func() { onPrimaryKeyConflict = tgtdb.ON_PRIMARY_KEY_CONFLICT_ERROR }()
+	checkBatchRowsAffected(batch, 90)
+	assert.Len(t, rowCountMismatches, 1)
+
+	summary := newImportDataSummary(nil, 0, time.Now(), time.Now())
+	assert.Len(t, summary.RowCountMismatches, 1)
+}
+
+func TestIsStatementTimeout(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(isStatementTimeout(&pgconn.PgError{Code: QUERY_CANCELED_ERR_CODE, Message: "canceling statement due to statement timeout"}))
+	assert.True(isStatementTimeout(fmt.Errorf("timeout: %w", context.DeadlineExceeded)))
+	assert.False(isStatementTimeout(&pgconn.PgError{Code: "40001", Message: "conflicts with higher priority transaction"}))
+	assert.False(isStatementTimeout(fmt.Errorf("relation \"t1\" already exists")))
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStreamingFlagsDefaults(t *testing.T) {
+	// exits on an invalid flag
+	validateStreamingFlags()
+	assert.Equal(t, MAX_EVENTS_PER_BATCH, EVENT_CHANNEL_SIZE)
+}
+
+func TestCheckSourceDBType(t *testing.T) {
+	assert := assert.New(t)
+	assert.NoError(checkSourceDBType(ORACLE, ORACLE))
+	assert.NoError(checkSourceDBType(POSTGRESQL, "PostgreSQL"))
+	assert.ErrorContains(checkSourceDBType(ORACLE, POSTGRESQL), `source-db-type "oracle" doesn't match the source db type "postgresql"`)
+	assert.ErrorContains(checkSourceDBType(MYSQL, ""), "not recorded in the metainfo")
+}
@@ -0,0 +1,152 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/datafile"
+)
+
+var numConversionWorkers int
+
+// Number of lines handed over to a conversion worker at a time.
+const CONVERSION_CHUNK_SIZE = 256
+
+// splitLine is a line of the data file along with its converted form.
+type splitLine struct {
+	line       string
+	err        error // error reading the line; io.EOF for the last line
+	numBytes   int64 // bytes of the data file read for the line
+	converted  string
+	convertErr error
+}
+
+type lineChunk struct {
+	lines []*splitLine
+	done  chan struct{}
+}
+
+/*
+LineReader reads the lines of a data file and converts them with `convertFn`. With more than one worker,
+the lines are read ahead in chunks which are converted in parallel, while Next() still returns the
+lines in the order of the file. The conversion of a line must not depend on that of the other lines.
+*/
+type LineReader struct {
+	dataFile  datafile.DataFile
+	convertFn func(string) (string, error)
+
+	// used with the conversion workers
+	chunks     chan *lineChunk
+	current    []*splitLine
+	stop       chan struct{}
+	readerDone chan struct{}
+}
+
+func NewLineReader(dataFile datafile.DataFile, convertFn func(string) (string, error), numWorkers int) *LineReader {
+	r := &LineReader{dataFile: dataFile, convertFn: convertFn}
+	if numWorkers > 1 {
+		// the chunks being converted and the ones ready to be consumed are bounded by 2 * numWorkers
+		r.chunks = make(chan *lineChunk, 2*numWorkers)
+		r.stop = make(chan struct{})
+		r.readerDone = make(chan struct{})
+		work := make(chan *lineChunk, numWorkers)
+		for i := 0; i < numWorkers; i++ {
+			go r.convertChunks(work)
+		}
+		go r.readChunks(work)
+	}
+	return r
+}
+
+// Next returns the next line. Reading past the line with a non-nil `err` is not allowed.
+func (r *LineReader) Next() *splitLine {
+	if r.chunks == nil {
+		l := r.readLine()
+		r.convert(l)
+		return l
+	}
+	for len(r.current) == 0 {
+		chunk := <-r.chunks
+		<-chunk.done
+		r.current = chunk.lines
+	}
+	l := r.current[0]
+	r.current = r.current[1:]
+	return l
+}
+
+// Stop stops reading ahead, when the rest of the lines are not needed. The data file can be closed after it returns.
+func (r *LineReader) Stop() {
+	if r.chunks == nil {
+		return
+	}
+	select {
+	case <-r.stop:
+	default:
+		close(r.stop)
+	}
+	<-r.readerDone
+}
+
+func (r *LineReader) readLine() *splitLine {
+	bytesBefore := r.dataFile.GetBytesRead()
+	line, err := r.dataFile.NextLine()
+	return &splitLine{line: line, err: err, numBytes: r.dataFile.GetBytesRead() - bytesBefore}
+}
+
+func (r *LineReader) convert(l *splitLine) {
+	if l.line != "" {
+		l.converted, l.convertErr = r.convertFn(l.line)
+	}
+}
+
+func (r *LineReader) readChunks(work chan<- *lineChunk) {
+	defer close(r.readerDone)
+	defer close(work)
+	for {
+		chunk := &lineChunk{done: make(chan struct{})}
+		var l *splitLine
+		for len(chunk.lines) < CONVERSION_CHUNK_SIZE {
+			l = r.readLine()
+			chunk.lines = append(chunk.lines, l)
+			if l.err != nil {
+				break
+			}
+		}
+		// queued for the consumer first, so that the chunks are consumed in the order of the file
+		select {
+		case r.chunks <- chunk:
+		case <-r.stop:
+			return
+		}
+		select {
+		case work <- chunk:
+		case <-r.stop:
+			return
+		}
+		if l.err != nil {
+			return
+		}
+	}
+}
+
+func (r *LineReader) convertChunks(work <-chan *lineChunk) {
+	for chunk := range work {
+		for _, l := range chunk.lines {
+			r.convert(l)
+		}
+		close(chunk.done)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	reporter "github.com/yugabyte/yb-voyager/yb-voyager/src/reporter/stats"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+// A send blocked on a full event channel for longer than this is reported. Plain integers are milliseconds.
+var EVENT_CHANNEL_BLOCKED_THRESHOLD = utils.GetEnvAsDuration("EVENT_CHANNEL_BLOCKED_THRESHOLD", 5*time.Second, time.Millisecond)
+
+// The blocked sends are logged at most once in this interval, as they are typically sustained while the target is slow.
+const EVENT_CHANNEL_BLOCKED_LOG_INTERVAL = time.Minute
+
+/*
+EventChannelBackpressure sends the events to the event channels, reporting the sends which block beyond
+EVENT_CHANNEL_BLOCKED_THRESHOLD on a full channel, i.e. when the events of the channel are applied to the target
+slower than they are read from the queue. This tells a slow target apart from a stalled stream, where nothing is
+read from the queue. The events are sent the same way either way, the channels are only observed.
+*/
+type EventChannelBackpressure struct {
+	threshold     time.Duration
+	statsReporter *reporter.StreamImportStatsReporter
+	// the blocked sends since the last one logged, and when it was logged
+	numBlockedSendsNotLogged int64
+	lastLogTime              time.Time
+}
+
+func NewEventChannelBackpressure(threshold time.Duration, statsReporter *reporter.StreamImportStatsReporter) *EventChannelBackpressure {
+	return &EventChannelBackpressure{threshold: threshold, statsReporter: statsReporter}
+}
+
+// send blocks until the event is sent to the channel, or an error is received on streamErrs.
+// Only the dispatcher of the events sends them, hence this isn't called concurrently.
+func (b *EventChannelBackpressure) send(evChan chan<- *tgtdb.Event, chanNo int, event *tgtdb.Event, streamErrs chan error) error {
+	select {
+	case evChan <- event:
+		return nil
+	default:
+	}
+
+	// the channel is full
+	start := time.Now()
+	timer := time.NewTimer(b.threshold)
+	defer timer.Stop()
+	blocked := false
+	for {
+		select {
+		case evChan <- event:
+			if blocked {
+				b.statsReporter.EventChannelUnblocked(time.Since(start))
+			}
+			return nil
+		case err := <-streamErrs:
+			if blocked {
+				b.statsReporter.EventChannelUnblocked(time.Since(start))
+			}
+			return err
+		case <-timer.C:
+			blocked = true
+			b.statsReporter.EventChannelBlocked()
+			b.logBlockedSend(chanNo, event)
+		}
+	}
+}
+
+func (b *EventChannelBackpressure) logBlockedSend(chanNo int, event *tgtdb.Event) {
+	b.numBlockedSendsNotLogged++
+	if time.Since(b.lastLogTime) < EVENT_CHANNEL_BLOCKED_LOG_INTERVAL {
+		return
+	}
+	log.WithField(tgtdb.LOG_FIELD_VSN, event.Vsn).Warnf("sending the event to channel %d is blocked for more than %s, as the channel is full: "+
+		"the events are applied to the target slower than they are read (%d blocked sends since the last warning)",
+		chanNo, b.threshold, b.numBlockedSendsNotLogged)
+	b.numBlockedSendsNotLogged = 0
+	b.lastLogTime = time.Now()
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	reporter "github.com/yugabyte/yb-voyager/yb-voyager/src/reporter/stats"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+)
+
+func TestEventChannelBackpressure(t *testing.T) {
+	assert := assert.New(t)
+	statsReporter := reporter.NewStreamImportStatsReporter()
+	backpressure := NewEventChannelBackpressure(20*time.Millisecond, statsReporter)
+	evChan := make(chan *tgtdb.Event, 1)
+	streamErrs := make(chan error, 1)
+	metrics := func() string {
+		var sb strings.Builder
+		statsReporter.WriteMetrics(&sb)
+		return sb.String()
+	}
+
+	assert.NoError(backpressure.send(evChan, 0, &tgtdb.Event{Vsn: 1}, streamErrs))
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		<-evChan
+	}()
+	assert.NoError(backpressure.send(evChan, 0, &tgtdb.Event{Vsn: 2}, streamErrs)) // blocked until the first event is read
+	assert.Contains(metrics(), "event_channel_blocked_sends 1\n")
+	assert.Contains(metrics(), "event_channels_blocked 0\n")
+
+	// a send blocked for less than the threshold isn't reported
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		<-evChan
+	}()
+	assert.NoError(backpressure.send(evChan, 0, &tgtdb.Event{Vsn: 3}, streamErrs))
+	assert.Contains(metrics(), "event_channel_blocked_sends 1\n")
+
+	streamErrs <- fmt.Errorf("channel 1 failed")
+	assert.ErrorContains(backpressure.send(evChan, 0, &tgtdb.Event{Vsn: 4}, streamErrs), "channel 1 failed")
+}
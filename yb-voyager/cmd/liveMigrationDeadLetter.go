@@ -0,0 +1,107 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+
+	reporter "github.com/yugabyte/yb-voyager/yb-voyager/src/reporter/stats"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+)
+
+var deadLetterDir string
+
+// DeadLetteredEventBatch is a batch of events which failed to apply even after the retries (or a single event whose
+// values failed to convert), as written to --dead-letter-dir to be reprocessed later. The events are as exported,
+// i.e. with the values before their conversion.
+type DeadLetteredEventBatch struct {
+	MigrationUUID  string         `json:"migration_uuid"`
+	ChanNo         int            `json:"channel_no"`
+	Error          string         `json:"error"`
+	DeadLetteredAt time.Time      `json:"dead_lettered_at"`
+	Events         []*tgtdb.Event `json:"events"`
+}
+
+// writeDeadLetteredEventBatch writes the batch to a file named by its channel and the range of its VSNs, which is
+// overwritten if the same batch is dead-lettered again, e.g. on a restart before its channel is advanced.
+func writeDeadLetteredEventBatch(dir string, chanNo int, batch []*tgtdb.Event, batchErr error) (string, error) {
+	deadLetteredBatch := &DeadLetteredEventBatch{
+		MigrationUUID:  migrationUUID.String(),
+		ChanNo:         chanNo,
+		Error:          batchErr.Error(),
+		DeadLetteredAt: time.Now(),
+		Events: lo.Map(batch, func(event *tgtdb.Event, _ int) *tgtdb.Event {
+			if event.ExportedEvent != nil {
+				return event.ExportedEvent
+			}
+			return event
+		}),
+	}
+	jsonBytes, err := json.MarshalIndent(deadLetteredBatch, "", "    ")
+	if err != nil {
+		return "", fmt.Errorf("marshal dead-lettered batch: %w", err)
+	}
+	filePath := filepath.Join(dir, fmt.Sprintf("event_batch_ch%d_%d-%d.json", chanNo, batch[0].Vsn, batch[len(batch)-1].Vsn))
+	// written to a temp file first, so that a partially written batch is never mistaken for a complete one
+	tmpFilePath := filePath + ".tmp"
+	err = os.WriteFile(tmpFilePath, jsonBytes, 0644)
+	if err != nil {
+		return "", fmt.Errorf("write dead-lettered batch to %q: %w", tmpFilePath, err)
+	}
+	err = os.Rename(tmpFilePath, filePath)
+	if err != nil {
+		return "", fmt.Errorf("rename %q to %q: %w", tmpFilePath, filePath, err)
+	}
+	return filePath, nil
+}
+
+// deadLetterEventBatch writes the failed batch to --dead-letter-dir and advances the channel past it on the target,
+// so that the streaming continues with the next events of the channel.
+func deadLetterEventBatch(chanNo int, batch []*tgtdb.Event, batchErr error, statsReporter *reporter.StreamImportStatsReporter) error {
+	filePath, err := writeDeadLetteredEventBatch(deadLetterDir, chanNo, batch, batchErr)
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter the batch of %d events on channel %d (%s): %w", len(batch), chanNo, batchErr, err)
+	}
+	lastVsn := batch[len(batch)-1].Vsn
+	err = tdb.AdvanceEventChannel(migrationUUID, chanNo, lastVsn)
+	if err != nil {
+		return fmt.Errorf("failed to advance channel %d past the dead-lettered batch %q: %w", chanNo, filePath, err)
+	}
+	log.Errorf("dead-lettered batch of %d events (vsn %d..%d) on channel %d to %q: %s",
+		len(batch), batch[0].Vsn, lastVsn, chanNo, filePath, batchErr)
+	statsReporter.EventBatchDeadLettered(int64(len(batch)))
+	return nil
+}
+
+// deadLetterEvent writes an event whose values fail to convert to --dead-letter-dir, in place of dispatching it. The
+// channel of the event is not advanced, as its earlier events may not be applied yet. Hence the event is dead-lettered
+// again (to the same file) if the streaming restarts before a later event of the channel is applied.
+func deadLetterEvent(chanNo int, event *tgtdb.Event, convErr error, statsReporter *reporter.StreamImportStatsReporter) error {
+	filePath, err := writeDeadLetteredEventBatch(deadLetterDir, chanNo, []*tgtdb.Event{event}, convErr)
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter the event with vsn %d (%s): %w", event.Vsn, convErr, err)
+	}
+	log.Errorf("dead-lettered event with vsn %d to %q: %s", event.Vsn, filePath, convErr)
+	statsReporter.EventBatchDeadLettered(1)
+	return nil
+}
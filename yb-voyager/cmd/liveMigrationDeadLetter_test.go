@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	reporter "github.com/yugabyte/yb-voyager/yb-voyager/src/reporter/stats"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+)
+
+func TestDeadLetterEventBatch(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	value := "not a number"
+	batch := []*tgtdb.Event{
+		{Vsn: 11, Op: "c", SchemaName: "public", TableName: "orders", Fields: map[string]*string{"qty": &value}},
+		{Vsn: 15, Op: "d", SchemaName: "public", TableName: "orders", Key: map[string]*string{"id": &value}},
+	}
+	// the values of the first event are converted, the exported event is written
+	converted := "'not a number'"
+	batch[0].ExportedEvent = batch[0].Clone()
+	batch[0].Fields["qty"] = &converted
+	assert.Equal("not a number", *batch[0].ExportedEvent.Fields["qty"])
+	filePath, err := writeDeadLetteredEventBatch(dir, 3, batch, fmt.Errorf("invalid input syntax for type integer"))
+	assert.NoError(err)
+	assert.Equal(filepath.Join(dir, "event_batch_ch3_11-15.json"), filePath)
+	entries, err := os.ReadDir(dir)
+	assert.NoError(err)
+	assert.Equal(1, len(entries)) // no temp file is left behind
+
+	bytes, err := os.ReadFile(filePath)
+	assert.NoError(err)
+	var deadLetteredBatch DeadLetteredEventBatch
+	assert.NoError(json.Unmarshal(bytes, &deadLetteredBatch))
+	assert.Equal(3, deadLetteredBatch.ChanNo)
+	assert.Equal("invalid input syntax for type integer", deadLetteredBatch.Error)
+	assert.Equal([]*tgtdb.Event{batch[0].ExportedEvent, batch[1]}, deadLetteredBatch.Events)
+
+	statsReporter := reporter.NewStreamImportStatsReporter()
+	statsReporter.EventBatchDeadLettered(int64(len(batch)))
+	var sb strings.Builder
+	statsReporter.WriteMetrics(&sb)
+	assert.Contains(sb.String(), "dead_lettered_event_batches 1\n")
+	assert.Contains(sb.String(), "dead_lettered_events 2\n")
+	statsReporter.UpdateRemainingEvents(10)
+	importedEvents, remainingEvents, _ := statsReporter.GetStreamingProgress()
+	assert.Equal(int64(2), importedEvents)
+	assert.Equal(int64(8), remainingEvents)
+}
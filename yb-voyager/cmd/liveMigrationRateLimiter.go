@@ -0,0 +1,78 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"sync"
+	"time"
+)
+
+var maxEventsPerSecond int
+
+/*
+EventRateLimiter caps the rate at which the streamed events are applied to the target, across all the event
+channels, with a token bucket holding up to a second worth of events. A batch larger than the tokens available
+is applied once the tokens it borrows are refilled, hence a batch of any size can be applied and the limiter
+never blocks the channels indefinitely. A nil limiter doesn't limit the rate.
+*/
+type EventRateLimiter struct {
+	sync.Mutex
+	eventsPerSecond float64
+	tokens          float64
+	lastRefill      time.Time
+}
+
+// NewEventRateLimiter returns nil, i.e. no limit, if eventsPerSecond is 0.
+func NewEventRateLimiter(eventsPerSecond int) *EventRateLimiter {
+	if eventsPerSecond <= 0 {
+		return nil
+	}
+	return &EventRateLimiter{
+		eventsPerSecond: float64(eventsPerSecond),
+		tokens:          float64(eventsPerSecond),
+		lastRefill:      time.Now(),
+	}
+}
+
+// Wait blocks until numEvents events can be applied.
+func (l *EventRateLimiter) Wait(numEvents int) {
+	if l == nil || numEvents <= 0 {
+		return
+	}
+	// The lock is released before sleeping, the other channels reserve their tokens after these.
+	delay := l.reserve(numEvents, time.Now())
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// reserve takes numEvents tokens and returns how long to wait for the tokens borrowed beyond the available ones.
+func (l *EventRateLimiter) reserve(numEvents int, now time.Time) time.Duration {
+	l.Lock()
+	defer l.Unlock()
+	if now.After(l.lastRefill) {
+		l.tokens += now.Sub(l.lastRefill).Seconds() * l.eventsPerSecond
+		if l.tokens > l.eventsPerSecond {
+			l.tokens = l.eventsPerSecond
+		}
+		l.lastRefill = now
+	}
+	l.tokens -= float64(numEvents)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.eventsPerSecond * float64(time.Second))
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventRateLimiter(t *testing.T) {
+	assert := assert.New(t)
+	var noLimit *EventRateLimiter = NewEventRateLimiter(0)
+	assert.Nil(noLimit)
+	noLimit.Wait(1000) // doesn't block
+
+	start := time.Now()
+	limiter := NewEventRateLimiter(1000)
+	limiter.lastRefill = start
+	assert.Equal(time.Duration(0), limiter.reserve(600, start))
+	assert.Equal(time.Duration(0), limiter.reserve(400, start))
+	// borrowed beyond the burst of a second worth of events
+	assert.Equal(2*time.Second, limiter.reserve(2000, start))
+	// the borrowed tokens are refilled first
+	assert.Equal(1500*time.Millisecond, limiter.reserve(500, start.Add(time.Second)))
+	// the refill is capped at a second worth of events
+	assert.Equal(time.Duration(0), limiter.reserve(1000, start.Add(time.Hour)))
+	assert.Equal(100*time.Millisecond, limiter.reserve(100, start.Add(time.Hour)))
+}
@@ -0,0 +1,84 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	reporter "github.com/yugabyte/yb-voyager/yb-voyager/src/reporter/stats"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+// StreamTableFilter drops the streamed events of the tables left out of the snapshot import by
+// --table-list/--exclude-table-list or --skip-missing-tables, so that the same set of tables is migrated in both the phases.
+// The table names of the events are matched exactly, i.e. with the same schema and case, against the
+// names of the snapshot tasks, which are named the same way (e.g. the public schema is left out for PG).
+type StreamTableFilter struct {
+	// reason of the filter by the names of the filtered tables without the quotes
+	filteredTables map[string]string
+	// result of the match of a table name of the events against filteredTables
+	isFiltered    map[string]bool
+	statsReporter *reporter.StreamImportStatsReporter
+}
+
+func NewStreamTableFilter(filteredTables, missingTables []string, statsReporter *reporter.StreamImportStatsReporter) *StreamTableFilter {
+	f := &StreamTableFilter{
+		filteredTables: make(map[string]string),
+		isFiltered:     make(map[string]bool),
+		statsReporter:  statsReporter,
+	}
+	for _, t := range filteredTables {
+		f.filteredTables[unquoteTableName(t)] = "it is filtered out by the table list"
+	}
+	for _, t := range missingTables {
+		f.filteredTables[unquoteTableName(t)] = "it is missing on the target"
+	}
+	return f
+}
+
+// Filter returns true if the event of the table `tableName` is to be skipped.
+func (f *StreamTableFilter) Filter(event *tgtdb.Event, tableName string) bool {
+	if len(f.filteredTables) == 0 {
+		return false
+	}
+	filtered, ok := f.isFiltered[tableName]
+	if !ok {
+		var reason string
+		reason, filtered = f.filteredTables[unquoteTableName(tableName)]
+		f.isFiltered[tableName] = filtered
+		if filtered {
+			utils.PrintAndLog("skipping the events of table %q as %s", tableName, reason)
+		}
+	}
+	if !filtered {
+		return false
+	}
+	log.Tracef("skipping event with vsn %d of filtered table %q", event.Vsn, tableName)
+	f.statsReporter.EventsSkipped(1)
+	return true
+}
+
+// unquoteTableName removes the quotes around the parts of a (possibly qualified) table name, preserving their case.
+func unquoteTableName(tableName string) string {
+	parts := strings.Split(tableName, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(part, `"`)
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	reporter "github.com/yugabyte/yb-voyager/yb-voyager/src/reporter/stats"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+)
+
+func TestStreamTableFilter(t *testing.T) {
+	statsReporter := reporter.NewStreamImportStatsReporter()
+	filter := NewStreamTableFilter([]string{"foo", `"Bar"`}, []string{"sales.baz"}, statsReporter)
+	assert.True(t, filter.Filter(&tgtdb.Event{Vsn: 1}, "foo"))
+	assert.True(t, filter.Filter(&tgtdb.Event{Vsn: 2}, "Bar"))
+	assert.True(t, filter.Filter(&tgtdb.Event{Vsn: 3}, "sales.baz"))
+	// the tables of the same name in the other schemas, or in another case, are not filtered
+	assert.False(t, filter.Filter(&tgtdb.Event{Vsn: 4}, "sales.foo"))
+	assert.False(t, filter.Filter(&tgtdb.Event{Vsn: 5}, "bar"))
+	assert.False(t, filter.Filter(&tgtdb.Event{Vsn: 6}, "baz"))
+	numEvents, _, _ := statsReporter.GetStreamingProgress()
+	assert.Equal(t, int64(3), numEvents)
+
+	// nothing is filtered without the table list filters
+	assert.False(t, NewStreamTableFilter(nil, nil, statsReporter).Filter(&tgtdb.Event{Vsn: 5}, "foo"))
+}
@@ -0,0 +1,177 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+
+	reporter "github.com/yugabyte/yb-voyager/yb-voyager/src/reporter/stats"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+// Action to take on the streamed events of the tables which are not part of the migration
+// (e.g. tables created on the source after the snapshot was exported).
+var unknownTablePolicy string
+
+var alterTableRegex = regexp.MustCompile(`(?i)^ALTER\s+TABLE\s+(?:ONLY\s+)?(?:IF\s+EXISTS\s+)?([a-zA-Z0-9_."]+)`)
+
+// UnknownTableHandler matches the table names of the events exactly, i.e. with the same schema and case, against the
+// names of the tables in the data file descriptor, which are named the same way (see StreamTableFilter).
+type UnknownTableHandler struct {
+	policy string
+	// names of the tables without the quotes
+	knownTables   map[string]bool
+	skippedTables map[string]bool
+	statsReporter *reporter.StreamImportStatsReporter
+}
+
+func NewUnknownTableHandler(policy string, statsReporter *reporter.StreamImportStatsReporter) *UnknownTableHandler {
+	knownTables := make(map[string]bool)
+	for _, fileEntry := range dataFileDescriptor.DataFileList {
+		knownTables[unquoteTableName(fileEntry.TableName)] = true
+	}
+	return &UnknownTableHandler{
+		policy:        policy,
+		knownTables:   knownTables,
+		skippedTables: make(map[string]bool),
+		statsReporter: statsReporter,
+	}
+}
+
+// Handle applies the policy to an event of the table `tableName`. It returns true if the event is to be skipped.
+func (h *UnknownTableHandler) Handle(event *tgtdb.Event, tableName string) (bool, error) {
+	key := unquoteTableName(tableName)
+	if h.knownTables[key] {
+		return false, nil
+	}
+	switch h.policy {
+	case UNKNOWN_TABLE_SKIP:
+		if !h.skippedTables[key] {
+			h.skippedTables[key] = true
+			utils.PrintAndLog("WARNING: skipping the events of table %q which is not part of the migration", tableName)
+		}
+		log.Debugf("skipping event with vsn %d of unknown table %q", event.Vsn, tableName)
+		h.statsReporter.EventsSkipped(1)
+		return true, nil
+	case UNKNOWN_TABLE_AUTO_CREATE:
+		err := createTableFromExportedSchema(tableName)
+		if err != nil {
+			return false, fmt.Errorf("auto-create table %q: %w", tableName, err)
+		}
+		err = valueConverter.RegisterTable(tableName)
+		if err != nil {
+			return false, fmt.Errorf("register created table %q to convert its events: %w", tableName, err)
+		}
+		color.Yellow("Created table %q, which is not part of the migration, using its DDL from the exported schema\n", tableName)
+		log.Infof("created unknown table %q on receiving the event with vsn %d", tableName, event.Vsn)
+		h.knownTables[key] = true
+		return false, nil
+	default:
+		return false, fmt.Errorf("received event with vsn %d for table %q which is not part of the migration. "+
+			"Use --on-unknown-table to skip the events of such tables or to create them", event.Vsn, tableName)
+	}
+}
+
+// createTableFromExportedSchema runs the CREATE TABLE statement of the table, along with the ALTER TABLE
+// statements (e.g. adding constraints) on it, from the table.sql of the exported schema.
+func createTableFromExportedSchema(tableName string) error {
+	tableFilePath := utils.GetObjectFilePath(filepath.Join(exportDir, "schema"), "TABLE")
+	if !utils.FileOrFolderExists(tableFilePath) {
+		return fmt.Errorf("exported schema file %q not found", tableFilePath)
+	}
+	var stmts []sqlInfo
+	for _, sqlInfo := range createSqlStrInfoArray(tableFilePath, "TABLE") {
+		objName := sqlInfo.objName
+		if objName == "" {
+			if matches := alterTableRegex.FindStringSubmatch(sqlInfo.stmt); matches != nil {
+				objName = matches[1]
+			}
+		}
+		if objName != "" && exportedTableNameMatches(objName, tableName) {
+			stmts = append(stmts, sqlInfo)
+		}
+	}
+	if len(stmts) == 0 {
+		return fmt.Errorf("CREATE TABLE statement not found in %q", tableFilePath)
+	}
+
+	conn := newTargetConn()
+	defer conn.Close(context.Background())
+	for _, sqlInfo := range stmts {
+		log.Infof("On %s run query:\n%s\n", tconf.Host, sqlInfo.formattedStmt)
+		_, err := conn.Exec(context.Background(), sqlInfo.formattedStmt)
+		if err != nil && !isAlreadyExists(err.Error()) {
+			return fmt.Errorf("run %q: %w", sqlInfo.formattedStmt, err)
+		}
+	}
+	return nil
+}
+
+// exportedTableNameMatches checks if the name of a table in the exported schema, as in the DDL, is that of the
+// table of the events. For PG, the names are compared exactly, with the unqualified ones in the public schema.
+// The schema of the other sources is exported by ora2pg, which lower cases the names, and the events of the migration
+// are all from its single schema; hence only the table names are compared, ignoring the case.
+func exportedTableNameMatches(objName, tableName string) bool {
+	parts := strings.Split(objName, ".")
+	for i, part := range parts {
+		if strings.HasPrefix(part, `"`) {
+			parts[i] = strings.Trim(part, `"`)
+		} else {
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	ddlName := strings.Join(parts, ".")
+	tableName = unquoteTableName(tableName)
+	if sourceDBType != POSTGRESQL {
+		return strings.EqualFold(ddlName[strings.LastIndex(ddlName, ".")+1:], tableName[strings.LastIndex(tableName, ".")+1:])
+	}
+	if !strings.Contains(ddlName, ".") {
+		ddlName = "public." + ddlName
+	}
+	if !strings.Contains(tableName, ".") {
+		tableName = "public." + tableName
+	}
+	return ddlName == tableName
+}
+
+// normalizeTableName returns the unquoted, lower case form of a (possibly qualified) table name for comparisons.
+func normalizeTableName(tableName string) string {
+	parts := strings.Split(tableName, ".")
+	for i, part := range parts {
+		parts[i] = strings.ToLower(strings.Trim(part, `"`))
+	}
+	return strings.Join(parts, ".")
+}
+
+func tableNamesMatch(name1, name2 string) bool {
+	name1, name2 = normalizeTableName(name1), normalizeTableName(name2)
+	if name1 == name2 {
+		return true
+	}
+	// an unqualified name matches the qualified name of the table in any schema
+	if !strings.Contains(name1, ".") || !strings.Contains(name2, ".") {
+		return name1[strings.LastIndex(name1, ".")+1:] == name2[strings.LastIndex(name2, ".")+1:]
+	}
+	return false
+}
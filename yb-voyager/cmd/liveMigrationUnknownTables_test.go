@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportedTableNameMatches(t *testing.T) {
+	defer func(dbType string) { sourceDBType = dbType }(sourceDBType)
+	sourceDBType = POSTGRESQL
+	assert.True(t, exportedTableNameMatches("public.foo", "foo"))
+	assert.True(t, exportedTableNameMatches("sales.foo", "sales.foo"))
+	assert.True(t, exportedTableNameMatches(`sales."Foo"`, "sales.Foo"))
+	// the tables of the same name in the other schemas, or in another case, don't match
+	assert.False(t, exportedTableNameMatches("sales.foo", "foo"))
+	assert.False(t, exportedTableNameMatches("public.foo", "sales.foo"))
+	assert.False(t, exportedTableNameMatches(`"Foo"`, "foo"))
+	assert.False(t, exportedTableNameMatches("Foo", "Foo"))
+
+	sourceDBType = ORACLE
+	assert.True(t, exportedTableNameMatches("orders", "ORDERS"))
+	assert.False(t, exportedTableNameMatches("order_items", "ORDERS"))
+}
@@ -22,8 +22,12 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/jackc/pgconn"
 	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
 	reporter "github.com/yugabyte/yb-voyager/yb-voyager/src/reporter/stats"
@@ -31,83 +35,329 @@ import (
 	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
 )
 
-var NUM_EVENT_CHANNELS int
-var EVENT_CHANNEL_SIZE int // has to be > MAX_EVENTS_PER_BATCH
-var MAX_EVENTS_PER_BATCH int
-var MAX_INTERVAL_BETWEEN_BATCHES int //ms
+var MAX_CONSECUTIVE_EXPORTED_EVENTS_STATS_ERRORS int
 var END_OF_QUEUE_SEGMENT_EVENT = &tgtdb.Event{Op: "end_of_source_queue_segment"}
+var CHECKPOINT_EVENT = &tgtdb.Event{Op: "checkpoint"}
+var streamingCheckpointInterval time.Duration
+var vsnGapDetectionMode string
+var maxInFlightSegments int
+var resumeFromVsn int64
+var metricsAddr string
+
+// Initialized at the package level (rather than in init()) as these are the defaults of the corresponding flags,
+// e.g. --num-event-channels, which override the env vars.
+var NUM_EVENT_CHANNELS = utils.GetEnvAsInt("NUM_EVENT_CHANNELS", 512)
+var EVENT_CHANNEL_SIZE = utils.GetEnvAsInt("EVENT_CHANNEL_SIZE", 2000) // has to be >= MAX_EVENTS_PER_BATCH
+var MAX_EVENTS_PER_BATCH = utils.GetEnvAsInt("MAX_EVENTS_PER_BATCH", 2000)
+
+// Retries of an event batch failed with a deadlock or a serialization failure (or due to its data with --dead-letter-dir),
+// before the streaming is aborted or the batch is dead-lettered. Overridden by --max-retries-per-event-batch.
+var EVENT_BATCH_MAX_RETRY_COUNT = utils.GetEnvAsInt("EVENT_BATCH_MAX_RETRY_COUNT", 5)
+
+// Sleep before the first retry of an event batch, doubled with every retry up to MAX_SLEEP_SECOND.
+var EVENT_BATCH_RETRY_SLEEP_INTERVAL = utils.GetEnvAsDuration("EVENT_BATCH_RETRY_SLEEP_INTERVAL", time.Second, time.Millisecond)
+
+// Plain integers in the env var are interpreted as milliseconds.
+var MAX_INTERVAL_BETWEEN_BATCHES = utils.GetEnvAsDuration("MAX_INTERVAL_BETWEEN_BATCHES", 2*time.Second, time.Millisecond)
+
+const (
+	MIN_ALLOWED_INTERVAL_BETWEEN_BATCHES = 10 * time.Millisecond
+	MAX_ALLOWED_INTERVAL_BETWEEN_BATCHES = 5 * time.Minute
+)
 
 func init() {
-	NUM_EVENT_CHANNELS = utils.GetEnvAsInt("NUM_EVENT_CHANNELS", 512)
-	EVENT_CHANNEL_SIZE = utils.GetEnvAsInt("EVENT_CHANNEL_SIZE", 2000)
-	MAX_EVENTS_PER_BATCH = utils.GetEnvAsInt("MAX_EVENTS_PER_BATCH", 2000)
-	MAX_INTERVAL_BETWEEN_BATCHES = utils.GetEnvAsInt("MAX_INTERVAL_BETWEEN_BATCHES", 2000)
+	MAX_CONSECUTIVE_EXPORTED_EVENTS_STATS_ERRORS = utils.GetEnvAsInt("MAX_CONSECUTIVE_EXPORTED_EVENTS_STATS_ERRORS", 30)
 }
 
+// streamChanges applies the exported changes to the target db until the streaming is stopped.
+// Errors of the event channel processors and of the background stats poller are reported on
+// a dedicated error channel and returned from here, leaving it to the caller to decide how to handle them.
 func streamChanges() error {
-	log.Infof("NUM_EVENT_CHANNELS: %d, EVENT_CHANNEL_SIZE: %d, MAX_EVENTS_PER_BATCH: %d, MAX_INTERVAL_BETWEEN_BATCHES: %d",
+	log.Infof("NUM_EVENT_CHANNELS: %d, EVENT_CHANNEL_SIZE: %d, MAX_EVENTS_PER_BATCH: %d, MAX_INTERVAL_BETWEEN_BATCHES: %s",
 		NUM_EVENT_CHANNELS, EVENT_CHANNEL_SIZE, MAX_EVENTS_PER_BATCH, MAX_INTERVAL_BETWEEN_BATCHES)
-	err := tdb.InitLiveMigrationState(migrationUUID, NUM_EVENT_CHANNELS, startClean, lo.Keys(TableToColumnNames))
+	tdb.SetApplicationName(tconf.GetApplicationName(migrationUUID, tgtdb.APPLICATION_PHASE_STREAMING))
+	streamingSpan = importSpan.StartChild("stream changes")
+	defer streamingSpan.End()
+	err := tdb.InitLiveMigrationState(migrationUUID, NUM_EVENT_CHANNELS, startClean, getTargetTableNames(lo.Keys(TableToColumnNames)))
 	if err != nil {
-		utils.ErrExit("Failed to init event channels metadata table on target DB: %s", err)
+		return fmt.Errorf("failed to init event channels metadata table on target DB: %w", err)
 	}
 	eventChannelsMetaInfo, err := tdb.GetEventChannelsMetaInfo(migrationUUID)
 	if err != nil {
 		return fmt.Errorf("failed to fetch event channel meta info from target : %w", err)
 	}
+	overrideLastAppliedVsn(eventChannelsMetaInfo)
 	statsReporter := reporter.NewStreamImportStatsReporter()
 	err = statsReporter.Init(tdb, migrationUUID)
 	if err != nil {
 		return fmt.Errorf("failed to initialize stats reporter: %w", err)
 	}
-	go updateExportedEventsStats(statsReporter)
+	// buffered so that none of the senders ever blocks, even after the streaming has stopped
+	streamErrs := make(chan error, NUM_EVENT_CHANNELS+2)
+	if overallProgressTracker != nil {
+		overallProgressTracker.StreamingStarted(statsReporter)
+		statsReporter.SetOverallProgressFn(overallProgressTracker.String)
+	}
+	go updateExportedEventsStats(statsReporter, streamErrs)
 	go statsReporter.ReportStats()
+	if metricsAddr != "" {
+		err = statsReporter.StartMetricsServer(metricsAddr)
+		if err != nil {
+			return fmt.Errorf("failed to start the metrics server: %w", err)
+		}
+	}
+	switchoverRequested := make(chan struct{})
+	streamDone := make(chan struct{})
+	defer close(streamDone)
+	if importDestinationType == FF_DB {
+		// left behind by an earlier run stopped for a switchover, it must not pass for this run having stopped
+		removeFallForwardFlag(FF_STREAMING_STOPPED_FLAG)
+		go waitForFallForwardSwitchover(statsReporter, switchoverRequested, streamErrs, streamDone)
+	}
 	eventQueue := NewEventQueue(exportDir)
+	vsnGapDetector := NewVsnGapDetector(vsnGapDetectionMode, statsReporter)
+	unknownTableHandler := NewUnknownTableHandler(unknownTablePolicy, statsReporter)
+	tableFilter := NewStreamTableFilter(skippedFilteredTables, skippedMissingTaskTables, statsReporter)
 	// setup target event channels
 	var evChans []chan *tgtdb.Event
 	var processingDoneChans []chan bool
 	for i := 0; i < NUM_EVENT_CHANNELS; i++ {
 		evChans = append(evChans, make(chan *tgtdb.Event, EVENT_CHANNEL_SIZE))
-		processingDoneChans = append(processingDoneChans, make(chan bool, 1))
+		// one ack per marker, and the markers of all the in-flight segments can be pending at once
+		processingDoneChans = append(processingDoneChans, make(chan bool, maxInFlightSegments+1))
 	}
+	// The processors consume from procChans while the events are dispatched on evChans;
+	// they differ only when the events overflowing the channels are spilled to disk.
+	procChans := evChans
+	if eventSpillMaxSizeMB > 0 {
+		evChans, err = setupEventSpillover(procChans, streamErrs)
+		if err != nil {
+			return fmt.Errorf("failed to setup event spillover: %w", err)
+		}
+	}
+	rateLimiter := NewEventRateLimiter(maxEventsPerSecond)
+	if rateLimiter != nil {
+		utils.PrintAndLog("applying at most %d events per second", maxEventsPerSecond)
+	}
+	// start target event channel processors, they live across the segments
+	for i := 0; i < NUM_EVENT_CHANNELS; i++ {
+		chanMetaInfo, exists := eventChannelsMetaInfo[i]
+		if !exists {
+			return fmt.Errorf("unable to find channel meta info for channel - %v", i)
+		}
+		go processEvents(i, procChans[i], chanMetaInfo.LastAppliedVsn, processingDoneChans[i], streamErrs, statsReporter, rateLimiter)
+	}
+	// The markers sent to the channels are acknowledged in order by a separate goroutine, so that
+	// the next segment can be dispatched while the previous ones are being applied. A segment holds
+	// a slot from the time it is opened until it is marked as processed.
+	markers := make(chan *streamMarker, maxInFlightSegments+1)
+	segmentSlots := make(chan struct{}, maxInFlightSegments)
+	markersDone := make(chan struct{})
+	go completeStreamMarkers(markers, processingDoneChans, segmentSlots, streamErrs, markersDone)
+	gate := &streamDispatchGate{
+		backpressure:  NewEventChannelBackpressure(EVENT_CHANNEL_BLOCKED_THRESHOLD, statsReporter),
+		statsReporter: statsReporter,
+	}
+	stopSignals := make(chan os.Signal, 1)
+	streamStopSignals.Store(&stopSignals)
+	defer streamStopSignals.Store(nil)
 
 	log.Infof("streaming changes from %s", eventQueue.QueueDirPath)
-	for { // continuously get next segments to stream
-		segment, err := eventQueue.GetNextSegment()
-		if err != nil {
-			if segment == nil && errors.Is(err, os.ErrNotExist) {
-				time.Sleep(2 * time.Second)
-				continue
+	// The queue is read in a separate goroutine, as it blocks while waiting for new events
+	// and the errors of the channel processors must be surfaced in the meantime.
+	queueReadErr := make(chan error, 1)
+	go func() {
+		for { // continuously get next segments to stream
+			select {
+			case segmentSlots <- struct{}{}:
+			case err := <-streamErrs:
+				queueReadErr <- err
+				return
 			}
-			return fmt.Errorf("error getting next segment to stream: %v", err)
-		}
-		log.Infof("got next segment to stream: %v", segment)
+			var segment *EventQueueSegment
+			var err error
+			for {
+				segment, err = eventQueue.GetNextSegment()
+				if err != nil && segment == nil && errors.Is(err, os.ErrNotExist) {
+					time.Sleep(2 * time.Second)
+					continue
+				}
+				break
+			}
+			if err != nil {
+				queueReadErr <- fmt.Errorf("error getting next segment to stream: %v", err)
+				return
+			}
+			log.Infof("got next segment to stream: %v", segment)
 
-		err = streamChangesFromSegment(segment, evChans, processingDoneChans, eventChannelsMetaInfo, statsReporter)
-		if err != nil {
-			return fmt.Errorf("error streaming changes for segment %s: %v", segment.FilePath, err)
+			err = streamChangesFromSegment(segment, evChans, markers, streamErrs, eventChannelsMetaInfo,
+				vsnGapDetector, unknownTableHandler, tableFilter, gate)
+			if errors.Is(err, errStreamingStopped) {
+				queueReadErr <- err
+				return
+			}
+			if err != nil {
+				queueReadErr <- fmt.Errorf("error streaming changes for segment %s: %v", segment.FilePath, err)
+				return
+			}
+		}
+	}()
+	select {
+	case err = <-queueReadErr:
+	case err = <-streamErrs:
+	case sig := <-stopSignals:
+		utils.PrintAndLog("Received %s, stopping the streaming once the changes read so far are applied...", sig)
+		err = stopStreaming(gate, evChans, markers, markersDone, streamErrs)
+	case <-switchoverRequested:
+		utils.PrintAndLog("Switchover to the fall forward database is requested, stopping the streaming once the changes read so far are applied...")
+		err = stopStreaming(gate, evChans, markers, markersDone, streamErrs)
+		if errors.Is(err, errStreamingStopped) {
+			createFallForwardFlag(FF_STREAMING_STOPPED_FLAG)
+			err = errStreamingStoppedForSwitchover
 		}
 	}
+	return err
 }
 
-func streamChangesFromSegment(segment *EventQueueSegment, evChans []chan *tgtdb.Event, processingDoneChans []chan bool, eventChannelsMetaInfo map[int]tgtdb.EventChannelMetaInfo, statsReporter *reporter.StreamImportStatsReporter) error {
-	err := segment.Open()
+// stopStreaming stops the dispatch of the events and waits for the events dispatched so far to be applied.
+// It returns errStreamingStopped once they are.
+func stopStreaming(gate *streamDispatchGate, evChans []chan *tgtdb.Event, markers chan<- *streamMarker,
+	markersDone <-chan struct{}, streamErrs chan error) error {
+	err := gate.stop(evChans, markers, streamErrs)
 	if err != nil {
 		return err
 	}
-	defer segment.Close()
+	select {
+	case <-markersDone:
+		return errStreamingStopped
+	case err = <-streamErrs:
+		return err
+	}
+}
+
+var errStreamingStopped = errors.New("streaming stopped")
+var errStreamingStoppedForSwitchover = errors.New("streaming stopped for the switchover")
+
+// Set while the changes are being streamed, for the signals to stop the streaming gracefully instead of exiting.
+var streamStopSignals atomic.Pointer[chan os.Signal]
 
-	// start target event channel processors
+// RouteSignal hands the signal received by the process over to the graceful stop of the streaming, if the changes
+// are being streamed. Only the first signal is routed, a second one terminates right away.
+// Returns false if the signal is not handled and the process is to exit.
+func RouteSignal(sig os.Signal) bool {
+	stopSignals := streamStopSignals.Swap(nil)
+	if stopSignals == nil {
+		return false
+	}
+	*stopSignals <- sig // buffered, and received only by the stream
+	return true
+}
+
+/*
+streamDispatchGate serializes the dispatch of the events and markers to the event channels with the graceful stop
+of the streaming. Once stopped, a last checkpoint marker is sent after the last dispatched event, and nothing
+is dispatched after it. The queue reader is not waited for, as it can be blocked waiting for new events.
+*/
+type streamDispatchGate struct {
+	sync.Mutex
+	stopped           bool
+	lastDispatchedVsn int64
+	backpressure      *EventChannelBackpressure
+	statsReporter     *reporter.StreamImportStatsReporter
+}
+
+func (g *streamDispatchGate) dispatchEvent(event *tgtdb.Event, evChans []chan *tgtdb.Event, streamErrs chan error) error {
+	g.Lock()
+	defer g.Unlock()
+	if g.stopped {
+		return errStreamingStopped
+	}
+	err := handleEvent(event, evChans, streamErrs, g.backpressure, g.statsReporter)
+	if err != nil {
+		return err
+	}
+	g.lastDispatchedVsn = event.Vsn
+	return nil
+}
+
+func (g *streamDispatchGate) dispatchMarker(evChans []chan *tgtdb.Event, markers chan<- *streamMarker, streamErrs chan error, marker *streamMarker) error {
+	g.Lock()
+	defer g.Unlock()
+	if g.stopped {
+		return errStreamingStopped
+	}
+	return signalEventChannels(evChans, markers, streamErrs, marker)
+}
+
+// stop sends a checkpoint marker after the last dispatched event, so that the last applied vsn of all the channels is
+// recorded once the events dispatched so far are applied, and closes the markers as nothing is dispatched after it.
+// The segment being read is not marked as processed, a subsequent run resumes it after the last applied vsns.
+func (g *streamDispatchGate) stop(evChans []chan *tgtdb.Event, markers chan<- *streamMarker, streamErrs chan error) error {
+	g.Lock()
+	defer g.Unlock()
+	g.stopped = true
+	if g.lastDispatchedVsn > 0 {
+		err := signalEventChannels(evChans, markers, streamErrs, &streamMarker{event: CHECKPOINT_EVENT, vsn: g.lastDispatchedVsn})
+		if err != nil {
+			return err
+		}
+	}
+	close(markers)
+	return nil
+}
+
+// overrideLastAppliedVsn lowers the last applied vsn of the channels to --resume-from-vsn, so that the events after it
+// are streamed again. The channels behind it are left as they are, as raising their vsn would skip the events in between.
+func overrideLastAppliedVsn(eventChannelsMetaInfo map[int]tgtdb.EventChannelMetaInfo) {
 	for i := 0; i < NUM_EVENT_CHANNELS; i++ {
-		var chanLastAppliedVsn int64
 		chanMetaInfo, exists := eventChannelsMetaInfo[i]
-		if exists {
-			chanLastAppliedVsn = chanMetaInfo.LastAppliedVsn
-		} else {
-			return fmt.Errorf("unable to find channel meta info for channel - %v", i)
+		if !exists {
+			continue
+		}
+		if resumeFromVsn >= 0 && resumeFromVsn < chanMetaInfo.LastAppliedVsn {
+			log.Infof("overriding last applied vsn %d of channel %d with --resume-from-vsn", chanMetaInfo.LastAppliedVsn, i)
+			chanMetaInfo.LastAppliedVsn = resumeFromVsn
+			eventChannelsMetaInfo[i] = chanMetaInfo
 		}
-		go processEvents(i, evChans[i], chanLastAppliedVsn, processingDoneChans[i], statsReporter)
+		log.Infof("channel %d: streaming events after vsn %d", i, chanMetaInfo.LastAppliedVsn)
 	}
+}
+
+// streamChangesFromSegment dispatches the events of the segment to the event channels. It returns once
+// all of them are dispatched; the segment is marked as processed by completeStreamMarkers once they are applied.
+func streamChangesFromSegment(segment *EventQueueSegment, evChans []chan *tgtdb.Event, markers chan<- *streamMarker, streamErrs chan error,
+	eventChannelsMetaInfo map[int]tgtdb.EventChannelMetaInfo, vsnGapDetector *VsnGapDetector, unknownTableHandler *UnknownTableHandler,
+	tableFilter *StreamTableFilter, gate *streamDispatchGate) error {
+	// Events up to the lowest last applied vsn of the channels are applied on all the channels and need not be
+	// converted and dispatched. Checkpoints raise the last applied vsn of the idle channels to keep this point recent.
+	resumeVsn := int64(-1)
+	for i, chanMetaInfo := range lo.Values(eventChannelsMetaInfo) {
+		if i == 0 || chanMetaInfo.LastAppliedVsn < resumeVsn {
+			resumeVsn = chanMetaInfo.LastAppliedVsn
+		}
+	}
+	// The events before the offset recorded at the last checkpoint are not read again, unless the channels
+	// are behind them, e.g. with --start-clean or --resume-from-vsn.
+	resumeOffset, resumeOffsetVsn, err := metaDB.GetEventQueueSegmentResumeOffset(segment.SegmentNum)
+	if err != nil {
+		return fmt.Errorf("get resume offset of segment %s: %w", segment.FilePath, err)
+	}
+	if resumeOffset > 0 && resumeOffsetVsn > resumeVsn {
+		log.Infof("not resuming segment %s from offset %d, as its vsn %d is after the last applied vsn %d",
+			segment.FilePath, resumeOffset, resumeOffsetVsn, resumeVsn)
+		resumeOffset = 0
+	}
+	if resumeOffset > 0 {
+		log.Infof("resuming segment %s from offset %d (vsn %d)", segment.FilePath, resumeOffset, resumeOffsetVsn)
+	}
+	err = segment.Open(resumeOffset)
+	if err != nil {
+		return err
+	}
+	defer segment.Close()
+
+	var lastDispatchedVsn, lastDispatchedOffset int64
+	lastCheckpointTime := time.Now()
 
 	log.Infof("streaming changes for segment %s", segment.FilePath)
 	for !segment.IsProcessed() {
@@ -120,21 +370,129 @@ func streamChangesFromSegment(segment *EventQueueSegment, evChans []chan *tgtdb.
 			break
 		}
 
-		err = handleEvent(event, evChans)
+		err = vsnGapDetector.Check(event)
+		if err != nil {
+			return err
+		}
+
+		if event.Vsn <= resumeVsn {
+			log.Tracef("skipping event %v already applied on all the channels (vsn <= %v)", event.Vsn, resumeVsn)
+			continue
+		}
+
+		tableName := getEventTableName(event)
+		if tableFilter.Filter(event, tableName) {
+			continue
+		}
+		skip, err := unknownTableHandler.Handle(event, tableName)
+		if err != nil {
+			return err
+		}
+		if skip {
+			continue
+		}
+
+		err = gate.dispatchEvent(event, evChans, streamErrs)
+		if errors.Is(err, errStreamingStopped) {
+			return err
+		}
 		if err != nil {
 			return fmt.Errorf("error handling event: %v", err)
 		}
+		lastDispatchedVsn = event.Vsn
+		lastDispatchedOffset = segment.Offset()
+
+		if streamingCheckpointInterval > 0 && time.Since(lastCheckpointTime) >= streamingCheckpointInterval {
+			err = gate.dispatchMarker(evChans, markers, streamErrs, &streamMarker{event: CHECKPOINT_EVENT, vsn: lastDispatchedVsn,
+				checkpointSegment: segment, checkpointOffset: lastDispatchedOffset})
+			if err != nil {
+				return err
+			}
+			lastCheckpointTime = time.Now()
+		}
 	}
 
+	return gate.dispatchMarker(evChans, markers, streamErrs,
+		&streamMarker{event: END_OF_QUEUE_SEGMENT_EVENT, vsn: lastDispatchedVsn, segment: segment,
+			checkpointSegment: segment, checkpointOffset: lastDispatchedOffset})
+}
+
+// streamMarker is a marker event sent to all the event channels, along with what has to be done once
+// all of them have applied the events dispatched before it.
+type streamMarker struct {
+	event   *tgtdb.Event
+	vsn     int64              // last vsn dispatched before the marker
+	segment *EventQueueSegment // set for END_OF_QUEUE_SEGMENT_EVENT
+	// the segment and the offset in it after the event of vsn, from which the streaming of the segment
+	// resumes once the event channels are checkpointed at the marker
+	checkpointSegment *EventQueueSegment
+	checkpointOffset  int64
+}
+
+// signalEventChannels sends the marker event to all the channels and hands it over to completeStreamMarkers,
+// without waiting for the channels to process it.
+func signalEventChannels(evChans []chan *tgtdb.Event, markers chan<- *streamMarker, streamErrs chan error, marker *streamMarker) error {
 	for i := 0; i < NUM_EVENT_CHANNELS; i++ {
-		evChans[i] <- END_OF_QUEUE_SEGMENT_EVENT
+		select {
+		case evChans[i] <- marker.event:
+		case err := <-streamErrs:
+			return err
+		}
 	}
+	select {
+	case markers <- marker:
+	case err := <-streamErrs:
+		return err
+	}
+	return nil
+}
 
-	for i := 0; i < NUM_EVENT_CHANNELS; i++ {
-		<-processingDoneChans[i]
+// completeStreamMarkers waits, in the order the markers were sent, until all the channels have processed each marker.
+// As the events of a channel are applied in order, all the events dispatched before the marker are applied by then:
+//   - for a CHECKPOINT_EVENT, the marker vsn is recorded as the last applied vsn of every channel.
+//   - for an END_OF_QUEUE_SEGMENT_EVENT, the segment is marked as processed and its slot is released.
+//
+// `done` is closed once the markers are closed and all of them are completed.
+func completeStreamMarkers(markers <-chan *streamMarker, processingDoneChans []chan bool, segmentSlots <-chan struct{}, streamErrs chan<- error,
+	done chan<- struct{}) {
+	for marker := range markers {
+		for i := 0; i < NUM_EVENT_CHANNELS; i++ {
+			// the channel processors report their errors on streamErrs and stop acknowledging the markers,
+			// in which case this blocks until the streaming is stopped.
+			<-processingDoneChans[i]
+		}
+		err := completeStreamMarker(marker)
+		if err != nil {
+			streamErrs <- err
+			return
+		}
+		if marker.segment != nil {
+			<-segmentSlots
+		}
+	}
+	close(done)
+}
+
+func completeStreamMarker(marker *streamMarker) error {
+	if marker.segment == nil {
+		err := tdb.CheckpointEventChannels(migrationUUID, marker.vsn)
+		if err != nil {
+			return fmt.Errorf("checkpoint event channels at vsn %d: %w", marker.vsn, err)
+		}
+		log.Infof("checkpointed event channels at vsn %d", marker.vsn)
+		recordSegmentResumeOffset(marker)
+		return nil
 	}
 
-	err = metaDB.MarkEventQueueSegmentAsProcessed(segment.SegmentNum)
+	segment := marker.segment
+	if streamingCheckpointInterval > 0 && marker.vsn > 0 {
+		err := tdb.CheckpointEventChannels(migrationUUID, marker.vsn)
+		if err != nil {
+			return fmt.Errorf("checkpoint event channels at the end of segment %s: %w", segment.FilePath, err)
+		}
+		recordSegmentResumeOffset(marker)
+	}
+	err := metaDB.MarkEventQueueSegmentAsProcessed(segment.SegmentNum)
 	if err != nil {
 		return fmt.Errorf("error marking segment %s as processed: %v", segment.FilePath, err)
 	}
@@ -142,24 +500,129 @@ func streamChangesFromSegment(segment *EventQueueSegment, evChans []chan *tgtdb.
 	return nil
 }
 
+// recordSegmentResumeOffset records the offset of the marker in its segment, after the event channels are checkpointed
+// at the marker. It only saves reading the segment from its start on restart, hence a failure doesn't fail the streaming.
+func recordSegmentResumeOffset(marker *streamMarker) {
+	if marker.checkpointSegment == nil || marker.checkpointOffset == 0 {
+		return
+	}
+	err := metaDB.SetEventQueueSegmentResumeOffset(marker.checkpointSegment.SegmentNum, marker.checkpointOffset, marker.vsn)
+	if err != nil {
+		log.Warnf("failed to record the resume offset of segment %s: %v", marker.checkpointSegment.FilePath, err)
+	}
+}
+
+// waitForFallForwardSwitchover notifies once the switchover to the fall forward database is requested, and the
+// remaining events are within the lag allowed by the request. The streaming is then stopped after applying the events
+// read so far; they are applied in transactions along with the last applied vsn of their channel.
+func waitForFallForwardSwitchover(statsReporter *reporter.StreamImportStatsReporter, switchoverRequested chan<- struct{},
+	streamErrs chan<- error, streamDone <-chan struct{}) {
+	for {
+		select {
+		case <-streamDone:
+			return
+		case <-time.After(5 * time.Second):
+		}
+		requested, allowedLag, err := getFallForwardSwitchoverRequest()
+		if err != nil {
+			streamErrs <- err
+			return
+		}
+		if !requested {
+			continue
+		}
+		remainingEvents, known := statsReporter.GetRemainingEvents()
+		if allowedLag != SWITCHOVER_ANY_LAG && (!known || remainingEvents > allowedLag) {
+			log.Infof("switchover requested, waiting for the remaining events (%d) to be within %d", remainingEvents, allowedLag)
+			continue
+		}
+		close(switchoverRequested)
+		return
+	}
+}
+
+// VsnGapDetector watches the VSNs of the events read from the queue and reports
+// missing VSNs, which indicate lost events. VSNs are assigned by the exporter in a single
+// contiguous sequence across all tables, hence the check is done on the queue reader side
+// before the events are distributed to the event channels.
+type VsnGapDetector struct {
+	mode          string
+	lastSeenVsn   int64
+	statsReporter *reporter.StreamImportStatsReporter
+}
+
+func NewVsnGapDetector(mode string, statsReporter *reporter.StreamImportStatsReporter) *VsnGapDetector {
+	return &VsnGapDetector{
+		mode:          mode,
+		lastSeenVsn:   -1,
+		statsReporter: statsReporter,
+	}
+}
+
+func (d *VsnGapDetector) Check(event *tgtdb.Event) error {
+	if d.mode == VSN_GAP_DETECTION_DISABLED {
+		return nil
+	}
+	lastSeenVsn := d.lastSeenVsn
+	d.lastSeenVsn = event.Vsn
+	if lastSeenVsn == -1 || event.Vsn == lastSeenVsn+1 {
+		return nil
+	}
+	numMissingEvents := event.Vsn - lastSeenVsn - 1
+	d.statsReporter.VsnGapDetected(numMissingEvents)
+	msg := fmt.Sprintf("gap in event sequence detected: expected vsn %d, got vsn %d (%d events missing)",
+		lastSeenVsn+1, event.Vsn, numMissingEvents)
+	if d.mode == VSN_GAP_DETECTION_ABORT {
+		return errors.New(msg)
+	}
+	log.Warn(msg)
+	return nil
+}
+
 func shouldFormatValues(event *tgtdb.Event) bool {
 	return (tconf.TargetDBType == YUGABYTEDB && event.Op == "u") ||
-			tconf.TargetDBType == ORACLE
+		tconf.TargetDBType == ORACLE || tconf.TargetDBType == MYSQL
 }
-func handleEvent(event *tgtdb.Event, evChans []chan *tgtdb.Event) error {
-	log.Debugf("Handling event: %v", event)
-	tableName := event.TableName
+func getEventTableName(event *tgtdb.Event) string {
 	if sourceDBType == "postgresql" && event.SchemaName != "public" {
-		tableName = event.SchemaName + "." + event.TableName
+		return event.SchemaName + "." + event.TableName
+	}
+	return event.TableName
+}
+
+func handleEvent(event *tgtdb.Event, evChans []chan *tgtdb.Event, streamErrs chan error, backpressure *EventChannelBackpressure,
+	statsReporter *reporter.StreamImportStatsReporter) error {
+	log.Debugf("Handling event: %v", event)
+	tableName := getEventTableName(event)
+	if deadLetterDir != "" {
+		// the values are converted in place
+		event.ExportedEvent = event.Clone()
 	}
 	// preparing value converters for the streaming mode
 	err := valueConverter.ConvertEvent(event, tableName, shouldFormatValues(event))
+	if err != nil && deadLetterDir != "" {
+		return deadLetterEvent(hashEvent(event.ExportedEvent), event, fmt.Errorf("error transforming event key fields: %w", err), statsReporter)
+	}
 	if err != nil {
 		return fmt.Errorf("error transforming event key fields: %v", err)
 	}
+	err = redactEventValues(event, tableName, shouldFormatValues(event))
+	if err != nil {
+		return fmt.Errorf("redact event of table %s: %w", tableName, err)
+	}
+	err = mapEventColumns(event, tableName)
+	if err != nil {
+		return fmt.Errorf("map columns of event of table %s: %w", tableName, err)
+	}
+	if targetSchema, ok := tconf.MapSourceSchema(event.SchemaName); ok {
+		event.TargetSchemaName = targetSchema
+	}
 
 	h := hashEvent(event)
-	evChans[h] <- event
+	err = backpressure.send(evChans[h], h, event, streamErrs)
+	if err != nil {
+		return err
+	}
 	log.Tracef("inserted event %v into channel %v", event.Vsn, h)
 	return nil
 }
@@ -182,22 +645,23 @@ func hashEvent(e *tgtdb.Event) int {
 	return int(hash.Sum64() % (uint64(NUM_EVENT_CHANNELS)))
 }
 
-func processEvents(chanNo int, evChan chan *tgtdb.Event, lastAppliedVsn int64, done chan bool, statsReporter *reporter.StreamImportStatsReporter) {
-	endOfProcessing := false
-	for !endOfProcessing {
+func processEvents(chanNo int, evChan chan *tgtdb.Event, lastAppliedVsn int64, done chan bool, streamErrs chan<- error,
+	statsReporter *reporter.StreamImportStatsReporter, rateLimiter *EventRateLimiter) {
+	for {
 		batch := []*tgtdb.Event{}
-		timer := time.NewTimer(time.Duration(MAX_INTERVAL_BETWEEN_BATCHES) * time.Millisecond)
+		markerReceived := false
+		timer := time.NewTimer(MAX_INTERVAL_BETWEEN_BATCHES)
 	Batching:
 		for {
 			// read from channel until MAX_EVENTS_PER_BATCH or MAX_INTERVAL_BETWEEN_BATCHES
 			select {
 			case event := <-evChan:
-				if event == END_OF_QUEUE_SEGMENT_EVENT {
-					endOfProcessing = true
+				if event == END_OF_QUEUE_SEGMENT_EVENT || event == CHECKPOINT_EVENT {
+					markerReceived = true
 					break Batching
 				}
 				if event.Vsn <= lastAppliedVsn {
-					log.Tracef("ignoring event %v because event vsn <= %v", event, lastAppliedVsn)
+					log.WithField(tgtdb.LOG_FIELD_VSN, event.Vsn).Tracef("ignoring event %v because event vsn <= %v", event, lastAppliedVsn)
 					continue
 				}
 				batch = append(batch, event)
@@ -210,32 +674,155 @@ func processEvents(chanNo int, evChan chan *tgtdb.Event, lastAppliedVsn int64, d
 		}
 		timer.Stop()
 
-		if len(batch) == 0 {
-			continue
+		if len(batch) > 0 {
+			// Only the batches wait for the rate limiter, the markers are acknowledged right after them.
+			rateLimiter.Wait(len(batch))
+			err := executeEventBatchWithRetries(chanNo, batch, statsReporter)
+			if err != nil {
+				streamErrs <- err
+				return
+			}
+		}
+		if markerReceived {
+			// all the events sent to the channel before the marker are applied
+			done <- true
 		}
+	}
+}
 
-		start := time.Now()
-		eventBatch := tgtdb.NewEventBatch(batch, chanNo, tconf.Schema)
-		err := tdb.ExecuteBatch(migrationUUID, eventBatch)
+/*
+executeEventBatchWithRetries retries the batch on the transient errors, i.e. deadlocks and serialization failures,
+with an exponential backoff. Any other error, or a transient one after EVENT_BATCH_MAX_RETRY_COUNT retries, is returned.
+With --dead-letter-dir, the errors due to the data of the events are retried too, and the batch is dead-lettered
+after the retries instead of the error being returned. The other errors, e.g. losing the connection to the target,
+are never dead-lettered, so that the healthy batches are not skipped while the target is unavailable.
+The batch is applied in a transaction along with the last applied vsn of the channel, hence before a retry, the events
+up to the last applied vsn are dropped from the batch, in case the failed attempt was committed (e.g. the error was on
+the commit), so that none of the events is applied twice.
+*/
+func executeEventBatchWithRetries(chanNo int, batch []*tgtdb.Event, statsReporter *reporter.StreamImportStatsReporter) error {
+	sleepInterval := EVENT_BATCH_RETRY_SLEEP_INTERVAL
+	for attempt := 1; ; attempt++ {
+		err := executeEventBatch(chanNo, batch, statsReporter)
+		deadLetter := deadLetterDir != "" && isDataEventBatchError(err)
+		if err == nil || (!isTransientEventBatchError(err) && !deadLetter) {
+			return err
+		}
+		if attempt > EVENT_BATCH_MAX_RETRY_COUNT {
+			err = fmt.Errorf("giving up after %d retries: %w", EVENT_BATCH_MAX_RETRY_COUNT, err)
+			if !deadLetter {
+				return err
+			}
+			return deadLetterEventBatch(chanNo, batch, err, statsReporter)
+		}
+		log.Warnf("error executing batch of %d events on channel %d (attempt %d), retrying in %s: %s",
+			len(batch), chanNo, attempt, sleepInterval, err)
+		time.Sleep(sleepInterval)
+		sleepInterval = getEventBatchRetrySleepInterval(sleepInterval)
+
+		eventChannelsMetaInfo, err := tdb.GetEventChannelsMetaInfo(migrationUUID)
 		if err != nil {
-			utils.ErrExit("error executing batch on channel %v: %w", chanNo, err)
+			return fmt.Errorf("failed to fetch the last applied vsn of channel %d to retry the batch: %w", chanNo, err)
+		}
+		lastAppliedVsn := eventChannelsMetaInfo[chanNo].LastAppliedVsn
+		batch = lo.Filter(batch, func(event *tgtdb.Event, _ int) bool { return event.Vsn > lastAppliedVsn })
+		if len(batch) == 0 {
+			log.Infof("batch on channel %d was applied by the failed attempt, last applied vsn: %d", chanNo, lastAppliedVsn)
+			return nil
 		}
-		statsReporter.BatchImported(eventBatch.EventCounts.NumInserts, eventBatch.EventCounts.NumUpdates, eventBatch.EventCounts.NumDeletes)
-		log.Debugf("processEvents from channel %v: Executed Batch of size - %d successfully in time %s",
-			chanNo, len(batch), time.Since(start).String())
 	}
-	done <- true
 }
 
-func updateExportedEventsStats(statsReporter *reporter.StreamImportStatsReporter) {
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
+func getEventBatchRetrySleepInterval(prevSleepInterval time.Duration) time.Duration {
+	sleepInterval := prevSleepInterval * 2
+	if sleepInterval > MAX_SLEEP_SECOND*time.Second {
+		sleepInterval = MAX_SLEEP_SECOND * time.Second
+	}
+	return sleepInterval
+}
+
+// isTransientEventBatchError tells if the batch failed due to a deadlock or a serialization failure (e.g. a
+// conflict with a concurrent transaction), which is expected to succeed on a retry.
+func isTransientEventBatchError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == DEADLOCK_DETECTED_ERR_CODE || pgErr.Code == SERIALIZATION_FAILED_ERR_CODE
+	}
+	return false
+}
+
+// isDataEventBatchError tells if the batch failed due to the data of its events, i.e. a data exception (SQLSTATE
+// class 22) or an integrity constraint violation (class 23), which fails the batch on every retry. The errors
+// without a PgError, e.g. of the other target dbs, are never classified as such.
+func isDataEventBatchError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, "22") || strings.HasPrefix(pgErr.Code, "23")
+	}
+	return false
+}
+
+func executeEventBatch(chanNo int, batch []*tgtdb.Event, statsReporter *reporter.StreamImportStatsReporter) error {
+	start := time.Now()
+	eventBatch := tgtdb.NewEventBatch(batch, chanNo, tconf.Schema)
+	logger := log.WithField(tgtdb.LOG_FIELD_EVENT_BATCH_ID, eventBatch.GetCorrelationID())
+	span := streamingSpan.StartChild("apply event batch")
+	defer span.End()
+	span.SetAttribute("channel", chanNo)
+	span.SetAttribute("events", len(batch))
+	err := tdb.ExecuteBatch(migrationUUID, eventBatch)
+	if err != nil {
+		span.SetError(err)
+		logger.Errorf("error executing batch on channel %v: %s", chanNo, err)
+		return fmt.Errorf("error executing batch %s on channel %v: %w", eventBatch.GetCorrelationID(), chanNo, err)
+	}
+	span.SetAttribute("inserts", eventBatch.EventCounts.NumInserts)
+	span.SetAttribute("updates", eventBatch.EventCounts.NumUpdates)
+	span.SetAttribute("deletes", eventBatch.EventCounts.NumDeletes)
+	for tableName, counts := range eventBatch.EventCountsByTable {
+		statsReporter.BatchImported(tableName, counts.NumInserts, counts.NumUpdates, counts.NumDeletes)
+	}
+	logger.Debugf("processEvents from channel %v: Executed Batch of size - %d successfully in time %s",
+		chanNo, len(batch), time.Since(start).String())
+	return nil
+}
 
-	for range ticker.C {
+// updateExportedEventsStats periodically refreshes the remaining events in the stats reporter.
+// Transient meta db errors are retried with backoff; the reporter keeps showing the last known
+// value until the next successful poll. Only sustained failures are reported on `streamErrs`.
+func updateExportedEventsStats(statsReporter *reporter.StreamImportStatsReporter, streamErrs chan<- error) {
+	pollInterval := 10 * time.Second
+	numConsecutiveErrors := 0
+	for {
+		sleepInterval := pollInterval
 		totalExportedEvents, _, err := metaDB.GetTotalExportedEvents(time.Now().String())
 		if err != nil {
-			utils.ErrExit("failed to fetch exported events stats from meta db: %v", err)
+			numConsecutiveErrors++
+			if numConsecutiveErrors > MAX_CONSECUTIVE_EXPORTED_EVENTS_STATS_ERRORS {
+				streamErrs <- fmt.Errorf("failed to fetch exported events stats from meta db after %d attempts: %w", numConsecutiveErrors, err)
+				return
+			}
+			// back off linearly with every consecutive failure, capped at MAX_SLEEP_SECOND
+			sleepInterval = time.Duration(numConsecutiveErrors) * pollInterval
+			if sleepInterval > MAX_SLEEP_SECOND*time.Second {
+				sleepInterval = MAX_SLEEP_SECOND * time.Second
+			}
+			log.Warnf("failed to fetch exported events stats from meta db (attempt %d), retrying in %s: %v",
+				numConsecutiveErrors, sleepInterval, err)
+		} else {
+			if numConsecutiveErrors > 0 {
+				log.Infof("fetched exported events stats from meta db after %d failed attempts", numConsecutiveErrors)
+			}
+			numConsecutiveErrors = 0
+			statsReporter.UpdateRemainingEvents(totalExportedEvents)
+			// only for the lagging tables displayed in the stats, hence not counted as a failure
+			exportedEventsByTable, err := metaDB.GetExportedEventsByTable(&tconf)
+			if err != nil {
+				log.Warnf("failed to fetch exported events stats of the tables from meta db: %v", err)
+			} else {
+				statsReporter.UpdateRemainingEventsByTable(exportedEventsByTable)
+			}
 		}
-		statsReporter.UpdateRemainingEvents(totalExportedEvents)
+		time.Sleep(sleepInterval)
 	}
-}
\ No newline at end of file
+}
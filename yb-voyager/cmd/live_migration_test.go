@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+)
+
+func TestRouteSignal(t *testing.T) {
+	assert := assert.New(t)
+	assert.False(RouteSignal(syscall.SIGINT))
+
+	stopSignals := make(chan os.Signal, 1)
+	streamStopSignals.Store(&stopSignals)
+	defer streamStopSignals.Store(nil)
+	assert.True(RouteSignal(syscall.SIGTERM))
+	assert.Equal(syscall.SIGTERM, <-stopSignals)
+	// a second signal is not routed to the stopping stream
+	assert.False(RouteSignal(syscall.SIGINT))
+}
+
+func TestOverrideLastAppliedVsn(t *testing.T) {
+	assert := assert.New(t)
+	defer func(n int, vsn int64) { NUM_EVENT_CHANNELS, resumeFromVsn = n, vsn }(NUM_EVENT_CHANNELS, resumeFromVsn)
+	NUM_EVENT_CHANNELS = 3
+	newMetaInfo := func() map[int]tgtdb.EventChannelMetaInfo {
+		return map[int]tgtdb.EventChannelMetaInfo{
+			0: {ChanNo: 0, LastAppliedVsn: 50},
+			1: {ChanNo: 1, LastAppliedVsn: 150},
+			2: {ChanNo: 2, LastAppliedVsn: -1},
+		}
+	}
+
+	resumeFromVsn = -1
+	metaInfo := newMetaInfo()
+	overrideLastAppliedVsn(metaInfo)
+	assert.Equal(newMetaInfo(), metaInfo)
+
+	resumeFromVsn = 100
+	metaInfo = newMetaInfo()
+	overrideLastAppliedVsn(metaInfo)
+	assert.Equal(int64(50), metaInfo[0].LastAppliedVsn) // behind the resume vsn
+	assert.Equal(int64(100), metaInfo[1].LastAppliedVsn)
+	assert.Equal(int64(-1), metaInfo[2].LastAppliedVsn)
+}
+
+func TestStreamDispatchGateStop(t *testing.T) {
+	assert := assert.New(t)
+	defer func(n int) { NUM_EVENT_CHANNELS = n }(NUM_EVENT_CHANNELS)
+	NUM_EVENT_CHANNELS = 2
+	evChans := []chan *tgtdb.Event{make(chan *tgtdb.Event, 4), make(chan *tgtdb.Event, 4)}
+	markers := make(chan *streamMarker, 4)
+	streamErrs := make(chan error, 1)
+
+	gate := &streamDispatchGate{}
+	assert.NoError(gate.dispatchMarker(evChans, markers, streamErrs, &streamMarker{event: CHECKPOINT_EVENT, vsn: 10}))
+	gate.lastDispatchedVsn = 15
+	assert.NoError(gate.stop(evChans, markers, streamErrs))
+	assert.ErrorIs(gate.dispatchMarker(evChans, markers, streamErrs, &streamMarker{event: CHECKPOINT_EVENT, vsn: 20}), errStreamingStopped)
+	assert.ErrorIs(gate.dispatchEvent(&tgtdb.Event{Vsn: 20}, evChans, streamErrs), errStreamingStopped)
+
+	// the checkpoint at the last dispatched vsn follows the earlier marker, and the markers are closed after it
+	var vsns []int64
+	for marker := range markers {
+		vsns = append(vsns, marker.vsn)
+	}
+	assert.Equal([]int64{10, 15}, vsns)
+	for _, evChan := range evChans {
+		assert.Equal(2, len(evChan))
+	}
+}
+
+func TestIsTransientEventBatchError(t *testing.T) {
+	assert := assert.New(t)
+	wrap := func(code string) error {
+		return fmt.Errorf("error executing batch: %w", fmt.Errorf("error executing stmt for event with vsn(1): %w", &pgconn.PgError{Code: code}))
+	}
+	assert.True(isTransientEventBatchError(wrap(DEADLOCK_DETECTED_ERR_CODE)))
+	assert.True(isTransientEventBatchError(wrap(SERIALIZATION_FAILED_ERR_CODE)))
+	assert.False(isTransientEventBatchError(wrap("23505")))
+	assert.False(isTransientEventBatchError(fmt.Errorf("connection refused")))
+
+	assert.True(isDataEventBatchError(wrap("23505")))
+	assert.True(isDataEventBatchError(wrap("22P02")))
+	for _, code := range []string{"08006", "57P01", "53300", DEADLOCK_DETECTED_ERR_CODE} {
+		assert.False(isDataEventBatchError(wrap(code)), code)
+	}
+	assert.False(isDataEventBatchError(fmt.Errorf("invalid input syntax for type integer")))
+
+	assert.Equal(2*time.Second, getEventBatchRetrySleepInterval(time.Second))
+	assert.Equal(MAX_SLEEP_SECOND*time.Second, getEventBatchRetrySleepInterval(45*time.Second))
+}
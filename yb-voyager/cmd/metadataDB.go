@@ -17,6 +17,7 @@ package cmd
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -24,6 +25,7 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
 	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
 )
 
@@ -32,6 +34,13 @@ var (
 	QUEUE_SEGMENT_META_TABLE_NAME              = "queue_segment_meta"
 	EXPORTED_EVENTS_STATS_TABLE_NAME           = "exported_events_stats"
 	EXPORTED_EVENTS_STATS_PER_TABLE_TABLE_NAME = "exported_events_stats_per_table"
+	MIGRATION_STATUS_TABLE_NAME                = "migration_status"
+	QUEUE_SEGMENT_RESUME_OFFSET_TABLE_NAME     = "queue_segment_resume_offset"
+)
+
+// keys of the MIGRATION_STATUS_TABLE_NAME table
+const (
+	FF_SWITCHOVER_DONE_KEY = "fall_forward_switchover_done"
 )
 
 func getMetaDBPath(exportDir string) string {
@@ -96,6 +105,8 @@ func initMetaDB(path string) error {
 			num_updates INTEGER, 
 			num_deletes INTEGER, 
 			PRIMARY KEY(schema_name, table_name) );`, EXPORTED_EVENTS_STATS_PER_TABLE_TABLE_NAME),
+		getCreateMigrationStatusTableQuery(),
+		getCreateQueueSegmentResumeOffsetTableQuery(),
 	}
 	for _, cmd := range cmds {
 		_, err = conn.Exec(cmd)
@@ -107,6 +118,24 @@ func initMetaDB(path string) error {
 	return nil
 }
 
+// The table is created when required too, as it is not present in the meta dbs initialized by the earlier versions.
+func getCreateMigrationStatusTableQuery() string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			key TEXT PRIMARY KEY,
+			value TEXT );`, MIGRATION_STATUS_TABLE_NAME)
+}
+
+// The offsets within the segments from which their streaming resumes, by the importer (target or ff db).
+// The table is created when required too, as it is not present in the meta dbs initialized by the earlier versions.
+func getCreateQueueSegmentResumeOffsetTableQuery() string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			segment_no INTEGER,
+			importer TEXT,
+			resume_offset INTEGER,
+			last_vsn INTEGER,
+			PRIMARY KEY(segment_no, importer) );`, QUEUE_SEGMENT_RESUME_OFFSET_TABLE_NAME)
+}
+
 func truncateTablesInMetaDb(exportDir string, tableNames []string) error {
 	conn, err := sql.Open("sqlite3", getMetaDBPath(exportDir))
 	defer func() {
@@ -182,6 +211,48 @@ func (m *MetaDB) GetLastValidOffsetInSegmentFile(segmentNum int64) (int64, error
 	return sizeCommitted, nil
 }
 
+/*
+SetEventQueueSegmentResumeOffset records the offset within the segment up to which all the events are applied,
+along with the vsn of the last of them, so that the streaming of the segment is resumed from there instead of
+its start. The offset is only used as long as the last applied vsn of the event channels is not behind that vsn.
+*/
+func (m *MetaDB) SetEventQueueSegmentResumeOffset(segmentNum int64, offset int64, lastVsn int64) error {
+	query := getCreateQueueSegmentResumeOffsetTableQuery()
+	_, err := m.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("error while running query on meta db -%s :%w", query, err)
+	}
+	query = fmt.Sprintf(`INSERT OR REPLACE INTO %s (segment_no, importer, resume_offset, last_vsn) VALUES (?, ?, ?, ?);`,
+		QUEUE_SEGMENT_RESUME_OFFSET_TABLE_NAME)
+	_, err = m.db.Exec(query, segmentNum, importDestinationType, offset, lastVsn)
+	if err != nil {
+		return fmt.Errorf("error while running query on meta db -%s :%w", query, err)
+	}
+	log.Debugf("recorded resume offset %d (vsn %d) of segment %d", offset, lastVsn, segmentNum)
+	return nil
+}
+
+// GetEventQueueSegmentResumeOffset returns the offset recorded by SetEventQueueSegmentResumeOffset with its vsn,
+// 0 and -1 if none is recorded.
+func (m *MetaDB) GetEventQueueSegmentResumeOffset(segmentNum int64) (int64, int64, error) {
+	query := getCreateQueueSegmentResumeOffsetTableQuery()
+	_, err := m.db.Exec(query)
+	if err != nil {
+		return 0, -1, fmt.Errorf("error while running query on meta db -%s :%w", query, err)
+	}
+	query = fmt.Sprintf(`SELECT resume_offset, last_vsn FROM %s WHERE segment_no = ? AND importer = ?;`,
+		QUEUE_SEGMENT_RESUME_OFFSET_TABLE_NAME)
+	var offset, lastVsn int64
+	err = m.db.QueryRow(query, segmentNum, importDestinationType).Scan(&offset, &lastVsn)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, -1, nil
+	}
+	if err != nil {
+		return 0, -1, fmt.Errorf("error while running query on meta db -%s :%w", query, err)
+	}
+	return offset, lastVsn, nil
+}
+
 func (m *MetaDB) GetTotalExportedEvents(runId string) (int64, int64, error) {
 	var totalCount int64
 	var totalCountRun int64
@@ -206,6 +277,32 @@ func (m *MetaDB) GetTotalExportedEvents(runId string) (int64, int64, error) {
 	return totalCount, totalCountRun, nil
 }
 
+// GetExportedEventsByTable returns the number of the exported events of each table, keyed by the name of its target table
+// (see tgtdb.GetEventTargetTableName), in the target schema of tconf, or the one its source schema is mapped to.
+func (m *MetaDB) GetExportedEventsByTable(tconf *tgtdb.TargetConf) (map[string]int64, error) {
+	query := fmt.Sprintf(`SELECT schema_name, table_name, num_total FROM %s`, EXPORTED_EVENTS_STATS_PER_TABLE_TABLE_NAME)
+	rows, err := m.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error while running query on meta db -%s :%w", query, err)
+	}
+	defer rows.Close()
+	result := make(map[string]int64)
+	for rows.Next() {
+		var schemaName, tableName string
+		var numTotal int64
+		err = rows.Scan(&schemaName, &tableName, &numTotal)
+		if err != nil {
+			return nil, fmt.Errorf("error while scanning rows of query on meta db -%s :%w", query, err)
+		}
+		targetSchema := tconf.Schema
+		if mappedSchema, ok := tconf.MapSourceSchema(schemaName); ok {
+			targetSchema = mappedSchema
+		}
+		result[tgtdb.GetEventTargetTableName(schemaName, tableName, targetSchema)] += numTotal
+	}
+	return result, rows.Err()
+}
+
 func (m *MetaDB) GetExportedEventsRateInLastNMinutes(runId string, n int) (int64, error) {
 	var totalCount int64
 	now := time.Now()
@@ -220,3 +317,37 @@ func (m *MetaDB) GetExportedEventsRateInLastNMinutes(runId string, n int) (int64
 	}
 	return totalCount / int64(n*60), nil
 }
+
+// SetMigrationStatus records the value of a key describing the status of the migration, e.g. FF_SWITCHOVER_DONE_KEY.
+func (m *MetaDB) SetMigrationStatus(key string, value string) error {
+	query := getCreateMigrationStatusTableQuery()
+	_, err := m.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("error while running query on meta db -%s :%w", query, err)
+	}
+	query = fmt.Sprintf(`INSERT OR REPLACE INTO %s (key, value) VALUES (?, ?);`, MIGRATION_STATUS_TABLE_NAME)
+	_, err = m.db.Exec(query, key, value)
+	if err != nil {
+		return fmt.Errorf("error while running query on meta db -%s :%w", query, err)
+	}
+	log.Infof("Executed query on meta db - %s with key %q, value %q", query, key, value)
+	return nil
+}
+
+// GetMigrationStatus returns the value of a key describing the status of the migration, and whether it is set.
+func (m *MetaDB) GetMigrationStatus(key string) (string, bool, error) {
+	query := getCreateMigrationStatusTableQuery()
+	_, err := m.db.Exec(query)
+	if err != nil {
+		return "", false, fmt.Errorf("error while running query on meta db -%s :%w", query, err)
+	}
+	query = fmt.Sprintf(`SELECT value FROM %s WHERE key = ?;`, MIGRATION_STATUS_TABLE_NAME)
+	var value string
+	err = m.db.QueryRow(query, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, fmt.Errorf("error while running query on meta db -%s :%w", query, err)
+	}
+	return value, true, nil
+}
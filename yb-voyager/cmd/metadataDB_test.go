@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationStatus(t *testing.T) {
+	assert := assert.New(t)
+	exportDir := t.TempDir()
+	assert.NoError(os.MkdirAll(filepath.Join(exportDir, "metainfo"), 0755))
+	assert.NoError(createAndInitMetaDBIfRequired(exportDir))
+	m, err := NewMetaDB(exportDir)
+	assert.NoError(err)
+
+	_, done, err := m.GetMigrationStatus(FF_SWITCHOVER_DONE_KEY)
+	assert.NoError(err)
+	assert.False(done)
+	assert.NoError(m.SetMigrationStatus(FF_SWITCHOVER_DONE_KEY, "2023-08-01T10:00:00Z"))
+	switchedOverAt, done, err := m.GetMigrationStatus(FF_SWITCHOVER_DONE_KEY)
+	assert.NoError(err)
+	assert.True(done)
+	assert.Equal("2023-08-01T10:00:00Z", switchedOverAt)
+}
+
+func TestEventQueueSegmentResumeOffset(t *testing.T) {
+	assert := assert.New(t)
+	exportDir := t.TempDir()
+	assert.NoError(os.MkdirAll(filepath.Join(exportDir, "metainfo"), 0755))
+	assert.NoError(createAndInitMetaDBIfRequired(exportDir))
+	var err error
+	metaDB, err = NewMetaDB(exportDir)
+	assert.NoError(err)
+	prevImportDestinationType := importDestinationType
+	importDestinationType = TARGET_DB
+	defer func() { metaDB, importDestinationType = nil, prevImportDestinationType }()
+
+	segmentFilePath := filepath.Join(exportDir, "segment.0.ndjson")
+	lines := []string{
+		`{"vsn":1,"op":"c","schema_name":"public","table_name":"t1"}`,
+		`{"vsn":2,"op":"u","schema_name":"public","table_name":"t1"}`,
+		`{"vsn":3,"op":"d","schema_name":"public","table_name":"t1"}`,
+		EOFMarker,
+	}
+	content := strings.Join(lines, "\n") + "\n"
+	assert.NoError(os.WriteFile(segmentFilePath, []byte(content), 0644))
+	_, err = metaDB.db.Exec(fmt.Sprintf(`INSERT INTO %s (segment_no, file_path, size_committed) VALUES (0, ?, ?)`,
+		QUEUE_SEGMENT_META_TABLE_NAME), segmentFilePath, len(content))
+	assert.NoError(err)
+
+	offset, lastVsn, err := metaDB.GetEventQueueSegmentResumeOffset(0)
+	assert.NoError(err)
+	assert.Equal(int64(0), offset)
+	assert.Equal(int64(-1), lastVsn)
+
+	segment := NewEventQueueSegment(segmentFilePath, 0)
+	assert.NoError(segment.Open(0))
+	for i := 0; i < 2; i++ {
+		_, err = segment.NextEvent()
+		assert.NoError(err)
+	}
+	assert.Equal(int64(len(lines[0])+len(lines[1])+2), segment.Offset())
+	assert.NoError(metaDB.SetEventQueueSegmentResumeOffset(0, segment.Offset(), 2))
+	segment.Close()
+
+	offset, lastVsn, err = metaDB.GetEventQueueSegmentResumeOffset(0)
+	assert.NoError(err)
+	assert.Equal(int64(2), lastVsn)
+	segment = NewEventQueueSegment(segmentFilePath, 0)
+	assert.NoError(segment.Open(offset))
+	defer segment.Close()
+	event, err := segment.NextEvent()
+	assert.NoError(err)
+	assert.Equal(int64(3), event.Vsn)
+	event, err = segment.NextEvent()
+	assert.NoError(err)
+	assert.Nil(event)
+	assert.True(segment.IsProcessed())
+}
@@ -0,0 +1,150 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/goccy/go-json"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/reporter/stats"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+var showOverallProgress bool
+var overallProgressTracker *OverallProgressTracker // nil unless --show-overall-progress is set
+
+/*
+The overall progress of the import combines its two phases:
+  - snapshot: progress amount (bytes or rows, as per --report-progress-in-bytes) imported, out of the total of all the data files.
+  - streaming: events imported (or skipped), out of the events imported so far plus the events remaining to be imported.
+
+The phases are weighted as per the import type: a snapshot-only import is all snapshot and a changes-only import is all
+streaming. For snapshot-and-changes, the snapshot phase weighs OVERALL_PROGRESS_SNAPSHOT_WEIGHT percent (50 by default)
+and the streaming phase the rest.
+As the source keeps producing changes until cutover, the streaming phase measures how far the import has caught up;
+it can go down when the changes are produced faster than they are imported.
+*/
+var OVERALL_PROGRESS_SNAPSHOT_WEIGHT = utils.GetEnvAsInt("OVERALL_PROGRESS_SNAPSHOT_WEIGHT", 50)
+
+type OverallProgress struct {
+	Percentage          float64 `json:"percentage"`
+	Phase               string  `json:"phase"`
+	SnapshotPercentage  float64 `json:"snapshot_percentage"`
+	SnapshotWeight      int     `json:"snapshot_weight"`
+	StreamingPercentage float64 `json:"streaming_percentage"`
+	StreamingWeight     int     `json:"streaming_weight"`
+}
+
+type OverallProgressTracker struct {
+	snapshotWeight      int
+	snapshotTotalAmount int64
+	snapshotDoneAmount  int64 // atomic
+	streamingStarted    atomic.Bool
+	streamStatsReporter *stats.StreamImportStatsReporter
+	progressFilePath    string
+}
+
+func NewOverallProgressTracker(importFileTasks []*ImportFileTask) *OverallProgressTracker {
+	snapshotWeight := 0
+	switch importType {
+	case SNAPSHOT_ONLY:
+		snapshotWeight = 100
+	case SNAPSHOT_AND_CHANGES:
+		snapshotWeight = OVERALL_PROGRESS_SNAPSHOT_WEIGHT
+	}
+	pt := &OverallProgressTracker{
+		snapshotWeight:   snapshotWeight,
+		progressFilePath: filepath.Join(exportDir, "metainfo", fmt.Sprintf("overall_progress_%s.json", importDestinationType)),
+	}
+	for _, task := range importFileTasks {
+		pt.snapshotTotalAmount += getTotalProgressAmount(task)
+	}
+	return pt
+}
+
+func (pt *OverallProgressTracker) AddSnapshotProgressAmount(progressAmount int64) {
+	atomic.AddInt64(&pt.snapshotDoneAmount, progressAmount)
+}
+
+// StreamingStarted switches the tracker to the streaming phase, whose progress is read from the stats reporter.
+func (pt *OverallProgressTracker) StreamingStarted(statsReporter *stats.StreamImportStatsReporter) {
+	pt.streamStatsReporter = statsReporter
+	pt.streamingStarted.Store(true)
+}
+
+func (pt *OverallProgressTracker) GetOverallProgress() OverallProgress {
+	progress := OverallProgress{
+		Phase:           "snapshot",
+		SnapshotWeight:  pt.snapshotWeight,
+		StreamingWeight: 100 - pt.snapshotWeight,
+	}
+	if pt.snapshotTotalAmount > 0 {
+		progress.SnapshotPercentage = math.Min(100, float64(atomic.LoadInt64(&pt.snapshotDoneAmount))*100/float64(pt.snapshotTotalAmount))
+	} else {
+		progress.SnapshotPercentage = 100
+	}
+	if pt.streamingStarted.Load() {
+		// the snapshot is complete by the time streaming starts
+		progress.Phase = "streaming"
+		progress.SnapshotPercentage = 100
+		done, remaining, known := pt.streamStatsReporter.GetStreamingProgress()
+		if remaining < 0 {
+			remaining = 0
+		}
+		if known && done+remaining > 0 {
+			progress.StreamingPercentage = float64(done) * 100 / float64(done+remaining)
+		}
+	}
+	progress.Percentage = (progress.SnapshotPercentage*float64(progress.SnapshotWeight) +
+		progress.StreamingPercentage*float64(progress.StreamingWeight)) / 100
+	return progress
+}
+
+func (pt *OverallProgressTracker) String() string {
+	progress := pt.GetOverallProgress()
+	return fmt.Sprintf("%.2f%% (%s)", progress.Percentage, progress.Phase)
+}
+
+// Report writes the overall progress to a JSON file in the metainfo dir, for consumption by external tools.
+func (pt *OverallProgressTracker) Report() {
+	bytes, err := json.Marshal(pt.GetOverallProgress())
+	if err != nil {
+		log.Warnf("marshal overall progress: %v", err)
+		return
+	}
+	err = os.WriteFile(pt.progressFilePath, bytes, 0644)
+	if err != nil {
+		log.Warnf("write overall progress to %q: %v", pt.progressFilePath, err)
+	}
+}
+
+// StartReporting reports the overall progress periodically until the process exits.
+func (pt *OverallProgressTracker) StartReporting() {
+	go func() {
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			pt.Report()
+		}
+	}()
+}
@@ -45,7 +45,8 @@ Refer to docs (https://docs.yugabyte.com/preview/migrate/) for more details like
 
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		if exportDir != "" && utils.FileOrFolderExists(exportDir) {
-			if cmd.Use != "version" && cmd.Use != "status" {
+			// validate-dir only reads the export-dir, hence it can run along with the other commands
+			if cmd.Use != "version" && cmd.Use != "status" && cmd.Use != "validate-dir" {
 				lockExportDir(cmd)
 			}
 			cmdName := cmd.Use
@@ -64,7 +65,7 @@ Refer to docs (https://docs.yugabyte.com/preview/migrate/) for more details like
 	},
 
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
-		if exportDir != "" && utils.FileOrFolderExists(exportDir) && cmd.Use != "version" && cmd.Use != "status" {
+		if exportDir != "" && utils.FileOrFolderExists(exportDir) && cmd.Use != "version" && cmd.Use != "status" && cmd.Use != "validate-dir" {
 			unlockExportDir()
 		}
 	},
@@ -145,9 +146,13 @@ func validateExportDirFlag() {
 func lockExportDir(cmd *cobra.Command) {
 	lockFileName := ".lockfile.lck"
 	// using different lockfile as import data can be run in parallel with export data(for live migration)
-	if cmd.Use == "data" && cmd.Parent().Use == "import" {
+	if (cmd.Use == "data" && cmd.Parent().Use == "import") || (cmd.Use == "retry-batch" && cmd.Parent().Use == "data") {
 		lockFileName = ".importDataLockfile.lck"
 	}
+	// the fall forward database is loaded in parallel with the import into the target database
+	if (cmd.Use == "setup" || cmd.Use == "synchronize") && cmd.Parent().Use == "fall-forward" {
+		lockFileName = ".ffImportDataLockfile.lck"
+	}
 
 	lockFilePath, err := filepath.Abs(filepath.Join(exportDir, lockFileName))
 	if err != nil {
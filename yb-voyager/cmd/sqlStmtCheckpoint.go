@@ -0,0 +1,96 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+)
+
+/*
+SqlStmtCheckpoint records the statements of a SQL file which are executed successfully, so that re-running the
+file after a partial failure skips them. The statements are recorded as their hashes, one per line, in the
+checkpoint file.
+*/
+type SqlStmtCheckpoint struct {
+	sync.Mutex
+	filePath string
+	executed map[string]bool
+}
+
+func NewSqlStmtCheckpoint(filePath string) (*SqlStmtCheckpoint, error) {
+	c := &SqlStmtCheckpoint{
+		filePath: filePath,
+		executed: make(map[string]bool),
+	}
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return c, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("open %q: %w", filePath, err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		c.executed[scanner.Text()] = true
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %q: %w", filePath, err)
+	}
+	return c, nil
+}
+
+func (c *SqlStmtCheckpoint) IsExecuted(stmt string) bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.executed[hashSqlStmt(stmt)]
+}
+
+func (c *SqlStmtCheckpoint) MarkExecuted(stmt string) error {
+	c.Lock()
+	defer c.Unlock()
+	hash := hashSqlStmt(stmt)
+	if c.executed[hash] {
+		return nil
+	}
+	err := appendLineToFile(c.filePath, hash)
+	if err != nil {
+		return fmt.Errorf("record executed statement in %q: %w", c.filePath, err)
+	}
+	c.executed[hash] = true
+	return nil
+}
+
+// Reset forgets all the executed statements.
+func (c *SqlStmtCheckpoint) Reset() error {
+	c.Lock()
+	defer c.Unlock()
+	err := os.Remove(c.filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove %q: %w", c.filePath, err)
+	}
+	c.executed = make(map[string]bool)
+	return nil
+}
+
+func hashSqlStmt(stmt string) string {
+	hash := sha1.Sum([]byte(stmt))
+	return hex.EncodeToString(hash[:])
+}
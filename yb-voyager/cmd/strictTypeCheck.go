@@ -0,0 +1,104 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/datafile"
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+)
+
+var strictTypeCheck bool
+
+// RowTypeChecker validates the values of the rows of a table against the types of the target columns
+// before the COPY, so that the values the target db would silently coerce are rejected instead.
+type RowTypeChecker struct {
+	tableName string
+	columns   []tgtdb.ColumnType
+	checkFns  []tgtdb.ValueCheckFn
+}
+
+// NewRowTypeChecker returns nil if none of the columns need a check; a nil checker accepts all the rows.
+func NewRowTypeChecker(tableName string, columns []string) (*RowTypeChecker, error) {
+	columnTypes, err := tdb.GetColumnTypes(getTargetTableName(tableName), columns)
+	if err != nil {
+		return nil, fmt.Errorf("get column types of table %s: %w", tableName, err)
+	}
+	checker := &RowTypeChecker{tableName: tableName, columns: columnTypes}
+	needsCheck := false
+	for _, col := range columnTypes {
+		checkFn := tgtdb.NewValueCheckFn(col.Type)
+		checker.checkFns = append(checker.checkFns, checkFn)
+		needsCheck = needsCheck || checkFn != nil
+	}
+	if !needsCheck {
+		log.Infof("no column of table %s needs a strict type check", tableName)
+		return nil, nil
+	}
+	log.Infof("strict type check of table %s: columns %v", tableName, columnTypes)
+	return checker, nil
+}
+
+// Check returns an error wrapping tgtdb.ErrLossyValue for a row with a value that would be coerced.
+func (c *RowTypeChecker) Check(row string) error {
+	if c == nil {
+		return nil
+	}
+	values, err := splitRowValues(row)
+	if err != nil {
+		return nil // malformed rows are reported by the target db
+	}
+	for i, value := range values {
+		if i >= len(c.checkFns) || c.checkFns[i] == nil || isNullValue(value) {
+			continue
+		}
+		err = c.checkFns[i](value)
+		if err != nil {
+			return fmt.Errorf("column %s of type %s: %w", c.columns[i].Name, c.columns[i].Type, err)
+		}
+	}
+	return nil
+}
+
+// splitRowValues splits the row of the data file (after the value conversion) into its values.
+func splitRowValues(row string) ([]string, error) {
+	delimiter := dataFileDescriptor.Delimiter
+	if delimiter == "" {
+		delimiter = "\t"
+	}
+	if dataFileDescriptor.FileFormat != datafile.CSV {
+		return datafile.SplitTextRowFields(row, delimiter[0]), nil
+	}
+	r := csv.NewReader(strings.NewReader(row))
+	r.Comma = rune(delimiter[0])
+	r.LazyQuotes = true
+	return r.Read()
+}
+
+func isNullValue(value string) bool {
+	if dataFileDescriptor.NullString != "" {
+		return value == dataFileDescriptor.NullString
+	}
+	if dataFileDescriptor.FileFormat == datafile.CSV {
+		return value == ""
+	}
+	return value == `\N`
+}
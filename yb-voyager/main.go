@@ -34,8 +34,12 @@ func registerSignalHandlers() {
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
-		sig := <-sigs
-		utils.PrintAndLog("Received signal %s. Exiting...", sig)
-		atexit.Exit(0)
+		for sig := range sigs {
+			if cmd.RouteSignal(sig) {
+				continue
+			}
+			utils.PrintAndLog("Received signal %s. Exiting...", sig)
+			atexit.Exit(0)
+		}
 	}()
 }
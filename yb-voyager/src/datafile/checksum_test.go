@@ -0,0 +1,42 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package datafile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeChecksum(t *testing.T) {
+	assert := assert.New(t)
+	filePath := filepath.Join(t.TempDir(), "t1_data.sql")
+	assert.NoError(os.WriteFile(filePath, []byte("1\tone\n2\ttwo\n"), 0644))
+	checksum, err := ComputeChecksum(filePath)
+	assert.NoError(err)
+	assert.Len(checksum, 64)
+
+	// truncated file
+	assert.NoError(os.WriteFile(filePath, []byte("1\tone\n"), 0644))
+	truncatedChecksum, err := ComputeChecksum(filePath)
+	assert.NoError(err)
+	assert.NotEqual(checksum, truncatedChecksum)
+
+	_, err = ComputeChecksum(filepath.Join(t.TempDir(), "missing.sql"))
+	assert.Error(err)
+}
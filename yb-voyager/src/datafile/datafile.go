@@ -22,9 +22,10 @@ import (
 )
 
 const (
-	CSV  = "csv"
-	SQL  = "sql"
-	TEXT = "text"
+	CSV     = "csv"
+	SQL     = "sql"
+	TEXT    = "text"
+	PARQUET = "parquet"
 )
 
 type DataFile interface {
@@ -47,6 +48,8 @@ func NewDataFile(fileName string, reader io.ReadCloser, descriptor *Descriptor)
 		return newTextDataFile(fileName, reader, descriptor)
 	case SQL:
 		return newSqlDataFile(fileName, reader, descriptor)
+	case PARQUET:
+		return newParquetDataFile(fileName, reader, descriptor)
 	default:
 		panic(fmt.Sprintf("Unknown file type %q", descriptor.FileFormat))
 
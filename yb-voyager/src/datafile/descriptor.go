@@ -45,6 +45,8 @@ type FileEntry struct {
 	// In that case, this field is set to -1.
 	RowCount int64 `json:"RowCount"`
 	FileSize int64 `json:"FileSize"`
+	// Checksum of the file computed with the ChecksumAlgorithm of the descriptor, empty if not computed.
+	Checksum string `json:"Checksum,omitempty"`
 }
 
 type Descriptor struct {
@@ -57,6 +59,7 @@ type Descriptor struct {
 	NullString                 string              `json:"NullString,omitempty"`
 	DataFileList               []*FileEntry        `json:"FileList"`
 	TableNameToExportedColumns map[string][]string `json:"TableNameToExportedColumns"`
+	ChecksumAlgorithm          string              `json:"ChecksumAlgorithm,omitempty"`
 }
 
 func OpenDescriptor(exportDir string) *Descriptor {
@@ -81,7 +84,8 @@ func OpenDescriptor(exportDir string) *Descriptor {
 		isAbs := path.IsAbs(fileEntry.FilePath) ||
 			strings.HasPrefix(fileEntry.FilePath, "s3://") || // AWS.
 			strings.HasPrefix(fileEntry.FilePath, "gs://") || // GCP.
-			strings.HasPrefix(fileEntry.FilePath, "https://") // Azure.
+			strings.HasPrefix(fileEntry.FilePath, "https://") || // Azure.
+			strings.HasPrefix(fileEntry.FilePath, "azblob://") // Azure, with the account in AZURE_STORAGE_ACCOUNT.
 		if !isAbs {
 			fileEntry.FilePath = path.Join(exportDir, "data", fileEntry.FilePath)
 		}
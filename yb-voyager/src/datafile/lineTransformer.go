@@ -0,0 +1,134 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package datafile
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+/*
+Line transformers preprocess the raw data lines of a table, before the values in them are converted for the target db.
+The transformers of a chain are applied in the order they are given, each one seeing the line returned by the previous one.
+They are applied only to the data lines: the header of a CSV file is written to the batches as is.
+
+A transformer is either a regex replacement, written as s/<regex>/<replacement>/ (any character following the `s`
+can be the delimiter, e.g. s|\t\t|\t|), or the name of a Go function registered with RegisterLineTransformer.
+*/
+
+// ErrRejectLine is returned (possibly wrapped) by a transformer to reject the line, which is then recorded
+// along with the other rejected rows of the table instead of being imported.
+var ErrRejectLine = errors.New("line rejected by transformer")
+
+// Transformers are called from multiple goroutines with --conversion-workers.
+type LineTransformer interface {
+	Name() string
+	Transform(tableName string, line string) (string, error)
+}
+
+type LineTransformerFunc func(tableName string, line string) (string, error)
+
+var (
+	registeredLineTransformersMutex sync.Mutex
+	registeredLineTransformers      = make(map[string]LineTransformerFunc)
+)
+
+// RegisterLineTransformer makes a Go function available as a transformer by its name. Meant to be called
+// by the programs embedding voyager, before the command line is parsed.
+func RegisterLineTransformer(name string, fn LineTransformerFunc) {
+	registeredLineTransformersMutex.Lock()
+	defer registeredLineTransformersMutex.Unlock()
+	registeredLineTransformers[name] = fn
+}
+
+type namedLineTransformer struct {
+	name string
+	fn   LineTransformerFunc
+}
+
+func (t *namedLineTransformer) Name() string {
+	return t.name
+}
+
+func (t *namedLineTransformer) Transform(tableName string, line string) (string, error) {
+	return t.fn(tableName, line)
+}
+
+type RegexReplaceTransformer struct {
+	spec        string
+	re          *regexp.Regexp
+	replacement string
+}
+
+func (t *RegexReplaceTransformer) Name() string {
+	return t.spec
+}
+
+func (t *RegexReplaceTransformer) Transform(tableName string, line string) (string, error) {
+	return t.re.ReplaceAllString(line, t.replacement), nil
+}
+
+// newRegexReplaceTransformer parses a transformer written as s<d><regex><d><replacement><d>.
+func newRegexReplaceTransformer(spec string) (*RegexReplaceTransformer, error) {
+	if len(spec) < 4 || spec[0] != 's' {
+		return nil, fmt.Errorf("not of the form s/<regex>/<replacement>/")
+	}
+	delimiter := spec[1:2]
+	parts := strings.Split(spec[2:], delimiter)
+	if len(parts) != 3 || parts[2] != "" {
+		return nil, fmt.Errorf("not of the form s%s<regex>%s<replacement>%s", delimiter, delimiter, delimiter)
+	}
+	re, err := regexp.Compile(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("compile regex %q: %w", parts[0], err)
+	}
+	return &RegexReplaceTransformer{spec: spec, re: re, replacement: parts[1]}, nil
+}
+
+type LineTransformerChain []LineTransformer
+
+// NewLineTransformerChain creates the chain of transformers given by the specs, in that order.
+func NewLineTransformerChain(specs []string) (LineTransformerChain, error) {
+	registeredLineTransformersMutex.Lock()
+	defer registeredLineTransformersMutex.Unlock()
+	var chain LineTransformerChain
+	for _, spec := range specs {
+		if fn, ok := registeredLineTransformers[spec]; ok {
+			chain = append(chain, &namedLineTransformer{name: spec, fn: fn})
+			continue
+		}
+		t, err := newRegexReplaceTransformer(spec)
+		if err != nil {
+			return nil, fmt.Errorf("line transformer %q is neither registered nor valid: %w", spec, err)
+		}
+		chain = append(chain, t)
+	}
+	return chain, nil
+}
+
+func (chain LineTransformerChain) Transform(tableName string, line string) (string, error) {
+	var err error
+	for _, t := range chain {
+		line, err = t.Transform(tableName, line)
+		if err != nil {
+			return "", fmt.Errorf("line transformer %q: %w", t.Name(), err)
+		}
+	}
+	return line, nil
+}
@@ -0,0 +1,311 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package datafile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils/parquet"
+)
+
+/*
+ParquetDataFile returns the rows of a Parquet file as lines of the COPY text format, with the values
+separated by the delimiter and the nulls written as the null string of the descriptor. Hence the rows
+are imported like those of a TEXT data file.
+
+The bytes read for a row are its share of the compressed size of its row group, so that the bytes of
+all the rows add up to (roughly) the size of the file.
+*/
+type ParquetDataFile struct {
+	closer     io.Closer
+	reader     *parquet.Reader
+	columns    []*parquet.Column
+	delimiter  string
+	nullString string
+
+	rowGroupNum int // of the next row group to read
+	rowGroup    *parquet.RowGroup
+	rowNum      int64 // of the next row in rowGroup
+	bytesRead   int64
+	DataFile
+}
+
+func newParquetDataFile(filePath string, readCloser io.ReadCloser, descriptor *Descriptor) (*ParquetDataFile, error) {
+	var readerAt io.ReaderAt
+	var size int64
+	if file, ok := readCloser.(interface {
+		io.ReaderAt
+		io.Seeker
+	}); ok {
+		var err error
+		size, err = file.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, fmt.Errorf("seek to the end of %q: %w", filePath, err)
+		}
+		readerAt = file
+	} else {
+		// The footer of a parquet file is at its end, hence the files which can't be read at random
+		// offsets, e.g. of the object stores, are read into memory.
+		log.Infof("reading parquet file %q into memory", filePath)
+		buf, err := io.ReadAll(readCloser)
+		if err != nil {
+			return nil, fmt.Errorf("read %q: %w", filePath, err)
+		}
+		readerAt, size = bytes.NewReader(buf), int64(len(buf))
+	}
+	reader, err := parquet.NewReader(readerAt, size)
+	if err != nil {
+		return nil, fmt.Errorf("open parquet file %q: %w", filePath, err)
+	}
+	df := &ParquetDataFile{
+		closer:     readCloser,
+		reader:     reader,
+		columns:    reader.Columns(),
+		delimiter:  descriptor.Delimiter,
+		nullString: descriptor.NullString,
+	}
+	if df.delimiter == "" {
+		df.delimiter = "\t"
+	}
+	if df.nullString == "" {
+		df.nullString = `\N`
+	}
+	log.Infof("created parquet data file struct for file: %s (%d rows, %d row groups)", filePath, reader.NumRows(), reader.NumRowGroups())
+	return df, nil
+}
+
+func (df *ParquetDataFile) SkipLines(numLines int64) error {
+	// the row groups skipped entirely are not decoded
+	for df.rowGroup == nil && df.rowGroupNum < df.reader.NumRowGroups() &&
+		numLines >= df.reader.RowGroupNumRows(df.rowGroupNum) {
+		numLines -= df.reader.RowGroupNumRows(df.rowGroupNum)
+		df.rowGroupNum++
+	}
+	for i := int64(1); i <= numLines; i++ {
+		_, err := df.NextLine()
+		if err != nil {
+			return err
+		}
+	}
+	df.ResetBytesRead()
+	return nil
+}
+
+func (df *ParquetDataFile) NextLine() (string, error) {
+	for df.rowGroup == nil || df.rowNum >= df.rowGroup.NumRows {
+		if df.rowGroupNum >= df.reader.NumRowGroups() {
+			return "", io.EOF
+		}
+		rowGroup, err := df.reader.ReadRowGroup(df.rowGroupNum)
+		if err != nil {
+			return "", err
+		}
+		df.rowGroup, df.rowGroupNum, df.rowNum = rowGroup, df.rowGroupNum+1, 0
+	}
+	values := make([]string, len(df.columns))
+	for i, column := range df.columns {
+		value := df.rowGroup.Columns[i][df.rowNum]
+		if value == nil {
+			values[i] = df.nullString
+		} else {
+			values[i] = escapeCopyTextValue(formatParquetValue(column, value), df.delimiter)
+		}
+	}
+	n, size := df.rowGroup.NumRows, df.rowGroup.CompressedSize
+	df.bytesRead += size*(df.rowNum+1)/n - size*df.rowNum/n
+	df.rowNum++
+	return strings.Join(values, df.delimiter), nil
+}
+
+func (df *ParquetDataFile) Close() {
+	df.closer.Close()
+}
+
+func (df *ParquetDataFile) GetBytesRead() int64 {
+	return df.bytesRead
+}
+
+func (df *ParquetDataFile) ResetBytesRead() {
+	df.bytesRead = 0
+}
+
+// GetHeader returns the column names. Parquet files don't have a header line, the rows are returned from the first.
+func (df *ParquetDataFile) GetHeader() string {
+	names := make([]string, len(df.columns))
+	for i, column := range df.columns {
+		names[i] = column.Name
+	}
+	return strings.Join(names, df.delimiter)
+}
+
+const julianDayOfUnixEpoch = 2440588
+
+// formatParquetValue returns the non-null value as accepted by the COPY for the column of the corresponding type.
+func formatParquetValue(column *parquet.Column, value interface{}) string {
+	logical := column.Logical
+	switch v := value.(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case int32:
+		switch logical.Kind {
+		case parquet.LogicalDate:
+			return time.Unix(int64(v)*24*60*60, 0).UTC().Format("2006-01-02")
+		case parquet.LogicalTime:
+			return formatTimeOfDay(int64(v), logical.Unit)
+		case parquet.LogicalDecimal:
+			return formatDecimal(big.NewInt(int64(v)), logical.Scale)
+		case parquet.LogicalInteger:
+			if !logical.IsSigned {
+				return strconv.FormatUint(uint64(uint32(v)), 10)
+			}
+		}
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		switch logical.Kind {
+		case parquet.LogicalTime:
+			return formatTimeOfDay(v, logical.Unit)
+		case parquet.LogicalTimestamp:
+			return formatTimestamp(toTime(v, logical.Unit), logical.IsAdjustedToUTC)
+		case parquet.LogicalDecimal:
+			return formatDecimal(big.NewInt(v), logical.Scale)
+		case parquet.LogicalInteger:
+			if !logical.IsSigned {
+				return strconv.FormatUint(uint64(v), 10)
+			}
+		}
+		return strconv.FormatInt(v, 10)
+	case parquet.Int96:
+		nanosOfDay := int64(binary.LittleEndian.Uint64(v[:8]))
+		julianDay := int64(binary.LittleEndian.Uint32(v[8:]))
+		t := time.Unix((julianDay-julianDayOfUnixEpoch)*24*60*60, nanosOfDay).UTC()
+		return formatTimestamp(t, false)
+	case float32:
+		return formatFloat(float64(v), 32)
+	case float64:
+		return formatFloat(v, 64)
+	case []byte:
+		switch logical.Kind {
+		case parquet.LogicalString, parquet.LogicalEnum, parquet.LogicalJSON:
+			return string(v)
+		case parquet.LogicalDecimal:
+			return formatDecimal(bigIntFromTwosComplement(v), logical.Scale)
+		case parquet.LogicalUUID:
+			if len(v) == 16 {
+				h := hex.EncodeToString(v)
+				return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+			}
+		}
+		return `\x` + hex.EncodeToString(v) // bytea
+	default:
+		panic(fmt.Sprintf("unexpected parquet value %v (%T) of column %q", value, value, column.Name))
+	}
+}
+
+func toTime(v int64, unit parquet.TimeUnit) time.Time {
+	switch unit {
+	case parquet.MILLIS:
+		return time.UnixMilli(v).UTC()
+	case parquet.MICROS:
+		return time.UnixMicro(v).UTC()
+	default:
+		return time.Unix(0, v).UTC()
+	}
+}
+
+func formatTimestamp(t time.Time, isAdjustedToUTC bool) string {
+	result := t.Format("2006-01-02 15:04:05.999999999")
+	if isAdjustedToUTC {
+		result += "+00"
+	}
+	return result
+}
+
+func formatTimeOfDay(v int64, unit parquet.TimeUnit) string {
+	return toTime(v, unit).Format("15:04:05.999999999")
+}
+
+func formatFloat(v float64, bitSize int) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "Infinity"
+	case math.IsInf(v, -1):
+		return "-Infinity"
+	}
+	return strconv.FormatFloat(v, 'g', -1, bitSize)
+}
+
+// bigIntFromTwosComplement returns the value of the big-endian two's complement bytes.
+func bigIntFromTwosComplement(b []byte) *big.Int {
+	result := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		result.Sub(result, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return result
+}
+
+func formatDecimal(unscaled *big.Int, scale int32) string {
+	digits := new(big.Int).Abs(unscaled).String()
+	if scale > 0 {
+		if len(digits) <= int(scale) {
+			digits = strings.Repeat("0", int(scale)-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-int(scale)] + "." + digits[len(digits)-int(scale):]
+	}
+	if unscaled.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// escapeCopyTextValue escapes the characters with a special meaning in the COPY text format.
+func escapeCopyTextValue(value string, delimiter string) string {
+	if !strings.ContainsAny(value, "\\\n\r\t"+delimiter) {
+		return value
+	}
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '\\':
+			sb.WriteString(`\\`)
+		case c == '\n':
+			sb.WriteString(`\n`)
+		case c == '\r':
+			sb.WriteString(`\r`)
+		case c == '\t':
+			sb.WriteString(`\t`)
+		case len(delimiter) == 1 && c == delimiter[0]:
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
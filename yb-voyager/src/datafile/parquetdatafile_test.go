@@ -0,0 +1,80 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package datafile
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParquetDataFile(t *testing.T) {
+	assert := assert.New(t)
+	// testdata/types.parquet has two row groups (of 2 rows and 1 row) of the columns:
+	//   id INT64, name BYTE_ARRAY (STRING), price INT32 (DECIMAL(9,2)), amount FIXED_LEN_BYTE_ARRAY(4) (DECIMAL(10,3)),
+	//   created_at INT64 (TIMESTAMP(MICROS, UTC)), updated_at INT64 (TIMESTAMP(MILLIS)), legacy_ts INT96, day INT32 (DATE),
+	//   score DOUBLE, payload BYTE_ARRAY
+	// all of them optional except id, SNAPPY compressed and dictionary encoded.
+	expectedLines := []string{
+		"1\talice\\tx\t123.45\t-1234.567\t2023-07-22 04:26:40.123456+00\t1970-01-01 00:00:00\t1970-01-02 01:00:00\t2022-01-08\t1.5\t\\\\x00ff",
+		"2\t\\N\t\\N\t\\N\t\\N\t\\N\t\\N\t\\N\t\\N\t\\N",
+		"3\tback\\\\slash\t-0.05\t0.007\t1969-12-31 23:59:59.999999+00\t1970-01-01 00:00:01.5\t\\N\t1969-12-31\tNaN\t\\\\x",
+	}
+
+	filePath := filepath.Join("testdata", "types.parquet")
+	fileInfo, err := os.Stat(filePath)
+	assert.NoError(err)
+
+	openDataFile := func() DataFile {
+		reader, err := os.Open(filePath)
+		assert.NoError(err)
+		dataFile, err := NewDataFile(filePath, reader, &Descriptor{FileFormat: PARQUET, Delimiter: "\t"})
+		assert.NoError(err)
+		return dataFile
+	}
+
+	dataFile := openDataFile()
+	assert.Equal("id\tname\tprice\tamount\tcreated_at\tupdated_at\tlegacy_ts\tday\tscore\tpayload", dataFile.GetHeader())
+	for _, expectedLine := range expectedLines {
+		line, err := dataFile.NextLine()
+		assert.NoError(err)
+		assert.Equal(expectedLine, line)
+	}
+	line, err := dataFile.NextLine()
+	assert.Equal(io.EOF, err)
+	assert.Equal("", line)
+	// the bytes of the rows add up to the size of the row groups, a bit less than the size of the file
+	assert.Greater(dataFile.GetBytesRead(), int64(0))
+	assert.Less(dataFile.GetBytesRead(), fileInfo.Size())
+	dataFile.Close()
+
+	for numLines := 0; numLines <= len(expectedLines); numLines++ {
+		dataFile = openDataFile()
+		assert.NoError(dataFile.SkipLines(int64(numLines)))
+		assert.Equal(int64(0), dataFile.GetBytesRead())
+		for _, expectedLine := range expectedLines[numLines:] {
+			line, err := dataFile.NextLine()
+			assert.NoError(err)
+			assert.Equal(expectedLine, line)
+		}
+		_, err = dataFile.NextLine()
+		assert.Equal(io.EOF, err)
+		dataFile.Close()
+	}
+}
@@ -94,6 +94,23 @@ func (df *TextDataFile) GetHeader() string {
 	return df.Header
 }
 
+// SplitTextRowFields splits a row of a TEXT data file at the delimiters not escaped with a backslash, retaining
+// the fields as they are, so that they can be joined back with the delimiter.
+func SplitTextRowFields(row string, delimiter byte) []string {
+	var fields []string
+	start := 0
+	for i := 0; i < len(row); i++ {
+		switch row[i] {
+		case '\\':
+			i++ // the escaped character
+		case delimiter:
+			fields = append(fields, row[start:i])
+			start = i + 1
+		}
+	}
+	return append(fields, row[start:])
+}
+
 func newTextDataFile(filePath string, readCloser io.ReadCloser, descriptor *Descriptor) (*TextDataFile, error) {
 	textDataFile := &TextDataFile{
 		closer:    readCloser,
@@ -74,7 +74,7 @@ func (ds *AzDataStore) FileSize(filePath string) (int64, error) {
 
 // Open the file at the given path for reading.
 func (ds *AzDataStore) Open(objectPath string) (io.ReadCloser, error) {
-	if strings.HasPrefix(objectPath, "https://") {
+	if strings.HasPrefix(objectPath, "https://") || strings.HasPrefix(objectPath, az.AZBLOB_URL_PREFIX) {
 		return az.NewObjectReader(objectPath)
 	}
 	// if objectPath is hidden underneath a symlink for az blobs...
@@ -0,0 +1,110 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Decompression of the gzipped data files, on top of any of the datastores.
+package datastore
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const GZIP_FILE_EXTENSION = ".gz"
+
+/*
+CompressedDataStore decompresses the files with the `.gz` suffix on Open(), so that the data files read
+the uncompressed data and count the uncompressed bytes. Their FileSize() is the uncompressed size as well,
+when it can be found without reading the whole file.
+*/
+type CompressedDataStore struct {
+	DataStore
+}
+
+func IsGzipFile(filePath string) bool {
+	return strings.HasSuffix(strings.ToLower(filePath), GZIP_FILE_EXTENSION)
+}
+
+func (ds *CompressedDataStore) Open(filePath string) (io.ReadCloser, error) {
+	reader, err := ds.DataStore.Open(filePath)
+	if err != nil || !IsGzipFile(filePath) {
+		return reader, err
+	}
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		reader.Close()
+		return nil, fmt.Errorf("open gzip reader on %q: %w", filePath, err)
+	}
+	return &gzipReadCloser{Reader: gzipReader, file: reader}, nil
+}
+
+func (ds *CompressedDataStore) FileSize(filePath string) (int64, error) {
+	size, err := ds.DataStore.FileSize(filePath)
+	if err != nil || !IsGzipFile(filePath) {
+		return size, err
+	}
+	if _, ok := ds.DataStore.(*LocalDataStore); !ok || IsObjectStoragePath(filePath) {
+		return size, nil
+	}
+	uncompressedSize, err := getGzipUncompressedSize(filePath)
+	if err != nil {
+		log.Warnf("using the compressed size of %q: %s", filePath, err)
+		return size, nil
+	}
+	// The trailer has the size modulo 2^32, and only of the last member of a multi-member file.
+	// The compressed size is a better estimate than such a wrapped around size.
+	if uncompressedSize < size {
+		return size, nil
+	}
+	return uncompressedSize, nil
+}
+
+// getGzipUncompressedSize returns the ISIZE field of the gzip trailer, i.e. the last 4 bytes of the file.
+func getGzipUncompressedSize(filePath string) (int64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	_, err = file.Seek(-4, io.SeekEnd)
+	if err != nil {
+		return 0, fmt.Errorf("seek to the gzip trailer: %w", err)
+	}
+	var buf [4]byte
+	_, err = io.ReadFull(file, buf[:])
+	if err != nil {
+		return 0, fmt.Errorf("read the gzip trailer: %w", err)
+	}
+	return int64(binary.LittleEndian.Uint32(buf[:])), nil
+}
+
+type gzipReadCloser struct {
+	*gzip.Reader
+	file io.ReadCloser
+}
+
+func (r *gzipReadCloser) Close() error {
+	err := r.Reader.Close()
+	fileErr := r.file.Close()
+	if err != nil {
+		return err
+	}
+	return fileErr
+}
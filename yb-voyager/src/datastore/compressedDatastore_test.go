@@ -0,0 +1,42 @@
+package datastore
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressedDataStore(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	data := strings.Repeat("1\tfoo\n2\tbar\n", 100)
+
+	gzFile, err := os.Create(filepath.Join(dir, "t1.csv.gz"))
+	assert.NoError(err)
+	gzWriter := gzip.NewWriter(gzFile)
+	_, err = gzWriter.Write([]byte(data))
+	assert.NoError(err)
+	assert.NoError(gzWriter.Close())
+	assert.NoError(gzFile.Close())
+	assert.NoError(os.WriteFile(filepath.Join(dir, "t2.csv"), []byte(data), 0644))
+
+	ds := &CompressedDataStore{DataStore: NewLocalDataStore(dir)}
+	for _, fileName := range []string{"t1.csv.gz", "t2.csv"} {
+		filePath := filepath.Join(dir, fileName)
+		size, err := ds.FileSize(filePath)
+		assert.NoError(err)
+		assert.Equal(int64(len(data)), size, fileName)
+
+		reader, err := ds.Open(filePath)
+		assert.NoError(err)
+		contents, err := io.ReadAll(reader)
+		assert.NoError(err)
+		assert.Equal(data, string(contents), fileName)
+		assert.NoError(reader.Close())
+	}
+}
@@ -18,6 +18,8 @@ package datastore
 import (
 	"io"
 	"strings"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils/az"
 )
 
 type DataStore interface {
@@ -27,16 +29,39 @@ type DataStore interface {
 	Open(string) (io.ReadCloser, error)
 }
 
+// NewDataStore returns the datastore of the location. The gzipped files in it are decompressed transparently.
 func NewDataStore(location string) DataStore {
+	ds := newObjectDataStore(location)
+	if ds == nil {
+		ds = NewLocalDataStore(location)
+	}
+	return &CompressedDataStore{DataStore: ds}
+}
+
+var objectStorageURLPrefixes = []string{"s3://", "gs://", "https://", az.AZBLOB_URL_PREFIX}
+
+// IsObjectStoragePath returns true if the path is the URL of an object (or a prefix of the objects) in S3, GCS
+// or Azure blob storage. The objects are streamed from the bucket, with the credentials found by the
+// default credential chain of the respective SDK (env vars, config files, instance metadata).
+func IsObjectStoragePath(path string) bool {
+	for _, prefix := range objectStorageURLPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// newObjectDataStore returns the datastore of the object storage URL, or nil for a local path.
+func newObjectDataStore(location string) DataStore {
 	switch true {
-	  case strings.HasPrefix(location, "s3://"):
+	case strings.HasPrefix(location, "s3://"):
 		return NewS3DataStore(location)
-	  case strings.HasPrefix(location, "gs://"):
+	case strings.HasPrefix(location, "gs://"):
 		return NewGCSDataStore(location)
-	  case strings.HasPrefix(location, "https://"):
+	case strings.HasPrefix(location, "https://"), strings.HasPrefix(location, az.AZBLOB_URL_PREFIX):
 		return NewAzDataStore(location)
-	  default:
-		return NewLocalDataStore(location)
- 	}
-
+	default:
+		return nil
+	}
 }
@@ -0,0 +1,28 @@
+package datastore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjectStoragePaths(t *testing.T) {
+	assert := assert.New(t)
+	testcases := map[string]DataStore{
+		"s3://bucket/data/t1.csv":                                &S3DataStore{},
+		"gs://bucket/data/t1.csv":                                &GCSDataStore{},
+		"https://account.blob.core.windows.net/container/t1.csv": &AzDataStore{},
+		"azblob://container/data/t1.csv":                         &AzDataStore{},
+		"/export-dir/data/t1.csv":                                nil,
+		"data/t1.csv":                                            nil,
+	}
+	for path, expected := range testcases {
+		assert.Equal(expected != nil, IsObjectStoragePath(path), path)
+		assert.IsType(expected, newObjectDataStore(path), path)
+	}
+
+	// the object storage URLs in the data file descriptor are read from their datastore, and not resolved as local paths
+	absPath, err := NewLocalDataStore("/export-dir/data").AbsolutePath("s3://bucket/data/t1.csv")
+	assert.NoError(err)
+	assert.Equal("s3://bucket/data/t1.csv", absPath)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
 )
 
+// The files referenced by an object storage URL (e.g. in the data file descriptor) are read from the datastore of their URL.
 type LocalDataStore struct {
 	dataDir string
 }
@@ -43,10 +44,16 @@ func (ds *LocalDataStore) Glob(pattern string) ([]string, error) {
 }
 
 func (ds *LocalDataStore) AbsolutePath(file string) (string, error) {
+	if IsObjectStoragePath(file) {
+		return file, nil
+	}
 	return filepath.Abs(file)
 }
 
 func (ds *LocalDataStore) FileSize(filePath string) (int64, error) {
+	if objectDataStore := newObjectDataStore(filePath); objectDataStore != nil {
+		return objectDataStore.FileSize(filePath)
+	}
 	fileInfo, err := os.Stat(filePath)
 	if err != nil {
 		return 0, err
@@ -55,5 +62,8 @@ func (ds *LocalDataStore) FileSize(filePath string) (int64, error) {
 }
 
 func (ds *LocalDataStore) Open(filePath string) (io.ReadCloser, error) {
+	if objectDataStore := newObjectDataStore(filePath); objectDataStore != nil {
+		return objectDataStore.Open(filePath)
+	}
 	return os.Open(filePath)
 }
@@ -20,16 +20,39 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	log "github.com/sirupsen/logrus"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
 )
 
 type ColumnSchema struct {
-	Type string `json:"type"`
-	Name string `json:"name"`
+	Type       string            `json:"type"`
+	Name       string            `json:"name"`
+	Parameters map[string]string `json:"parameters"`
 	// Not decoding the rest of the fields for now.
 }
 
+// Name of the parameter in which debezium propagates the type of the column in the source db.
+const SOURCE_COLUMN_TYPE_PARAM = "__debezium.source.column.type"
+
+// getComplexType returns the type name of values which are exported as plain strings by debezium
+// but need special handling on import: ranges, multiranges and arrays of composite types.
+// `compositeTypes` has the upper cased names of the composite types in the exported schema.
+func (cs *ColumnSchema) getComplexType(compositeTypes map[string]bool) string {
+	sourceType := strings.ToUpper(cs.Parameters[SOURCE_COLUMN_TYPE_PARAM])
+	switch true {
+	case strings.HasSuffix(sourceType, "RANGE"): // covers multiranges as well
+		return "RANGE"
+	case strings.HasPrefix(sourceType, "_") && compositeTypes[strings.TrimPrefix(sourceType, "_")]:
+		// the arrays of the other user defined types (enums, domains, ...) are imported as they are exported.
+		return "ARRAY_OF_COMPOSITE"
+	}
+	return ""
+}
+
 type Column struct {
 	Name   string       `json:"name"`
 	Index  int64        `json:"index"`
@@ -40,10 +63,12 @@ type TableSchema struct {
 	Columns []Column `json:"columns"`
 }
 
-func (ts *TableSchema) getColumnType(columnName string) (string, error) {
+func (ts *TableSchema) getColumnType(columnName string, compositeTypes map[string]bool) (string, error) {
 	for _, colSchema := range ts.Columns {
 		if colSchema.Name == columnName {
-			if colSchema.Schema.Name != "" { // in case Kafka speicfic type which start with io.debezium...
+			if complexType := colSchema.Schema.getComplexType(compositeTypes); complexType != "" {
+				return complexType, nil
+			} else if colSchema.Schema.Name != "" { // in case Kafka speicfic type which start with io.debezium...
 				return colSchema.Schema.Name, nil
 			} else {
 				return colSchema.Schema.Type, nil // in case of Primitive types e.g. BYTES/STRING..
@@ -57,14 +82,16 @@ func (ts *TableSchema) getColumnType(columnName string) (string, error) {
 //===========================================================
 
 type SchemaRegistry struct {
-	exportDir     string
+	exportDir         string
 	tableNameToSchema map[string]*TableSchema
+	compositeTypes    map[string]bool
 }
 
 func NewSchemaRegistry(exportDir string) *SchemaRegistry {
 	return &SchemaRegistry{
-		exportDir:     exportDir,
+		exportDir:         exportDir,
 		tableNameToSchema: make(map[string]*TableSchema),
+		compositeTypes:    make(map[string]bool),
 	}
 }
 
@@ -76,7 +103,7 @@ func (sreg *SchemaRegistry) GetColumnTypes(tableName string, columnNames []strin
 	columnTypes := make([]string, len(columnNames))
 	for idx, columnName := range columnNames {
 		var err error
-		columnTypes[idx], err = tableSchema.getColumnType(columnName)
+		columnTypes[idx], err = tableSchema.getColumnType(columnName, sreg.compositeTypes)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get column type for table %s, column %s: %w", tableName, columnName, err)
 		}
@@ -89,7 +116,7 @@ func (sreg *SchemaRegistry) GetColumnType(tableName, columnName string) (string,
 	if tableSchema == nil {
 		return "", fmt.Errorf("table %s not found in schema registry", tableName)
 	}
-	return tableSchema.getColumnType(columnName)
+	return tableSchema.getColumnType(columnName, sreg.compositeTypes)
 }
 
 func (sreg *SchemaRegistry) Init() error {
@@ -99,19 +126,57 @@ func (sreg *SchemaRegistry) Init() error {
 		return fmt.Errorf("failed to read schema dir %s: %w", schemaDir, err)
 	}
 	for _, schemaFile := range schemaFiles {
-		schemaFilePath := filepath.Join(schemaDir, schemaFile.Name())
-		schemaFile, err := os.Open(schemaFilePath)
-		if err != nil {
-			return fmt.Errorf("failed to open table schema file %s: %w", schemaFilePath, err)
-		}
-		var tableSchema TableSchema
-		err = json.NewDecoder(schemaFile).Decode(&tableSchema)
+		table := strings.TrimSuffix(filepath.Base(schemaFile.Name()), "_schema.json")
+		err = sreg.loadTableSchema(table, filepath.Join(schemaDir, schemaFile.Name()))
 		if err != nil {
-			return fmt.Errorf("failed to decode table schema file %s: %w", schemaFilePath, err)
+			return err
 		}
-		table := strings.TrimSuffix(filepath.Base(schemaFile.Name()), "_schema.json")
-		sreg.tableNameToSchema[table] = &tableSchema
-		schemaFile.Close()
 	}
+	return sreg.loadCompositeTypes()
+}
+
+// Matches the definition of a composite type, e.g. `CREATE TYPE public.address AS (`, but not of an enum or a range.
+var createCompositeTypeRegex = regexp.MustCompile(`(?i)CREATE\s+TYPE\s+(\S+)\s+AS\s*\(`)
+
+// loadCompositeTypes reads the names of the composite types from the types in the exported schema.
+func (sreg *SchemaRegistry) loadCompositeTypes() error {
+	typesFilePath := utils.GetObjectFilePath(filepath.Join(sreg.exportDir, "schema"), "TYPE")
+	bytes, err := os.ReadFile(typesFilePath)
+	if os.IsNotExist(err) {
+		log.Infof("no types exported in %s, the arrays of composite types are imported as they are exported", typesFilePath)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read types file %s: %w", typesFilePath, err)
+	}
+	for _, match := range createCompositeTypeRegex.FindAllStringSubmatch(string(bytes), -1) {
+		// debezium reports the type of the array without the schema name, e.g. _address
+		typeName := match[1][strings.LastIndex(match[1], ".")+1:]
+		sreg.compositeTypes[strings.ToUpper(strings.Trim(typeName, `"`))] = true
+	}
+	return nil
+}
+
+// RegisterTable loads the schema of a table which is not part of the migration, exported after the registry was initialized.
+func (sreg *SchemaRegistry) RegisterTable(tableName string) error {
+	schemaFilePath := filepath.Join(sreg.exportDir, "data", "schemas", tableName+"_schema.json")
+	if _, err := os.Stat(schemaFilePath); err != nil {
+		return fmt.Errorf("schema of table %s not found: %w", tableName, err)
+	}
+	return sreg.loadTableSchema(tableName, schemaFilePath)
+}
+
+func (sreg *SchemaRegistry) loadTableSchema(table, schemaFilePath string) error {
+	schemaFile, err := os.Open(schemaFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open table schema file %s: %w", schemaFilePath, err)
+	}
+	defer schemaFile.Close()
+	var tableSchema TableSchema
+	err = json.NewDecoder(schemaFile).Decode(&tableSchema)
+	if err != nil {
+		return fmt.Errorf("failed to decode table schema file %s: %w", schemaFilePath, err)
+	}
+	sreg.tableNameToSchema[table] = &tableSchema
 	return nil
 }
@@ -0,0 +1,70 @@
+package dbzm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaRegistryRegisterTable(t *testing.T) {
+	exportDir := t.TempDir()
+	schemaDir := filepath.Join(exportDir, "data", "schemas")
+	assert.NoError(t, os.MkdirAll(schemaDir, 0755))
+	sreg := NewSchemaRegistry(exportDir)
+	assert.NoError(t, sreg.Init())
+	_, err := sreg.GetColumnType("sales.orders", "id")
+	assert.Error(t, err)
+	assert.ErrorContains(t, sreg.RegisterTable("sales.orders"), "schema of table sales.orders not found")
+
+	// exported after the start of the import
+	schema := `{"columns": [{"name": "id", "index": 0, "schema": {"type": "INT64"}}]}`
+	assert.NoError(t, os.WriteFile(filepath.Join(schemaDir, "sales.orders_schema.json"), []byte(schema), 0644))
+	assert.NoError(t, sreg.RegisterTable("sales.orders"))
+	colType, err := sreg.GetColumnType("sales.orders", "id")
+	assert.NoError(t, err)
+	assert.Equal(t, "INT64", colType)
+}
+
+func TestSchemaRegistryArrayOfCompositeType(t *testing.T) {
+	exportDir := t.TempDir()
+	schemaDir := filepath.Join(exportDir, "data", "schemas")
+	assert.NoError(t, os.MkdirAll(schemaDir, 0755))
+	typesDir := filepath.Join(exportDir, "schema", "types")
+	assert.NoError(t, os.MkdirAll(typesDir, 0755))
+	types := `CREATE TYPE public.address AS (
+    street text,
+    city text
+);
+CREATE TYPE public."Mood" AS ENUM (
+    'sad',
+    'happy'
+);
+CREATE TYPE public.float_range AS RANGE (
+    subtype = double precision
+);
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(typesDir, "type.sql"), []byte(types), 0644))
+	schema := `{"columns": [
+		{"name": "addresses", "index": 0, "schema": {"type": "STRING", "parameters": {"__debezium.source.column.type": "_address"}}},
+		{"name": "moods", "index": 1, "schema": {"type": "STRING", "parameters": {"__debezium.source.column.type": "_Mood"}}},
+		{"name": "tags", "index": 2, "schema": {"type": "ARRAY", "parameters": {"__debezium.source.column.type": "_text"}}},
+		{"name": "ranges", "index": 3, "schema": {"type": "STRING", "parameters": {"__debezium.source.column.type": "float_range"}}}
+	]}`
+	assert.NoError(t, os.WriteFile(filepath.Join(schemaDir, "public.foo_schema.json"), []byte(schema), 0644))
+	sreg := NewSchemaRegistry(exportDir)
+	assert.NoError(t, sreg.Init())
+	colTypes, err := sreg.GetColumnTypes("public.foo", []string{"addresses", "moods", "tags", "ranges"})
+	assert.NoError(t, err)
+	// the array of the enum is imported as it is exported
+	assert.Equal(t, []string{"ARRAY_OF_COMPOSITE", "STRING", "ARRAY", "RANGE"}, colTypes)
+
+	// without the exported types, no array is taken for an array of a composite type
+	assert.NoError(t, os.RemoveAll(typesDir))
+	sreg = NewSchemaRegistry(exportDir)
+	assert.NoError(t, sreg.Init())
+	colType, err := sreg.GetColumnType("public.foo", "addresses")
+	assert.NoError(t, err)
+	assert.Equal(t, "STRING", colType)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package dbzm
+
+import (
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+)
+
+// TypeCompatibilityIssue is a column whose values may not be imported as they are exported.
+type TypeCompatibilityIssue struct {
+	TableName  string
+	ColumnName string
+	Type       string
+	Reason     string
+}
+
+// Logical types of debezium whose values are exported in a form the target accepts, and need no converter.
+var typesNotRequiringConversion = []string{
+	"io.debezium.data.Json",
+	"io.debezium.data.Uuid",
+	"io.debezium.data.Enum",
+	"io.debezium.data.Ltree",
+	"io.debezium.time.Year",
+}
+
+// Types whose values are known to need manual handling, e.g. a change of the type of the target column.
+// The keys are either the debezium types or the source column types (when propagated by debezium).
+var typesRequiringManualHandling = map[string]string{
+	"io.debezium.data.EnumSet":       "the MySQL SET values are exported as comma separated strings",
+	"io.debezium.data.Xml":           "the XML values are imported as text, without validation",
+	"io.debezium.time.MicroDuration": "the durations are exported as numbers of microseconds",
+	"io.debezium.time.NanoDuration":  "the durations are exported as numbers of nanoseconds",
+	"RAW":                            "the RAW values are exported as bytes, which are imported as BYTEA",
+	"LONG RAW":                       "the LONG RAW values are exported as bytes, which are imported as BYTEA",
+	"BFILE":                          "the BFILE values are references to files outside the database, which are not exported",
+	"SDO_GEOMETRY":                   "the SDO_GEOMETRY values have no equivalent type on the target",
+	"XMLTYPE":                        "the XMLTYPE values are imported as text, without validation",
+	"SET":                            "the MySQL SET values are exported as comma separated strings",
+}
+
+/*
+getTypeCompatibilityIssues returns the columns of the tables whose types have no converter in the suite, or are known
+to need manual handling. The primitive types (INT32, STRING, ...) need no conversion. It only reads the schema registry.
+*/
+func getTypeCompatibilityIssues(schemaRegistry *SchemaRegistry, suite map[string]tgtdb.ConverterFn,
+	tableNameToColumns map[string][]string) []*TypeCompatibilityIssue {
+	var result []*TypeCompatibilityIssue
+	tableNames := make([]string, 0, len(tableNameToColumns))
+	for tableName := range tableNameToColumns {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+	for _, tableName := range tableNames {
+		tableSchema := schemaRegistry.tableNameToSchema[tableName]
+		if tableSchema == nil {
+			log.Infof("type compatibility check: table %s not found in schema registry", tableName)
+			continue
+		}
+		for _, columnName := range tableNameToColumns[tableName] {
+			i := slices.IndexFunc(tableSchema.Columns, func(column Column) bool { return column.Name == columnName })
+			if i == -1 {
+				continue
+			}
+			column := tableSchema.Columns[i]
+			colType, _ := tableSchema.getColumnType(columnName, schemaRegistry.compositeTypes)
+			issue := &TypeCompatibilityIssue{TableName: tableName, ColumnName: columnName, Type: colType}
+			sourceType := strings.ToUpper(column.Schema.Parameters[SOURCE_COLUMN_TYPE_PARAM])
+			switch {
+			case typesRequiringManualHandling[colType] != "":
+				issue.Reason = typesRequiringManualHandling[colType]
+			case typesRequiringManualHandling[sourceType] != "":
+				issue.Type = sourceType
+				issue.Reason = typesRequiringManualHandling[sourceType]
+			case strings.Contains(colType, ".") && suite[colType] == nil && !slices.Contains(typesNotRequiringConversion, colType):
+				issue.Reason = "no converter for the type, the values are imported as they are exported"
+			default:
+				continue
+			}
+			result = append(result, issue)
+		}
+	}
+	return result
+}
@@ -18,13 +18,47 @@ package dbzm
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
 )
 
+var registeredConverters = make(map[string]tgtdb.ConverterFn)
+var registeredConvertersMutex sync.Mutex
+
+/*
+RegisterConverter registers the converter of the values of a type, e.g. for custom data types which need special
+handling. It takes precedence over the converter of the target db for the type, and is used by the value converters
+created (with NewValueConverter) after the call, for both the snapshot rows and the streamed events.
+See tgtdb.ConverterFn for the formatIfRequired semantics.
+*/
+func RegisterConverter(typeName string, fn tgtdb.ConverterFn) {
+	registeredConvertersMutex.Lock()
+	defer registeredConvertersMutex.Unlock()
+	registeredConverters[typeName] = fn
+}
+
+// getValueConverterSuite returns the converters of the target db, along with the registered ones.
+func getValueConverterSuite(tdb tgtdb.TargetDB) map[string]tgtdb.ConverterFn {
+	registeredConvertersMutex.Lock()
+	defer registeredConvertersMutex.Unlock()
+	suite := make(map[string]tgtdb.ConverterFn)
+	for typeName, fn := range tdb.GetDebeziumValueConverterSuite() {
+		suite[typeName] = fn
+	}
+	for typeName, fn := range registeredConverters {
+		suite[typeName] = fn
+	}
+	return suite
+}
+
 type ValueConverter interface {
 	ConvertRow(tableName string, columnNames []string, row string) (string, error)
 	ConvertEvent(ev *tgtdb.Event, table string, formatIfRequired bool) error
+	// GetTypeCompatibilityIssues returns the exported columns of the tables whose values may not convert cleanly.
+	GetTypeCompatibilityIssues(tableNameToColumns map[string][]string) []*TypeCompatibilityIssue
+	// RegisterTable prepares to convert the events of a table created after the start of the import.
+	RegisterTable(tableName string) error
 }
 
 func NewValueConverter(exportDir string, tdb tgtdb.TargetDB) (ValueConverter, error) {
@@ -47,12 +81,22 @@ func (nvc *NoOpValueConverter) ConvertEvent(ev *tgtdb.Event, table string, forma
 	return nil
 }
 
+func (nvc *NoOpValueConverter) GetTypeCompatibilityIssues(tableNameToColumns map[string][]string) []*TypeCompatibilityIssue {
+	return nil
+}
+
+func (nvc *NoOpValueConverter) RegisterTable(tableName string) error {
+	return nil
+}
+
 //============================================================================
 
 type DebeziumValueConverter struct {
 	schemaRegistry      *SchemaRegistry
 	valueConverterSuite map[string]tgtdb.ConverterFn
 	converterFnCache    map[string][]tgtdb.ConverterFn //stores table name to converter functions for each column
+	// ConvertRow() is called from multiple goroutines with --conversion-workers
+	converterFnCacheMutex sync.Mutex
 }
 
 func NewDebeziumValueConverter(exportDir string, tdb tgtdb.TargetDB) (*DebeziumValueConverter, error) {
@@ -61,11 +105,9 @@ func NewDebeziumValueConverter(exportDir string, tdb tgtdb.TargetDB) (*DebeziumV
 	if err != nil {
 		return nil, fmt.Errorf("initializing schema registry: %w", err)
 	}
-	tdbValueConverterSuite := tdb.GetDebeziumValueConverterSuite()
-
 	return &DebeziumValueConverter{
 		schemaRegistry:      schemaRegistry,
-		valueConverterSuite: tdbValueConverterSuite,
+		valueConverterSuite: getValueConverterSuite(tdb),
 		converterFnCache:    map[string][]tgtdb.ConverterFn{},
 	}, nil
 }
@@ -90,6 +132,8 @@ func (conv *DebeziumValueConverter) ConvertRow(tableName string, columnNames []s
 }
 
 func (conv *DebeziumValueConverter) getConverterFns(tableName string, columnNames []string) ([]tgtdb.ConverterFn, error) {
+	conv.converterFnCacheMutex.Lock()
+	defer conv.converterFnCacheMutex.Unlock()
 	result := conv.converterFnCache[tableName]
 	if result == nil {
 		colTypes, err := conv.schemaRegistry.GetColumnTypes(tableName, columnNames)
@@ -105,6 +149,14 @@ func (conv *DebeziumValueConverter) getConverterFns(tableName string, columnName
 	return result, nil
 }
 
+func (conv *DebeziumValueConverter) GetTypeCompatibilityIssues(tableNameToColumns map[string][]string) []*TypeCompatibilityIssue {
+	return getTypeCompatibilityIssues(conv.schemaRegistry, conv.valueConverterSuite, tableNameToColumns)
+}
+
+func (conv *DebeziumValueConverter) RegisterTable(tableName string) error {
+	return conv.schemaRegistry.RegisterTable(tableName)
+}
+
 func (conv *DebeziumValueConverter) ConvertEvent(ev *tgtdb.Event, table string, formatIfRequired bool) error {
 	err := conv.convertMap(table, ev.Key, formatIfRequired)
 	if err != nil {
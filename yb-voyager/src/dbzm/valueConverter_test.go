@@ -0,0 +1,74 @@
+package dbzm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+)
+
+type fakeTargetDB struct {
+	tgtdb.TargetDB
+	suite map[string]tgtdb.ConverterFn
+}
+
+func (tdb *fakeTargetDB) GetDebeziumValueConverterSuite() map[string]tgtdb.ConverterFn {
+	return tdb.suite
+}
+
+func TestRegisterConverter(t *testing.T) {
+	constFn := func(result string) tgtdb.ConverterFn {
+		return func(v string, formatIfRequired bool) (string, error) { return result, nil }
+	}
+	tdb := &fakeTargetDB{suite: map[string]tgtdb.ConverterFn{
+		"io.debezium.time.Date":              constFn("date"),
+		"io.debezium.data.geometry.Geometry": constFn("geometry"),
+	}}
+	RegisterConverter("custom.Wkb", constFn("wkb"))
+	RegisterConverter("io.debezium.data.geometry.Geometry", constFn("custom geometry"))
+	defer func() { registeredConverters = make(map[string]tgtdb.ConverterFn) }()
+
+	suite := getValueConverterSuite(tdb)
+	assert.Len(t, suite, 3)
+	for typeName, expected := range map[string]string{
+		"io.debezium.time.Date":              "date",
+		"io.debezium.data.geometry.Geometry": "custom geometry",
+		"custom.Wkb":                         "wkb",
+	} {
+		result, err := suite[typeName]("", false)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	}
+	// the suite of the target db is not modified
+	assert.Len(t, tdb.suite, 2)
+}
+
+func TestGetTypeCompatibilityIssues(t *testing.T) {
+	schemaRegistry := NewSchemaRegistry("")
+	schemaRegistry.tableNameToSchema["public.foo"] = &TableSchema{Columns: []Column{
+		{Name: "id", Schema: ColumnSchema{Type: "INT32"}},
+		{Name: "created_at", Schema: ColumnSchema{Type: "INT64", Name: "io.debezium.time.MicroTimestamp"}},
+		{Name: "doc", Schema: ColumnSchema{Type: "STRING", Name: "io.debezium.data.Json"}},
+		{Name: "tags", Schema: ColumnSchema{Type: "STRING", Name: "io.debezium.data.EnumSet"}},
+		{Name: "payload", Schema: ColumnSchema{Type: "BYTES", Parameters: map[string]string{SOURCE_COLUMN_TYPE_PARAM: "raw"}}},
+		{Name: "shape", Schema: ColumnSchema{Type: "STRUCT", Name: "custom.Shape"}},
+	}}
+	suite := map[string]tgtdb.ConverterFn{
+		"io.debezium.time.MicroTimestamp": func(v string, formatIfRequired bool) (string, error) { return v, nil },
+		"BYTES":                           func(v string, formatIfRequired bool) (string, error) { return v, nil },
+	}
+	issues := getTypeCompatibilityIssues(schemaRegistry, suite, map[string][]string{
+		"public.foo": {"id", "created_at", "doc", "tags", "payload", "shape"},
+		"public.bar": {"id"}, // not in the schema registry
+	})
+	var columns, types []string
+	for _, issue := range issues {
+		assert.Equal(t, "public.foo", issue.TableName)
+		assert.NotEmpty(t, issue.Reason)
+		columns = append(columns, issue.ColumnName)
+		types = append(types, issue.Type)
+	}
+	assert.Equal(t, []string{"tags", "payload", "shape"}, columns)
+	assert.Equal(t, []string{"io.debezium.data.EnumSet", "RAW", "custom.Shape"}, types)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+)
+
+const METRICS_PREFIX = "yb_voyager_stream_import_"
+
+// StartMetricsServer serves the stats in the Prometheus text exposition format on the `/metrics` path of addr,
+// e.g. `:9100`. The listener is opened before returning, so that an unusable address is reported to the caller.
+func (s *StreamImportStatsReporter) StartMetricsServer(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen on %q: %w", addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		s.WriteMetrics(w)
+	})
+	log.Infof("serving live migration metrics on %s/metrics", listener.Addr())
+	go func() {
+		err := http.Serve(listener, mux)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("metrics server on %q stopped: %s", addr, err)
+		}
+	}()
+	return nil
+}
+
+// WriteMetrics writes a snapshot of the stats in the Prometheus text exposition format.
+func (s *StreamImportStatsReporter) WriteMetrics(w io.Writer) {
+	s.Mutex.Lock()
+	elapsedMins := time.Since(s.startTime).Minutes()
+	totalEventsImported := s.totalEventsImported
+	currImportedEvents := s.CurrImportedEvents
+	remainingEvents := s.remainingEvents
+	estimatedTimeToCatchUp := s.estimatedTimeToCatchUp
+	averageRateLast3Mins := s.getAverageIngestionRate(3, elapsedMins)
+	averageRateLast10Mins := s.getAverageIngestionRate(10, elapsedMins)
+	numVsnGaps := s.numVsnGaps
+	numMissingEvents := s.numMissingEvents
+	numSkippedEvents := s.numSkippedEvents
+	numBlockedSends := s.numBlockedSends
+	numChannelsBlocked := s.numChannelsBlocked
+	blockedSendsDuration := s.blockedSendsDuration
+	numDeadLetteredBatches := s.numDeadLetteredBatches
+	numDeadLetteredEvents := s.numDeadLetteredEvents
+	tableRemainingEvents := make(map[string]int64, len(s.tableRemainingEvents))
+	for tableName, remainingEvents := range s.tableRemainingEvents {
+		tableRemainingEvents[tableName] = remainingEvents
+	}
+	s.Mutex.Unlock()
+
+	writeMetric(w, "total_events_imported", "counter", "Events imported across all the runs.", totalEventsImported)
+	writeMetric(w, "current_run_events_imported", "counter", "Events imported in this run.", currImportedEvents)
+	writeMetric(w, "remaining_events", "gauge", "Exported events yet to be imported.", remainingEvents)
+	writeMetric(w, "estimated_time_to_catch_up_seconds", "gauge", "Estimated time to import the remaining events.",
+		int64(estimatedTimeToCatchUp.Seconds()))
+	name := METRICS_PREFIX + "ingestion_rate_events_per_second"
+	fmt.Fprintf(w, "# HELP %s Average events imported per second over the window.\n", name)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(w, "%s{window=\"3m\"} %d\n", name, averageRateLast3Mins/60)
+	fmt.Fprintf(w, "%s{window=\"10m\"} %d\n", name, averageRateLast10Mins/60)
+	writeMetric(w, "vsn_gaps", "counter", "Gaps detected in the VSNs of the exported events.", numVsnGaps)
+	writeMetric(w, "missing_events", "counter", "Events missing in the VSN gaps.", numMissingEvents)
+	writeMetric(w, "skipped_events", "counter", "Events intentionally not imported.", numSkippedEvents)
+	writeMetric(w, "event_channel_blocked_sends", "counter",
+		"Sends of the events blocked on a full event channel beyond the threshold, as the target is slower than the stream.", numBlockedSends)
+	writeMetric(w, "event_channels_blocked", "gauge", "Event channels the sends are blocked on currently.", numChannelsBlocked)
+	writeMetric(w, "event_channel_blocked_seconds", "counter", "Time the completed blocked sends were blocked for.",
+		int64(blockedSendsDuration.Seconds()))
+	writeMetric(w, "dead_lettered_event_batches", "counter", "Event batches written to the dead-letter dir after their retries.",
+		numDeadLetteredBatches)
+	writeMetric(w, "dead_lettered_events", "counter", "Events of the dead-lettered event batches.", numDeadLetteredEvents)
+	if len(tableRemainingEvents) > 0 {
+		name = METRICS_PREFIX + "table_remaining_events"
+		fmt.Fprintf(w, "# HELP %s Exported events of the table yet to be imported.\n", name)
+		fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+		tableNames := lo.Keys(tableRemainingEvents)
+		slices.Sort(tableNames)
+		for _, tableName := range tableNames {
+			fmt.Fprintf(w, "%s{table=%q} %d\n", name, tableName, tableRemainingEvents[tableName])
+		}
+	}
+}
+
+func writeMetric(w io.Writer, name string, metricType string, help string, value int64) {
+	name = METRICS_PREFIX + name
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+	fmt.Fprintf(w, "%s %d\n", name, value)
+}
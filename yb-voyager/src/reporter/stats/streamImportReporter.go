@@ -27,21 +27,50 @@ import (
 	"github.com/gosuri/uilive"
 	"github.com/samber/lo"
 	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+	"golang.org/x/exp/slices"
 )
 
 type StreamImportStatsReporter struct {
 	sync.Mutex
-	migrationUUID       uuid.UUID
-	totalEventsImported int64
-	CurrImportedEvents  int64
-	startTime           time.Time
-	eventsSlidingWindow [61]int64 // stores events per 10 secs for last 10 mins
-	remainingEvents     int64
+	migrationUUID          uuid.UUID
+	totalEventsImported    int64
+	CurrImportedEvents     int64
+	startTime              time.Time
+	eventsSlidingWindow    [61]int64 // stores events per 10 secs for last 10 mins
+	remainingEvents        int64
+	remainingEventsKnown   bool
 	estimatedTimeToCatchUp time.Duration
+	numVsnGaps             int64
+	numMissingEvents       int64
+	numSkippedEvents       int64
+	overallProgressFn      func() string // optional; displays the overall progress of the migration if set
+	// events imported by table, across all the runs, and the exported events of the tables yet to be imported
+	tableEventCounts     map[string]*tgtdb.EventCounter
+	tableRemainingEvents map[string]int64
+	// sends of the events blocked on the full event channels, i.e. the target is slower than the stream
+	numBlockedSends      int64
+	numChannelsBlocked   int64
+	blockedSendsDuration time.Duration
+	// event batches written to the dead-letter dir instead of being imported, after their retries are exhausted
+	numDeadLetteredBatches int64
+	numDeadLetteredEvents  int64
+}
+
+// number of the tables with the most remaining events displayed in the stats
+const NUM_LAGGING_TABLES_TO_REPORT = 5
+
+// TableLag is the progress of the import of the events of a table.
+type TableLag struct {
+	TableName       string
+	RemainingEvents int64
+	ImportedEvents  tgtdb.EventCounter
 }
 
 func NewStreamImportStatsReporter() *StreamImportStatsReporter {
-	return &StreamImportStatsReporter{}
+	return &StreamImportStatsReporter{
+		tableEventCounts:     make(map[string]*tgtdb.EventCounter),
+		tableRemainingEvents: make(map[string]int64),
+	}
 }
 
 func (s *StreamImportStatsReporter) Init(tdb tgtdb.TargetDB, migrationUUID uuid.UUID) error {
@@ -51,6 +80,13 @@ func (s *StreamImportStatsReporter) Init(tdb tgtdb.TargetDB, migrationUUID uuid.
 	if err != nil {
 		return fmt.Errorf("failed to fetch import stats meta info from target : %w", err)
 	}
+	tableEventCounts, err := tdb.GetImportedEventCountsByTable(migrationUUID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch import stats of the tables from target : %w", err)
+	}
+	for tableName, counts := range tableEventCounts {
+		s.tableEventCounts[tableName] = counts
+	}
 	s.startTime = time.Now()
 	return nil
 }
@@ -69,7 +105,13 @@ func (s *StreamImportStatsReporter) ReportStats() {
 	row4 := table.Newline()
 	row5 := table.Newline()
 	row6 := table.Newline()
+	row7 := table.Newline()
+	row8 := table.Newline()
+	row9 := table.Newline()
+	row10 := table.Newline()
+	row11 := table.Newline()
 	timerRow := table.Newline()
+	laggingTablesRows := table.Newline()
 
 	table.Start()
 
@@ -81,23 +123,43 @@ func (s *StreamImportStatsReporter) ReportStats() {
 		fmt.Fprint(seperator2, color.GreenString("| %-30s | %30s |\n", "-----------------------------", "-----------------------------"))
 		fmt.Fprint(row1, color.GreenString("| %-30s | %30s |\n", "Total Imported events", strconv.FormatInt(s.totalEventsImported, 10)))
 		fmt.Fprint(row2, color.GreenString("| %-30s | %30s |\n", "Events Imported in this Run", strconv.FormatInt(s.CurrImportedEvents, 10)))
-		var averageRateLast3Mins, averageRateLast10Mins int64
-		if elapsedTime < 3 {
-			averageRateLast3Mins = s.getIngestionRateForLastNMinutes(int64(elapsedTime) + 1)
-		} else {
-			averageRateLast3Mins = s.getIngestionRateForLastNMinutes(3)
-		}
-		if elapsedTime < 10 {
-			averageRateLast10Mins = s.getIngestionRateForLastNMinutes(int64(elapsedTime) + 1)
-		} else {
-			averageRateLast10Mins = s.getIngestionRateForLastNMinutes(10)
-		}
+		averageRateLast3Mins := s.getAverageIngestionRate(3, elapsedTime)
+		averageRateLast10Mins := s.getAverageIngestionRate(10, elapsedTime)
 		fmt.Fprint(row3, color.GreenString("| %-30s | %30s |\n", "Ingestion Rate (last 3 mins)", fmt.Sprintf("%d events/sec", averageRateLast3Mins/60)))
 		fmt.Fprint(row4, color.GreenString("| %-30s | %30s |\n", "Ingestion Rate (last 10 mins)", fmt.Sprintf("%d events/sec", averageRateLast10Mins/60)))
 		fmt.Fprint(timerRow, color.GreenString("| %-30s | %30s |\n", "Time taken in this Run", fmt.Sprintf("%.2f mins", elapsedTime)))
 		fmt.Fprint(row5, color.GreenString("| %-30s | %30s |\n", "Remaining Events", strconv.FormatInt(s.remainingEvents, 10)))
 		fmt.Fprint(row6, color.GreenString("| %-30s | %30s |\n", "Estimated Time to catch up", s.estimatedTimeToCatchUp.String()))
+		if s.numVsnGaps > 0 {
+			fmt.Fprint(row7, color.RedString("| %-30s | %30s |\n", "VSN gaps (missing events)", fmt.Sprintf("%d (%d)", s.numVsnGaps, s.numMissingEvents)))
+		}
+		if s.numSkippedEvents > 0 {
+			fmt.Fprint(row8, color.YellowString("| %-30s | %30s |\n", "Skipped events", strconv.FormatInt(s.numSkippedEvents, 10)))
+		}
+		if s.numBlockedSends > 0 {
+			value := fmt.Sprintf("%d (%s)", s.numBlockedSends, s.blockedSendsDuration.Round(time.Second))
+			if s.numChannelsBlocked > 0 {
+				value = "blocked now, " + value
+			}
+			fmt.Fprint(row10, color.YellowString("| %-30s | %30s |\n", "Slow target (blocked sends)", value))
+		}
+		if s.numDeadLetteredBatches > 0 {
+			fmt.Fprint(row11, color.RedString("| %-30s | %30s |\n", "Dead-lettered batches (events)",
+				fmt.Sprintf("%d (%d)", s.numDeadLetteredBatches, s.numDeadLetteredEvents)))
+		}
+		if s.overallProgressFn != nil {
+			fmt.Fprint(row9, color.GreenString("| %-30s | %30s |\n", "Overall progress", s.overallProgressFn()))
+		}
 		fmt.Fprint(seperator3, color.GreenString("| %-30s | %30s |\n", "-----------------------------", "-----------------------------"))
+		laggingTables := s.GetLaggingTables(NUM_LAGGING_TABLES_TO_REPORT)
+		if len(laggingTables) > 0 {
+			fmt.Fprint(laggingTablesRows, color.YellowString("| %-30s | %30s |\n", "Lagging tables", "Remaining (imported I/U/D)"))
+			for _, lag := range laggingTables {
+				fmt.Fprint(laggingTablesRows, color.YellowString("| %-30s | %30s |\n", lag.TableName,
+					fmt.Sprintf("%d (%d/%d/%d)", lag.RemainingEvents, lag.ImportedEvents.NumInserts, lag.ImportedEvents.NumUpdates, lag.ImportedEvents.NumDeletes)))
+			}
+			fmt.Fprint(laggingTablesRows, color.YellowString("| %-30s | %30s |\n", "-----------------------------", "-----------------------------"))
+		}
 		table.Flush()
 	}
 }
@@ -111,13 +173,70 @@ func (s *StreamImportStatsReporter) slideWindow() {
 	s.Mutex.Unlock()
 }
 
-func (s *StreamImportStatsReporter) BatchImported(numInserts, numUpdates, numDeletes int64) {
+// BatchImported records the events of a table imported in a batch, which can have the events of several tables.
+func (s *StreamImportStatsReporter) BatchImported(tableName string, numInserts, numUpdates, numDeletes int64) {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 	total := numInserts + numUpdates + numDeletes
 	s.CurrImportedEvents += total
 	s.totalEventsImported += total
 	s.eventsSlidingWindow[0] += total
+	counts, ok := s.tableEventCounts[tableName]
+	if !ok {
+		counts = &tgtdb.EventCounter{}
+		s.tableEventCounts[tableName] = counts
+	}
+	counts.TotalEvents += total
+	counts.NumInserts += numInserts
+	counts.NumUpdates += numUpdates
+	counts.NumDeletes += numDeletes
+	if _, ok := s.tableRemainingEvents[tableName]; ok {
+		s.tableRemainingEvents[tableName] -= total
+	}
+}
+
+/*
+UpdateRemainingEventsByTable refreshes the remaining events of the tables from their exported events, keyed by
+the table names of the imported events. Only the tables with imported events are tracked, so that the tables
+whose events are skipped don't appear to lag.
+*/
+func (s *StreamImportStatsReporter) UpdateRemainingEventsByTable(exportedEventsByTable map[string]int64) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	for tableName, counts := range s.tableEventCounts {
+		exportedEvents, ok := exportedEventsByTable[tableName]
+		if !ok {
+			continue
+		}
+		s.tableRemainingEvents[tableName] = exportedEvents - counts.TotalEvents
+	}
+}
+
+// GetLaggingTables returns up to n tables with the most remaining events, in the descending order of them.
+func (s *StreamImportStatsReporter) GetLaggingTables(n int) []*TableLag {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	var lags []*TableLag
+	for tableName, remainingEvents := range s.tableRemainingEvents {
+		if remainingEvents <= 0 {
+			continue
+		}
+		lags = append(lags, &TableLag{
+			TableName:       tableName,
+			RemainingEvents: remainingEvents,
+			ImportedEvents:  *s.tableEventCounts[tableName],
+		})
+	}
+	slices.SortFunc(lags, func(a, b *TableLag) bool {
+		if a.RemainingEvents != b.RemainingEvents {
+			return a.RemainingEvents > b.RemainingEvents
+		}
+		return a.TableName < b.TableName
+	})
+	if len(lags) > n {
+		lags = lags[:n]
+	}
+	return lags
 }
 
 func (s *StreamImportStatsReporter) getIngestionRateForLastNMinutes(n int64) int64 {
@@ -125,12 +244,81 @@ func (s *StreamImportStatsReporter) getIngestionRateForLastNMinutes(n int64) int
 	return lo.Sum(s.eventsSlidingWindow[1:windowSize]) / n
 }
 
+// getAverageIngestionRate returns the events per minute over the last n minutes, or over the
+// elapsed minutes of this run if it is shorter.
+func (s *StreamImportStatsReporter) getAverageIngestionRate(n int64, elapsedMins float64) int64 {
+	if elapsedMins < float64(n) {
+		return s.getIngestionRateForLastNMinutes(int64(elapsedMins) + 1)
+	}
+	return s.getIngestionRateForLastNMinutes(n)
+}
+
 func (s *StreamImportStatsReporter) UpdateRemainingEvents(totalExportedEvents int64) {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
-	s.remainingEvents = totalExportedEvents - s.totalEventsImported
+	s.remainingEvents = totalExportedEvents - s.totalEventsImported - s.numSkippedEvents - s.numDeadLetteredEvents
+	s.remainingEventsKnown = true
 	lastMinIngestionRate := s.getIngestionRateForLastNMinutes(1)
 	if lastMinIngestionRate > 0 {
 		s.estimatedTimeToCatchUp = time.Duration(s.remainingEvents/lastMinIngestionRate) * time.Minute
 	}
-}
\ No newline at end of file
+}
+
+// GetRemainingEvents returns the number of exported events yet to be imported, and
+// whether it is known, i.e. whether the number of exported events has been fetched at least once.
+func (s *StreamImportStatsReporter) GetRemainingEvents() (int64, bool) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	return s.remainingEvents, s.remainingEventsKnown
+}
+
+func (s *StreamImportStatsReporter) VsnGapDetected(numMissingEvents int64) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.numVsnGaps++
+	s.numMissingEvents += numMissingEvents
+}
+
+// EventsSkipped records the events which are intentionally not imported, so that they are not counted as remaining.
+func (s *StreamImportStatsReporter) EventsSkipped(numEvents int64) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.numSkippedEvents += numEvents
+}
+
+// EventChannelBlocked records a send of an event blocked on a full event channel beyond the threshold.
+func (s *StreamImportStatsReporter) EventChannelBlocked() {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.numBlockedSends++
+	s.numChannelsBlocked++
+}
+
+// EventChannelUnblocked records the completion of a blocked send, which was blocked for the given duration.
+func (s *StreamImportStatsReporter) EventChannelUnblocked(blockedFor time.Duration) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.numChannelsBlocked--
+	s.blockedSendsDuration += blockedFor
+}
+
+// EventBatchDeadLettered records a batch of events written to the dead-letter dir, so that its events are not counted as remaining.
+func (s *StreamImportStatsReporter) EventBatchDeadLettered(numEvents int64) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.numDeadLetteredBatches++
+	s.numDeadLetteredEvents += numEvents
+}
+
+// SetOverallProgressFn adds a row with the overall progress of the migration, as returned by fn, to the displayed stats.
+func (s *StreamImportStatsReporter) SetOverallProgressFn(fn func() string) {
+	s.overallProgressFn = fn
+}
+
+// GetStreamingProgress returns the number of events imported (or skipped or dead-lettered) so far and the number of events
+// remaining to be imported, along with whether the latter is known.
+func (s *StreamImportStatsReporter) GetStreamingProgress() (int64, int64, bool) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	return s.totalEventsImported + s.numSkippedEvents + s.numDeadLetteredEvents, s.remainingEvents, s.remainingEventsKnown
+}
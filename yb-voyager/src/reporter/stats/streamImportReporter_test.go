@@ -0,0 +1,32 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/tgtdb"
+)
+
+func TestStreamImportStatsReporterLaggingTables(t *testing.T) {
+	assert := assert.New(t)
+	statsReporter := NewStreamImportStatsReporter()
+	statsReporter.BatchImported("public.t1", 10, 5, 1)
+	statsReporter.BatchImported("public.t2", 2, 0, 0)
+	statsReporter.BatchImported("public.t3", 1, 0, 0)
+	statsReporter.BatchImported("public.t1", 4, 0, 0)
+	assert.Empty(statsReporter.GetLaggingTables(5))
+
+	statsReporter.UpdateRemainingEventsByTable(map[string]int64{"public.t1": 30, "public.t2": 102, "public.t3": 1, "public.t4": 50})
+	lags := statsReporter.GetLaggingTables(5)
+	assert.Equal([]string{"public.t2", "public.t1"}, lo.Map(lags, func(lag *TableLag, _ int) string { return lag.TableName }))
+	assert.Equal(int64(100), lags[0].RemainingEvents)
+	assert.Equal(int64(10), lags[1].RemainingEvents)
+	assert.Equal(tgtdb.EventCounter{TotalEvents: 20, NumInserts: 14, NumUpdates: 5, NumDeletes: 1}, lags[1].ImportedEvents)
+
+	statsReporter.BatchImported("public.t2", 95, 0, 0)
+	lags = statsReporter.GetLaggingTables(1)
+	assert.Equal(1, len(lags))
+	assert.Equal("public.t1", lags[0].TableName)
+}
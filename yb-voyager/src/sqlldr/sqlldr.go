@@ -20,8 +20,42 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
 )
 
+// Minimum major version of sqlldr required for the options used by voyager (e.g. NO_INDEX_ERRORS).
+const MIN_SQLLDR_MAJOR_VERSION = 12
+
+const SQLLDR_INSTALLATION_GUIDANCE = "sqlldr is part of the Oracle Instant Client Tools package. " +
+	"Install it (https://www.oracle.com/database/technologies/instant-client/downloads.html) and add its directory to PATH."
+
+// CheckSqlldr verifies that a supported version of sqlldr is available on PATH and returns its major version.
+func CheckSqlldr() (int, error) {
+	sqlldrPath, err := exec.LookPath("sqlldr")
+	if err != nil {
+		return 0, fmt.Errorf("sqlldr not found in PATH: %w", err)
+	}
+	// sqlldr prints its banner (with the version) even when invoked without arguments, and exits with a non-zero code.
+	out, _ := exec.Command(sqlldrPath).CombinedOutput()
+	matches := regexp.MustCompile(`SQL\*Loader: Release (\d+)\.`).FindStringSubmatch(string(out))
+	if len(matches) < 2 {
+		return 0, fmt.Errorf("failed to find the version of sqlldr %q in its output: %s", sqlldrPath, string(out))
+	}
+	majorVersion, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, fmt.Errorf("parse version of sqlldr %q: %w", sqlldrPath, err)
+	}
+	if majorVersion < MIN_SQLLDR_MAJOR_VERSION {
+		return majorVersion, fmt.Errorf("sqlldr %q is of version %d, minimum supported version is %d",
+			sqlldrPath, majorVersion, MIN_SQLLDR_MAJOR_VERSION)
+	}
+	return majorVersion, nil
+}
+
 func CreateSqlldrDir(exportDir string) error {
 	if _, err := os.Stat(fmt.Sprintf("%s/sqlldr", exportDir)); os.IsNotExist(err) {
 		err = os.Mkdir(fmt.Sprintf("%s/sqlldr", exportDir), 0755)
@@ -47,8 +81,8 @@ func CreateSqlldrControlFile(exportDir string, tableName string, sqlldrConfig st
 	return sqlldrControlFilePath, nil
 }
 
-func CreateSqlldrLogFile(exportDir string, tableName string) (sqlldrLogFilePath string, sqlldrLogFile *os.File, err error) {
-	sqlldrLogFileName := fmt.Sprintf("%s.log", tableName)
+func CreateSqlldrLogFile(exportDir string, tableName string, fileName string) (sqlldrLogFilePath string, sqlldrLogFile *os.File, err error) {
+	sqlldrLogFileName := fmt.Sprintf("%s-%s.log", tableName, fileName)
 	sqlldrLogFilePath = fmt.Sprintf("%s/sqlldr/%s", exportDir, sqlldrLogFileName)
 	sqlldrLogFile, err = os.Create(sqlldrLogFilePath)
 	if err != nil {
@@ -57,6 +91,73 @@ func CreateSqlldrLogFile(exportDir string, tableName string) (sqlldrLogFilePath
 	return sqlldrLogFilePath, sqlldrLogFile, nil
 }
 
+func GetSqlldrBadFilePath(exportDir string, tableName string, fileName string) string {
+	return fmt.Sprintf("%s/sqlldr/%s-%s.bad", exportDir, tableName, fileName)
+}
+
+// RemoveSqlldrFiles removes the control, log and bad files of a sqlldr run once they are no longer needed.
+func RemoveSqlldrFiles(filePaths ...string) {
+	for _, filePath := range filePaths {
+		err := os.Remove(filePath)
+		if err != nil && !os.IsNotExist(err) {
+			log.Warnf("remove sqlldr file %q: %s", filePath, err)
+		}
+	}
+}
+
+type RejectedRecord struct {
+	Record string
+	Reason string
+}
+
+/*
+GetRejectedRecords returns the records written by sqlldr to the bad file along with the reasons of their
+rejection from the log file. The log file reports every rejected record as:
+
+	Record 3: Rejected - Error on table TEST.T1, column C1.
+	ORA-12899: value too large for column "TEST"."T1"."C1" (actual: 12, maximum: 10)
+
+in the same order as the records are written to the bad file.
+*/
+func GetRejectedRecords(badFilePath string, logFilePath string) ([]RejectedRecord, error) {
+	badFileContent, err := os.ReadFile(badFilePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read sqlldr bad file %q: %w", badFilePath, err)
+	}
+	logFileContent, err := os.ReadFile(logFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("read sqlldr log file %q: %w", logFilePath, err)
+	}
+
+	var reasons []string
+	rejectedPattern := regexp.MustCompile(`^Record \d+: Rejected - (.*)$`)
+	logLines := strings.Split(string(logFileContent), "\n")
+	for i, line := range logLines {
+		matches := rejectedPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+		reason := matches[1]
+		if i+1 < len(logLines) && strings.HasPrefix(logLines[i+1], "ORA-") {
+			reason = fmt.Sprintf("%s %s", reason, strings.TrimSpace(logLines[i+1]))
+		}
+		reasons = append(reasons, reason)
+	}
+
+	var rejectedRecords []RejectedRecord
+	for i, record := range strings.Split(strings.TrimSuffix(string(badFileContent), "\n"), "\n") {
+		reason := fmt.Sprintf("unknown, see %s", logFilePath)
+		if i < len(reasons) {
+			reason = reasons[i]
+		}
+		rejectedRecords = append(rejectedRecords, RejectedRecord{Record: record, Reason: reason})
+	}
+	return rejectedRecords, nil
+}
+
 func RunSqlldr(sqlldrArgs string, password string) (outbufStr string, errbufStr string, err error) {
 	var outbuf bytes.Buffer
 	var errbuf bytes.Buffer
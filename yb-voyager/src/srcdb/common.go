@@ -41,11 +41,16 @@ func getExportedDataFileList(tablesMetadata map[string]*utils.TableProgressMetad
 				tableMetadata.FinalFilePath, targetTableName)
 			continue
 		}
+		checksum, err := datafile.ComputeChecksum(tableMetadata.FinalFilePath)
+		if err != nil {
+			utils.ErrExit("compute checksum of the exported data file: %v", err)
+		}
 		fileEntry := &datafile.FileEntry{
 			FilePath:  filepath.Base(tableMetadata.FinalFilePath),
 			TableName: targetTableName,
 			RowCount:  tableMetadata.CountLiveRows,
 			FileSize:  -1, // Not available.
+			Checksum:  checksum,
 		}
 		fileEntries = append(fileEntries, fileEntry)
 	}
@@ -176,6 +176,7 @@ func (ms *MySQL) ExportDataPostProcessing(exportDir string, tablesProgressMetada
 		ExportDir:                  exportDir,
 		NullString:                 `\N`,
 		DataFileList:               getExportedDataFileList(tablesProgressMetadata),
+		ChecksumAlgorithm:          datafile.CHECKSUM_ALGORITHM_SHA256,
 		TableNameToExportedColumns: getOra2pgExportedColumnsMap(exportDir, tablesProgressMetadata),
 	}
 	dfd.Save()
@@ -182,6 +182,7 @@ func (ora *Oracle) ExportDataPostProcessing(exportDir string, tablesProgressMeta
 	dfd := datafile.Descriptor{
 		FileFormat:                 datafile.SQL,
 		DataFileList:               getExportedDataFileList(tablesProgressMetadata),
+		ChecksumAlgorithm:          datafile.CHECKSUM_ALGORITHM_SHA256,
 		Delimiter:                  "\t",
 		HasHeader:                  false,
 		ExportDir:                  exportDir,
@@ -221,6 +221,7 @@ func (pg *PostgreSQL) ExportDataPostProcessing(exportDir string, tablesProgressM
 	dfd := datafile.Descriptor{
 		FileFormat:                 datafile.TEXT,
 		DataFileList:               getExportedDataFileList(tablesProgressMetadata),
+		ChecksumAlgorithm:          datafile.CHECKSUM_ALGORITHM_SHA256,
 		Delimiter:                  "\t",
 		HasHeader:                  false,
 		ExportDir:                  exportDir,
@@ -219,6 +219,7 @@ func (yb *YugabyteDB) ExportDataPostProcessing(exportDir string, tablesProgressM
 	dfd := datafile.Descriptor{
 		FileFormat:                 datafile.TEXT,
 		DataFileList:               getExportedDataFileList(tablesProgressMetadata),
+		ChecksumAlgorithm:          datafile.CHECKSUM_ALGORITHM_SHA256,
 		Delimiter:                  "\t",
 		HasHeader:                  false,
 		ExportDir:                  exportDir,
@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"strings"
 	"sync"
 	"time"
 
@@ -33,10 +34,17 @@ var defaultSessionVars = []string{
 	"SET session_replication_role to replica",
 }
 
+// Connections idle for longer than this are pinged before use, and replaced if they turn out to be dead
+// (e.g. killed by an idle timeout on the target). 0 disables the health checks.
+var CONN_HEALTH_CHECK_INTERVAL = utils.GetEnvAsDuration("CONN_HEALTH_CHECK_INTERVAL", 30*time.Second, time.Second)
+
+const CONN_HEALTH_CHECK_TIMEOUT = 10 * time.Second
+
 type ConnectionParams struct {
-	NumConnections    int
-	ConnUriList       []string
-	SessionInitScript []string
+	NumConnections      int
+	ConnUriList         []string
+	SessionInitScript   []string
+	HealthCheckInterval time.Duration
 }
 
 type ConnectionPool struct {
@@ -45,6 +53,9 @@ type ConnectionPool struct {
 	conns                     chan *pgx.Conn
 	connIdToPreparedStmtCache map[uint32]map[string]bool // cache list of prepared statements per connection
 	nextUriIndex              int
+	applicationName           string
+	connIdToApplicationName   map[uint32]string // application_name last set on each connection
+	connIdToLastUsedTime      map[uint32]time.Time
 }
 
 func NewConnectionPool(params *ConnectionParams) *ConnectionPool {
@@ -52,6 +63,8 @@ func NewConnectionPool(params *ConnectionParams) *ConnectionPool {
 		params:                    params,
 		conns:                     make(chan *pgx.Conn, params.NumConnections),
 		connIdToPreparedStmtCache: make(map[uint32]map[string]bool, params.NumConnections),
+		connIdToApplicationName:   make(map[uint32]string, params.NumConnections),
+		connIdToLastUsedTime:      make(map[uint32]time.Time, params.NumConnections),
 	}
 	for i := 0; i < params.NumConnections; i++ {
 		pool.conns <- nil
@@ -74,21 +87,29 @@ func (pool *ConnectionPool) WithConn(fn func(*pgx.Conn) (bool, error)) error {
 			time.Sleep(2 * time.Second)
 			continue
 		}
+		if conn != nil && !pool.isHealthy(conn) {
+			log.Infof("reconnecting to replace the dead connection %d", conn.PgConn().PID())
+			pool.dropConn(conn)
+			conn = nil
+		}
 		if conn == nil {
 			conn, err = pool.createNewConnection()
 			if err != nil {
+				pool.conns <- nil
 				return err
 			}
 		}
+		pool.ensureApplicationName(conn)
 
 		retry, err = fn(conn)
 		if err != nil {
 			// On err, drop the connection and clear the prepared statement cache.
-			conn.Close(context.Background())
-			// assuming PID will still be available
-			delete(pool.connIdToPreparedStmtCache, conn.PgConn().PID())
+			pool.dropConn(conn)
 			pool.conns <- nil
 		} else {
+			pool.Lock()
+			pool.connIdToLastUsedTime[conn.PgConn().PID()] = time.Now()
+			pool.Unlock()
 			pool.conns <- conn
 		}
 	}
@@ -96,6 +117,87 @@ func (pool *ConnectionPool) WithConn(fn func(*pgx.Conn) (bool, error)) error {
 	return err
 }
 
+func (pool *ConnectionPool) dropConn(conn *pgx.Conn) {
+	conn.Close(context.Background())
+	// assuming PID will still be available
+	connId := conn.PgConn().PID()
+	pool.Lock()
+	defer pool.Unlock()
+	delete(pool.connIdToPreparedStmtCache, connId)
+	delete(pool.connIdToApplicationName, connId)
+	delete(pool.connIdToLastUsedTime, connId)
+}
+
+// isHealthy pings the connection if it has been idle for longer than the health check interval.
+func (pool *ConnectionPool) isHealthy(conn *pgx.Conn) bool {
+	if conn.IsClosed() {
+		return false
+	}
+	connId := conn.PgConn().PID()
+	if !pool.needsHealthCheck(connId, time.Now()) {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), CONN_HEALTH_CHECK_TIMEOUT)
+	defer cancel()
+	err := conn.Ping(ctx)
+	if err != nil {
+		log.Warnf("connection %d failed the health check: %s", connId, err)
+		return false
+	}
+	pool.Lock()
+	pool.connIdToLastUsedTime[connId] = time.Now()
+	pool.Unlock()
+	return true
+}
+
+func (pool *ConnectionPool) needsHealthCheck(connId uint32, now time.Time) bool {
+	if pool.params.HealthCheckInterval <= 0 {
+		return false
+	}
+	pool.Lock()
+	defer pool.Unlock()
+	lastUsedTime, ok := pool.connIdToLastUsedTime[connId]
+	return !ok || now.Sub(lastUsedTime) >= pool.params.HealthCheckInterval
+}
+
+// SetApplicationName sets the application_name of the connections of the pool. The existing
+// connections pick it up the next time they are used.
+func (pool *ConnectionPool) SetApplicationName(applicationName string) {
+	pool.Lock()
+	defer pool.Unlock()
+	pool.applicationName = applicationName
+}
+
+func (pool *ConnectionPool) ensureApplicationName(conn *pgx.Conn) {
+	pool.Lock()
+	applicationName := pool.applicationName
+	connId := conn.PgConn().PID()
+	alreadySet := pool.connIdToApplicationName[connId] == applicationName
+	pool.Unlock()
+	if applicationName == "" || alreadySet {
+		return
+	}
+	err := SetApplicationName(conn, applicationName)
+	if err != nil {
+		// Not worth failing the import for.
+		log.Warnf("failed to set application_name on connection %d: %s", connId, err)
+		return
+	}
+	pool.Lock()
+	pool.connIdToApplicationName[connId] = applicationName
+	pool.Unlock()
+}
+
+// SetApplicationName sets the application_name of the session, which identifies it in pg_stat_activity.
+func SetApplicationName(conn *pgx.Conn, applicationName string) error {
+	query := fmt.Sprintf("SET application_name TO '%s'", strings.ReplaceAll(applicationName, "'", "''"))
+	_, err := conn.Exec(context.Background(), query)
+	if err != nil {
+		return fmt.Errorf("run query %q: %w", query, err)
+	}
+	return nil
+}
+
 func (pool *ConnectionPool) PrepareStatement(conn *pgx.Conn, stmtName string, stmt string) error {
 	if pool.isStmtAlreadyPreparedOnConn(conn.PgConn().PID(), stmtName) {
 		return nil
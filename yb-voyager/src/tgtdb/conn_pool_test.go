@@ -0,0 +1,23 @@
+package tgtdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeedsHealthCheck(t *testing.T) {
+	pool := NewConnectionPool(&ConnectionParams{NumConnections: 1, HealthCheckInterval: time.Minute})
+	now := time.Now()
+	// connections not used yet are always checked
+	assert.True(t, pool.needsHealthCheck(1, now))
+
+	pool.connIdToLastUsedTime[1] = now.Add(-30 * time.Second)
+	assert.False(t, pool.needsHealthCheck(1, now))
+	pool.connIdToLastUsedTime[1] = now.Add(-time.Minute)
+	assert.True(t, pool.needsHealthCheck(1, now))
+
+	pool.params.HealthCheckInterval = 0
+	assert.False(t, pool.needsHealthCheck(1, now))
+}
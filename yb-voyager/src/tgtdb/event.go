@@ -23,6 +23,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
 )
@@ -34,10 +35,36 @@ type Event struct {
 	TableName  string             `json:"table_name"`
 	Key        map[string]*string `json:"key"`
 	Fields     map[string]*string `json:"fields"`
+	// target schema of the table, if the source schema is mapped to one, instead of the target schema of the batch
+	TargetSchemaName string `json:"-"`
+	// the event as exported, before its values are converted, if it is retained e.g. to be dead-lettered
+	ExportedEvent *Event `json:"-"`
 }
 
 var cachePreparedStmt = sync.Map{}
 
+// Clone returns a copy of the event, with its own copies of the keys and the values.
+func (e *Event) Clone() *Event {
+	cloneMap := func(m map[string]*string) map[string]*string {
+		if m == nil {
+			return nil
+		}
+		result := make(map[string]*string, len(m))
+		for k, v := range m {
+			if v != nil {
+				value := *v
+				v = &value
+			}
+			result[k] = v
+		}
+		return result
+	}
+	clone := *e
+	clone.Key = cloneMap(e.Key)
+	clone.Fields = cloneMap(e.Fields)
+	return &clone
+}
+
 func (e *Event) String() string {
 	return fmt.Sprintf("Event{vsn=%v, op=%v, schema=%v, table=%v, key=%v, fields=%v}",
 		e.Vsn, e.Op, e.SchemaName, e.TableName, e.Key, e.Fields)
@@ -106,6 +133,7 @@ func (event *Event) GetPreparedStmtName(targetSchema string) string {
 const insertTemplate = "INSERT INTO %s (%s) VALUES (%s)"
 const updateTemplate = "UPDATE %s SET %s WHERE %s"
 const deleteTemplate = "DELETE FROM %s WHERE %s"
+const multiRowInsertTemplate = "INSERT INTO %s (%s) VALUES %s"
 
 func (event *Event) getInsertStmt(targetSchema string) string {
 	tableName := event.getTableName(targetSchema)
@@ -207,6 +235,58 @@ func (event *Event) getPreparedDeleteStmt(targetSchema string) string {
 	return fmt.Sprintf(deleteTemplate, tableName, whereClause)
 }
 
+// getPreparedMultiRowInsertStmt returns a single parameterized INSERT statement (and its params) for
+// the given insert events. All the events are expected to be of the same table and set of columns.
+func getPreparedMultiRowInsertStmt(events []*Event, targetSchema string) (string, []interface{}) {
+	tableName := events[0].getTableName(targetSchema)
+	keys := utils.GetMapKeysSorted(events[0].Fields)
+	rowList := make([]string, 0, len(events))
+	params := make([]interface{}, 0, len(events)*len(keys))
+	for i, event := range events {
+		valueList := make([]string, 0, len(keys))
+		for pos := range keys {
+			valueList = append(valueList, fmt.Sprintf("$%d", i*len(keys)+pos+1))
+		}
+		rowList = append(rowList, fmt.Sprintf("(%s)", strings.Join(valueList, ", ")))
+		params = append(params, event.getInsertParams()...)
+	}
+	stmt := fmt.Sprintf(multiRowInsertTemplate, tableName, strings.Join(keys, ", "), strings.Join(rowList, ", "))
+	return stmt, params
+}
+
+// getOracleMultiRowInsertStmt returns a single INSERT ALL statement for the given insert events.
+// Oracle doesn't support multiple rows in the VALUES clause of an INSERT.
+func getOracleMultiRowInsertStmt(events []*Event, targetSchema string) string {
+	var stmt strings.Builder
+	stmt.WriteString("INSERT ALL")
+	for _, event := range events {
+		stmt.WriteString(" ")
+		stmt.WriteString(strings.TrimPrefix(event.getInsertStmt(targetSchema), "INSERT "))
+	}
+	stmt.WriteString(" SELECT 1 FROM DUAL")
+	return stmt.String()
+}
+
+// getMultiRowInsertStmt returns a single INSERT statement with the values of all the given insert events.
+// All the events are expected to be of the same table and set of columns.
+func getMultiRowInsertStmt(events []*Event, targetSchema string) string {
+	tableName := events[0].getTableName(targetSchema)
+	keys := utils.GetMapKeysSorted(events[0].Fields)
+	rowList := make([]string, 0, len(events))
+	for _, event := range events {
+		valueList := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if event.Fields[key] == nil {
+				valueList = append(valueList, "NULL")
+			} else {
+				valueList = append(valueList, *event.Fields[key])
+			}
+		}
+		rowList = append(rowList, fmt.Sprintf("(%s)", strings.Join(valueList, ", ")))
+	}
+	return fmt.Sprintf(multiRowInsertTemplate, tableName, strings.Join(keys, ", "), strings.Join(rowList, ", "))
+}
+
 func (event *Event) getInsertParams() []interface{} {
 	return getMapValuesForQuery(event.Fields)
 }
@@ -232,26 +312,34 @@ func getMapValuesForQuery(m map[string]*string) []interface{} {
 }
 
 func (event *Event) getTableName(targetSchema string) string {
-	tableName := strings.Join([]string{event.SchemaName, event.TableName}, ".")
+	if event.TargetSchemaName != "" {
+		targetSchema = event.TargetSchemaName
+	}
+	return GetEventTargetTableName(event.SchemaName, event.TableName, targetSchema)
+}
+
+// GetEventTargetTableName returns the name of the target table of the events of a source table,
+// as in EventBatch.EventCountsByTable.
+func GetEventTargetTableName(schemaName string, tableName string, targetSchema string) string {
 	if targetSchema != "" {
-		tableName = strings.Join([]string{targetSchema, event.TableName}, ".")
+		schemaName = targetSchema
 	}
-	return tableName
+	return strings.Join([]string{schemaName, tableName}, ".")
 }
 
 // ==============================================================================================================================
 type EventBatch struct {
-	Events     []*Event
-	ChanNo     int
-	EventCounts *EventCounter
+	Events             []*Event
+	ChanNo             int
+	EventCounts        *EventCounter
 	EventCountsByTable map[string]*EventCounter
 }
 
 func NewEventBatch(events []*Event, chanNo int, targetSchema string) *EventBatch {
 	batch := &EventBatch{
-		Events: events, 
-		ChanNo: chanNo, 
-		EventCounts : &EventCounter{}, 
+		Events:             events,
+		ChanNo:             chanNo,
+		EventCounts:        &EventCounter{},
 		EventCountsByTable: make(map[string]*EventCounter),
 	}
 	batch.updateCounts(targetSchema)
@@ -262,6 +350,17 @@ func (eb *EventBatch) GetLastVsn() int64 {
 	return eb.Events[len(eb.Events)-1].Vsn
 }
 
+// GetCorrelationID identifies the batch of events in the logs by its channel and the range of its VSNs,
+// e.g. ch3:1001-1050, see LOG_FIELD_EVENT_BATCH_ID.
+func (eb *EventBatch) GetCorrelationID() string {
+	return fmt.Sprintf("ch%d:%d-%d", eb.ChanNo, eb.Events[0].Vsn, eb.GetLastVsn())
+}
+
+// logger returns the log entry to log the execution of the batch with.
+func (eb *EventBatch) logger() *log.Entry {
+	return log.WithField(LOG_FIELD_EVENT_BATCH_ID, eb.GetCorrelationID())
+}
+
 func (eb *EventBatch) GetChannelMetadataUpdateQuery(migrationUUID uuid.UUID) string {
 	queryTemplate := `UPDATE %s 
 	SET 
@@ -273,11 +372,11 @@ func (eb *EventBatch) GetChannelMetadataUpdateQuery(migrationUUID uuid.UUID) str
 		migration_uuid='%s' AND channel_no=%d
 	`
 	return fmt.Sprintf(queryTemplate,
-		EVENT_CHANNELS_METADATA_TABLE_NAME, 
-		eb.GetLastVsn(), 
-		eb.EventCounts.NumInserts, 
-		eb.EventCounts.NumUpdates, 
-		eb.EventCounts.NumDeletes, 
+		EVENT_CHANNELS_METADATA_TABLE_NAME,
+		eb.GetLastVsn(),
+		eb.EventCounts.NumInserts,
+		eb.EventCounts.NumUpdates,
+		eb.EventCounts.NumDeletes,
 		migrationUUID, eb.ChanNo)
 }
 
@@ -292,14 +391,38 @@ func (eb *EventBatch) GetQueriesToUpdateEventStatsByTable(migrationUUID uuid.UUI
 		migration_uuid='%s' AND table_name='%s' AND channel_no=%d
 	`
 	return fmt.Sprintf(queryTemplate,
-		EVENTS_PER_TABLE_METADATA_TABLE_NAME, 
-		eb.EventCountsByTable[tableName].TotalEvents, 
-		eb.EventCountsByTable[tableName].NumInserts,  
-		eb.EventCountsByTable[tableName].NumUpdates,  
-		eb.EventCountsByTable[tableName].NumDeletes, 
+		EVENTS_PER_TABLE_METADATA_TABLE_NAME,
+		eb.EventCountsByTable[tableName].TotalEvents,
+		eb.EventCountsByTable[tableName].NumInserts,
+		eb.EventCountsByTable[tableName].NumUpdates,
+		eb.EventCountsByTable[tableName].NumDeletes,
 		migrationUUID, tableName, eb.ChanNo)
 }
 
+// SplitIntoRuns splits the events of the batch, preserving their order, into runs which can be applied
+// together. A run is either a sequence of (at most maxInsertsPerRun) inserts into the same table with
+// the same set of columns, or a single update/delete event.
+func (eb *EventBatch) SplitIntoRuns(targetSchema string, maxInsertsPerRun int) [][]*Event {
+	var runs [][]*Event
+	var lastRunKey string
+	for _, event := range eb.Events {
+		if event.Op != "c" {
+			runs = append(runs, []*Event{event})
+			lastRunKey = ""
+			continue
+		}
+		runKey := event.getTableName(targetSchema) + ":" + strings.Join(utils.GetMapKeysSorted(event.Fields), ",")
+		lastRun := len(runs) - 1
+		if runKey == lastRunKey && len(runs[lastRun]) < maxInsertsPerRun {
+			runs[lastRun] = append(runs[lastRun], event)
+		} else {
+			runs = append(runs, []*Event{event})
+		}
+		lastRunKey = runKey
+	}
+	return runs
+}
+
 func (eb *EventBatch) GetTableNames() []string {
 	return lo.Keys(eb.EventCountsByTable)
 }
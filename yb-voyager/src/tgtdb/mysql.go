@@ -0,0 +1,1101 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tgtdb
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+/*
+TargetMySQLDB imports the data into MySQL with LOAD DATA LOCAL INFILE, which requires the `local_infile`
+system variable to be enabled on the server. The database (--target-db-name) is the schema of the tables.
+
+The sessions run with the ANSI_QUOTES sql mode, so that the identifiers quoted with double quotes in the
+exported data and events are understood the same as on the other targets.
+*/
+type TargetMySQLDB struct {
+	tconf *TargetConf
+	db    *sql.DB
+	conn  *sql.Conn
+}
+
+const MYSQL_DEFAULT_PARALLELISM = 4
+
+// Errors of the MySQL server due to the data of the batch, which fail the same way on a retry.
+var mysqlNonRetryableErrorCodes = []uint16{
+	1048, // ER_BAD_NULL_ERROR
+	1054, // ER_BAD_FIELD_ERROR
+	1062, // ER_DUP_ENTRY
+	1146, // ER_NO_SUCH_TABLE
+	1261, // ER_WARN_TOO_FEW_RECORDS
+	1262, // ER_WARN_TOO_MANY_RECORDS
+	1264, // ER_WARN_DATA_OUT_OF_RANGE
+	1265, // WARN_DATA_TRUNCATED
+	1292, // ER_TRUNCATED_WRONG_VALUE
+	1366, // ER_TRUNCATED_WRONG_VALUE_FOR_FIELD
+	1406, // ER_DATA_TOO_LONG
+	1452, // ER_NO_REFERENCED_ROW_2
+}
+
+var mysqlValueConverterSuite = getMySQLValueConverterSuite()
+
+func getMySQLValueConverterSuite() map[string]ConverterFn {
+	suite := map[string]ConverterFn{}
+	// The dates, times, and decimals are formatted the same as for YugabyteDB.
+	for _, typ := range []string{
+		"io.debezium.time.Date",
+		"io.debezium.time.Timestamp",
+		"io.debezium.time.MicroTimestamp",
+		"io.debezium.time.NanoTimestamp",
+		"io.debezium.time.Time",
+		"io.debezium.time.MicroTime",
+		"org.apache.kafka.connect.data.Decimal",
+		"io.debezium.data.VariableScaleDecimal",
+	} {
+		suite[typ] = ybValueConverterSuite[typ]
+	}
+	suite["STRING"] = func(columnValue string, formatIfRequired bool) (string, error) {
+		if formatIfRequired {
+			return quoteMySQLString(columnValue), nil
+		}
+		return columnValue, nil
+	}
+	suite["BYTES"] = func(columnValue string, formatIfRequired bool) (string, error) {
+		decodedBytes, err := base64.StdEncoding.DecodeString(columnValue)
+		if err != nil {
+			return columnValue, fmt.Errorf("decoding base64 string: %v", err)
+		}
+		if formatIfRequired {
+			return fmt.Sprintf("X'%x'", decodedBytes), nil
+		}
+		// escaped as in the data files loaded with LOAD DATA
+		return escapeMySQLLoadDataValue(string(decodedBytes)), nil
+	}
+	return suite
+}
+
+func newTargetMySQLDB(tconf *TargetConf) TargetDB {
+	return &TargetMySQLDB{tconf: tconf}
+}
+
+func (tdb *TargetMySQLDB) Init() error {
+	cfg, err := tdb.getConfig()
+	if err != nil {
+		return err
+	}
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return fmt.Errorf("open connection to target db: %w", err)
+	}
+	tdb.db = sql.OpenDB(connector)
+	tdb.conn, err = tdb.db.Conn(context.Background())
+	if err != nil {
+		return fmt.Errorf("connect to target db: %w", err)
+	}
+
+	checkSchemaExistsQuery := "SELECT COUNT(*) FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ?"
+	var cntSchemaName int
+	if err = tdb.conn.QueryRowContext(context.Background(), checkSchemaExistsQuery, tdb.tconf.Schema).Scan(&cntSchemaName); err != nil {
+		err = fmt.Errorf("run query %q on target %q to check schema exists: %s", checkSchemaExistsQuery, tdb.tconf.Host, err)
+	} else if cntSchemaName == 0 {
+		err = fmt.Errorf("database '%s' does not exist in target", tdb.tconf.Schema)
+	}
+	return err
+}
+
+func (tdb *TargetMySQLDB) getConfig() (*mysql.Config, error) {
+	var cfg *mysql.Config
+	if tdb.tconf.Uri != "" {
+		var err error
+		cfg, err = mysql.ParseDSN(tdb.tconf.Uri)
+		if err != nil {
+			return nil, fmt.Errorf("parse target db uri: %w", err)
+		}
+	} else {
+		cfg = mysql.NewConfig()
+		cfg.User = tdb.tconf.User
+		cfg.Passwd = tdb.tconf.Password
+		cfg.Net = "tcp"
+		cfg.Addr = fmt.Sprintf("%s:%d", tdb.tconf.Host, tdb.tconf.Port)
+		cfg.DBName = tdb.tconf.DBName
+		switch tdb.tconf.SSLMode {
+		case "disable":
+			cfg.TLSConfig = "false"
+		case "prefer", "allow", "":
+			cfg.TLSConfig = "preferred"
+		case "require":
+			cfg.TLSConfig = "skip-verify"
+		case "verify-ca", "verify-full":
+			tlsConf, err := tdb.createTLSConf()
+			if err != nil {
+				return nil, err
+			}
+			err = mysql.RegisterTLSConfig("target", tlsConf)
+			if err != nil {
+				return nil, fmt.Errorf("register TLS config: %w", err)
+			}
+			cfg.TLSConfig = "target"
+		default:
+			return nil, fmt.Errorf("invalid sslmode %q", tdb.tconf.SSLMode)
+		}
+	}
+	if cfg.Params == nil {
+		cfg.Params = map[string]string{}
+	}
+	cfg.Params["sql_mode"] = "CONCAT(@@sql_mode, ',ANSI_QUOTES')"
+	return cfg, nil
+}
+
+func (tdb *TargetMySQLDB) createTLSConf() (*tls.Config, error) {
+	if tdb.tconf.SSLRootCert == "" {
+		return nil, fmt.Errorf("root certificate needed for verify-ca and verify-full SSL modes")
+	}
+	pem, err := os.ReadFile(tdb.tconf.SSLRootCert)
+	if err != nil {
+		return nil, fmt.Errorf("read SSL root certificate: %w", err)
+	}
+	rootCertPool := x509.NewCertPool()
+	if ok := rootCertPool.AppendCertsFromPEM(pem); !ok {
+		return nil, fmt.Errorf("append SSL root certificate %q", tdb.tconf.SSLRootCert)
+	}
+	var clientCerts []tls.Certificate
+	if tdb.tconf.SSLCertPath != "" && tdb.tconf.SSLKey != "" {
+		cert, err := tls.LoadX509KeyPair(tdb.tconf.SSLCertPath, tdb.tconf.SSLKey)
+		if err != nil {
+			return nil, fmt.Errorf("read SSL key pair: %w", err)
+		}
+		clientCerts = append(clientCerts, cert)
+	}
+	if tdb.tconf.SSLMode == "verify-ca" {
+		return &tls.Config{RootCAs: rootCertPool, Certificates: clientCerts, InsecureSkipVerify: true}, nil
+	}
+	return &tls.Config{RootCAs: rootCertPool, Certificates: clientCerts, ServerName: tdb.tconf.Host}, nil
+}
+
+func (tdb *TargetMySQLDB) Finalize() {
+	if tdb.conn == nil {
+		return
+	}
+	err := tdb.conn.Close()
+	if err != nil {
+		log.Errorf("Failed to close connection to the target database: %v", err)
+	}
+	tdb.conn = nil
+}
+
+func (tdb *TargetMySQLDB) InitConnPool() error {
+	if tdb.tconf.Parallelism == -1 {
+		tdb.tconf.Parallelism = MYSQL_DEFAULT_PARALLELISM
+		utils.PrintAndLog("Using %d parallel jobs by default. Use --parallel-jobs to specify a custom value", tdb.tconf.Parallelism)
+	} else {
+		utils.PrintAndLog("Using %d parallel jobs", tdb.tconf.Parallelism)
+	}
+	tdb.db.SetMaxIdleConns(tdb.tconf.Parallelism + 1)
+	tdb.db.SetMaxOpenConns(tdb.tconf.Parallelism + 1)
+	return nil
+}
+
+func (tdb *TargetMySQLDB) WithConn(fn func(*sql.Conn) (bool, error)) error {
+	var err error
+	retry := true
+
+	for retry {
+		var maxAttempts = 5
+		var conn *sql.Conn
+		for attempt := 1; attempt <= maxAttempts; attempt++ {
+			conn, err = tdb.db.Conn(context.Background())
+			if err == nil {
+				break
+			}
+
+			if attempt < maxAttempts {
+				log.Warnf("Connection pool is busy. Sleeping for 2 seconds: %s", err)
+				time.Sleep(2 * time.Second)
+				continue
+			}
+		}
+
+		if conn == nil {
+			return fmt.Errorf("failed to get connection from target db: %w", err)
+		}
+
+		retry, err = fn(conn)
+		conn.Close()
+
+		if retry {
+			time.Sleep(2 * time.Second)
+		}
+	}
+	return err
+}
+
+func (tdb *TargetMySQLDB) GetVersion() string {
+	var version string
+	query := "SELECT VERSION()"
+	err := tdb.conn.QueryRowContext(context.Background(), query).Scan(&version)
+	if err != nil {
+		utils.ErrExit("run query %q on target: %s", query, err)
+	}
+	return version
+}
+
+func (tdb *TargetMySQLDB) CreateVoyagerSchema() error {
+	cmds := []string{
+		fmt.Sprintf(`CREATE DATABASE IF NOT EXISTS %s`, BATCH_METADATA_TABLE_SCHEMA),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			data_file_name VARCHAR(250),
+			batch_number INT,
+			schema_name VARCHAR(250),
+			table_name VARCHAR(250),
+			rows_imported BIGINT,
+			PRIMARY KEY (data_file_name, batch_number, schema_name, table_name)
+		)`, BATCH_METADATA_TABLE_NAME),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			migration_uuid VARCHAR(36),
+			channel_no INT,
+			last_applied_vsn BIGINT,
+			num_inserts BIGINT,
+			num_deletes BIGINT,
+			num_updates BIGINT,
+			PRIMARY KEY (migration_uuid, channel_no))`, EVENT_CHANNELS_METADATA_TABLE_NAME),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			migration_uuid VARCHAR(36),
+			table_name VARCHAR(250),
+			channel_no INT,
+			total_events BIGINT,
+			num_inserts BIGINT,
+			num_deletes BIGINT,
+			num_updates BIGINT,
+			PRIMARY KEY (migration_uuid, table_name, channel_no))`, EVENTS_PER_TABLE_METADATA_TABLE_NAME),
+	}
+	for _, cmd := range cmds {
+		log.Infof("Executing on target: [%s]", cmd)
+		_, err := tdb.conn.ExecContext(context.Background(), cmd)
+		if err != nil {
+			return fmt.Errorf("create ybvoyager schema on target: %w", err)
+		}
+	}
+	return nil
+}
+
+func (tdb *TargetMySQLDB) qualifyTableName(tableName string) string {
+	if len(strings.Split(tableName, ".")) != 2 {
+		tableName = fmt.Sprintf("%s.%s", tdb.tconf.Schema, tableName)
+	}
+	return tableName
+}
+
+func (tdb *TargetMySQLDB) CleanFileImportState(filePath, tableName string) error {
+	schemaName := tdb.tconf.GetTargetSchemaName(tableName)
+	cmd := fmt.Sprintf(
+		`DELETE FROM %s WHERE data_file_name = ? AND schema_name = ? AND table_name = ?`, BATCH_METADATA_TABLE_NAME)
+	res, err := tdb.conn.ExecContext(context.Background(), cmd, filePath, schemaName, tableName)
+	if err != nil {
+		return fmt.Errorf("remove %q related entries from %s: %w", tableName, BATCH_METADATA_TABLE_NAME, err)
+	}
+	rowsAffected, _ := res.RowsAffected()
+	log.Infof("query: [%s] => rows affected %v", cmd, rowsAffected)
+	return nil
+}
+
+func (tdb *TargetMySQLDB) GetNonEmptyTables(tables []string) []string {
+	result := []string{}
+
+	for _, table := range tables {
+		log.Infof("Checking if table %q is empty.", table)
+		var tmp int
+		stmt := fmt.Sprintf("SELECT 1 FROM %s LIMIT 1", tdb.qualifyTableName(table))
+		err := tdb.conn.QueryRowContext(context.Background(), stmt).Scan(&tmp)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			utils.ErrExit("failed to check whether table %q empty: %s", table, err)
+		}
+		result = append(result, table)
+	}
+	log.Infof("non empty tables: %v", result)
+	return result
+}
+
+func (tdb *TargetMySQLDB) GetMissingTables(tables []string) []string {
+	result := []string{}
+
+	for _, table := range tables {
+		log.Infof("Checking if table %q exists.", table)
+		schemaName := tdb.tconf.GetTargetSchemaName(table)
+		parts := strings.Split(table, ".")
+		tableName := strings.Trim(parts[len(parts)-1], `"`)
+		count := 0
+		stmt := "SELECT COUNT(*) FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?"
+		err := tdb.conn.QueryRowContext(context.Background(), stmt, strings.Trim(schemaName, `"`), tableName).Scan(&count)
+		if err != nil {
+			utils.ErrExit("run query %q on target: %s", stmt, err)
+		}
+		if count == 0 {
+			result = append(result, table)
+		}
+	}
+	return result
+}
+
+// newMySQLCopyError wraps the MySQL error in the chain of err, if any, in a CopyError classified by its error number.
+func newMySQLCopyError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return err
+	}
+	retryable := !slices.Contains(mysqlNonRetryableErrorCodes, mysqlErr.Number)
+	return &CopyError{Code: strconv.Itoa(int(mysqlErr.Number)), Retryable: retryable, Err: err}
+}
+
+func (tdb *TargetMySQLDB) IsNonRetryableCopyError(err error) bool {
+	copyErr, ok := getCopyError(err)
+	return ok && !copyErr.Retryable
+}
+
+var mysqlLoadDataReaderNum int64
+
+func (tdb *TargetMySQLDB) ImportBatch(batch Batch, args *ImportBatchArgs, exportDir string) (int64, error) {
+	var rowsAffected int64
+	err := tdb.WithConn(func(conn *sql.Conn) (bool, error) {
+		var err error
+		rowsAffected, err = tdb.importBatch(conn, batch, args)
+		return false, err
+	})
+	if err != nil {
+		err = newMySQLCopyError(err)
+	}
+	return rowsAffected, err
+}
+
+// importBatch loads the batch with LOAD DATA LOCAL INFILE, in the same transaction in which it is recorded as imported.
+func (tdb *TargetMySQLDB) importBatch(conn *sql.Conn, batch Batch, args *ImportBatchArgs) (rowsAffected int64, err error) {
+	var file io.ReadCloser
+	file, err = batch.Open()
+	if err != nil {
+		return 0, fmt.Errorf("open batch file %q: %w", batch.GetFilePath(), err)
+	}
+	defer file.Close()
+
+	ctx := context.Background()
+	var tx *sql.Tx
+	tx, err = conn.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		var err2 error
+		if err != nil {
+			err2 = tx.Rollback()
+			if err2 != nil {
+				rowsAffected = 0
+				err = fmt.Errorf("rollback transaction: %w (while processing %s)", err2, err)
+			}
+		} else {
+			err2 = tx.Commit()
+			if err2 != nil {
+				rowsAffected = 0
+				err = fmt.Errorf("commit transaction: %w", err2)
+			}
+		}
+	}()
+
+	var alreadyImported bool
+	alreadyImported, rowsAffected, err = tdb.isBatchAlreadyImported(tx, batch)
+	if err != nil {
+		return 0, err
+	}
+	if alreadyImported {
+		batch.RecordAlreadyImported()
+		return rowsAffected, nil
+	}
+
+	var reader io.Reader = file
+	if args.FileFormat == "csv" {
+		csvReader := newMySQLCSVReader(file, args)
+		defer csvReader.Close()
+		reader = csvReader
+	}
+	// The driver reads the batch from the registered reader when the server asks for the "file".
+	readerName := fmt.Sprintf("batch_%d", atomic.AddInt64(&mysqlLoadDataReaderNum, 1))
+	mysql.RegisterReaderHandler(readerName, func() io.Reader { return reader })
+	defer mysql.DeregisterReaderHandler(readerName)
+
+	stmt := getMySQLLoadDataStmt(tdb.tconf.Schema, args, readerName)
+	batchLogger(batch).Infof("loading batch %q of table %s: %s", batch.GetFilePath(), batch.GetTableName(), stmt)
+	var res sql.Result
+	res, err = tx.ExecContext(ctx, stmt)
+	if err != nil {
+		return 0, fmt.Errorf("load data of batch %q: %w", batch.GetFilePath(), err)
+	}
+	rowsAffected, err = res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get rows affected by loading batch %q: %w", batch.GetFilePath(), err)
+	}
+	err = checkMySQLLoadDataResult(ctx, tx, batch, rowsAffected)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, batch.GetQueryToRecordEntryInDB(rowsAffected))
+	if err != nil {
+		return 0, fmt.Errorf("record entry in DB for batch %q: %w", batch.GetFilePath(), err)
+	}
+	return rowsAffected, nil
+}
+
+/*
+checkMySQLLoadDataResult fails the load of the batch if any of its rows were not loaded as they are. LOAD DATA LOCAL
+doesn't fail on the rows with a duplicate key, or with too few or too many values, or the values not fitting the
+columns; it skips or adjusts the rows, with a warning for each of them.
+*/
+func checkMySQLLoadDataResult(ctx context.Context, tx *sql.Tx, batch Batch, rowsAffected int64) error {
+	rows, err := tx.QueryContext(ctx, "SHOW WARNINGS")
+	if err != nil {
+		return fmt.Errorf("fetch warnings of loading batch %q: %w", batch.GetFilePath(), err)
+	}
+	defer rows.Close()
+	var warnings []*mysql.MySQLError
+	for rows.Next() {
+		var level string
+		warning := &mysql.MySQLError{}
+		err = rows.Scan(&level, &warning.Number, &warning.Message)
+		if err != nil {
+			return fmt.Errorf("scan warnings of loading batch %q: %w", batch.GetFilePath(), err)
+		}
+		warnings = append(warnings, warning)
+	}
+	if rows.Err() != nil {
+		return fmt.Errorf("fetch warnings of loading batch %q: %w", batch.GetFilePath(), rows.Err())
+	}
+	if len(warnings) > 0 {
+		// classified as retryable or not by the code of the first warning
+		return fmt.Errorf("load data of batch %q: %d rows loaded, out of %d, with %d warnings, the first being: %w",
+			batch.GetFilePath(), rowsAffected, batch.GetRecordCount(), len(warnings), warnings[0])
+	}
+	if rowsAffected != batch.GetRecordCount() {
+		return &CopyError{Retryable: false, Err: fmt.Errorf("load data of batch %q: %d rows loaded, expected %d",
+			batch.GetFilePath(), rowsAffected, batch.GetRecordCount())}
+	}
+	return nil
+}
+
+func (tdb *TargetMySQLDB) isBatchAlreadyImported(tx *sql.Tx, batch Batch) (bool, int64, error) {
+	var rowsImported int64
+	query := batch.GetQueryIsBatchAlreadyImported()
+	err := tx.QueryRowContext(context.Background(), query).Scan(&rowsImported)
+	if err == nil {
+		batchLogger(batch).Infof("%v rows from %q are already imported", rowsImported, batch.GetFilePath())
+		return true, rowsImported, nil
+	}
+	if err == sql.ErrNoRows {
+		batchLogger(batch).Infof("%q is not imported yet", batch.GetFilePath())
+		return false, 0, nil
+	}
+	return false, 0, fmt.Errorf("check if %s is already imported: %w", batch.GetFilePath(), err)
+}
+
+/*
+getMySQLLoadDataStmt returns the LOAD DATA statement loading the batch from the registered reader.
+
+The TEXT format is the default format of LOAD DATA: the values are escaped with backslashes and \N is NULL.
+A custom null string is turned into NULL with the columns known. For CSV, the NULLs are written as the unquoted
+word NULL by newMySQLCSVReader.
+*/
+func getMySQLLoadDataStmt(schema string, args *ImportBatchArgs, readerName string) string {
+	var stmt strings.Builder
+	fmt.Fprintf(&stmt, "LOAD DATA LOCAL INFILE 'Reader::%s'", readerName)
+	tableName := args.TableName
+	if len(strings.Split(tableName, ".")) != 2 {
+		tableName = fmt.Sprintf("%s.%s", schema, tableName)
+	}
+	fmt.Fprintf(&stmt, " INTO TABLE %s CHARACTER SET utf8mb4", tableName)
+
+	delimiter := args.Delimiter
+	if delimiter == "" {
+		delimiter = "\t"
+		if args.FileFormat == "csv" {
+			delimiter = ","
+		}
+	}
+	fmt.Fprintf(&stmt, " FIELDS TERMINATED BY %s", quoteMySQLString(delimiter))
+	nullString := args.NullString
+	if args.FileFormat == "csv" {
+		quoteChar := args.QuoteChar
+		if quoteChar == 0 {
+			quoteChar = '"'
+		}
+		fmt.Fprintf(&stmt, " OPTIONALLY ENCLOSED BY %s", quoteMySQLString(string(quoteChar)))
+		// LOAD DATA reads a doubled enclosing character as one, as COPY does with the default escape
+		escape := ""
+		if args.EscapeChar != 0 && args.EscapeChar != quoteChar {
+			escape = string(args.EscapeChar)
+		}
+		fmt.Fprintf(&stmt, " ESCAPED BY %s", quoteMySQLString(escape))
+	} else {
+		stmt.WriteString(` ESCAPED BY '\\'`)
+		if nullString == `\N` {
+			nullString = ""
+		}
+	}
+	stmt.WriteString(` LINES TERMINATED BY '\n'`)
+	if args.HasHeader {
+		stmt.WriteString(" IGNORE 1 LINES")
+	}
+	if len(args.Columns) == 0 {
+		return stmt.String()
+	}
+	if args.FileFormat == "csv" || nullString == "" {
+		fmt.Fprintf(&stmt, " (%s)", strings.Join(args.Columns, ", "))
+		return stmt.String()
+	}
+	variables := make([]string, 0, len(args.Columns))
+	setClauses := make([]string, 0, len(args.Columns))
+	for i, column := range args.Columns {
+		variables = append(variables, fmt.Sprintf("@v%d", i+1))
+		setClauses = append(setClauses, fmt.Sprintf("%s = NULLIF(@v%d, %s)", column, i+1, quoteMySQLString(nullString)))
+	}
+	fmt.Fprintf(&stmt, " (%s) SET %s", strings.Join(variables, ", "), strings.Join(setClauses, ", "))
+	return stmt.String()
+}
+
+func quoteMySQLString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "'", "''")
+	return "'" + s + "'"
+}
+
+/*
+newMySQLCSVReader returns the CSV batch as it is to be read by LOAD DATA, which can't tell an unquoted empty
+value (NULL for COPY) from a quoted one. The unquoted values which are NULL for COPY, i.e. the null string
+(empty by default), are written as the unquoted word NULL, which is NULL for LOAD DATA. An unquoted word NULL,
+which is a string for COPY, is quoted. The reader is to be closed for the rewrite to stop when it is not read to the end.
+*/
+func newMySQLCSVReader(r io.Reader, args *ImportBatchArgs) *io.PipeReader {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(rewriteMySQLCSVNulls(r, pw, args))
+	}()
+	return pr
+}
+
+func rewriteMySQLCSVNulls(r io.Reader, w io.Writer, args *ImportBatchArgs) error {
+	delimiter := byte(',')
+	if args.Delimiter != "" {
+		delimiter = args.Delimiter[0]
+	}
+	quoteChar := args.QuoteChar
+	if quoteChar == 0 {
+		quoteChar = '"'
+	}
+	escapeChar := args.EscapeChar
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	var value []byte // the unquoted value of the current field
+	quoted, inQuotes, lineStarted := false, false, false
+	endField := func() {
+		if !quoted {
+			switch string(value) {
+			case args.NullString:
+				bw.WriteString("NULL")
+			case "NULL":
+				bw.WriteString(string(quoteChar) + "NULL" + string(quoteChar))
+			default:
+				bw.Write(value)
+			}
+		}
+		value = value[:0]
+		quoted = false
+	}
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			if lineStarted {
+				endField()
+			}
+			return bw.Flush()
+		}
+		if err != nil {
+			return err
+		}
+		lineStarted = true
+		switch {
+		case inQuotes && c == escapeChar && escapeChar != 0 && escapeChar != quoteChar:
+			bw.WriteByte(c)
+			c, err = br.ReadByte()
+			if err != nil && err != io.EOF {
+				return err
+			}
+			if err == nil {
+				bw.WriteByte(c)
+			}
+		case c == quoteChar && (quoted || len(value) == 0):
+			quoted = true
+			inQuotes = !inQuotes
+			bw.WriteByte(c)
+		case inQuotes:
+			bw.WriteByte(c)
+		case c == delimiter || c == '\n':
+			endField()
+			bw.WriteByte(c)
+			lineStarted = c != '\n'
+		case quoted:
+			bw.WriteByte(c)
+		default:
+			value = append(value, c)
+		}
+	}
+}
+
+// escapeMySQLLoadDataValue escapes the characters special to LOAD DATA with the default FIELDS ESCAPED BY '\\'.
+func escapeMySQLLoadDataValue(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case 0:
+			sb.WriteString(`\0`)
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
+
+// IfRequiredQuoteColumnNames quotes all the column names, as the names can be reserved words in MySQL.
+// The column names are case insensitive in MySQL, and the quoted ones are understood with ANSI_QUOTES.
+func (tdb *TargetMySQLDB) IfRequiredQuoteColumnNames(tableName string, columns []string) ([]string, error) {
+	result := make([]string, len(columns))
+	for i, colName := range columns {
+		colName = strings.Trim(colName, `"`)
+		result[i] = `"` + strings.ReplaceAll(colName, `"`, `""`) + `"`
+	}
+	return result, nil
+}
+
+func (tdb *TargetMySQLDB) GetColumnTypes(tableName string, columns []string) ([]ColumnType, error) {
+	return nil, fmt.Errorf("fetching the column types is not supported for MySQL")
+}
+
+// GetGeneratedColumns returns the VIRTUAL and STORED generated columns of the table.
+func (tdb *TargetMySQLDB) GetGeneratedColumns(tableName string) ([]string, error) {
+	schemaName := tdb.tconf.GetTargetSchemaName(tableName)
+	parts := strings.Split(tableName, ".")
+	stmt := `SELECT COLUMN_NAME FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+		AND EXTRA IN ('VIRTUAL GENERATED', 'STORED GENERATED') ORDER BY ORDINAL_POSITION`
+	rows, err := tdb.conn.QueryContext(context.Background(), stmt, strings.Trim(schemaName, `"`), strings.Trim(parts[len(parts)-1], `"`))
+	if err != nil {
+		return nil, fmt.Errorf("run query %q on target for table %s: %w", stmt, tableName, err)
+	}
+	defer rows.Close()
+	var result []string
+	for rows.Next() {
+		var colName string
+		err = rows.Scan(&colName)
+		if err != nil {
+			return nil, fmt.Errorf("scan generated columns of table %s: %w", tableName, err)
+		}
+		result = append(result, colName)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("fetch generated columns of table %s: %w", tableName, rows.Err())
+	}
+	return result, nil
+}
+
+// Number of rows inserted by a single INSERT statement in multi-row apply statement mode.
+const MYSQL_MAX_INSERTS_PER_RUN = 100
+
+func (tdb *TargetMySQLDB) ExecuteBatch(migrationUUID uuid.UUID, batch *EventBatch) error {
+	batch.logger().Infof("executing batch of %d events", len(batch.Events))
+	start := time.Now()
+	err := tdb.WithConn(func(conn *sql.Conn) (bool, error) {
+		tx, err := conn.BeginTx(context.Background(), nil)
+		if err != nil {
+			return false, fmt.Errorf("begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		for _, run := range batch.SplitIntoRuns(tdb.tconf.Schema, MYSQL_MAX_INSERTS_PER_RUN) {
+			if run[0].Op == "c" && len(run) > 1 && tdb.tconf.ApplyStatementMode == APPLY_STATEMENT_MODE_MULTI_ROW {
+				_, err = tx.Exec(getMultiRowInsertStmt(run, tdb.tconf.Schema))
+				if err != nil {
+					log.Errorf("error executing stmt for events with vsn(%d..%d): %v", run[0].Vsn, run[len(run)-1].Vsn, err)
+					return false, fmt.Errorf("error executing stmt for events with vsn(%d..%d): %w", run[0].Vsn, run[len(run)-1].Vsn, err)
+				}
+				continue
+			}
+			for _, event := range run {
+				stmt := event.GetSQLStmt(tdb.tconf.Schema)
+				_, err = tx.Exec(stmt)
+				if err != nil {
+					log.Errorf("error executing stmt for event with vsn(%d): %v", event.Vsn, err)
+					return false, fmt.Errorf("error executing stmt for event with vsn(%d): %w", event.Vsn, err)
+				}
+			}
+		}
+
+		updateVsnQuery := batch.GetChannelMetadataUpdateQuery(migrationUUID)
+		res, err := tx.Exec(updateVsnQuery)
+		if err != nil {
+			log.Errorf("error executing stmt: %v", err)
+			return false, fmt.Errorf("failed to update vsn on target db via query-%s: %w", updateVsnQuery, err)
+		} else if rowsAffected, err := res.RowsAffected(); rowsAffected == 0 || err != nil {
+			log.Errorf("error executing stmt: %v, rowsAffected: %v", err, rowsAffected)
+			return false, fmt.Errorf("failed to update vsn on target db via query-%s: %w, rowsAffected: %v",
+				updateVsnQuery, err, rowsAffected)
+		}
+
+		for _, tableName := range batch.GetTableNames() {
+			tableName := tdb.qualifyTableName(tableName)
+			updatePerTableEvents := batch.GetQueriesToUpdateEventStatsByTable(migrationUUID, tableName)
+			res, err = tx.Exec(updatePerTableEvents)
+			if err != nil {
+				log.Errorf("error executing stmt: %v", err)
+				return false, fmt.Errorf("failed to update per table events on target db via query-%s: %w", updatePerTableEvents, err)
+			} else if rowsAffected, err := res.RowsAffected(); rowsAffected == 0 || err != nil {
+				log.Errorf("error executing stmt: %v, rowsAffected: %v", err, rowsAffected)
+				return false, fmt.Errorf("failed to update per table events on target db via query-%s: %w, rowsAffected: %v",
+					updatePerTableEvents, err, rowsAffected)
+			}
+		}
+
+		if err = tx.Commit(); err != nil {
+			return false, fmt.Errorf("failed to commit transaction : %w", err)
+		}
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("error executing batch: %w", err)
+	}
+	elapsed := time.Since(start)
+	batch.logger().Infof("executed batch of %d events in %s using %q apply statement mode (%.2f events/sec)",
+		len(batch.Events), elapsed, tdb.tconf.ApplyStatementMode, float64(len(batch.Events))/elapsed.Seconds())
+	return nil
+}
+
+func (tdb *TargetMySQLDB) GetDebeziumValueConverterSuite() map[string]ConverterFn {
+	return mysqlValueConverterSuite
+}
+
+func (tdb *TargetMySQLDB) clearMigrationStateFromTable(conn *sql.Conn, tableName string, migrationUUID uuid.UUID) error {
+	stmt := fmt.Sprintf("DELETE FROM %s where migration_uuid='%s'", tableName, migrationUUID)
+	res, err := conn.ExecContext(context.Background(), stmt)
+	if err != nil {
+		return fmt.Errorf("error executing stmt - %v: %w", stmt, err)
+	}
+	rowsAffected, _ := res.RowsAffected()
+	log.Infof("Query: %s ==> Rows affected: %d", stmt, rowsAffected)
+	return nil
+}
+
+func (tdb *TargetMySQLDB) initChannelMetaInfo(conn *sql.Conn, migrationUUID uuid.UUID, numChans int) error {
+	// if there are >0 rows, then skip because already been inited.
+	rowsStmt := fmt.Sprintf(
+		"SELECT count(*) FROM %s where migration_uuid='%s'", EVENT_CHANNELS_METADATA_TABLE_NAME, migrationUUID)
+	var rowCount int64
+	err := conn.QueryRowContext(context.Background(), rowsStmt).Scan(&rowCount)
+	if err != nil {
+		return fmt.Errorf("error executing stmt - %v: %w", rowsStmt, err)
+	}
+	if rowCount > 0 {
+		log.Info("event channels meta info already created. Skipping init.")
+		return nil
+	}
+	tx, err := conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("error creating tx: %w", err)
+	}
+	defer tx.Rollback()
+	for c := 0; c < numChans; c++ {
+		insertStmt := fmt.Sprintf("INSERT INTO %s VALUES ('%s', %d, -1, %d, %d, %d)", EVENT_CHANNELS_METADATA_TABLE_NAME, migrationUUID, c, 0, 0, 0)
+		_, err := tx.Exec(insertStmt)
+		if err != nil {
+			return fmt.Errorf("error executing stmt - %v: %w", insertStmt, err)
+		}
+		log.Infof("created channels meta info: %s;", insertStmt)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing tx: %w", err)
+	}
+	return nil
+}
+
+func (tdb *TargetMySQLDB) initEventStatByTableMetainfo(conn *sql.Conn, migrationUUID uuid.UUID, tableNames []string, numChans int) error {
+	tx, err := conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("error creating tx: %w", err)
+	}
+	defer tx.Rollback()
+	for _, tableName := range tableNames {
+		tableName = tdb.qualifyTableName(tableName)
+		var rowCount int64
+		rowsStmt := fmt.Sprintf(
+			"SELECT count(*) FROM %s where migration_uuid='%s' AND table_name='%s'",
+			EVENTS_PER_TABLE_METADATA_TABLE_NAME, migrationUUID, tableName)
+		err := tx.QueryRow(rowsStmt).Scan(&rowCount)
+		if err != nil {
+			return fmt.Errorf("error executing stmt - %v: %w", rowsStmt, err)
+		}
+		if rowCount > 0 {
+			log.Info("table wise meta info already created. Skipping init.")
+			continue
+		}
+		for c := 0; c < numChans; c++ {
+			insertStmt := fmt.Sprintf("INSERT INTO %s VALUES ('%s', '%s', %d, %d, %d, %d, %d)", EVENTS_PER_TABLE_METADATA_TABLE_NAME, migrationUUID, tableName, c, 0, 0, 0, 0)
+			_, err := tx.Exec(insertStmt)
+			if err != nil {
+				return fmt.Errorf("error executing stmt - %v: %w", insertStmt, err)
+			}
+			log.Infof("created table wise meta info: %s;", insertStmt)
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing tx: %w", err)
+	}
+	return nil
+}
+
+func (tdb *TargetMySQLDB) InitLiveMigrationState(migrationUUID uuid.UUID, numChans int, startClean bool, tableNames []string) error {
+	return tdb.WithConn(func(conn *sql.Conn) (bool, error) {
+		if startClean {
+			err := tdb.clearMigrationStateFromTable(conn, EVENT_CHANNELS_METADATA_TABLE_NAME, migrationUUID)
+			if err != nil {
+				return false, fmt.Errorf("failed to clear live migration meta info: %w", err)
+			}
+			err = tdb.clearMigrationStateFromTable(conn, EVENTS_PER_TABLE_METADATA_TABLE_NAME, migrationUUID)
+			if err != nil {
+				return false, fmt.Errorf("failed to clear live migration meta info: %w", err)
+			}
+		}
+		err := tdb.initChannelMetaInfo(conn, migrationUUID, numChans)
+		if err != nil {
+			return false, fmt.Errorf("failed to init live migration meta info: %w", err)
+		}
+		err = tdb.initEventStatByTableMetainfo(conn, migrationUUID, tableNames, numChans)
+		if err != nil {
+			return false, fmt.Errorf("failed to init table wise meta info: %w", err)
+		}
+		return false, nil
+	})
+}
+
+func (tdb *TargetMySQLDB) GetEventChannelsMetaInfo(migrationUUID uuid.UUID) (map[int]EventChannelMetaInfo, error) {
+	metainfo := map[int]EventChannelMetaInfo{}
+
+	query := fmt.Sprintf("SELECT channel_no, last_applied_vsn FROM %s where migration_uuid='%s'", EVENT_CHANNELS_METADATA_TABLE_NAME, migrationUUID)
+	rows, err := tdb.conn.QueryContext(context.Background(), query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query meta info for channels: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var chanMetaInfo EventChannelMetaInfo
+		err := rows.Scan(&(chanMetaInfo.ChanNo), &(chanMetaInfo.LastAppliedVsn))
+		if err != nil {
+			return nil, fmt.Errorf("error while scanning rows returned from DB: %w", err)
+		}
+		metainfo[chanMetaInfo.ChanNo] = chanMetaInfo
+	}
+	return metainfo, rows.Err()
+}
+
+func (tdb *TargetMySQLDB) CheckpointEventChannels(migrationUUID uuid.UUID, vsn int64) error {
+	query := fmt.Sprintf("UPDATE %s SET last_applied_vsn = %d WHERE migration_uuid = '%s' AND last_applied_vsn < %d",
+		EVENT_CHANNELS_METADATA_TABLE_NAME, vsn, migrationUUID, vsn)
+	return tdb.WithConn(func(conn *sql.Conn) (bool, error) {
+		_, err := conn.ExecContext(context.Background(), query)
+		if err != nil {
+			return false, fmt.Errorf("run query %q: %w", query, err)
+		}
+		return false, nil
+	})
+}
+
+func (tdb *TargetMySQLDB) AdvanceEventChannel(migrationUUID uuid.UUID, chanNo int, vsn int64) error {
+	query := fmt.Sprintf("UPDATE %s SET last_applied_vsn = %d WHERE migration_uuid = '%s' AND channel_no = %d AND last_applied_vsn < %d",
+		EVENT_CHANNELS_METADATA_TABLE_NAME, vsn, migrationUUID, chanNo, vsn)
+	return tdb.WithConn(func(conn *sql.Conn) (bool, error) {
+		_, err := conn.ExecContext(context.Background(), query)
+		if err != nil {
+			return false, fmt.Errorf("run query %q: %w", query, err)
+		}
+		return false, nil
+	})
+}
+
+func (tdb *TargetMySQLDB) GetTotalNumOfEventsImportedByType(migrationUUID uuid.UUID) (int64, int64, int64, error) {
+	query := fmt.Sprintf("SELECT COALESCE(SUM(num_inserts), 0), COALESCE(SUM(num_updates), 0), COALESCE(SUM(num_deletes), 0) FROM %s where migration_uuid='%s'",
+		EVENT_CHANNELS_METADATA_TABLE_NAME, migrationUUID)
+	var numInserts, numUpdates, numDeletes int64
+	err := tdb.conn.QueryRowContext(context.Background(), query).Scan(&numInserts, &numUpdates, &numDeletes)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("error in getting import stats from target db: %w", err)
+	}
+	return numInserts, numUpdates, numDeletes, nil
+}
+
+func (tdb *TargetMySQLDB) GetImportedEventCountsByTable(migrationUUID uuid.UUID) (map[string]*EventCounter, error) {
+	query := fmt.Sprintf(`SELECT table_name, SUM(total_events), SUM(num_inserts), SUM(num_updates), SUM(num_deletes)
+		FROM %s WHERE migration_uuid='%s' GROUP BY table_name`, EVENTS_PER_TABLE_METADATA_TABLE_NAME, migrationUUID)
+	rows, err := tdb.conn.QueryContext(context.Background(), query)
+	if err != nil {
+		return nil, fmt.Errorf("error in getting import stats by table from target db: %w", err)
+	}
+	defer rows.Close()
+	result := make(map[string]*EventCounter)
+	for rows.Next() {
+		var tableName string
+		counter := &EventCounter{}
+		err = rows.Scan(&tableName, &counter.TotalEvents, &counter.NumInserts, &counter.NumUpdates, &counter.NumDeletes)
+		if err != nil {
+			return nil, fmt.Errorf("error in scanning import stats by table: %w", err)
+		}
+		result[tableName] = counter
+	}
+	return result, rows.Err()
+}
+
+func (tdb *TargetMySQLDB) GetRowCount(tableName string) (int64, error) {
+	var rowCount int64
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", tdb.qualifyTableName(tableName))
+	err := tdb.conn.QueryRowContext(context.Background(), query).Scan(&rowCount)
+	if err != nil {
+		return 0, fmt.Errorf("run query %q on target: %w", query, err)
+	}
+	return rowCount, nil
+}
+
+func (tdb *TargetMySQLDB) TruncateTables(tableNames []string) error {
+	for _, tableName := range tableNames {
+		query := fmt.Sprintf("TRUNCATE TABLE %s", tdb.qualifyTableName(tableName))
+		_, err := tdb.conn.ExecContext(context.Background(), query)
+		if err != nil {
+			return fmt.Errorf("run query %q on target: %w", query, err)
+		}
+	}
+	return nil
+}
+
+// getAutoIncrementTable returns the table whose AUTO_INCREMENT stands for the sequence. The sequences are
+// named either after the table, or after the table and its column as <table>_<column>_seq.
+func (tdb *TargetMySQLDB) getAutoIncrementTable(sequenceName string) (string, error) {
+	schemaName := strings.Trim(tdb.tconf.GetTargetSchemaName(sequenceName), `"`)
+	parts := strings.Split(sequenceName, ".")
+	name := strings.Trim(parts[len(parts)-1], `"`)
+	query := `SELECT TABLE_NAME FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = ? AND EXTRA LIKE '%auto_increment%'
+		AND (TABLE_NAME = ? OR CONCAT(TABLE_NAME, '_', COLUMN_NAME, '_seq') = ?)`
+	var tableName string
+	err := tdb.conn.QueryRowContext(context.Background(), query, schemaName, name, name).Scan(&tableName)
+	if err != nil {
+		return "", fmt.Errorf("find AUTO_INCREMENT table of sequence %s: %w", sequenceName, err)
+	}
+	return tableName, nil
+}
+
+func (tdb *TargetMySQLDB) RestoreSequences(sequencesLastVal map[string]int64) []*SequenceRestoreResult {
+	log.Infof("restoring sequences on target")
+	var results []*SequenceRestoreResult
+	for _, sequenceName := range getSequencesToRestore(sequencesLastVal) {
+		lastValue := sequencesLastVal[sequenceName]
+		tableName, err := tdb.getAutoIncrementTable(sequenceName)
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Warnf("no AUTO_INCREMENT column for sequence %s, skipping its restore", sequenceName)
+			continue
+		}
+		result := &SequenceRestoreResult{SequenceName: sequenceName, LastValue: lastValue, Err: err}
+		results = append(results, result)
+		if err != nil {
+			continue
+		}
+		// AUTO_INCREMENT is the next value to be generated
+		stmt := fmt.Sprintf("ALTER TABLE %s AUTO_INCREMENT = %d", tdb.qualifyTableName(`"`+tableName+`"`), lastValue+1)
+		log.Infof("restore sequence %s to %d: %s", sequenceName, lastValue, stmt)
+		_, err = tdb.conn.ExecContext(context.Background(), stmt)
+		if err != nil {
+			result.Err = fmt.Errorf("error restoring sequence %s: %w", sequenceName, err)
+		}
+	}
+	return results
+}
+
+func (tdb *TargetMySQLDB) GetSequenceLastValue(sequenceName string) (int64, error) {
+	tableName, err := tdb.getAutoIncrementTable(sequenceName)
+	if err != nil {
+		return 0, err
+	}
+	var autoIncrement sql.NullInt64
+	query := "SELECT AUTO_INCREMENT FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?"
+	err = tdb.conn.QueryRowContext(context.Background(), query,
+		strings.Trim(tdb.tconf.GetTargetSchemaName(sequenceName), `"`), tableName).Scan(&autoIncrement)
+	if err != nil {
+		return 0, fmt.Errorf("run query %q on target for sequence %s: %w", query, sequenceName, err)
+	}
+	return autoIncrement.Int64 - 1, nil
+}
+
+// GetInvalidForeignKeys returns none, as the foreign keys can't be disabled or left unvalidated in MySQL;
+// their checks are disabled only for the sessions setting foreign_key_checks.
+func (tdb *TargetMySQLDB) GetInvalidForeignKeys() ([]string, error) {
+	return nil, nil
+}
+
+func (tdb *TargetMySQLDB) GetForeignKeyViolations(tableNames []string) ([]*ForeignKeyViolation, error) {
+	return nil, fmt.Errorf("checking the foreign key violations is not supported for MySQL")
+}
+
+func (tdb *TargetMySQLDB) MaxBatchSizeInBytes() int64 {
+	return 200 * 1024 * 1024 // 200 MB
+}
+
+// SetApplicationName is a no-op for MySQL; the driver doesn't send the program_name connection attribute.
+func (tdb *TargetMySQLDB) SetApplicationName(applicationName string) {}
+
+// SetTargetSchema is a no-op, the table names are qualified with the target schema (database) in the statements.
+func (tdb *TargetMySQLDB) SetTargetSchema(conn interface{}) error {
+	return nil
+}
@@ -0,0 +1,76 @@
+package tgtdb
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteMySQLString(t *testing.T) {
+	assert := assert.New(t)
+	testcases := map[string]string{
+		"":     "''",
+		",":    "','",
+		"\t":   "'\t'",
+		`\N`:   `'\\N'`,
+		"it's": "'it''s'",
+		`"`:    `'"'`,
+		`a\'b`: `'a\\''b'`,
+	}
+	for s, expected := range testcases {
+		assert.Equal(expected, quoteMySQLString(s), "%q", s)
+	}
+}
+
+func TestGetMySQLLoadDataStmt(t *testing.T) {
+	assert := assert.New(t)
+	testcases := []struct {
+		args     ImportBatchArgs
+		expected string
+	}{
+		{ImportBatchArgs{TableName: "employees", Columns: []string{"id", "name"}, FileFormat: "text", NullString: `\N`},
+			`LOAD DATA LOCAL INFILE 'Reader::batch_1' INTO TABLE test.employees CHARACTER SET utf8mb4 FIELDS TERMINATED BY '	' ESCAPED BY '\\' LINES TERMINATED BY '\n' (id, name)`},
+		{ImportBatchArgs{TableName: "hr.employees", FileFormat: "text", Delimiter: "|"},
+			`LOAD DATA LOCAL INFILE 'Reader::batch_1' INTO TABLE hr.employees CHARACTER SET utf8mb4 FIELDS TERMINATED BY '|' ESCAPED BY '\\' LINES TERMINATED BY '\n'`},
+		{ImportBatchArgs{TableName: "employees", Columns: []string{"id", "name"}, FileFormat: "csv", HasHeader: true},
+			`LOAD DATA LOCAL INFILE 'Reader::batch_1' INTO TABLE test.employees CHARACTER SET utf8mb4 FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '"' ESCAPED BY '' LINES TERMINATED BY '\n' IGNORE 1 LINES (id, name)`},
+		{ImportBatchArgs{TableName: "employees", Columns: []string{"id"}, FileFormat: "csv", QuoteChar: '\'', EscapeChar: '\\', NullString: "NULL"},
+			`LOAD DATA LOCAL INFILE 'Reader::batch_1' INTO TABLE test.employees CHARACTER SET utf8mb4 FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '''' ESCAPED BY '\\' LINES TERMINATED BY '\n' (id)`},
+		{ImportBatchArgs{TableName: "employees", Columns: []string{"id"}, FileFormat: "text", NullString: "NULL"},
+			`LOAD DATA LOCAL INFILE 'Reader::batch_1' INTO TABLE test.employees CHARACTER SET utf8mb4 FIELDS TERMINATED BY '	' ESCAPED BY '\\' LINES TERMINATED BY '\n' (@v1) SET id = NULLIF(@v1, 'NULL')`},
+	}
+	for _, tc := range testcases {
+		assert.Equal(tc.expected, getMySQLLoadDataStmt("test", &tc.args, "batch_1"), "%s", tc.args.TableName)
+	}
+}
+
+func TestMySQLCSVReader(t *testing.T) {
+	assert := assert.New(t)
+	testcases := []struct {
+		args     ImportBatchArgs
+		input    string
+		expected string
+	}{
+		// unquoted empty values are NULL for COPY, quoted ones are empty strings
+		{ImportBatchArgs{}, "1,,\"\"\n2,\"a,\"\"b\"\"\n\",\n", "1,NULL,\"\"\n2,\"a,\"\"b\"\"\n\",NULL\n"},
+		{ImportBatchArgs{}, "NULL,\"NULL\",x", "\"NULL\",\"NULL\",x"},
+		{ImportBatchArgs{}, "\n", "NULL\n"},
+		{ImportBatchArgs{NullString: "NULL"}, "NULL,,\"NULL\"\n", "NULL,,\"NULL\"\n"},
+		{ImportBatchArgs{NullString: `\N`, Delimiter: "|", QuoteChar: '\'', EscapeChar: '\\'}, `\N|'it\'s'|'\N'`, `NULL|'it\'s'|'\N'`},
+	}
+	for _, tc := range testcases {
+		reader := newMySQLCSVReader(strings.NewReader(tc.input), &tc.args)
+		output, err := io.ReadAll(reader)
+		assert.NoError(err)
+		assert.Equal(tc.expected, string(output), "%q", tc.input)
+	}
+}
+
+func TestMySQLIfRequiredQuoteColumnNames(t *testing.T) {
+	tdb := &TargetMySQLDB{}
+	columns, err := tdb.IfRequiredQuoteColumnNames("employees", []string{"id", "order", `"First Name"`})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`"id"`, `"order"`, `"First Name"`}, columns)
+}
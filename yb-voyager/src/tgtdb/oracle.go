@@ -20,12 +20,14 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -50,8 +52,18 @@ type TargetOracleDB struct {
 	tconf *TargetConf
 	oraDB *sql.DB
 	conn  *sql.Conn
+
+	useInsertsForImport bool // sqlldr is not available, import batches using INSERT statements
+
+	loaderSem      chan struct{} // bounds the number of concurrent sqlldr processes
+	runningLoaders int32
+	tableLoadLocks sync.Map // table name -> *sync.Mutex serializing the direct path loads of the table
 }
 
+// Number of the rows of a batch committed by its failed sqlldr loads, to skip when the batch is retried,
+// also after a restart. The entry of the batch is removed once it is imported.
+const SQLLDR_LOADED_ROWS_TABLE_NAME = BATCH_METADATA_TABLE_SCHEMA + "." + "ybvoyager_sqlldr_loaded_rows"
+
 func newTargetOracleDB(tconf *TargetConf) TargetDB {
 	return &TargetOracleDB{tconf: tconf}
 }
@@ -61,9 +73,13 @@ func (tdb *TargetOracleDB) connect() error {
 	if err != nil {
 		return fmt.Errorf("connect to target db: %w", err)
 	}
-	tdb.setTargetSchema(conn)
+	err = tdb.SetTargetSchema(conn)
+	if err != nil {
+		conn.Close()
+		return err
+	}
 	tdb.conn = conn
-	return err
+	return nil
 }
 
 func (tdb *TargetOracleDB) Init() error {
@@ -78,6 +94,14 @@ func (tdb *TargetOracleDB) Init() error {
 		return err
 	}
 
+	if tdb.tconf.ImportMode {
+		err = tdb.checkSqlldr()
+		if err != nil {
+			return err
+		}
+	}
+	tdb.loaderSem = make(chan struct{}, tdb.getLoaderParallelism())
+
 	checkSchemaExistsQuery := fmt.Sprintf(
 		"SELECT 1 FROM ALL_USERS WHERE USERNAME = '%s'",
 		strings.ToUpper(tdb.tconf.Schema))
@@ -90,6 +114,21 @@ func (tdb *TargetOracleDB) Init() error {
 	return err
 }
 
+func (tdb *TargetOracleDB) checkSqlldr() error {
+	version, err := sqlldr.CheckSqlldr()
+	if err == nil {
+		log.Infof("using sqlldr version %d", version)
+		return nil
+	}
+	if !tdb.tconf.SqlldrInsertFallback {
+		return fmt.Errorf("%w\n%s Alternatively, use --oracle-insert-fallback to import using (slower) INSERT statements",
+			err, sqlldr.SQLLDR_INSTALLATION_GUIDANCE)
+	}
+	utils.PrintAndLog("WARNING: %s. Importing data using INSERT statements, which is slower than sqlldr.", err)
+	tdb.useInsertsForImport = true
+	return nil
+}
+
 func (tdb *TargetOracleDB) disconnect() {
 	if tdb.conn != nil {
 		log.Infof("No connection to the target database to close")
@@ -131,17 +170,9 @@ func (tdb *TargetOracleDB) Finalize() {
 	tdb.disconnect()
 }
 
-func (tdb *TargetOracleDB) getTargetSchemaName(tableName string) string {
-	parts := strings.Split(tableName, ".")
-	if len(parts) == 2 {
-		return parts[0]
-	}
-	return tdb.tconf.Schema
-}
-
 func (tdb *TargetOracleDB) CleanFileImportState(filePath, tableName string) error {
 	// Delete all entries from ${BATCH_METADATA_TABLE_NAME} for the given file.
-	schemaName := tdb.getTargetSchemaName(tableName)
+	schemaName := tdb.tconf.GetTargetSchemaName(tableName)
 	cmd := fmt.Sprintf(
 		`DELETE FROM %s WHERE data_file_name = '%s' AND schema_name = '%s' AND table_name = '%s'`,
 		BATCH_METADATA_TABLE_NAME, filePath, schemaName, tableName)
@@ -151,6 +182,16 @@ func (tdb *TargetOracleDB) CleanFileImportState(filePath, tableName string) erro
 	}
 	rowsAffected, _ := res.RowsAffected()
 	log.Infof("query: [%s] => rows affected %v", cmd, rowsAffected)
+
+	cmd = fmt.Sprintf(
+		`DELETE FROM %s WHERE data_file_name = '%s' AND schema_name = '%s' AND table_name = '%s'`,
+		SQLLDR_LOADED_ROWS_TABLE_NAME, filePath, schemaName, tableName)
+	res, err = tdb.conn.ExecContext(context.Background(), cmd)
+	if err != nil {
+		return fmt.Errorf("remove %q related entries from %s: %w", tableName, SQLLDR_LOADED_ROWS_TABLE_NAME, err)
+	}
+	rowsAffected, _ = res.RowsAffected()
+	log.Infof("query: [%s] => rows affected %v", cmd, rowsAffected)
 	return nil
 }
 
@@ -193,6 +234,21 @@ END;`, BATCH_METADATA_TABLE_SCHEMA, BATCH_METADATA_TABLE_SCHEMA)
 				RAISE;
 			END IF;
 	END;`, BATCH_METADATA_TABLE_NAME)
+	createSqlldrLoadedRowsTableQuery := fmt.Sprintf(`BEGIN
+		EXECUTE IMMEDIATE 'CREATE TABLE %s (
+			data_file_name VARCHAR2(250),
+			batch_number NUMBER(10),
+			schema_name VARCHAR2(250),
+			table_name VARCHAR2(250),
+			rows_loaded NUMBER(19),
+			PRIMARY KEY (data_file_name, batch_number, schema_name, table_name)
+		)';
+	EXCEPTION
+		WHEN OTHERS THEN
+			IF SQLCODE != -955 THEN
+				RAISE;
+			END IF;
+	END;`, SQLLDR_LOADED_ROWS_TABLE_NAME)
 	// The exception block is to ignore the error if the table already exists and continue without error.
 	createEventChannelsMetadataTableQuery := fmt.Sprintf(`BEGIN
 		EXECUTE IMMEDIATE 'CREATE TABLE %s (
@@ -234,6 +290,7 @@ END;`, EVENTS_PER_TABLE_METADATA_TABLE_NAME)
 		grantQuery,
 		alterQuery,
 		createBatchMetadataTableQuery,
+		createSqlldrLoadedRowsTableQuery,
 		createEventChannelsMetadataTableQuery,
 		tableWiseEventsMetadataTableQuery,
 	}
@@ -290,8 +347,8 @@ func (tdb *TargetOracleDB) getEventChannelsRowCount(conn *sql.Conn, migrationUUI
 func (tdb *TargetOracleDB) getLiveMigrationMetaInfoByTable(conn *sql.Conn, migrationUUID uuid.UUID, tableName string) (int64, error) {
 	var rowCount int64
 	rowsStmt := fmt.Sprintf(
-		"SELECT count(*) FROM %s where migration_uuid='%s' AND table_name='%s'", 
-			EVENTS_PER_TABLE_METADATA_TABLE_NAME, migrationUUID, tableName)
+		"SELECT count(*) FROM %s where migration_uuid='%s' AND table_name='%s'",
+		EVENTS_PER_TABLE_METADATA_TABLE_NAME, migrationUUID, tableName)
 	err := conn.QueryRowContext(context.Background(), rowsStmt).Scan(&rowCount)
 	if err != nil {
 		return 0, fmt.Errorf("error executing stmt - %v: %w", rowsStmt, err)
@@ -342,7 +399,7 @@ func (tdb *TargetOracleDB) initEventStatByTableMetainfo(tableNames []string, mig
 		tableName = tdb.qualifyTableName(tableName)
 		rowCount, err := tdb.getLiveMigrationMetaInfoByTable(conn, migrationUUID, tableName)
 		if err != nil {
-			return  fmt.Errorf("failed to get table wise meta info: %w", err)
+			return fmt.Errorf("failed to get table wise meta info: %w", err)
 		}
 		if rowCount > 0 {
 			log.Info("table wise meta info already created. Skipping init.")
@@ -417,6 +474,30 @@ func (tdb *TargetOracleDB) GetEventChannelsMetaInfo(migrationUUID uuid.UUID) (ma
 	return metainfo, nil
 }
 
+func (tdb *TargetOracleDB) CheckpointEventChannels(migrationUUID uuid.UUID, vsn int64) error {
+	query := fmt.Sprintf("UPDATE %s SET last_applied_vsn = %d WHERE migration_uuid = '%s' AND last_applied_vsn < %d",
+		EVENT_CHANNELS_METADATA_TABLE_NAME, vsn, migrationUUID, vsn)
+	return tdb.WithConn(func(conn *sql.Conn) (bool, error) {
+		_, err := conn.ExecContext(context.Background(), query)
+		if err != nil {
+			return false, fmt.Errorf("run query %q: %w", query, err)
+		}
+		return false, nil
+	})
+}
+
+func (tdb *TargetOracleDB) AdvanceEventChannel(migrationUUID uuid.UUID, chanNo int, vsn int64) error {
+	query := fmt.Sprintf("UPDATE %s SET last_applied_vsn = %d WHERE migration_uuid = '%s' AND channel_no = %d AND last_applied_vsn < %d",
+		EVENT_CHANNELS_METADATA_TABLE_NAME, vsn, migrationUUID, chanNo, vsn)
+	return tdb.WithConn(func(conn *sql.Conn) (bool, error) {
+		_, err := conn.ExecContext(context.Background(), query)
+		if err != nil {
+			return false, fmt.Errorf("run query %q: %w", query, err)
+		}
+		return false, nil
+	})
+}
+
 func (tdb *TargetOracleDB) GetNonEmptyTables(tables []string) []string {
 	result := []string{}
 
@@ -436,27 +517,141 @@ func (tdb *TargetOracleDB) GetNonEmptyTables(tables []string) []string {
 	return result
 }
 
+func (tdb *TargetOracleDB) GetMissingTables(tables []string) []string {
+	result := []string{}
+
+	for _, table := range tables {
+		log.Infof("Checking if table %s.%s exists", tdb.tconf.Schema, table)
+		// unquoted identifiers are stored in upper case in the dictionary
+		tableName := strings.ToUpper(table)
+		if strings.HasPrefix(table, "\"") {
+			tableName = strings.Trim(table, "\"")
+		}
+		count := 0
+		stmt := "SELECT COUNT(*) FROM ALL_TABLES WHERE OWNER = :1 AND TABLE_NAME = :2"
+		err := tdb.conn.QueryRowContext(context.Background(), stmt, strings.ToUpper(tdb.tconf.Schema), tableName).Scan(&count)
+		if err != nil {
+			utils.ErrExit("run query %q on target: %s", stmt, err)
+		}
+		if count == 0 {
+			result = append(result, table)
+		}
+	}
+
+	return result
+}
+
+func (tdb *TargetOracleDB) GetColumnTypes(tableName string, columns []string) ([]ColumnType, error) {
+	return nil, fmt.Errorf("fetching the column types is not supported for Oracle")
+}
+
+// GetGeneratedColumns returns the virtual columns of the table, excluding the hidden ones.
+func (tdb *TargetOracleDB) GetGeneratedColumns(tableName string) ([]string, error) {
+	schemaName := strings.ToUpper(strings.Trim(tdb.tconf.GetTargetSchemaName(tableName), "\""))
+	parts := strings.Split(tableName, ".")
+	// unquoted identifiers are stored in upper case in the dictionary
+	table := strings.ToUpper(parts[len(parts)-1])
+	if strings.HasPrefix(parts[len(parts)-1], "\"") {
+		table = strings.Trim(parts[len(parts)-1], "\"")
+	}
+	stmt := `SELECT COLUMN_NAME FROM ALL_TAB_COLS WHERE OWNER = :1 AND TABLE_NAME = :2
+		AND VIRTUAL_COLUMN = 'YES' AND HIDDEN_COLUMN = 'NO' ORDER BY COLUMN_ID`
+	rows, err := tdb.conn.QueryContext(context.Background(), stmt, schemaName, table)
+	if err != nil {
+		return nil, fmt.Errorf("run query %q on target for table %s: %w", stmt, tableName, err)
+	}
+	defer rows.Close()
+	var result []string
+	for rows.Next() {
+		var colName string
+		err = rows.Scan(&colName)
+		if err != nil {
+			return nil, fmt.Errorf("scan generated columns of table %s: %w", tableName, err)
+		}
+		result = append(result, colName)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("fetch generated columns of table %s: %w", tableName, rows.Err())
+	}
+	return result, nil
+}
+
 func (tdb *TargetOracleDB) IsNonRetryableCopyError(err error) bool {
 	return false
 }
 
 // NOTE: TODO support for identity columns sequences
-func (tdb *TargetOracleDB) RestoreSequences(sequencesLastVal map[string]int64) error {
-	return nil
+func (tdb *TargetOracleDB) RestoreSequences(sequencesLastVal map[string]int64) []*SequenceRestoreResult {
+	log.Infof("restoring sequences on target")
+	// RESTART START WITH is supported from Oracle 18c onwards.
+	restoreStmt := "ALTER SEQUENCE %s RESTART START WITH %d"
+	var results []*SequenceRestoreResult
+	for _, sequenceName := range getSequencesToRestore(sequencesLastVal) {
+		results = append(results, &SequenceRestoreResult{SequenceName: sequenceName, LastValue: sequencesLastVal[sequenceName]})
+	}
+	err := tdb.WithConn(func(conn *sql.Conn) (bool, error) {
+		for _, result := range results {
+			sequenceName := tdb.qualifyTableName(result.SequenceName)
+			log.Infof("restore sequence %s to %d", sequenceName, result.LastValue)
+			_, err := conn.ExecContext(context.Background(), fmt.Sprintf(restoreStmt, sequenceName, result.LastValue+1))
+			if err != nil {
+				log.Errorf("error executing restore sequence stmt: %v", err)
+				result.Err = fmt.Errorf("error restoring sequence %s: %w", sequenceName, err)
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		for _, result := range results {
+			result.Err = fmt.Errorf("error restoring sequences: %w", err)
+		}
+	}
+	return results
 }
 
 func (tdb *TargetOracleDB) ImportBatch(batch Batch, args *ImportBatchArgs, exportDir string) (int64, error) {
-	tdb.Lock()
-	defer tdb.Unlock()
+	// Taken before the loader slot, so that no slot is held while waiting for the other loads of the table.
+	unlockTable := tdb.lockTableForLoad(batch.GetTableName())
+	defer unlockTable()
+	// Each sqlldr process opens its own session and files, so limit their number independently of the batch pool.
+	tdb.loaderSem <- struct{}{}
+	defer func() { <-tdb.loaderSem }()
+	running := atomic.AddInt32(&tdb.runningLoaders, 1)
+	defer atomic.AddInt32(&tdb.runningLoaders, -1)
+	batchLogger(batch).Infof("importing batch %q of table %s (%d/%d loaders running)",
+		batch.GetFilePath(), batch.GetTableName(), running, cap(tdb.loaderSem))
 
 	var rowsAffected int64
+	var rejectedRecords []sqlldr.RejectedRecord
 	var err error
 	copyFn := func(conn *sql.Conn) (bool, error) {
-		rowsAffected, err = tdb.importBatch(conn, batch, args, exportDir)
+		rowsAffected, err = tdb.importBatch(conn, batch, args, exportDir, &rejectedRecords)
 		return false, err
 	}
 	err = tdb.WithConn(copyFn)
-	return rowsAffected, err
+	if err != nil || len(rejectedRecords) == 0 {
+		return rowsAffected, err
+	}
+	for _, record := range rejectedRecords {
+		err = batch.RecordRejectedRow(record.Record, record.Reason)
+		if err != nil {
+			return rowsAffected, fmt.Errorf("record row rejected by sqlldr for batch %q: %w", batch.GetFilePath(), err)
+		}
+	}
+	utils.PrintAndLog("%d rows of batch %q of table %q were rejected by sqlldr", len(rejectedRecords),
+		filepath.Base(batch.GetFilePath()), batch.GetTableName())
+	return rowsAffected, nil
+}
+
+// lockTableForLoad serializes the loads of a table if they lock it exclusively, as a direct path load
+// (other than a parallel one) does. The returned function releases the lock.
+func (tdb *TargetOracleDB) lockTableForLoad(tableName string) func() {
+	if !tdb.tconf.SqlldrDirectPath || tdb.tconf.SqlldrParallel || tdb.useInsertsForImport {
+		return func() {}
+	}
+	lock, _ := tdb.tableLoadLocks.LoadOrStore(tableName, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	return lock.(*sync.Mutex).Unlock
 }
 
 func (tdb *TargetOracleDB) WithConn(fn func(*sql.Conn) (bool, error)) error {
@@ -493,8 +688,10 @@ func (tdb *TargetOracleDB) WithConn(fn func(*sql.Conn) (bool, error)) error {
 	return err
 }
 
-func (tdb *TargetOracleDB) importBatch(conn *sql.Conn, batch Batch, args *ImportBatchArgs, exportDir string) (rowsAffected int64, err error) {
-	var file *os.File
+// importBatch loads the batch using sqlldr. The rows rejected by sqlldr are returned in `rejectedRecords`.
+func (tdb *TargetOracleDB) importBatch(conn *sql.Conn, batch Batch, args *ImportBatchArgs, exportDir string,
+	rejectedRecords *[]sqlldr.RejectedRecord) (rowsAffected int64, err error) {
+	var file io.ReadCloser
 	file, err = batch.Open()
 	if err != nil {
 		return 0, fmt.Errorf("open batch file %q: %w", batch.GetFilePath(), err)
@@ -502,7 +699,10 @@ func (tdb *TargetOracleDB) importBatch(conn *sql.Conn, batch Batch, args *Import
 	defer file.Close()
 
 	//setting the schema so that the table is created in the correct schema
-	tdb.setTargetSchema(conn)
+	err = tdb.SetTargetSchema(conn)
+	if err != nil {
+		return 0, err
+	}
 
 	ctx := context.Background()
 	var tx *sql.Tx
@@ -533,9 +733,29 @@ func (tdb *TargetOracleDB) importBatch(conn *sql.Conn, batch Batch, args *Import
 		return 0, err
 	}
 	if alreadyImported {
+		batch.RecordAlreadyImported()
 		return rowsAffected, nil
 	}
 
+	if tdb.useInsertsForImport {
+		rowsAffected, err = tdb.insertBatch(tx, file, args)
+		if err != nil {
+			return 0, err
+		}
+		err = tdb.recordEntryInDB(tx, batch, rowsAffected)
+		if err != nil {
+			err = fmt.Errorf("record entry in DB for batch %q: %w", batch.GetFilePath(), err)
+		}
+		return rowsAffected, err
+	}
+
+	// rows committed by the failed loads of the batch, also by an earlier run
+	var skip int64
+	skip, err = tdb.getRowsLoadedBeforeFailure(tx, batch)
+	if err != nil {
+		return 0, err
+	}
+
 	tableName := batch.GetTableName()
 	sqlldrConfig := args.GetSqlLdrControlFile(tdb.tconf.Schema)
 	fileName := filepath.Base(batch.GetFilePath())
@@ -552,21 +772,34 @@ func (tdb *TargetOracleDB) importBatch(conn *sql.Conn, batch Batch, args *Import
 
 	var sqlldrLogFilePath string
 	var sqlldrLogFile *os.File
-	sqlldrLogFilePath, sqlldrLogFile, err = sqlldr.CreateSqlldrLogFile(exportDir, tableName)
+	sqlldrLogFilePath, sqlldrLogFile, err = sqlldr.CreateSqlldrLogFile(exportDir, tableName, fileName)
 	if err != nil {
 		return 0, err
 	}
 	defer sqlldrLogFile.Close()
+	sqlldrBadFilePath := sqlldr.GetSqlldrBadFilePath(exportDir, tableName, fileName)
+	// sqlldr creates the bad file only if it rejects rows, don't mistake the one of an earlier attempt for it
+	sqlldr.RemoveSqlldrFiles(sqlldrBadFilePath)
+	defer func() {
+		// keep the files of the failed runs for troubleshooting
+		if err == nil && !(tdb.tconf.KeepRejects && len(*rejectedRecords) > 0) {
+			sqlldr.RemoveSqlldrFiles(sqlldrControlFilePath, sqlldrLogFilePath, sqlldrBadFilePath)
+		}
+	}()
 
 	user := tdb.tconf.User
 	password := tdb.tconf.Password
 	connectString := tdb.getConnectionString(tdb.tconf)
 	oracleConnectionString := fmt.Sprintf("%s@\"%s\"", user, connectString)
-	sqlldrArgs := fmt.Sprintf("userid=%s control=%s log=%s DIRECT=TRUE NO_INDEX_ERRORS=TRUE", oracleConnectionString, sqlldrControlFilePath, sqlldrLogFilePath)
+	sqlldrArgs := fmt.Sprintf("userid=%s control=%s log=%s bad=%s %s", oracleConnectionString, sqlldrControlFilePath, sqlldrLogFilePath,
+		sqlldrBadFilePath, tdb.getSqlldrOptions(batch.GetFilePath(), skip))
 
 	var outbuf string
 	var errbuf string
+	start := time.Now()
 	outbuf, errbuf, err = sqlldr.RunSqlldr(sqlldrArgs, password)
+	batchLogger(batch).Infof("sqlldr for batch %q of table %s finished in %s: %s", batch.GetFilePath(), tableName,
+		time.Since(start), strings.TrimSpace(outbuf))
 
 	if outbuf == "" && errbuf == "" && err != nil {
 		// for error related to the stdinPipe of created while running sqlldr
@@ -594,11 +827,39 @@ func (tdb *TargetOracleDB) importBatch(conn *sql.Conn, batch Batch, args *Import
 			}
 		}
 
+		var records []sqlldr.RejectedRecord
+		records, err2 = sqlldr.GetRejectedRecords(sqlldrBadFilePath, sqlldrLogFilePath)
+		if err2 != nil {
+			return 0, fmt.Errorf("get rows rejected by sqlldr: %w", err2)
+		}
+		for _, record := range records {
+			// rows imported by an earlier attempt of the batch are not rejects
+			if !strings.Contains(record.Reason, "ORA-00001") {
+				*rejectedRecords = append(*rejectedRecords, record)
+			}
+		}
+		// sqlldr exits with a warning if it rejects rows. The load is still complete if all the rows of the batch
+		// were read, as opposed to the load being discontinued after too many errors.
+		if len(*rejectedRecords) > 0 && sqlldrReadAllRows(batch, rowsAffected, skip) {
+			batchLogger(batch).Warnf("sqlldr rejected %d rows of batch %q", len(*rejectedRecords), batch.GetFilePath())
+			ignoreError = true
+		}
+
 		if !ignoreError {
+			*rejectedRecords = nil
+			tdb.recordRowsLoadedBeforeFailure(batch, sqlldrLogFilePath)
 			return rowsAffected, fmt.Errorf("run sqlldr: %w", err)
 		}
+		rowsAffected -= int64(len(*rejectedRecords))
 	}
 
+	if skip > 0 {
+		rowsAffected += skip
+		err = tdb.clearRowsLoadedBeforeFailure(tx, batch)
+		if err != nil {
+			return 0, err
+		}
+	}
 	err = tdb.recordEntryInDB(tx, batch, rowsAffected)
 	if err != nil {
 		err = fmt.Errorf("record entry in DB for batch %q: %w", batch.GetFilePath(), err)
@@ -607,6 +868,63 @@ func (tdb *TargetOracleDB) importBatch(conn *sql.Conn, batch Batch, args *Import
 	return rowsAffected, err
 }
 
+// insertBatch imports the rows of the batch file using INSERT statements in the given transaction.
+// The rows are in the same format as loaded by sqlldr: tab separated values with \N as NULL.
+func (tdb *TargetOracleDB) insertBatch(tx *sql.Tx, file io.Reader, args *ImportBatchArgs) (int64, error) {
+	var stmt *sql.Stmt
+	var rowsAffected int64
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 100*1024), 1024*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		values := strings.Split(line, "\t")
+		if stmt == nil {
+			var err error
+			stmt, err = tx.PrepareContext(context.Background(), getOracleInsertStmtForBatch(tdb.tconf.Schema, args, len(values)))
+			if err != nil {
+				return 0, fmt.Errorf("prepare insert stmt for %s: %w", args.TableName, err)
+			}
+			defer stmt.Close()
+		}
+		params := make([]interface{}, len(values))
+		for i, value := range values {
+			if value == `\N` {
+				params[i] = nil
+			} else {
+				params[i] = value
+			}
+		}
+		_, err := stmt.ExecContext(context.Background(), params...)
+		if err != nil {
+			return 0, fmt.Errorf("insert row %d of batch %q into %s: %w", rowsAffected+1, args.FilePath, args.TableName, err)
+		}
+		rowsAffected++
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("read batch file %q: %w", args.FilePath, err)
+	}
+	return rowsAffected, nil
+}
+
+func getOracleInsertStmtForBatch(schema string, args *ImportBatchArgs, numValues int) string {
+	columns := ""
+	if len(args.Columns) > 0 {
+		quotedColumns := make([]string, 0, len(args.Columns))
+		for _, col := range args.Columns {
+			quotedColumns = append(quotedColumns, quoteOracleIdentifierIfRequired(col))
+		}
+		columns = fmt.Sprintf("(%s)", strings.Join(quotedColumns, ", "))
+	}
+	binds := make([]string, numValues)
+	for i := range binds {
+		binds[i] = fmt.Sprintf(":%d", i+1)
+	}
+	return fmt.Sprintf("INSERT INTO %s %s VALUES (%s)", qualifyOracleTableName(schema, args.TableName), columns, strings.Join(binds, ", "))
+}
+
 func (tdb *TargetOracleDB) recordEntryInDB(tx *sql.Tx, batch Batch, rowsAffected int64) error {
 	cmd := batch.GetQueryToRecordEntryInDB(rowsAffected)
 	_, err := tx.ExecContext(context.Background(), cmd)
@@ -616,6 +934,103 @@ func (tdb *TargetOracleDB) recordEntryInDB(tx *sql.Tx, batch Batch, rowsAffected
 	return nil
 }
 
+// sqlldrReadAllRows tells if sqlldr read all the rows of the batch file, counting the rows skipped as committed by
+// the failed loads of the batch. The offsets of the batch (ROWS_PER_TRANSACTION) span the lines read from the data file,
+// not all of which are necessarily written to the batch file, hence the rows are counted by the record count.
+func sqlldrReadAllRows(batch Batch, rowsRead int64, skip int64) bool {
+	return rowsRead+skip == batch.GetRecordCount()
+}
+
+// getSqlldrOptions returns the sqlldr command line options as per the sqlldr knobs in the target conf.
+func (tdb *TargetOracleDB) getSqlldrOptions(batchFilePath string, skip int64) string {
+	options := []string{}
+	if tdb.tconf.SqlldrDirectPath {
+		options = append(options, "DIRECT=TRUE", "NO_INDEX_ERRORS=TRUE")
+		if tdb.tconf.SqlldrParallel {
+			options = append(options, "PARALLEL=TRUE")
+		}
+	} else {
+		options = append(options, "DIRECT=FALSE")
+		if tdb.tconf.SqlldrBindSize > 0 {
+			options = append(options, fmt.Sprintf("BINDSIZE=%d", tdb.tconf.SqlldrBindSize))
+		}
+	}
+	if tdb.tconf.SqlldrRows > 0 {
+		options = append(options, fmt.Sprintf("ROWS=%d", tdb.tconf.SqlldrRows))
+	}
+	// resume a batch whose previous load failed after loading (and committing) some rows.
+	if skip > 0 {
+		log.Infof("skipping %d rows already loaded from %q", skip, batchFilePath)
+		options = append(options, fmt.Sprintf("SKIP=%d", skip))
+	}
+	return strings.Join(options, " ")
+}
+
+// recordRowsLoadedBeforeFailure records the number of rows committed by a failed sqlldr load, so that they are
+// skipped when the batch is retried. Rows are committed midway only with the ROWS option. The rows are recorded
+// outside the transaction of the batch, which is rolled back, as sqlldr commits them in its own session.
+func (tdb *TargetOracleDB) recordRowsLoadedBeforeFailure(batch Batch, sqlldrLogFilePath string) {
+	if tdb.tconf.SqlldrRows <= 0 {
+		return
+	}
+	sqlldrLog, err := os.ReadFile(sqlldrLogFilePath)
+	if err != nil {
+		log.Warnf("read sqlldr log file %q: %v", sqlldrLogFilePath, err)
+		return
+	}
+	regex := regexp.MustCompile(`(\d+) Rows? successfully loaded`)
+	matches := regex.FindStringSubmatch(string(sqlldrLog))
+	if len(matches) < 2 {
+		return
+	}
+	numRows, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil || numRows == 0 {
+		return
+	}
+	schemaName := tdb.tconf.GetTargetSchemaName(batch.GetTableName())
+	stmt := fmt.Sprintf(`MERGE INTO %s t
+		USING (SELECT :1 data_file_name, :2 batch_number, :3 schema_name, :4 table_name FROM dual) b
+		ON (t.data_file_name = b.data_file_name AND t.batch_number = b.batch_number
+			AND t.schema_name = b.schema_name AND t.table_name = b.table_name)
+		WHEN MATCHED THEN UPDATE SET t.rows_loaded = t.rows_loaded + :5
+		WHEN NOT MATCHED THEN INSERT (data_file_name, batch_number, schema_name, table_name, rows_loaded)
+			VALUES (b.data_file_name, b.batch_number, b.schema_name, b.table_name, :6)`, SQLLDR_LOADED_ROWS_TABLE_NAME)
+	_, err = tdb.oraDB.ExecContext(context.Background(), stmt,
+		batch.GetBaseFilePath(), batch.GetBatchNumber(), schemaName, batch.GetTableName(), numRows, numRows)
+	if err != nil {
+		log.Warnf("record %d rows loaded from %q before the failure: %v", numRows, batch.GetFilePath(), err)
+	}
+}
+
+// getRowsLoadedBeforeFailure returns the number of rows of the batch committed by its failed sqlldr loads.
+func (tdb *TargetOracleDB) getRowsLoadedBeforeFailure(tx *sql.Tx, batch Batch) (int64, error) {
+	schemaName := tdb.tconf.GetTargetSchemaName(batch.GetTableName())
+	query := fmt.Sprintf(`SELECT rows_loaded FROM %s
+		WHERE data_file_name = :1 AND batch_number = :2 AND schema_name = :3 AND table_name = :4`, SQLLDR_LOADED_ROWS_TABLE_NAME)
+	var rowsLoaded int64
+	err := tx.QueryRowContext(context.Background(), query,
+		batch.GetBaseFilePath(), batch.GetBatchNumber(), schemaName, batch.GetTableName()).Scan(&rowsLoaded)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("get rows of %q loaded before the failure: %w", batch.GetFilePath(), err)
+	}
+	return rowsLoaded, nil
+}
+
+// clearRowsLoadedBeforeFailure removes the entry of the batch, in the transaction in which it is recorded as imported.
+func (tdb *TargetOracleDB) clearRowsLoadedBeforeFailure(tx *sql.Tx, batch Batch) error {
+	schemaName := tdb.tconf.GetTargetSchemaName(batch.GetTableName())
+	stmt := fmt.Sprintf(`DELETE FROM %s
+		WHERE data_file_name = :1 AND batch_number = :2 AND schema_name = :3 AND table_name = :4`, SQLLDR_LOADED_ROWS_TABLE_NAME)
+	_, err := tx.ExecContext(context.Background(), stmt,
+		batch.GetBaseFilePath(), batch.GetBatchNumber(), schemaName, batch.GetTableName())
+	if err != nil {
+		return fmt.Errorf("clear rows of %q loaded before the failure: %w", batch.GetFilePath(), err)
+	}
+	return nil
+}
+
 func getRowsAffected(outbuf string) (int64, error) {
 	regex := regexp.MustCompile(`Load completed - logical record count (\d+).`)
 	matches := regex.FindStringSubmatch(outbuf)
@@ -630,22 +1045,28 @@ func (tdb *TargetOracleDB) isBatchAlreadyImported(tx *sql.Tx, batch Batch) (bool
 	query := batch.GetQueryIsBatchAlreadyImported()
 	err := tx.QueryRowContext(context.Background(), query).Scan(&rowsImported)
 	if err == nil {
-		log.Infof("%v rows from %q are already imported", rowsImported, batch.GetFilePath())
+		batchLogger(batch).Infof("%v rows from %q are already imported", rowsImported, batch.GetFilePath())
 		return true, rowsImported, nil
 	}
 	if err == sql.ErrNoRows {
-		log.Infof("%q is not imported yet", batch.GetFilePath())
+		batchLogger(batch).Infof("%q is not imported yet", batch.GetFilePath())
 		return false, 0, nil
 	}
 	return false, 0, fmt.Errorf("check if %s is already imported: %w", batch.GetFilePath(), err)
 }
 
-func (tdb *TargetOracleDB) setTargetSchema(conn *sql.Conn) {
+// SetTargetSchema sets the current schema of the session, which is the owner prefix of the unqualified table names.
+func (tdb *TargetOracleDB) SetTargetSchema(conn interface{}) error {
+	sqlConn, ok := conn.(*sql.Conn)
+	if !ok {
+		return fmt.Errorf("set target schema: unexpected connection type %T", conn)
+	}
 	setSchemaQuery := fmt.Sprintf("ALTER SESSION SET CURRENT_SCHEMA = %s", tdb.tconf.Schema)
-	_, err := conn.ExecContext(context.Background(), setSchemaQuery)
+	_, err := sqlConn.ExecContext(context.Background(), setSchemaQuery)
 	if err != nil {
-		utils.ErrExit("run query %q on target %q to set schema: %s", setSchemaQuery, tdb.tconf.Host, err)
+		return fmt.Errorf("run query %q on target %q to set schema: %w", setSchemaQuery, tdb.tconf.Host, err)
 	}
+	return nil
 }
 
 func (tdb *TargetOracleDB) IfRequiredQuoteColumnNames(tableName string, columns []string) ([]string, error) {
@@ -653,9 +1074,13 @@ func (tdb *TargetOracleDB) IfRequiredQuoteColumnNames(tableName string, columns
 }
 
 // execute all events sequentially one by one in a single transaction
+// Number of rows to be inserted by a single INSERT ALL statement in multi-row apply statement mode.
+const ORACLE_MAX_INSERTS_PER_RUN = 100
+
 func (tdb *TargetOracleDB) ExecuteBatch(migrationUUID uuid.UUID, batch *EventBatch) error {
 	// TODO: figure out how to avoid round trips to Oracle DB
-	log.Infof("executing batch of %d events", len(batch.Events))
+	batch.logger().Infof("executing batch of %d events", len(batch.Events))
+	start := time.Now()
 	err := tdb.WithConn(func(conn *sql.Conn) (bool, error) {
 		tx, err := conn.BeginTx(context.Background(), nil)
 		if err != nil {
@@ -663,13 +1088,22 @@ func (tdb *TargetOracleDB) ExecuteBatch(migrationUUID uuid.UUID, batch *EventBat
 		}
 		defer tx.Rollback()
 
-		for i := 0; i < len(batch.Events); i++ {
-			event := batch.Events[i]
-			stmt := event.GetSQLStmt(tdb.tconf.Schema)
-			_, err = tx.Exec(stmt)
-			if err != nil {
-				log.Errorf("error executing stmt for event with vsn(%d): %v", event.Vsn, err)
-				return false, fmt.Errorf("error executing stmt for event with vsn(%d): %w", event.Vsn, err)
+		for _, run := range batch.SplitIntoRuns(tdb.tconf.Schema, ORACLE_MAX_INSERTS_PER_RUN) {
+			if run[0].Op == "c" && len(run) > 1 && tdb.tconf.ApplyStatementMode == APPLY_STATEMENT_MODE_MULTI_ROW {
+				_, err = tx.Exec(getOracleMultiRowInsertStmt(run, tdb.tconf.Schema))
+				if err != nil {
+					log.Errorf("error executing stmt for events with vsn(%d..%d): %v", run[0].Vsn, run[len(run)-1].Vsn, err)
+					return false, fmt.Errorf("error executing stmt for events with vsn(%d..%d): %w", run[0].Vsn, run[len(run)-1].Vsn, err)
+				}
+				continue
+			}
+			for _, event := range run {
+				stmt := event.GetSQLStmt(tdb.tconf.Schema)
+				_, err = tx.Exec(stmt)
+				if err != nil {
+					log.Errorf("error executing stmt for event with vsn(%d): %v", event.Vsn, err)
+					return false, fmt.Errorf("error executing stmt for event with vsn(%d): %w", event.Vsn, err)
+				}
 			}
 		}
 
@@ -707,6 +1141,9 @@ func (tdb *TargetOracleDB) ExecuteBatch(migrationUUID uuid.UUID, batch *EventBat
 	if err != nil {
 		return fmt.Errorf("error executing batch: %w", err)
 	}
+	elapsed := time.Since(start)
+	batch.logger().Infof("executed batch of %d events in %s using %q apply statement mode (%.2f events/sec)",
+		len(batch.Events), elapsed, tdb.tconf.ApplyStatementMode, float64(len(batch.Events))/elapsed.Seconds())
 
 	return nil
 }
@@ -720,9 +1157,27 @@ func (tdb *TargetOracleDB) InitConnPool() error {
 	}
 	tdb.oraDB.SetMaxIdleConns(tdb.tconf.Parallelism + 1)
 	tdb.oraDB.SetMaxOpenConns(tdb.tconf.Parallelism + 1)
+	if tdb.tconf.OracleLoaderParallelism > 0 {
+		utils.PrintAndLog("Using %d concurrent sqlldr processes", tdb.tconf.OracleLoaderParallelism)
+	}
 	return nil
 }
 
+// getLoaderParallelism returns the number of concurrent sqlldr processes, by default one per parallel job.
+func (tdb *TargetOracleDB) getLoaderParallelism() int {
+	if tdb.tconf.OracleLoaderParallelism > 0 {
+		return tdb.tconf.OracleLoaderParallelism
+	}
+	if tdb.tconf.Parallelism < 1 { // the default of --parallel-jobs, set to 1 in InitConnPool
+		return 1
+	}
+	return tdb.tconf.Parallelism
+}
+
+// SetApplicationName is a no-op for Oracle, which has no application_name; the sessions
+// of voyager can be identified by the program name (sqlldr or yb-voyager) in V$SESSION.
+func (tdb *TargetOracleDB) SetApplicationName(applicationName string) {}
+
 func (tdb *TargetOracleDB) GetDebeziumValueConverterSuite() map[string]ConverterFn {
 	return oraValueConverterSuite
 }
@@ -763,6 +1218,84 @@ func (tdb *TargetOracleDB) GetTotalNumOfEventsImportedByType(migrationUUID uuid.
 	return numInserts, numUpdates, numDeletes, nil
 }
 
+func (tdb *TargetOracleDB) GetImportedEventCountsByTable(migrationUUID uuid.UUID) (map[string]*EventCounter, error) {
+	query := fmt.Sprintf(`SELECT table_name, SUM(total_events), SUM(num_inserts), SUM(num_updates), SUM(num_deletes)
+		FROM %s WHERE migration_uuid='%s' GROUP BY table_name`, EVENTS_PER_TABLE_METADATA_TABLE_NAME, migrationUUID)
+	rows, err := tdb.conn.QueryContext(context.Background(), query)
+	if err != nil {
+		return nil, fmt.Errorf("error in getting import stats by table from target db: %w", err)
+	}
+	defer rows.Close()
+	result := make(map[string]*EventCounter)
+	for rows.Next() {
+		var tableName string
+		counter := &EventCounter{}
+		err = rows.Scan(&tableName, &counter.TotalEvents, &counter.NumInserts, &counter.NumUpdates, &counter.NumDeletes)
+		if err != nil {
+			return nil, fmt.Errorf("error in scanning import stats by table: %w", err)
+		}
+		result[tableName] = counter
+	}
+	return result, rows.Err()
+}
+
+func (tdb *TargetOracleDB) GetRowCount(tableName string) (int64, error) {
+	var rowCount int64
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", tdb.qualifyTableName(tableName))
+	err := tdb.conn.QueryRowContext(context.Background(), query).Scan(&rowCount)
+	if err != nil {
+		return 0, fmt.Errorf("run query %q on target: %w", query, err)
+	}
+	return rowCount, nil
+}
+
+func (tdb *TargetOracleDB) TruncateTables(tableNames []string) error {
+	for _, tableName := range tableNames {
+		query := fmt.Sprintf("TRUNCATE TABLE %s", tdb.qualifyTableName(tableName))
+		_, err := tdb.conn.ExecContext(context.Background(), query)
+		if err != nil {
+			return fmt.Errorf("run query %q on target: %w", query, err)
+		}
+	}
+	return nil
+}
+
+func (tdb *TargetOracleDB) GetSequenceLastValue(sequenceName string) (int64, error) {
+	// LAST_NUMBER is the next value to be generated, including the values cached in memory.
+	var lastNumber int64
+	query := "SELECT LAST_NUMBER FROM ALL_SEQUENCES WHERE SEQUENCE_OWNER = :1 AND SEQUENCE_NAME = :2"
+	err := tdb.conn.QueryRowContext(context.Background(), query,
+		strings.ToUpper(tdb.tconf.Schema), strings.ToUpper(sequenceName)).Scan(&lastNumber)
+	if err != nil {
+		return 0, fmt.Errorf("run query %q on target for sequence %s: %w", query, sequenceName, err)
+	}
+	return lastNumber - 1, nil
+}
+
+func (tdb *TargetOracleDB) GetInvalidForeignKeys() ([]string, error) {
+	query := `SELECT TABLE_NAME || '.' || CONSTRAINT_NAME FROM ALL_CONSTRAINTS
+		WHERE OWNER = :1 AND CONSTRAINT_TYPE = 'R' AND (STATUS != 'ENABLED' OR VALIDATED != 'VALIDATED')`
+	rows, err := tdb.conn.QueryContext(context.Background(), query, strings.ToUpper(tdb.tconf.Schema))
+	if err != nil {
+		return nil, fmt.Errorf("run query %q on target: %w", query, err)
+	}
+	defer rows.Close()
+	var result []string
+	for rows.Next() {
+		var constraintName string
+		err = rows.Scan(&constraintName)
+		if err != nil {
+			return nil, fmt.Errorf("scan foreign key constraint name: %w", err)
+		}
+		result = append(result, constraintName)
+	}
+	return result, rows.Err()
+}
+
+func (tdb *TargetOracleDB) GetForeignKeyViolations(tableNames []string) ([]*ForeignKeyViolation, error) {
+	return nil, fmt.Errorf("checking the foreign key violations is not supported for Oracle")
+}
+
 func (tdb *TargetOracleDB) MaxBatchSizeInBytes() int64 {
 	return 2 * 1024 * 1024 * 1024 // 2GB
 }
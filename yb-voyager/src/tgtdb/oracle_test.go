@@ -0,0 +1,49 @@
+package tgtdb
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOracleLockTableForLoad(t *testing.T) {
+	assert := assert.New(t)
+	tdb := &TargetOracleDB{tconf: &TargetConf{SqlldrDirectPath: true}}
+	unlock := tdb.lockTableForLoad("ORDERS")
+	lock, _ := tdb.tableLoadLocks.Load("ORDERS")
+	assert.False(lock.(*sync.Mutex).TryLock(), "the loads of a table are serialized")
+	unlockOther := tdb.lockTableForLoad("CUSTOMERS")
+	unlockOther()
+	unlock()
+	assert.True(lock.(*sync.Mutex).TryLock())
+	lock.(*sync.Mutex).Unlock()
+
+	// the parallel direct path and the conventional path loads do not lock the table
+	for _, tconf := range []*TargetConf{{SqlldrDirectPath: true, SqlldrParallel: true}, {}} {
+		tdb = &TargetOracleDB{tconf: tconf}
+		tdb.lockTableForLoad("ORDERS")
+		_, locked := tdb.tableLoadLocks.Load("ORDERS")
+		assert.False(locked)
+	}
+}
+
+type recordCountBatch struct {
+	Batch
+	recordCount int64
+}
+
+func (b *recordCountBatch) GetRecordCount() int64 {
+	return b.recordCount
+}
+
+func TestSqlldrReadAllRows(t *testing.T) {
+	assert := assert.New(t)
+	// the batch spans the lines 0-1000 of the data file (ROWS_PER_TRANSACTION 1000), of which 998 are written to it
+	args := &ImportBatchArgs{RowsPerTransaction: 1000}
+	batch := &recordCountBatch{recordCount: 998}
+	assert.True(sqlldrReadAllRows(batch, 998, 0))
+	assert.True(sqlldrReadAllRows(batch, 498, 500)) // rows committed by a failed load are skipped
+	assert.False(sqlldrReadAllRows(batch, args.RowsPerTransaction, 0))
+	assert.False(sqlldrReadAllRows(batch, 300, 0)) // discontinued after too many errors
+}
@@ -0,0 +1,267 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tgtdb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnparseableValue is returned by the value converters for the values which can't be imported
+// as is. Rows containing such values are rejected instead of failing the whole import.
+var ErrUnparseableValue = errors.New("unparseable value")
+
+// Decodes a value of a row in the COPY text format.
+var copyTextUnescaper = strings.NewReplacer(`\\`, `\`, `\t`, "\t", `\n`, "\n", `\r`, "\r")
+
+/*
+Range and multirange values (e.g. int4range, tstzrange) and arrays of composite types are
+exported as their text representation. These literals quote their elements with double quotes
+and escape with backslashes, both of which need to survive the COPY text format (where the
+backslash is itself an escape character) and the INSERT statements of the streaming phase
+(where the literal is enclosed in single quotes).
+*/
+func convertPgLiteral(columnValue string, formatIfRequired bool, validate func(string) error) (string, error) {
+	literal := columnValue
+	if !formatIfRequired {
+		literal = copyTextUnescaper.Replace(columnValue)
+	}
+	err := validate(literal)
+	if err != nil {
+		return columnValue, fmt.Errorf("%w %q: %s", ErrUnparseableValue, columnValue, err)
+	}
+	if formatIfRequired {
+		return fmt.Sprintf("'%s'", strings.Replace(literal, "'", "''", -1)), nil
+	}
+	return copyTextEscaper.Replace(literal), nil
+}
+
+// validateRangeLiteral checks that s is a valid range (`[1,5)`, `empty`) or multirange (`{[1,3),[5,7)}`) literal.
+func validateRangeLiteral(s string) error {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") {
+		return validateMultirangeLiteral(s)
+	}
+	if strings.EqualFold(s, "empty") {
+		return nil
+	}
+	if len(s) < 2 || !strings.ContainsRune("[(", rune(s[0])) || !strings.ContainsRune("])", rune(s[len(s)-1])) {
+		return fmt.Errorf("range must be enclosed in [ or ( and ] or )")
+	}
+	numSeparators := 0
+	err := scanLiteral(s[1:len(s)-1], func(c byte) error {
+		if c == ',' {
+			numSeparators++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if numSeparators != 1 {
+		return fmt.Errorf("range must have exactly two bounds, found %d", numSeparators+1)
+	}
+	return nil
+}
+
+func validateMultirangeLiteral(s string) error {
+	if !strings.HasSuffix(s, "}") {
+		return fmt.Errorf("multirange must be enclosed in { and }")
+	}
+	inner := strings.TrimSpace(s[1 : len(s)-1])
+	start := -1
+	for i := 0; i < len(inner); i++ {
+		switch c := inner[i]; {
+		case start == -1 && (c == '[' || c == '('):
+			start = i
+		case start == -1 && (c == ',' || c == ' '):
+		case start == -1:
+			return fmt.Errorf("unexpected character %q in multirange", c)
+		case c == '"' || c == '\\':
+			// skip over the quoted or escaped part of the bound
+			end, err := skipQuotedOrEscaped(inner, i)
+			if err != nil {
+				return err
+			}
+			i = end
+		case c == ']' || c == ')':
+			err := validateRangeLiteral(inner[start : i+1])
+			if err != nil {
+				return err
+			}
+			start = -1
+		}
+	}
+	if start != -1 {
+		return fmt.Errorf("unterminated range in multirange")
+	}
+	return nil
+}
+
+// validateCompositeArrayLiteral checks that s is a valid array literal whose non-NULL elements are composite values.
+func validateCompositeArrayLiteral(s string) error {
+	elements, err := parseArrayLiteral(s)
+	if err != nil {
+		return err
+	}
+	for _, element := range elements {
+		if element == nil {
+			continue
+		}
+		err = validateCompositeLiteral(*element)
+		if err != nil {
+			return fmt.Errorf("element %q: %w", *element, err)
+		}
+	}
+	return nil
+}
+
+func validateCompositeLiteral(s string) error {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return fmt.Errorf("composite value must be enclosed in ( and )")
+	}
+	return scanLiteral(s[1:len(s)-1], func(c byte) error { return nil })
+}
+
+// parseArrayLiteral returns the (unquoted and unescaped) elements of an array literal, flattening nested arrays.
+// NULL elements are returned as nil.
+func parseArrayLiteral(s string) ([]*string, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") { // optional dimension decoration e.g. `[1:2]={...}`
+		idx := strings.Index(s, "=")
+		if idx == -1 {
+			return nil, fmt.Errorf("invalid array dimensions")
+		}
+		s = strings.TrimSpace(s[idx+1:])
+	}
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, fmt.Errorf("array must be enclosed in { and }")
+	}
+
+	var elements []*string
+	var element strings.Builder
+	depth, quoted, inElement := 0, false, false
+	endElement := func() {
+		value := element.String()
+		if !quoted && strings.EqualFold(strings.TrimSpace(value), "NULL") {
+			elements = append(elements, nil)
+		} else {
+			if !quoted {
+				value = strings.TrimSpace(value)
+			}
+			elements = append(elements, &value)
+		}
+		element.Reset()
+		quoted, inElement = false, false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"':
+			end, err := skipQuotedOrEscaped(s, i)
+			if err != nil {
+				return nil, err
+			}
+			element.WriteString(unquoteArrayElement(s[i+1 : end]))
+			quoted, inElement = true, true
+			i = end
+		case c == '\\':
+			if i+1 == len(s) {
+				return nil, fmt.Errorf("unterminated escape sequence")
+			}
+			element.WriteByte(s[i+1])
+			inElement = true
+			i++
+		case c == '{':
+			depth++
+		case c == '}' || c == ',':
+			if inElement {
+				endElement()
+			}
+			if c == '}' {
+				depth--
+				if depth < 0 || (depth == 0 && i != len(s)-1) {
+					return nil, fmt.Errorf("unbalanced braces")
+				}
+			}
+		case c == ' ' && !inElement:
+		default:
+			element.WriteByte(c)
+			inElement = true
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced braces")
+	}
+	return elements, nil
+}
+
+func unquoteArrayElement(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// scanLiteral calls fn for every character of s which is neither quoted nor escaped.
+func scanLiteral(s string, fn func(c byte) error) error {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '"' || c == '\\' {
+			end, err := skipQuotedOrEscaped(s, i)
+			if err != nil {
+				return err
+			}
+			i = end
+			continue
+		}
+		err := fn(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipQuotedOrEscaped returns the index of the last character of the quoted string
+// or the escape sequence starting at s[start].
+func skipQuotedOrEscaped(s string, start int) (int, error) {
+	if s[start] == '\\' {
+		if start+1 == len(s) {
+			return 0, fmt.Errorf("unterminated escape sequence")
+		}
+		return start + 1, nil
+	}
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			if i+1 < len(s) && s[i+1] == '"' { // doubled quote inside a quoted range bound or composite field
+				i++
+				continue
+			}
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated quoted string")
+}
@@ -0,0 +1,80 @@
+package tgtdb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangeConverter(t *testing.T) {
+	assert := assert.New(t)
+	convert := ybValueConverterSuite["RANGE"]
+	testcases := []struct {
+		value            string
+		formatIfRequired bool
+		expected         string
+		unparseable      bool
+	}{
+		// int4range
+		{`[1,5)`, false, `[1,5)`, false},
+		{`(,10]`, false, `(,10]`, false},
+		{`empty`, false, `empty`, false},
+		{`[1,5)`, true, `'[1,5)'`, false},
+		{`{[1,3), [5,7)}`, false, `{[1,3), [5,7)}`, false},
+		{`{}`, false, `{}`, false},
+		{`[1,5`, false, ``, true},
+		{`[1,3,5)`, false, ``, true},
+		{`{[1,3), 5}`, false, ``, true},
+		// tstzrange
+		{`["2023-01-01 00:00:00+00","2023-06-01 00:00:00+00")`, false, `["2023-01-01 00:00:00+00","2023-06-01 00:00:00+00")`, false},
+		{`["2023-01-01 00:00:00+00",infinity)`, true, `'["2023-01-01 00:00:00+00",infinity)'`, false},
+		{`["2023-01-01 00:00:00+00,2023-06-01 00:00:00+00")`, false, ``, true},
+		{`["2023-01-01 00:00:00+00,"2023-06-01 00:00:00+00")`, false, ``, true},
+	}
+	for _, tc := range testcases {
+		converted, err := convert(tc.value, tc.formatIfRequired)
+		if tc.unparseable {
+			assert.True(errors.Is(err, ErrUnparseableValue), "%q: %v", tc.value, err)
+			continue
+		}
+		assert.NoError(err, "%q", tc.value)
+		assert.Equal(tc.expected, converted, "%q", tc.value)
+	}
+}
+
+func TestArrayOfCompositeConverter(t *testing.T) {
+	assert := assert.New(t)
+	convert := ybValueConverterSuite["ARRAY_OF_COMPOSITE"]
+	testcases := []struct {
+		value            string
+		formatIfRequired bool
+		expected         string
+		unparseable      bool
+	}{
+		{`{"(1,abc)","(2,def)"}`, true, `'{"(1,abc)","(2,def)"}'`, false},
+		{`{"(1,\"a,b\")",NULL}`, true, `'{"(1,\"a,b\")",NULL}'`, false},
+		{`{"(1,\"it's\")"}`, true, `'{"(1,\"it''s\")"}'`, false},
+		{`{"(1,\"say \"\"hi\"\"\")"}`, true, `'{"(1,\"say \"\"hi\"\"\")"}'`, false},
+		{`{{"(1,x)"},{"(2,y)"}}`, true, `'{{"(1,x)"},{"(2,y)"}}'`, false},
+		{`[1:2]={"(1,x)","(2,y)"}`, true, `'[1:2]={"(1,x)","(2,y)"}'`, false},
+		{`{}`, true, `'{}'`, false},
+		// values in the COPY text format have their backslashes, tabs and newlines escaped.
+		{`{"(1,\\"a\\tb\\")"}`, false, `{"(1,\\"a\\tb\\")"}`, false},
+		{`{"(1,\\"line1\nline2\\")"}`, false, `{"(1,\\"line1\nline2\\")"}`, false},
+		{`{"(1,\\"a,b\\")","(2,\\"{}\\")"}`, false, `{"(1,\\"a,b\\")","(2,\\"{}\\")"}`, false},
+		{`{"(1,abc)"`, true, ``, true},
+		{`{"(1,abc"}`, true, ``, true},
+		{`{"1,abc"}`, true, ``, true},
+		{`{"(1,\"abc)"}`, true, ``, true},
+	}
+	for _, tc := range testcases {
+		converted, err := convert(tc.value, tc.formatIfRequired)
+		if tc.unparseable {
+			assert.True(errors.Is(err, ErrUnparseableValue), "%q: %v", tc.value, err)
+			continue
+		}
+		assert.NoError(err, "%q", tc.value)
+		assert.Equal(tc.expected, converted, "%q", tc.value)
+	}
+}
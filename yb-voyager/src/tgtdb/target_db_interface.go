@@ -16,11 +16,19 @@ limitations under the License.
 package tgtdb
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"io"
+	"regexp"
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"github.com/sourcegraph/conc/pool"
+	"golang.org/x/exp/slices"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils/sqlname"
 )
 
 type TargetDB interface {
@@ -31,16 +39,109 @@ type TargetDB interface {
 	GetVersion() string
 	CreateVoyagerSchema() error
 	GetNonEmptyTables(tableNames []string) []string
+	// Returns the tables which don't exist on the target db.
+	GetMissingTables(tableNames []string) []string
 	IsNonRetryableCopyError(err error) bool
 	ImportBatch(batch Batch, args *ImportBatchArgs, exportDir string) (int64, error)
 	IfRequiredQuoteColumnNames(tableName string, columns []string) ([]string, error)
+	// Returns the types of the given columns, or of all the columns in the table order if `columns` is empty.
+	GetColumnTypes(tableName string, columns []string) ([]ColumnType, error)
+	// Returns the generated (computed) columns of the table, whose values can't be given in the COPY.
+	GetGeneratedColumns(tableName string) ([]string, error)
 	ExecuteBatch(migrationUUID uuid.UUID, batch *EventBatch) error
 	GetDebeziumValueConverterSuite() map[string]ConverterFn
 	GetEventChannelsMetaInfo(migrationUUID uuid.UUID) (map[int]EventChannelMetaInfo, error)
+	// Raises the last applied vsn of all the event channels to `vsn`.
+	// Must be called only after all the events up to `vsn` are applied.
+	CheckpointEventChannels(migrationUUID uuid.UUID, vsn int64) error
+	// Raises the last applied vsn of the channel to `vsn` without applying its events up to it, e.g. to skip
+	// a batch of events which can't be applied.
+	AdvanceEventChannel(migrationUUID uuid.UUID, chanNo int, vsn int64) error
 	GetTotalNumOfEventsImportedByType(migrationUUID uuid.UUID) (int64, int64, int64, error)
+	GetImportedEventCountsByTable(migrationUUID uuid.UUID) (map[string]*EventCounter, error)
+	GetRowCount(tableName string) (int64, error)
+	// Removes all the rows of the given tables.
+	TruncateTables(tableNames []string) error
+	// Returns the last value generated by the sequence, as far as it can be determined on the target db.
+	GetSequenceLastValue(sequenceName string) (int64, error)
+	// Returns the foreign key constraints which are disabled or not validated on the target db.
+	GetInvalidForeignKeys() ([]string, error)
+	// Returns the foreign keys of the given tables which are violated by their rows, e.g. loaded with the checks disabled.
+	GetForeignKeyViolations(tableNames []string) ([]*ForeignKeyViolation, error)
 	InitLiveMigrationState(migrationUUID uuid.UUID, numChans int, startClean bool, tableNames []string) error
 	MaxBatchSizeInBytes() int64
-	RestoreSequences(sequencesLastValue map[string]int64) error
+	// Restores each of the sequences to its last value on the source, continuing past the failures.
+	RestoreSequences(sequencesLastValue map[string]int64) []*SequenceRestoreResult
+	// Identifies the sessions of voyager on the target db, e.g. in pg_stat_activity.
+	SetApplicationName(applicationName string)
+	// Sets the schema of the table names without the schema name on the connection, which is a *pgx.Conn
+	// for YugabyteDB and a *sql.Conn for Oracle and MySQL. It can be called any number of times on a connection.
+	SetTargetSchema(conn interface{}) error
+}
+
+// SequenceRestoreResult is the outcome of the restore of a sequence to its last value on the source.
+type SequenceRestoreResult struct {
+	SequenceName string
+	LastValue    int64
+	Err          error
+}
+
+// Number of the violating rows of a foreign key reported in ForeignKeyViolation.SampleRows.
+const MAX_FOREIGN_KEY_VIOLATION_SAMPLE_ROWS = 10
+
+// ForeignKeyViolation is a foreign key constraint with the rows of its table which have no referenced row.
+type ForeignKeyViolation struct {
+	ConstraintName string `json:"constraint_name"`
+	TableName      string `json:"table_name"`
+	RefTableName   string `json:"referenced_table_name"`
+	NumRows        int64  `json:"num_rows"`
+	// values of the foreign key columns of some of the violating rows, e.g. (10,abc)
+	SampleRows []string `json:"sample_rows"`
+}
+
+// getSequencesToRestore returns the names of the sequences to restore, in order.
+func getSequencesToRestore(sequencesLastVal map[string]int64) []string {
+	sequenceNames := lo.Filter(lo.Keys(sequencesLastVal), func(sequenceName string, _ int) bool {
+		// TODO: can be valid for cases like cyclic sequences
+		return sequencesLastVal[sequenceName] != 0
+	})
+	slices.Sort(sequenceNames)
+	return sequenceNames
+}
+
+// filterTablesConcurrently returns the tables for which `fn` returns true, in the given order.
+// `fn` is called for up to `parallelism` tables at a time.
+func filterTablesConcurrently(tables []string, parallelism int, fn func(table string) (bool, error)) ([]string, error) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	matched := make([]bool, len(tables))
+	p := pool.New().WithErrors().WithMaxGoroutines(parallelism)
+	for i, table := range tables {
+		i, table := i, table
+		p.Go(func() error {
+			var err error
+			matched[i], err = fn(table)
+			return err
+		})
+	}
+	err := p.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return lo.Filter(tables, func(_ string, i int) bool { return matched[i] }), nil
+}
+
+// Phases of the import, included in the application_name of the target db sessions.
+const (
+	APPLICATION_PHASE_DDL       = "ddl"
+	APPLICATION_PHASE_SNAPSHOT  = "snapshot"
+	APPLICATION_PHASE_STREAMING = "streaming"
+)
+
+// GetApplicationName returns the application_name for the sessions of the given phase of the migration.
+func (t *TargetConf) GetApplicationName(migrationUUID uuid.UUID, phase string) string {
+	return fmt.Sprintf("%s:%s:%s", t.ApplicationName, migrationUUID, phase)
 }
 
 const (
@@ -50,21 +151,94 @@ const (
 	YUGABYTEDB = "yugabytedb"
 )
 
-// value converter Function type
+// Actions on the rows of a batch conflicting with the existing rows of the table, on YugabyteDB.
+const (
+	ON_PRIMARY_KEY_CONFLICT_ERROR  = "error"
+	ON_PRIMARY_KEY_CONFLICT_SKIP   = "skip"
+	ON_PRIMARY_KEY_CONFLICT_UPDATE = "update"
+)
+
+// Modes in which the insert events of a batch are applied on the target db.
+const (
+	APPLY_STATEMENT_MODE_PER_ROW   = "per-row"
+	APPLY_STATEMENT_MODE_MULTI_ROW = "multi-row"
+	APPLY_STATEMENT_MODE_COPY      = "copy"
+)
+
+/*
+ConverterFn converts a value exported by debezium, keyed by its (logical) type name, to the form the target db accepts.
+The snapshot rows are imported with COPY, and their values are converted with formatIfRequired false: the value is
+returned as is in the text format, without quotes. The values of the streamed events are embedded in the DML statements,
+and are converted with formatIfRequired true: the value must then be returned as a literal of the target db, quoted
+(and escaped) if it is a string. Hence, a converter returning a string must quote it only when formatIfRequired is set.
+*/
 type ConverterFn func(v string, formatIfRequired bool) (string, error)
 
+// Fields of the log entries identifying the batch (or the batch of events) they are about, so that the
+// lifecycle of a batch can be traced across the interleaved logs of the goroutines importing them.
+const (
+	LOG_FIELD_BATCH_ID       = "batch_id"
+	LOG_FIELD_EVENT_BATCH_ID = "event_batch_id"
+	LOG_FIELD_VSN            = "vsn"
+)
+
 type Batch interface {
-	Open() (*os.File, error)
+	Open() (io.ReadCloser, error)
 	GetFilePath() string
+	// GetBaseFilePath returns the path of the data file of the batch, which identifies it in the batch metadata
+	// along with the batch number.
+	GetBaseFilePath() string
+	GetBatchNumber() int64
 	GetTableName() string
+	// GetRecordCount returns the number of rows of the batch, excluding the header.
+	GetRecordCount() int64
+	// GetCorrelationID identifies the batch in the logs, see LOG_FIELD_BATCH_ID.
+	GetCorrelationID() string
 	GetQueryIsBatchAlreadyImported() string
 	GetQueryToRecordEntryInDB(rowsAffected int64) string
+	RecordRejectedRow(row string, reason string) error
+	// RecordAlreadyImported records that the batch was found imported by an earlier run, so nothing is imported.
+	RecordAlreadyImported()
+}
+
+// batchLogger returns the log entry to log the import of the batch with.
+func batchLogger(batch Batch) *log.Entry {
+	return log.WithField(LOG_FIELD_BATCH_ID, batch.GetCorrelationID())
+}
+
+// CopyError is returned by ImportBatch when the target db fails to import the batch with an error code,
+// classifying the failure by the code so that the caller doesn't have to inspect the error message.
+type CopyError struct {
+	// Error code of the target db, e.g. the SQLSTATE for YugabyteDB.
+	Code      string
+	Retryable bool
+	Err       error
+}
+
+func (e *CopyError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CopyError) Unwrap() error {
+	return e.Err
+}
+
+// Returns the CopyError in the chain of err, if any.
+func getCopyError(err error) (*CopyError, bool) {
+	var copyErr *CopyError
+	if errors.As(err, &copyErr) {
+		return copyErr, true
+	}
+	return nil, false
 }
 
 func NewTargetDB(tconf *TargetConf) TargetDB {
-	if tconf.TargetDBType == "oracle" {
+	if tconf.TargetDBType == ORACLE {
 		return newTargetOracleDB(tconf)
 	}
+	if tconf.TargetDBType == MYSQL {
+		return newTargetMySQLDB(tconf)
+	}
 	return newTargetYugabyteDB(tconf)
 }
 
@@ -79,8 +253,14 @@ type ImportBatchArgs struct {
 	QuoteChar  byte
 	EscapeChar byte
 	NullString string
+	// columns loaded by sqlldr without a NULLIF clause, so that the null marker is not turned into NULL for them
+	NoNullIfColumns []string
+	// action on the rows conflicting with the existing rows of the table, one of ON_PRIMARY_KEY_CONFLICT_*
+	OnPrimaryKeyConflict string
 
 	RowsPerTransaction int64
+	// extra options of the COPY command, as returned by ParseCopyOptions
+	CopyOptions []string
 }
 
 func (args *ImportBatchArgs) GetYBCopyStatement() string {
@@ -115,16 +295,115 @@ func (args *ImportBatchArgs) GetYBCopyStatement() string {
 	if args.NullString != "" {
 		options = append(options, fmt.Sprintf("NULL '%s'", args.NullString))
 	}
+	options = append(options, args.CopyOptions...)
 	return fmt.Sprintf(`COPY %s %s FROM STDIN WITH (%s)`, args.TableName, columns, strings.Join(options, ", "))
 }
 
+// Kinds of the values of the COPY options which can be passed through.
+const (
+	copyOptionBool   = "boolean"
+	copyOptionString = "string"
+)
+
+// COPY options which can be passed through with their kind of value. The options set from the data file descriptor
+// (FORMAT, DELIMITER, ...) and ROWS_PER_TRANSACTION are not allowed, as are the options taking a list of columns.
+// SKIP and REPLACE are not allowed either, as they break the row counts and the resumption of the batches.
+var passthroughCopyOptions = map[string]string{
+	"FREEZE":           copyOptionBool,
+	"DISABLE_FK_CHECK": copyOptionBool,
+	"ENCODING":         copyOptionString,
+}
+
+var (
+	copyOptionBoolValueRegex   = regexp.MustCompile(`^(?i)(true|false|on|off|1|0)$`)
+	copyOptionStringValueRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+/*
+ParseCopyOptions parses the `key=value` COPY options to be appended to the WITH clause of the COPY command, e.g.
+FREEZE=true. Only the options in passthroughCopyOptions are allowed, and their values are restricted to the
+characters of their kind, so that nothing but the option can be injected into the statement. The options are
+returned in the form of the WITH clause, e.g. FREEZE true. The server still rejects the options it doesn't support,
+e.g. FREEZE on a table not created or truncated in the transaction, failing the COPY.
+*/
+func ParseCopyOptions(specs []string) ([]string, error) {
+	var options, names []string
+	for _, spec := range specs {
+		name, value, found := strings.Cut(spec, "=")
+		name, value = strings.ToUpper(strings.TrimSpace(name)), strings.TrimSpace(value)
+		if !found || name == "" || value == "" {
+			return nil, fmt.Errorf("option %q must be of the form <key>=<value>", spec)
+		}
+		kind, ok := passthroughCopyOptions[name]
+		if !ok {
+			allowedNames := lo.Keys(passthroughCopyOptions)
+			slices.Sort(allowedNames)
+			return nil, fmt.Errorf("option %s is not allowed, the allowed options are: %v", name, allowedNames)
+		}
+		if slices.Contains(names, name) {
+			return nil, fmt.Errorf("option %s is given more than once", name)
+		}
+		names = append(names, name)
+		switch {
+		case kind == copyOptionBool && copyOptionBoolValueRegex.MatchString(value):
+			options = append(options, fmt.Sprintf("%s %s", name, strings.ToLower(value)))
+		case kind == copyOptionString && copyOptionStringValueRegex.MatchString(value):
+			options = append(options, fmt.Sprintf("%s '%s'", name, value))
+		default:
+			return nil, fmt.Errorf("invalid %s value %q of option %s", kind, value, name)
+		}
+	}
+	return options, nil
+}
+
+// HandlesPrimaryKeyConflicts returns whether the conflicting rows are skipped or updated, rather than failing the batch.
+func (args *ImportBatchArgs) HandlesPrimaryKeyConflicts() bool {
+	return args.OnPrimaryKeyConflict == ON_PRIMARY_KEY_CONFLICT_SKIP || args.OnPrimaryKeyConflict == ON_PRIMARY_KEY_CONFLICT_UPDATE
+}
+
+/*
+GetYBInsertOnConflictStatement returns the statement moving the rows of the batch, COPYed into the staging table,
+into the table as per OnPrimaryKeyConflict. `columns` are the columns of the batch, and `pkColumns` the primary key
+columns of the table, which are the conflict target, so that the violations of the other unique constraints still
+fail the batch. The values of the GENERATED ALWAYS identity columns are imported as they are, like COPY does.
+*/
+func (args *ImportBatchArgs) GetYBInsertOnConflictStatement(stagingTable string, columns []string, pkColumns []string) string {
+	insertColumns, selectColumns := "", "*"
+	if len(columns) > 0 {
+		insertColumns = fmt.Sprintf(" (%s)", strings.Join(columns, ", "))
+		selectColumns = strings.Join(columns, ", ")
+	}
+	var setClauses []string
+	if args.OnPrimaryKeyConflict == ON_PRIMARY_KEY_CONFLICT_UPDATE {
+		for _, col := range columns {
+			if !slices.Contains(pkColumns, col) {
+				setClauses = append(setClauses, fmt.Sprintf("%s = EXCLUDED.%s", col, col))
+			}
+		}
+	}
+	onConflict := fmt.Sprintf("ON CONFLICT (%s) DO NOTHING", strings.Join(pkColumns, ", "))
+	if len(setClauses) > 0 {
+		onConflict = fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(pkColumns, ", "), strings.Join(setClauses, ", "))
+	}
+	return fmt.Sprintf("INSERT INTO %s%s OVERRIDING SYSTEM VALUE SELECT %s FROM %s %s",
+		args.TableName, insertColumns, selectColumns, stagingTable, onConflict)
+}
+
 func (args *ImportBatchArgs) GetSqlLdrControlFile(schema string) string {
 	var columns string
 	if len(args.Columns) > 0 {
 		columnsSlice := make([]string, 0, len(args.Columns))
 		for _, col := range args.Columns {
+			noNullIf := slices.ContainsFunc(args.NoNullIfColumns, func(c string) bool {
+				return strings.EqualFold(strings.Trim(c, `"`), strings.Trim(col, `"`))
+			})
+			col = quoteOracleIdentifierIfRequired(col)
+			if noNullIf {
+				columnsSlice = append(columnsSlice, col)
+				continue
+			}
 			// Add the column name and the NULLIF clause after it
-			columnsSlice = append(columnsSlice, fmt.Sprintf(`%s NULLIF %s='\\N'`, col, col))
+			columnsSlice = append(columnsSlice, fmt.Sprintf(`%s NULLIF %s=%s`, col, col, args.getSqlLdrNullMarker()))
 		}
 		columns = fmt.Sprintf("(%s)", strings.Join(columnsSlice, ", "))
 	}
@@ -136,5 +415,44 @@ INTO TABLE %s
 REENABLE DISABLED_CONSTRAINTS
 FIELDS TERMINATED BY '%s'
 %s`
-	return fmt.Sprintf(configTemplate, args.FilePath, schema+"."+args.TableName, "\\t", columns)
+	return fmt.Sprintf(configTemplate, args.FilePath, qualifyOracleTableName(schema, args.TableName), "\\t", columns)
+}
+
+// getSqlLdrNullMarker returns the value compared against in the NULLIF clauses of the sqlldr control file.
+// Without an explicit null string, it is an empty field for CSV and \N for text, the defaults of COPY.
+func (args *ImportBatchArgs) getSqlLdrNullMarker() string {
+	nullString := args.NullString
+	if nullString == "" && args.FileFormat != "csv" {
+		nullString = `\N`
+	}
+	if nullString == "" {
+		return "BLANKS"
+	}
+	nullString = strings.ReplaceAll(nullString, `\`, `\\`)
+	nullString = strings.ReplaceAll(nullString, `'`, `''`)
+	return fmt.Sprintf("'%s'", nullString)
+}
+
+var oracleUnquotedIdentifierRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_$#]*$`)
+
+// quoteOracleIdentifierIfRequired quotes the table or column names which Oracle can't resolve unquoted:
+// reserved words, mixed/lower case names and names with special characters. Already quoted names are returned as is.
+func quoteOracleIdentifierIfRequired(name string) string {
+	if sqlname.IsQuoted(name) {
+		return name
+	}
+	if sqlname.IsReservedKeywordOracle(name) || sqlname.IsCaseSensitive(name, sqlname.ORACLE) ||
+		!oracleUnquotedIdentifierRegex.MatchString(name) {
+		return fmt.Sprintf(`"%s"`, name)
+	}
+	return name
+}
+
+// qualifyOracleTableName returns the table name qualified with the schema name. Unlike the table name, the schema
+// name is passed by the user and is case insensitive, so it is quoted (in upper case) only if it is a reserved word.
+func qualifyOracleTableName(schema string, tableName string) string {
+	if !sqlname.IsQuoted(schema) && sqlname.IsReservedKeywordOracle(strings.ToUpper(schema)) {
+		schema = fmt.Sprintf(`"%s"`, strings.ToUpper(schema))
+	}
+	return schema + "." + quoteOracleIdentifierIfRequired(tableName)
 }
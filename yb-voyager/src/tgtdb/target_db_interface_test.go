@@ -0,0 +1,286 @@
+package tgtdb
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteOracleIdentifierIfRequired(t *testing.T) {
+	assert := assert.New(t)
+	testcases := map[string]string{
+		"EMPLOYEES":   "EMPLOYEES",
+		"EMP_2$#":     "EMP_2$#",
+		"Employees":   `"Employees"`,
+		"employees":   `"employees"`,
+		`"Employees"`: `"Employees"`,
+		"TABLE":       `"TABLE"`,
+		"USER":        `"USER"`,
+		"FIRST NAME":  `"FIRST NAME"`,
+		"1ST_COL":     `"1ST_COL"`,
+	}
+	for name, expected := range testcases {
+		assert.Equal(expected, quoteOracleIdentifierIfRequired(name), "%q", name)
+	}
+}
+
+func TestGetSqlLdrControlFile(t *testing.T) {
+	assert := assert.New(t)
+	testcases := []struct {
+		schema    string
+		tableName string
+		columns   []string
+		expected  string
+	}{
+		{"TEST", "EMPLOYEES", []string{"ID", "NAME"},
+			`LOAD DATA
+INFILE '/tmp/batch'
+APPEND
+INTO TABLE TEST.EMPLOYEES
+REENABLE DISABLED_CONSTRAINTS
+FIELDS TERMINATED BY '\t'
+(ID NULLIF ID='\\N', NAME NULLIF NAME='\\N')`},
+		{"test", "MixedCase", []string{"Id", "SIZE"},
+			`LOAD DATA
+INFILE '/tmp/batch'
+APPEND
+INTO TABLE test."MixedCase"
+REENABLE DISABLED_CONSTRAINTS
+FIELDS TERMINATED BY '\t'
+("Id" NULLIF "Id"='\\N', "SIZE" NULLIF "SIZE"='\\N')`},
+		{"user", `"ORDER"`, nil,
+			`LOAD DATA
+INFILE '/tmp/batch'
+APPEND
+INTO TABLE "USER"."ORDER"
+REENABLE DISABLED_CONSTRAINTS
+FIELDS TERMINATED BY '\t'
+`},
+	}
+	for _, tc := range testcases {
+		args := &ImportBatchArgs{FilePath: "/tmp/batch", TableName: tc.tableName, Columns: tc.columns}
+		assert.Equal(tc.expected, args.GetSqlLdrControlFile(tc.schema), "%s.%s", tc.schema, tc.tableName)
+	}
+}
+
+func TestGetSqlLdrControlFileNullIf(t *testing.T) {
+	assert := assert.New(t)
+	testcases := []struct {
+		fileFormat      string
+		nullString      string
+		noNullIfColumns []string
+		expected        string
+	}{
+		// text files without an explicit null string use \N
+		{"text", "", nil, `(ID NULLIF ID='\\N', NAME NULLIF NAME='\\N')`},
+		// empty fields are NULL in CSV files without an explicit null string
+		{"csv", "", nil, `(ID NULLIF ID=BLANKS, NAME NULLIF NAME=BLANKS)`},
+		// configured marker
+		{"csv", "NULL", nil, `(ID NULLIF ID='NULL', NAME NULLIF NAME='NULL')`},
+		{"text", `it's \null`, nil, `(ID NULLIF ID='it''s \\null', NAME NULLIF NAME='it''s \\null')`},
+		// a column holding literal \N data
+		{"text", "", []string{"name"}, `(ID NULLIF ID='\\N', NAME)`},
+	}
+	for _, tc := range testcases {
+		args := &ImportBatchArgs{FilePath: "/tmp/batch", TableName: "EMPLOYEES", Columns: []string{"ID", "NAME"},
+			FileFormat: tc.fileFormat, NullString: tc.nullString, NoNullIfColumns: tc.noNullIfColumns}
+		expected := `LOAD DATA
+INFILE '/tmp/batch'
+APPEND
+INTO TABLE TEST.EMPLOYEES
+REENABLE DISABLED_CONSTRAINTS
+FIELDS TERMINATED BY '\t'
+` + tc.expected
+		assert.Equal(expected, args.GetSqlLdrControlFile("TEST"), "%+v", tc)
+	}
+}
+
+func TestIsNonRetryableCopyError(t *testing.T) {
+	assert := assert.New(t)
+	yb := &TargetYugabyteDB{}
+	testcases := []struct {
+		err          error
+		nonRetryable bool
+	}{
+		{&pgconn.PgError{Code: "23505", Message: "duplicate key value violates unique constraint \"t_pkey\""}, true},
+		{&pgconn.PgError{Code: "23505", Message: "duplicate key value"}, true},
+		{&pgconn.PgError{Code: "22P02", Message: "invalid input syntax for type integer"}, true},
+		{&pgconn.PgError{Code: "40001", Message: "could not serialize access due to concurrent update"}, false},
+		{&pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"}, false},
+		{&pgconn.PgError{Code: "XX000", Message: "Sending too long RPC message"}, true},
+		{&pgconn.PgError{Code: "XX000", Message: "Timed out: Perform RPC timed out"}, false},
+		{fmt.Errorf("syntax error at line 1"), true},
+		{fmt.Errorf("connection reset by peer"), false},
+	}
+	for _, tc := range testcases {
+		err := newYBCopyError(fmt.Errorf("COPY t: %w", tc.err))
+		assert.Equal(tc.nonRetryable, yb.IsNonRetryableCopyError(err), "%s", err)
+	}
+
+	err := newYBCopyError(&pgconn.PgError{Code: "40001", Message: "could not serialize access"})
+	copyErr, ok := getCopyError(fmt.Errorf("import batch: %w", err))
+	assert.True(ok)
+	assert.Equal("40001", copyErr.Code)
+	assert.True(copyErr.Retryable)
+}
+
+func TestGetYBInsertOnConflictStatement(t *testing.T) {
+	assert := assert.New(t)
+	testcases := []struct {
+		onConflict string
+		columns    []string
+		pkColumns  []string
+		expected   string
+	}{
+		{ON_PRIMARY_KEY_CONFLICT_SKIP, []string{"id", "name"}, []string{"id"},
+			`INSERT INTO public.t (id, name) OVERRIDING SYSTEM VALUE SELECT id, name FROM staging ON CONFLICT (id) DO NOTHING`},
+		{ON_PRIMARY_KEY_CONFLICT_SKIP, nil, []string{"a", "b"},
+			`INSERT INTO public.t OVERRIDING SYSTEM VALUE SELECT * FROM staging ON CONFLICT (a, b) DO NOTHING`},
+		{ON_PRIMARY_KEY_CONFLICT_UPDATE, []string{"id", `"Name"`, "age"}, []string{"id"},
+			`INSERT INTO public.t (id, "Name", age) OVERRIDING SYSTEM VALUE SELECT id, "Name", age FROM staging ON CONFLICT (id) DO UPDATE SET "Name" = EXCLUDED."Name", age = EXCLUDED.age`},
+		{ON_PRIMARY_KEY_CONFLICT_UPDATE, []string{"a", "b", "c"}, []string{"a", "b"},
+			`INSERT INTO public.t (a, b, c) OVERRIDING SYSTEM VALUE SELECT a, b, c FROM staging ON CONFLICT (a, b) DO UPDATE SET c = EXCLUDED.c`},
+		// nothing to update when all the columns are in the primary key
+		{ON_PRIMARY_KEY_CONFLICT_UPDATE, []string{"a", "b"}, []string{"a", "b"},
+			`INSERT INTO public.t (a, b) OVERRIDING SYSTEM VALUE SELECT a, b FROM staging ON CONFLICT (a, b) DO NOTHING`},
+	}
+	for _, tc := range testcases {
+		args := &ImportBatchArgs{TableName: "public.t", OnPrimaryKeyConflict: tc.onConflict}
+		assert.Equal(tc.expected, args.GetYBInsertOnConflictStatement("staging", tc.columns, tc.pkColumns))
+	}
+}
+
+func TestFilterTablesConcurrently(t *testing.T) {
+	assert := assert.New(t)
+	var tables []string
+	for i := 0; i < 50; i++ {
+		tables = append(tables, fmt.Sprintf("public.t%d", i))
+	}
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	isNonEmpty := func(table string) (bool, error) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		var i int
+		fmt.Sscanf(table, "public.t%d", &i)
+		return i%3 == 0, nil
+	}
+	result, err := filterTablesConcurrently(tables, 4, isNonEmpty)
+	assert.NoError(err)
+	expected := lo.Filter(tables, func(_ string, i int) bool { return i%3 == 0 })
+	assert.Equal(expected, result)
+	assert.LessOrEqual(maxRunning, 4)
+	assert.Greater(maxRunning, 1)
+
+	_, err = filterTablesConcurrently(tables, 4, func(table string) (bool, error) {
+		if table == "public.t7" {
+			return false, fmt.Errorf("failed to check whether table %q empty", table)
+		}
+		return true, nil
+	})
+	assert.ErrorContains(err, "public.t7")
+}
+
+func TestGetSequencesToRestore(t *testing.T) {
+	sequencesLastVal := map[string]int64{`public."Orders_id_seq"`: 10, "public.items_id_seq": 5, "public.unused_seq": 0}
+	assert.Equal(t, []string{`public."Orders_id_seq"`, "public.items_id_seq"}, getSequencesToRestore(sequencesLastVal))
+}
+
+func TestGetSequenceRestoreStmt(t *testing.T) {
+	yb := &TargetYugabyteDB{tconf: &TargetConf{Schema: "public"}}
+	testcases := []struct {
+		sequenceName string
+		owner        *identityColumn
+		expected     string
+	}{
+		{"orders_id_seq", nil, "SELECT pg_catalog.setval('public.orders_id_seq', 100, true)"},
+		{`"Orders_id_seq"`, nil, `SELECT pg_catalog.setval('public."Orders_id_seq"', 100, true)`},
+		{`"Sales"."Orders_id_seq"`, nil, `SELECT pg_catalog.setval('"Sales"."Orders_id_seq"', 100, true)`},
+		{`"it's_seq"`, nil, `SELECT pg_catalog.setval('public."it''s_seq"', 100, true)`},
+		// the sequences owned by identity columns are restarted through the column
+		{`"Orders_id_seq"`, &identityColumn{tableName: `"Sales"."Orders"`, columnName: "Id", increment: 1},
+			`ALTER TABLE "Sales"."Orders" ALTER COLUMN "Id" RESTART WITH 101`},
+		{"orders_id_seq", &identityColumn{tableName: "orders", columnName: "id", increment: 5},
+			`ALTER TABLE orders ALTER COLUMN "id" RESTART WITH 105`},
+	}
+	for _, tc := range testcases {
+		assert.Equal(t, tc.expected, getSequenceRestoreStmt(yb.qualifyTableName(tc.sequenceName), 100, tc.owner), tc.sequenceName)
+	}
+}
+
+func TestGetForeignKeyViolationsQuery(t *testing.T) {
+	fk := &foreignKey{name: "orders_fk", tableName: "public.orders", refTableName: `public."Customers"`,
+		columns: []string{"region", "cust_id"}, refColumns: []string{"region", `"Id"`}}
+	assert.Equal(t, `SELECT count(*) OVER (), ROW(c.region, c.cust_id)::text FROM public.orders c `+
+		`WHERE c.region IS NOT NULL AND c.cust_id IS NOT NULL `+
+		`AND NOT EXISTS (SELECT 1 FROM public."Customers" p WHERE p.region = c.region AND p."Id" = c.cust_id) LIMIT 10`,
+		getForeignKeyViolationsQuery(fk))
+}
+
+func TestEventBatchCorrelationID(t *testing.T) {
+	events := []*Event{
+		{Vsn: 1001, Op: "c", SchemaName: "public", TableName: "t1"},
+		{Vsn: 1005, Op: "u", SchemaName: "public", TableName: "t2"},
+		{Vsn: 1050, Op: "d", SchemaName: "public", TableName: "t1"},
+	}
+	assert.Equal(t, "ch3:1001-1050", NewEventBatch(events, 3, "public").GetCorrelationID())
+	assert.Equal(t, "ch0:1005-1005", NewEventBatch(events[1:2], 0, "public").GetCorrelationID())
+}
+
+func TestParseCopyOptions(t *testing.T) {
+	assert := assert.New(t)
+	options, err := ParseCopyOptions([]string{"freeze=TRUE", " DISABLE_FK_CHECK = on ", "ENCODING=UTF8"})
+	assert.NoError(err)
+	assert.Equal([]string{"FREEZE true", "DISABLE_FK_CHECK on", "ENCODING 'UTF8'"}, options)
+
+	options, err = ParseCopyOptions(nil)
+	assert.NoError(err)
+	assert.Empty(options)
+
+	for _, specs := range [][]string{
+		{"FREEZE"},
+		{"=true"},
+		{"FORMAT=csv"},
+		{"ROWS_PER_TRANSACTION=100"},
+		{"FREEZE=true", "FREEZE=false"},
+		{"FREEZE=yes"},
+		{"SKIP=10"},
+		{"REPLACE=true"},
+		{"FREEZE=true); DROP TABLE t1; --"},
+		{"ENCODING=UTF8'); DROP TABLE t1; --"},
+	} {
+		_, err = ParseCopyOptions(specs)
+		assert.Error(err, "%v", specs)
+	}
+}
+
+func TestGetYBCopyStatementWithCopyOptions(t *testing.T) {
+	copyOptions, err := ParseCopyOptions([]string{"FREEZE=true", "ENCODING=LATIN1"})
+	assert.NoError(t, err)
+	args := &ImportBatchArgs{
+		TableName:          "public.t1",
+		Columns:            []string{"id", "name"},
+		FileFormat:         "csv",
+		HasHeader:          true,
+		Delimiter:          ",",
+		NullString:         `\N`,
+		RowsPerTransaction: 1000,
+		CopyOptions:        copyOptions,
+	}
+	assert.Equal(t,
+		`COPY public.t1 (id, name) FROM STDIN WITH (FORMAT 'csv', ROWS_PER_TRANSACTION 1000, HEADER, DELIMITER E',', NULL '\N', FREEZE true, ENCODING 'LATIN1')`,
+		args.GetYBCopyStatement())
+}
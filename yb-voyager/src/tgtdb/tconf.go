@@ -18,12 +18,17 @@ package tgtdb
 import (
 	"fmt"
 	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/exp/slices"
 
 	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
 )
 
 type TargetConf struct {
 	TargetDBType         string
+	SourceDBType         string // of the migration, e.g. for the orafce schema in the search_path for the Oracle source
 	Host                 string
 	Port                 int
 	User                 string
@@ -55,6 +60,71 @@ type TargetConf struct {
 	EnableUpsert               bool
 	DisableTransactionalWrites bool
 	Parallelism                int
+	ApplyStatementMode         string
+	// base of the application_name set on the target db sessions
+	ApplicationName string
+
+	// sqlldr options for the Oracle target
+	SqlldrDirectPath bool
+	SqlldrRows       int
+	SqlldrBindSize   int
+	SqlldrParallel   bool
+	// use INSERT statements to import data into Oracle if sqlldr is not available
+	SqlldrInsertFallback bool
+	// maximum number of concurrent sqlldr processes, defaults to Parallelism
+	OracleLoaderParallelism int
+	// keep the bad and log files of the sqlldr runs which rejected rows
+	KeepRejects bool
+
+	// target schema of the tables of each source schema (in lower case), instead of Schema
+	SchemaMap map[string]string
+
+	// fail, rather than import with the foreign keys and triggers enforced, if the import sessions can't skip them
+	DisableConstraintsDuringLoad bool
+}
+
+// GetTargetSchemaName returns the schema of the table, which is the target schema if the name is not qualified.
+// The schema of a qualified name is mapped as per the SchemaMap.
+func (t *TargetConf) GetTargetSchemaName(tableName string) string {
+	parts := strings.Split(tableName, ".")
+	if len(parts) == 2 {
+		if targetSchema, ok := t.MapSourceSchema(parts[0]); ok {
+			return targetSchema
+		}
+		return parts[0]
+	}
+	return t.Schema // default set to "public" for YugabyteDB
+}
+
+// MapSourceSchema returns the target schema of the tables of the source schema, if it is in the SchemaMap.
+func (t *TargetConf) MapSourceSchema(sourceSchema string) (string, bool) {
+	targetSchema, ok := t.SchemaMap[strings.ToLower(strings.Trim(sourceSchema, `"`))]
+	return targetSchema, ok
+}
+
+// GetTargetTableName returns the name of the table in the target, which has the schema mapped as per the SchemaMap
+// if the name is qualified with one of the mapped source schemas.
+func (t *TargetConf) GetTargetTableName(tableName string) string {
+	schemaName, name, found := strings.Cut(tableName, ".")
+	if !found {
+		return tableName
+	}
+	if targetSchema, ok := t.MapSourceSchema(schemaName); ok {
+		return targetSchema + "." + name
+	}
+	return tableName
+}
+
+// GetTargetSchemaNames returns the target schema along with the target schemas of the SchemaMap.
+func (t *TargetConf) GetTargetSchemaNames() []string {
+	schemaNames := []string{t.Schema}
+	for _, targetSchema := range t.SchemaMap {
+		if !slices.Contains(schemaNames, targetSchema) {
+			schemaNames = append(schemaNames, targetSchema)
+		}
+	}
+	slices.Sort(schemaNames[1:])
+	return schemaNames
 }
 
 func (t *TargetConf) Clone() *TargetConf {
@@ -89,19 +159,21 @@ func generateSSLQueryStringIfNotExists(t *TargetConf) string {
 
 		if t.SSLMode == "disable" || t.SSLMode == "allow" || t.SSLMode == "prefer" || t.SSLMode == "require" || t.SSLMode == "verify-ca" || t.SSLMode == "verify-full" {
 			SSLQueryString = "sslmode=" + t.SSLMode
-			if t.SSLMode == "require" || t.SSLMode == "verify-ca" || t.SSLMode == "verify-full" {
-
+			// The client certificate is presented whenever SSL is used, for the mutual TLS.
+			if t.SSLMode != "disable" {
 				if t.SSLCertPath != "" {
-					SSLQueryString += "&sslcert=" + t.SSLCertPath
+					SSLQueryString += "&sslcert=" + url.QueryEscape(t.SSLCertPath)
 				}
 				if t.SSLKey != "" {
-					SSLQueryString += "&sslkey=" + t.SSLKey
+					SSLQueryString += "&sslkey=" + url.QueryEscape(t.SSLKey)
 				}
+			}
+			if t.SSLMode == "require" || t.SSLMode == "verify-ca" || t.SSLMode == "verify-full" {
 				if t.SSLRootCert != "" {
-					SSLQueryString += "&sslrootcert=" + t.SSLRootCert
+					SSLQueryString += "&sslrootcert=" + url.QueryEscape(t.SSLRootCert)
 				}
 				if t.SSLCRL != "" {
-					SSLQueryString += "&sslcrl=" + t.SSLCRL
+					SSLQueryString += "&sslcrl=" + url.QueryEscape(t.SSLCRL)
 				}
 			}
 		} else {
@@ -113,6 +185,31 @@ func generateSSLQueryStringIfNotExists(t *TargetConf) string {
 	return SSLQueryString
 }
 
+// CheckSSLFiles returns an error if any of the given SSL files is not readable, so that it is reported
+// clearly instead of as a failure to connect.
+func (t *TargetConf) CheckSSLFiles() error {
+	if (t.SSLCertPath == "") != (t.SSLKey == "") {
+		return fmt.Errorf("both the SSL certificate and the SSL key are required for the client certificate authentication")
+	}
+	sslFiles := []struct{ name, path string }{
+		{"SSL certificate", t.SSLCertPath},
+		{"SSL key", t.SSLKey},
+		{"SSL root certificate", t.SSLRootCert},
+		{"SSL CRL", t.SSLCRL},
+	}
+	for _, sslFile := range sslFiles {
+		if sslFile.path == "" {
+			continue
+		}
+		file, err := os.Open(sslFile.path)
+		if err != nil {
+			return fmt.Errorf("read %s %q: %w", sslFile.name, sslFile.path, err)
+		}
+		file.Close()
+	}
+	return nil
+}
+
 func GetRedactedTargetConf(t *TargetConf) *TargetConf {
 	redacted := *t
 	redacted.Uri = utils.GetRedactedURLs([]string{t.Uri})[0]
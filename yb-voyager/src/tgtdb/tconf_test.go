@@ -0,0 +1,74 @@
+package tgtdb
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetConnectionUriWithSSLFiles(t *testing.T) {
+	assert := assert.New(t)
+	tconf := &TargetConf{
+		Host: "localhost", Port: 5433, User: "yugabyte", DBName: "db1", SSLMode: "prefer",
+		SSLCertPath: "/certs/client cert.crt", SSLKey: "/certs/client.key", SSLRootCert: "/certs/root.crt",
+	}
+	uri, err := url.Parse(tconf.GetConnectionUri())
+	assert.NoError(err)
+	query := uri.Query()
+	assert.Equal("prefer", query.Get("sslmode"))
+	assert.Equal("/certs/client cert.crt", query.Get("sslcert"))
+	assert.Equal("/certs/client.key", query.Get("sslkey"))
+	assert.False(query.Has("sslrootcert")) // verified only in require, verify-ca and verify-full
+
+	tconf.Uri, tconf.SSLMode = "", "verify-full"
+	uri, err = url.Parse(tconf.GetConnectionUri())
+	assert.NoError(err)
+	assert.Equal("/certs/root.crt", uri.Query().Get("sslrootcert"))
+	assert.Equal("/certs/client cert.crt", uri.Query().Get("sslcert"))
+
+	tconf.Uri, tconf.SSLMode = "", "disable"
+	uri, err = url.Parse(tconf.GetConnectionUri())
+	assert.NoError(err)
+	assert.False(uri.Query().Has("sslcert"))
+}
+
+func TestCheckSSLFiles(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	assert.NoError(os.WriteFile(certPath, []byte("cert"), 0600))
+	assert.NoError(os.WriteFile(keyPath, []byte("key"), 0600))
+
+	assert.NoError((&TargetConf{}).CheckSSLFiles())
+	assert.NoError((&TargetConf{SSLCertPath: certPath, SSLKey: keyPath}).CheckSSLFiles())
+	assert.ErrorContains((&TargetConf{SSLCertPath: certPath}).CheckSSLFiles(), "both the SSL certificate and the SSL key are required")
+	assert.ErrorContains((&TargetConf{SSLCertPath: certPath, SSLKey: keyPath, SSLRootCert: filepath.Join(dir, "root.crt")}).CheckSSLFiles(),
+		"read SSL root certificate")
+}
+
+func TestGetTargetSchemaName(t *testing.T) {
+	assert := assert.New(t)
+	tconf := &TargetConf{Schema: "sales"}
+	assert.Equal("sales", tconf.GetTargetSchemaName("orders"))
+	assert.Equal("hr", tconf.GetTargetSchemaName("hr.employees"))
+	assert.Equal(`"Hr"`, tconf.GetTargetSchemaName(`"Hr".employees`))
+
+	tconf.SchemaMap = map[string]string{"hr": "hr_v2", "finance": "sales"}
+	assert.Equal("sales", tconf.GetTargetSchemaName("orders"))
+	assert.Equal("hr_v2", tconf.GetTargetSchemaName("HR.employees"))
+	assert.Equal("ops", tconf.GetTargetSchemaName("ops.tickets"))
+	assert.Equal("hr_v2.employees", tconf.GetTargetTableName(`"HR".employees`))
+	assert.Equal("ops.tickets", tconf.GetTargetTableName("ops.tickets"))
+	assert.Equal("orders", tconf.GetTargetTableName("orders"))
+	assert.Equal([]string{"sales", "hr_v2"}, tconf.GetTargetSchemaNames())
+}
+
+func TestYBQualifyTableNameMapsOnce(t *testing.T) {
+	yb := &TargetYugabyteDB{tconf: &TargetConf{Schema: "public", SchemaMap: map[string]string{"a": "b", "b": "c"}}}
+	// the names are of the target tables, already mapped
+	assert.Equal(t, "b.foo", yb.qualifyTableName("b.foo"))
+	assert.Equal(t, "public.foo", yb.qualifyTableName("foo"))
+}
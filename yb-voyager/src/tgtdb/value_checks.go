@@ -0,0 +1,110 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tgtdb
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrLossyValue is returned by the value checks for the values which the target db would silently
+// coerce on import (e.g. round to the scale of a numeric column), losing data.
+var ErrLossyValue = errors.New("value would be coerced by the target column type")
+
+// ErrValueTooLarge is returned for the values larger than the configured maximum value size.
+var ErrValueTooLarge = errors.New("value too large")
+
+type ColumnType struct {
+	Name string
+	Type string // as reported by format_type(), e.g. `numeric(10,2)`, `timestamp(3) without time zone`
+}
+
+// ValueCheckFn validates a (non-null) value in the COPY text format against the type of its target column.
+type ValueCheckFn func(value string) error
+
+var columnTypeRegexp = regexp.MustCompile(`^(numeric|timestamp|time)(?:\((\d+)(?:,(\d+))?\))?(?: with(?:out)? time zone)?$`)
+var fractionalSecondsRegexp = regexp.MustCompile(`\d:\d\d\.(\d+)`)
+
+// Precision of the fractional seconds of the timestamp and time types when it isn't given.
+const DEFAULT_FRACTIONAL_SECONDS_PRECISION = 6
+
+// NewValueCheckFn returns the check for the values of a column of the given type, or nil when the
+// values of the type are not coerced. Only numeric types with a precision and the timestamp/time types are checked.
+func NewValueCheckFn(columnType string) ValueCheckFn {
+	matches := columnTypeRegexp.FindStringSubmatch(columnType)
+	if matches == nil {
+		return nil
+	}
+	switch matches[1] {
+	case "numeric":
+		if matches[2] == "" {
+			return nil // unconstrained numeric stores the values as is
+		}
+		precision, _ := strconv.Atoi(matches[2])
+		scale := 0
+		if matches[3] != "" {
+			scale, _ = strconv.Atoi(matches[3])
+		}
+		return func(value string) error {
+			return checkNumericValue(value, precision, scale)
+		}
+	default:
+		precision := DEFAULT_FRACTIONAL_SECONDS_PRECISION
+		if matches[2] != "" {
+			precision, _ = strconv.Atoi(matches[2])
+		}
+		return func(value string) error {
+			return checkFractionalSeconds(value, precision)
+		}
+	}
+}
+
+func checkNumericValue(value string, precision, scale int) error {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "NaN") {
+		return nil
+	}
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil // invalid input, the target db reports it
+	}
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(scale)))
+	if !scaled.IsInt() {
+		return fmt.Errorf("%w: %q has more than %d digits after the decimal point of numeric(%d,%d)",
+			ErrLossyValue, value, scale, precision, scale)
+	}
+	if new(big.Int).Abs(scaled.Num()).Cmp(pow10(precision)) >= 0 {
+		return fmt.Errorf("%w: %q exceeds the precision of numeric(%d,%d)", ErrLossyValue, value, precision, scale)
+	}
+	return nil
+}
+
+func checkFractionalSeconds(value string, precision int) error {
+	matches := fractionalSecondsRegexp.FindStringSubmatch(value)
+	// trailing zeros are not lost
+	if matches == nil || len(strings.TrimRight(matches[1], "0")) <= precision {
+		return nil
+	}
+	return fmt.Errorf("%w: %q has more than %d digits of fractional seconds", ErrLossyValue, value, precision)
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tgtdb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueCheckFn(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, columnType := range []string{"integer", "numeric", "text", "timestamp with time zone[]", "numeric(5,2)[]", "date"} {
+		assert.Nil(NewValueCheckFn(columnType), columnType)
+	}
+
+	testcases := []struct {
+		columnType string
+		value      string
+		lossy      bool
+	}{
+		{"numeric(5,2)", "123.45", false},
+		{"numeric(5,2)", "-123.4", false},
+		{"numeric(5,2)", "123.456", true},
+		{"numeric(5,2)", "1234.5", true},
+		{"numeric(5,2)", "1.5e2", false},
+		{"numeric(5,2)", "1.2345e2", false},
+		{"numeric(5,2)", "1.23456e2", true},
+		{"numeric(5,2)", "NaN", false},
+		{"numeric(5)", "12345", false},
+		{"numeric(5)", "1.5", true},
+		{"timestamp(3) without time zone", "2023-01-01 10:00:00.123", false},
+		{"timestamp(3) without time zone", "2023-01-01 10:00:00.123000", false},
+		{"timestamp(3) without time zone", "2023-01-01 10:00:00.1234", true},
+		{"timestamp with time zone", "2023-01-01 10:00:00.123456+05:30", false},
+		{"timestamp with time zone", "2023-01-01 10:00:00.1234567+05:30", true},
+		{"timestamp(0) without time zone", "2023-01-01 10:00:00", false},
+		{"timestamp(0) without time zone", "infinity", false},
+		{"time(2) without time zone", "10:00:00.123", true},
+	}
+	for _, tc := range testcases {
+		err := NewValueCheckFn(tc.columnType)(tc.value)
+		if tc.lossy {
+			assert.True(errors.Is(err, ErrLossyValue), "%s %q: %v", tc.columnType, tc.value, err)
+		} else {
+			assert.NoError(err, "%s %q", tc.columnType, tc.value)
+		}
+	}
+}
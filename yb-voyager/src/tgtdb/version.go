@@ -0,0 +1,70 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tgtdb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// YugabyteDB server_version, e.g. 11.2-YB-2.18.1.0-b84
+	ybVersionRegexp = regexp.MustCompile(`-YB-(\d+(?:\.\d+)*)`)
+	// Oracle banner, e.g. Oracle Database 19c Enterprise Edition Release 19.0.0.0.0 - Production
+	oracleVersionRegexp = regexp.MustCompile(`Release (\d+(?:\.\d+)*)`)
+	// PostgreSQL server_version, e.g. 15.3 (Ubuntu 15.3-1.pgdg22.04+1), or a version passed by the user
+	plainVersionRegexp = regexp.MustCompile(`^\s*(\d+(?:\.\d+)*)`)
+)
+
+// ParseVersion extracts the numeric components of the version from the version string reported by the target db.
+// For YugabyteDB, it is the YB version rather than the PG version it is based on.
+func ParseVersion(dbVersion string) ([]int, error) {
+	var versionStr string
+	for _, re := range []*regexp.Regexp{ybVersionRegexp, oracleVersionRegexp, plainVersionRegexp} {
+		matches := re.FindStringSubmatch(dbVersion)
+		if matches != nil {
+			versionStr = matches[1]
+			break
+		}
+	}
+	if versionStr == "" {
+		return nil, fmt.Errorf("no version number found in %q", dbVersion)
+	}
+	var version []int
+	for _, part := range strings.Split(versionStr, ".") {
+		num, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("parse version %q: %w", versionStr, err)
+		}
+		version = append(version, num)
+	}
+	return version, nil
+}
+
+// CompareVersions returns -1, 0 or 1 if v1 is lower than, equal to or higher than v2.
+// Only the components present in both are compared, so 2.18 is equal to 2.18.1.0.
+func CompareVersions(v1, v2 []int) int {
+	for i := 0; i < len(v1) && i < len(v2); i++ {
+		if v1[i] < v2[i] {
+			return -1
+		} else if v1[i] > v2[i] {
+			return 1
+		}
+	}
+	return 0
+}
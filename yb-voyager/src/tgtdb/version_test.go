@@ -0,0 +1,35 @@
+package tgtdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	assert := assert.New(t)
+	testcases := []struct {
+		dbVersion string
+		expected  []int
+	}{
+		{"11.2-YB-2.18.1.0-b84", []int{2, 18, 1, 0}},
+		{"Oracle Database 19c Enterprise Edition Release 19.0.0.0.0 - Production", []int{19, 0, 0, 0, 0}},
+		{"15.3 (Ubuntu 15.3-1.pgdg22.04+1)", []int{15, 3}},
+		{"2.18", []int{2, 18}},
+	}
+	for _, tc := range testcases {
+		version, err := ParseVersion(tc.dbVersion)
+		assert.NoError(err, tc.dbVersion)
+		assert.Equal(tc.expected, version, tc.dbVersion)
+	}
+	_, err := ParseVersion("unknown")
+	assert.Error(err)
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(-1, CompareVersions([]int{2, 16, 5}, []int{2, 18}))
+	assert.Equal(0, CompareVersions([]int{2, 18, 1, 0}, []int{2, 18}))
+	assert.Equal(1, CompareVersions([]int{2, 19}, []int{2, 18, 9}))
+	assert.Equal(1, CompareVersions([]int{21}, []int{19}))
+}
@@ -23,17 +23,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/google/uuid"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
+	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/exp/slices"
 
@@ -46,6 +49,8 @@ type TargetYugabyteDB struct {
 	tconf    *TargetConf
 	conn_    *pgx.Conn
 	connPool *ConnectionPool
+
+	targetSchemaChecked atomic.Bool // whether the existence of the target schema is checked
 }
 
 var ybValueConverterSuite = map[string]ConverterFn{
@@ -222,6 +227,12 @@ var ybValueConverterSuite = map[string]ConverterFn{
 		}
 		return columnValue, nil
 	},
+	"RANGE": func(columnValue string, formatIfRequired bool) (string, error) {
+		return convertPgLiteral(columnValue, formatIfRequired, validateRangeLiteral)
+	},
+	"ARRAY_OF_COMPOSITE": func(columnValue string, formatIfRequired bool) (string, error) {
+		return convertPgLiteral(columnValue, formatIfRequired, validateCompositeArrayLiteral)
+	},
 }
 
 func newTargetYugabyteDB(tconf *TargetConf) *TargetYugabyteDB {
@@ -234,16 +245,23 @@ func (yb *TargetYugabyteDB) Init() error {
 		return err
 	}
 
-	checkSchemaExistsQuery := fmt.Sprintf(
-		"SELECT count(schema_name) FROM information_schema.schemata WHERE schema_name = '%s'",
-		yb.tconf.Schema)
-	var cntSchemaName int
-	if err = yb.conn_.QueryRow(context.Background(), checkSchemaExistsQuery).Scan(&cntSchemaName); err != nil {
-		err = fmt.Errorf("run query %q on target %q to check schema exists: %s", checkSchemaExistsQuery, yb.tconf.Host, err)
-	} else if cntSchemaName == 0 {
-		err = fmt.Errorf("schema '%s' does not exist in target", yb.tconf.Schema)
+	return yb.checkTargetSchemasExist(yb.conn_)
+}
+
+// checkTargetSchemasExist checks that the target schema, and the target schemas of the schema map, exist.
+func (yb *TargetYugabyteDB) checkTargetSchemasExist(conn *pgx.Conn) error {
+	for _, schemaName := range yb.tconf.GetTargetSchemaNames() {
+		checkSchemaExistsQuery := fmt.Sprintf(
+			"SELECT count(schema_name) FROM information_schema.schemata WHERE schema_name = '%s'", schemaName)
+		var cntSchemaName int
+		err := conn.QueryRow(context.Background(), checkSchemaExistsQuery).Scan(&cntSchemaName)
+		if err != nil {
+			return fmt.Errorf("run query %q on target %q to check schema exists: %w", checkSchemaExistsQuery, yb.tconf.Host, err)
+		} else if cntSchemaName == 0 {
+			return fmt.Errorf("schema '%s' does not exist in target", schemaName)
+		}
 	}
-	return err
+	return nil
 }
 
 func (yb *TargetYugabyteDB) Finalize() {
@@ -286,7 +304,11 @@ func (yb *TargetYugabyteDB) connect() error {
 	if err != nil {
 		return fmt.Errorf("connect to target db: %w", err)
 	}
-	yb.setTargetSchema(conn)
+	err = yb.SetTargetSchema(conn)
+	if err != nil {
+		conn.Close(context.Background())
+		return err
+	}
 	yb.conn_ = conn
 	return nil
 }
@@ -343,14 +365,29 @@ func (yb *TargetYugabyteDB) InitConnPool() error {
 	}
 
 	params := &ConnectionParams{
-		NumConnections:    yb.tconf.Parallelism,
-		ConnUriList:       targetUriList,
-		SessionInitScript: getYBSessionInitScript(yb.tconf),
+		NumConnections:      yb.tconf.Parallelism,
+		ConnUriList:         targetUriList,
+		SessionInitScript:   getYBSessionInitScript(yb.tconf),
+		HealthCheckInterval: CONN_HEALTH_CHECK_INTERVAL,
 	}
 	yb.connPool = NewConnectionPool(params)
 	return nil
 }
 
+func (yb *TargetYugabyteDB) SetApplicationName(applicationName string) {
+	if yb.connPool != nil {
+		yb.connPool.SetApplicationName(applicationName)
+	}
+	yb.Mutex.Lock()
+	defer yb.Mutex.Unlock()
+	if yb.conn_ != nil {
+		err := SetApplicationName(yb.conn_, applicationName)
+		if err != nil {
+			log.Warnf("failed to set application_name: %s", err)
+		}
+	}
+}
+
 // The _v2 is appended in the table name so that the import code doesn't
 // try to use the similar table created by the voyager 1.3 and earlier.
 // Voyager 1.4 uses import data state format that is incompatible from
@@ -485,6 +522,7 @@ func (yb *TargetYugabyteDB) initChannelMetaInfo(conn *pgx.Conn, migrationUUID uu
 	return nil
 }
 
+// qualifyTableName qualifies the name of the target table, i.e. with the schema already mapped as per the SchemaMap.
 func (yb *TargetYugabyteDB) qualifyTableName(tableName string) string {
 	if len(strings.Split(tableName, ".")) != 2 {
 		tableName = fmt.Sprintf("%s.%s", yb.tconf.Schema, tableName)
@@ -572,29 +610,92 @@ func (yb *TargetYugabyteDB) GetEventChannelsMetaInfo(migrationUUID uuid.UUID) (m
 	return metainfo, nil
 }
 
-func (yb *TargetYugabyteDB) GetNonEmptyTables(tables []string) []string {
-	result := []string{}
+func (yb *TargetYugabyteDB) CheckpointEventChannels(migrationUUID uuid.UUID, vsn int64) error {
+	query := fmt.Sprintf("UPDATE %s SET last_applied_vsn = %d WHERE migration_uuid = '%s' AND last_applied_vsn < %d",
+		EVENT_CHANNELS_METADATA_TABLE_NAME, vsn, migrationUUID, vsn)
+	return yb.connPool.WithConn(func(conn *pgx.Conn) (bool, error) {
+		_, err := conn.Exec(context.Background(), query)
+		if err != nil {
+			return false, fmt.Errorf("run query %q: %w", query, err)
+		}
+		return false, nil
+	})
+}
 
-	for _, table := range tables {
+func (yb *TargetYugabyteDB) AdvanceEventChannel(migrationUUID uuid.UUID, chanNo int, vsn int64) error {
+	query := fmt.Sprintf("UPDATE %s SET last_applied_vsn = %d WHERE migration_uuid = '%s' AND channel_no = %d AND last_applied_vsn < %d",
+		EVENT_CHANNELS_METADATA_TABLE_NAME, vsn, migrationUUID, chanNo, vsn)
+	return yb.connPool.WithConn(func(conn *pgx.Conn) (bool, error) {
+		_, err := conn.Exec(context.Background(), query)
+		if err != nil {
+			return false, fmt.Errorf("run query %q: %w", query, err)
+		}
+		return false, nil
+	})
+}
+
+func (yb *TargetYugabyteDB) GetNonEmptyTables(tables []string) []string {
+	isNonEmpty := func(conn *pgx.Conn, table string) (bool, error) {
 		log.Infof("Checking if table %q is empty.", table)
 		tmp := false
 		stmt := fmt.Sprintf("SELECT TRUE FROM %s LIMIT 1;", table)
-		err := yb.Conn().QueryRow(context.Background(), stmt).Scan(&tmp)
+		err := conn.QueryRow(context.Background(), stmt).Scan(&tmp)
 		if err == pgx.ErrNoRows {
-			continue
+			return false, nil
 		}
 		if err != nil {
-			utils.ErrExit("failed to check whether table %q empty: %s", table, err)
+			return false, fmt.Errorf("failed to check whether table %q empty: %w", table, err)
 		}
-		result = append(result, table)
+		return true, nil
+	}
+	var result []string
+	var err error
+	if yb.connPool == nil {
+		result, err = filterTablesConcurrently(tables, 1, func(table string) (bool, error) {
+			return isNonEmpty(yb.Conn(), table)
+		})
+	} else {
+		// For schemas with many tables, the tables are checked on all the connections of the pool.
+		result, err = filterTablesConcurrently(tables, yb.tconf.Parallelism, func(table string) (bool, error) {
+			nonEmpty := false
+			err := yb.connPool.WithConn(func(conn *pgx.Conn) (bool, error) {
+				var err error
+				nonEmpty, err = isNonEmpty(conn, table)
+				return false, err
+			})
+			return nonEmpty, err
+		})
+	}
+	if err != nil {
+		utils.ErrExit("%s", err)
 	}
 	log.Infof("non empty tables: %v", result)
 	return result
 }
 
+func (yb *TargetYugabyteDB) GetMissingTables(tables []string) []string {
+	result := []string{}
+
+	for _, table := range tables {
+		log.Infof("Checking if table %q exists.", table)
+		exists := false
+		// to_regclass() resolves the name the same way as the COPY statement, quoting and search_path included.
+		stmt := "SELECT to_regclass($1) IS NOT NULL;"
+		err := yb.Conn().QueryRow(context.Background(), stmt, table).Scan(&exists)
+		if err != nil {
+			utils.ErrExit("failed to check whether table %q exists: %s", table, err)
+		}
+		if !exists {
+			result = append(result, table)
+		}
+	}
+	log.Infof("missing tables: %v", result)
+	return result
+}
+
 func (yb *TargetYugabyteDB) CleanFileImportState(filePath, tableName string) error {
 	// Delete all entries from ${BATCH_METADATA_TABLE_NAME} for this table.
-	schemaName := yb.getTargetSchemaName(tableName)
+	schemaName := yb.tconf.GetTargetSchemaName(tableName)
 	cmd := fmt.Sprintf(
 		`DELETE FROM %s WHERE data_file_name = '%s' AND schema_name = '%s' AND table_name = '%s'`,
 		BATCH_METADATA_TABLE_NAME, filePath, schemaName, tableName)
@@ -614,11 +715,14 @@ func (yb *TargetYugabyteDB) ImportBatch(batch Batch, args *ImportBatchArgs, expo
 		return false, err // Retries are now implemented in the caller.
 	}
 	err = yb.connPool.WithConn(copyFn)
+	if err != nil {
+		err = newYBCopyError(err)
+	}
 	return rowsAffected, err
 }
 
 func (yb *TargetYugabyteDB) importBatch(conn *pgx.Conn, batch Batch, args *ImportBatchArgs) (rowsAffected int64, err error) {
-	var file *os.File
+	var file io.ReadCloser
 	file, err = batch.Open()
 	if err != nil {
 		return 0, fmt.Errorf("open file %s: %w", batch.GetFilePath(), err)
@@ -626,7 +730,10 @@ func (yb *TargetYugabyteDB) importBatch(conn *pgx.Conn, batch Batch, args *Impor
 	defer file.Close()
 
 	//setting the schema so that COPY command can acesss the table
-	yb.setTargetSchema(conn)
+	err = yb.SetTargetSchema(conn)
+	if err != nil {
+		return 0, err
+	}
 
 	// NOTE: DO NOT DEFINE A NEW err VARIABLE IN THIS FUNCTION. ELSE, IT WILL MASK THE err FROM RETURN LIST.
 	ctx := context.Background()
@@ -659,18 +766,31 @@ func (yb *TargetYugabyteDB) importBatch(conn *pgx.Conn, batch Batch, args *Impor
 		return 0, err
 	}
 	if alreadyImported {
+		batch.RecordAlreadyImported()
 		return rowsAffected, nil
 	}
 
+	if args.HandlesPrimaryKeyConflicts() {
+		rowsAffected, err = yb.importBatchHandlingConflicts(tx, file, batch, args)
+		if err != nil {
+			return rowsAffected, err
+		}
+		err = yb.recordEntryInDB(tx, batch, rowsAffected)
+		if err != nil {
+			err = fmt.Errorf("record entry in DB for batch %q: %w", batch.GetFilePath(), err)
+		}
+		return rowsAffected, err
+	}
+
 	// Import the split using COPY command.
 	var res pgconn.CommandTag
 	copyCommand := args.GetYBCopyStatement()
-	log.Infof("Importing %q using COPY command: [%s]", batch.GetFilePath(), copyCommand)
+	batchLogger(batch).Infof("Importing %q using COPY command: [%s]", batch.GetFilePath(), copyCommand)
 	res, err = tx.Conn().PgConn().CopyFrom(context.Background(), file, copyCommand)
 	if err != nil {
 		var pgerr *pgconn.PgError
 		if errors.As(err, &pgerr) {
-			err = fmt.Errorf("%s, %s in %s", err.Error(), pgerr.Where, batch.GetFilePath())
+			err = fmt.Errorf("%w, %s in %s", err, pgerr.Where, batch.GetFilePath())
 		}
 		return res.RowsAffected(), err
 	}
@@ -682,6 +802,106 @@ func (yb *TargetYugabyteDB) importBatch(conn *pgx.Conn, batch Batch, args *Impor
 	return res.RowsAffected(), err
 }
 
+const YB_IMPORT_STAGING_TABLE_NAME = "voyager_import_staging"
+
+/*
+importBatchHandlingConflicts COPYs the batch into a temporary staging table, and moves its rows into the table with
+INSERT ... ON CONFLICT, as COPY can't skip or update the rows conflicting with the existing ones. Writing every row
+twice and checking the conflicts makes it considerably slower than COPYing into the table.
+Returns the number of rows inserted (or updated) into the table.
+*/
+func (yb *TargetYugabyteDB) importBatchHandlingConflicts(tx pgx.Tx, file io.Reader, batch Batch, args *ImportBatchArgs) (int64, error) {
+	ctx := context.Background()
+	// The upsert mode writes the rows without checking for the conflicts, which ON CONFLICT relies on.
+	disableUpsertMode := "SELECT set_config('yb_enable_upsert_mode', 'false', true) WHERE current_setting('yb_enable_upsert_mode', true) IS NOT NULL"
+	_, err := tx.Exec(ctx, disableUpsertMode)
+	if err != nil {
+		return 0, fmt.Errorf("disable upsert mode for batch %q: %w", batch.GetFilePath(), err)
+	}
+	stmt := fmt.Sprintf("CREATE TEMP TABLE %s (LIKE %s INCLUDING DEFAULTS) ON COMMIT DROP", YB_IMPORT_STAGING_TABLE_NAME, args.TableName)
+	_, err = tx.Exec(ctx, stmt)
+	if err != nil {
+		return 0, fmt.Errorf("create staging table for batch %q: %w", batch.GetFilePath(), err)
+	}
+
+	stagingArgs := *args
+	stagingArgs.TableName = YB_IMPORT_STAGING_TABLE_NAME
+	copyCommand := stagingArgs.GetYBCopyStatement()
+	batchLogger(batch).Infof("Importing %q into the staging table using COPY command: [%s]", batch.GetFilePath(), copyCommand)
+	res, err := tx.Conn().PgConn().CopyFrom(ctx, file, copyCommand)
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) {
+			err = fmt.Errorf("%w, %s in %s", err, pgerr.Where, batch.GetFilePath())
+		}
+		return 0, err
+	}
+
+	columns := args.Columns
+	pkColumns, err := yb.getPrimaryKeyColumns(tx, args.TableName)
+	if err != nil {
+		return 0, err
+	}
+	if len(pkColumns) == 0 {
+		return 0, fmt.Errorf("table %s has no primary key to skip or update the conflicting rows on", args.TableName)
+	}
+	if args.OnPrimaryKeyConflict == ON_PRIMARY_KEY_CONFLICT_UPDATE {
+		if len(columns) == 0 {
+			columns, err = yb.getColumnNames(tx, args.TableName)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+	insertStmt := args.GetYBInsertOnConflictStatement(YB_IMPORT_STAGING_TABLE_NAME, columns, pkColumns)
+	batchLogger(batch).Infof("Moving the rows of %q into %s: [%s]", batch.GetFilePath(), args.TableName, insertStmt)
+	insertRes, err := tx.Exec(ctx, insertStmt)
+	if err != nil {
+		return 0, fmt.Errorf("insert the rows of batch %q: %w", batch.GetFilePath(), err)
+	}
+	if args.OnPrimaryKeyConflict == ON_PRIMARY_KEY_CONFLICT_SKIP {
+		batchLogger(batch).Infof("skipped %d of the %d rows of batch %q conflicting with the existing rows",
+			res.RowsAffected()-insertRes.RowsAffected(), res.RowsAffected(), batch.GetFilePath())
+	}
+	return insertRes.RowsAffected(), nil
+}
+
+// Returns the (quoted if required) primary key columns of the table.
+func (yb *TargetYugabyteDB) getPrimaryKeyColumns(tx pgx.Tx, tableName string) ([]string, error) {
+	query := `SELECT quote_ident(a.attname) FROM pg_index i
+		JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+		WHERE i.indrelid = to_regclass($1) AND i.indisprimary`
+	return yb.queryColumnNames(tx, query, tableName)
+}
+
+// Returns the (quoted if required) columns of the table in their order.
+func (yb *TargetYugabyteDB) getColumnNames(tx pgx.Tx, tableName string) ([]string, error) {
+	query := `SELECT quote_ident(attname) FROM pg_attribute
+		WHERE attrelid = to_regclass($1) AND attnum > 0 AND NOT attisdropped ORDER BY attnum`
+	return yb.queryColumnNames(tx, query, tableName)
+}
+
+func (yb *TargetYugabyteDB) queryColumnNames(tx pgx.Tx, query string, tableName string) ([]string, error) {
+	rows, err := tx.Query(context.Background(), query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("run [%s] on target for table %s: %w", query, tableName, err)
+	}
+	defer rows.Close()
+	var columns []string
+	for rows.Next() {
+		var col string
+		err = rows.Scan(&col)
+		if err != nil {
+			return nil, fmt.Errorf("scan column names of table %s: %w", tableName, err)
+		}
+		columns = append(columns, col)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("fetch column names of table %s: %w", tableName, rows.Err())
+	}
+	return columns, nil
+}
+
 func (yb *TargetYugabyteDB) IfRequiredQuoteColumnNames(tableName string, columns []string) ([]string, error) {
 	result := make([]string, len(columns))
 	// FAST PATH.
@@ -740,6 +960,74 @@ func (yb *TargetYugabyteDB) IfRequiredQuoteColumnNames(tableName string, columns
 	return result, nil
 }
 
+func (yb *TargetYugabyteDB) GetColumnTypes(tableName string, columns []string) ([]ColumnType, error) {
+	query := `SELECT attname, format_type(atttypid, atttypmod) FROM pg_attribute
+		WHERE attrelid = to_regclass($1) AND attnum > 0 AND NOT attisdropped ORDER BY attnum`
+	rows, err := yb.Conn().Query(context.Background(), query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("run [%s] on target for table %s: %w", query, tableName, err)
+	}
+	defer rows.Close()
+	var tableColumns []ColumnType
+	for rows.Next() {
+		var col ColumnType
+		err = rows.Scan(&col.Name, &col.Type)
+		if err != nil {
+			return nil, fmt.Errorf("scan column types of table %s: %w", tableName, err)
+		}
+		tableColumns = append(tableColumns, col)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("fetch column types of table %s: %w", tableName, rows.Err())
+	}
+	if len(tableColumns) == 0 {
+		return nil, fmt.Errorf("table %s not found in target db", tableName)
+	}
+	if len(columns) == 0 {
+		return tableColumns, nil
+	}
+
+	result := make([]ColumnType, len(columns))
+	for i, colName := range columns {
+		quoted := colName[0] == '"'
+		colName = strings.Trim(colName, `"`)
+		col, found := lo.Find(tableColumns, func(col ColumnType) bool {
+			return col.Name == colName || (!quoted && col.Name == strings.ToLower(colName))
+		})
+		if !found {
+			return nil, fmt.Errorf("column %q not found in table %s", colName, tableName)
+		}
+		result[i] = col
+	}
+	return result, nil
+}
+
+func (yb *TargetYugabyteDB) GetGeneratedColumns(tableName string) ([]string, error) {
+	// is_generated is always NEVER before PG 12, which doesn't have the generated columns
+	query := `SELECT column_name FROM information_schema.columns
+		WHERE (table_schema, table_name) = (SELECT n.nspname, c.relname FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace WHERE c.oid = to_regclass($1))
+		AND is_generated = 'ALWAYS' ORDER BY ordinal_position`
+	rows, err := yb.Conn().Query(context.Background(), query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("run [%s] on target for table %s: %w", query, tableName, err)
+	}
+	defer rows.Close()
+	var result []string
+	for rows.Next() {
+		var colName string
+		err = rows.Scan(&colName)
+		if err != nil {
+			return nil, fmt.Errorf("scan generated columns of table %s: %w", tableName, err)
+		}
+		result = append(result, colName)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("fetch generated columns of table %s: %w", tableName, rows.Err())
+	}
+	return result, nil
+}
+
 func (yb *TargetYugabyteDB) getListOfTableAttributes(schemaName, tableName string) ([]string, error) {
 	var result []string
 	if tableName[0] == '"' {
@@ -772,97 +1060,158 @@ var NonRetryCopyErrors = []string{
 	"syntax error at",
 }
 
+// SQLSTATE classes (or codes) of the COPY errors which fail the same way on a retry.
+var nonRetryableCopyErrorCodes = []string{
+	"0A", // feature_not_supported
+	"21", // cardinality_violation, e.g. a row updated twice by ON CONFLICT DO UPDATE
+	"22", // data_exception, e.g. invalid_text_representation
+	"23", // integrity_constraint_violation, e.g. unique_violation
+	"42", // syntax_error_or_access_rule_violation, e.g. undefined_table
+}
+
+// SQLSTATE classes (or codes) of the COPY errors which are transient.
+var retryableCopyErrorCodes = []string{
+	"08",    // connection_exception
+	"40",    // transaction_rollback, e.g. serialization_failure
+	"53",    // insufficient_resources
+	"57014", // query_canceled, e.g. by the statement_timeout
+	"57P01", // admin_shutdown
+}
+
+// newYBCopyError wraps the PG error in the chain of err, if any, in a CopyError classified by its SQLSTATE.
+// The SQLSTATEs of neither list, e.g. the internal errors of YugabyteDB, are classified by the error message.
+func newYBCopyError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	hasCodePrefix := func(prefix string) bool { return strings.HasPrefix(pgErr.Code, prefix) }
+	var retryable bool
+	switch {
+	case lo.ContainsBy(nonRetryableCopyErrorCodes, hasCodePrefix):
+		retryable = false
+	case lo.ContainsBy(retryableCopyErrorCodes, hasCodePrefix):
+		retryable = true
+	default:
+		retryable = !utils.InsensitiveSliceContains(NonRetryCopyErrors, err.Error())
+	}
+	return &CopyError{Code: pgErr.Code, Retryable: retryable, Err: err}
+}
+
 func (yb *TargetYugabyteDB) IsNonRetryableCopyError(err error) bool {
-	return err != nil && utils.InsensitiveSliceContains(NonRetryCopyErrors, err.Error())
+	if err == nil {
+		return false
+	}
+	if copyErr, ok := getCopyError(err); ok {
+		return !copyErr.Retryable
+	}
+	return utils.InsensitiveSliceContains(NonRetryCopyErrors, err.Error())
 }
 
-func (yb *TargetYugabyteDB) RestoreSequences(sequencesLastVal map[string]int64) error {
+func (yb *TargetYugabyteDB) RestoreSequences(sequencesLastVal map[string]int64) []*SequenceRestoreResult {
 	log.Infof("restoring sequences on target")
-	batch := pgx.Batch{}
-	restoreStmt := "SELECT pg_catalog.setval('%s', %d, true)"
-	for sequenceName, lastValue := range sequencesLastVal {
-		if lastValue == 0 {
-			// TODO: can be valid for cases like cyclic sequences
-			continue
-		}
-		// same function logic will work for sequences as well
-		sequenceName = yb.qualifyTableName(sequenceName)
-		log.Infof("restore sequence %s to %d", sequenceName, lastValue)
-		batch.Queue(fmt.Sprintf(restoreStmt, sequenceName, lastValue))
+	var results []*SequenceRestoreResult
+	for _, sequenceName := range getSequencesToRestore(sequencesLastVal) {
+		results = append(results, &SequenceRestoreResult{SequenceName: sequenceName, LastValue: sequencesLastVal[sequenceName]})
 	}
-
 	err := yb.connPool.WithConn(func(conn *pgx.Conn) (retry bool, err error) {
-		br := conn.SendBatch(context.Background(), &batch)
-		for i := 0; i < batch.Len(); i++ {
-			_, err := br.Exec()
-			if err != nil {
-				log.Errorf("error executing restore sequence stmt: %v", err)
-				return false, fmt.Errorf("error executing restore sequence stmt: %w", err)
-			}
-		}
-		if err := br.Close(); err != nil {
-			log.Errorf("error closing batch: %v", err)
-			return false, fmt.Errorf("error closing batch: %w", err)
+		for _, result := range results {
+			result.Err = yb.restoreSequence(conn, result.SequenceName, result.LastValue)
 		}
 		return false, nil
 	})
 	if err != nil {
-		return fmt.Errorf("error restoring sequences: %w", err)
+		for _, result := range results {
+			result.Err = fmt.Errorf("error restoring sequences: %w", err)
+		}
 	}
-	return err
+	return results
+}
+
+// The identity column owning the sequence (with an internal dependency on it, unlike the serial columns),
+// along with the increment of the sequence.
+const IDENTITY_SEQUENCE_QUERY = `SELECT d.refobjid::regclass::text, a.attname, s.seqincrement
+FROM pg_catalog.pg_depend d
+JOIN pg_catalog.pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+JOIN pg_catalog.pg_sequence s ON s.seqrelid = d.objid
+WHERE d.classid = 'pg_catalog.pg_class'::regclass AND d.objid = $1::regclass AND d.deptype = 'i'`
+
+type identityColumn struct {
+	tableName  string // qualified and quoted as required
+	columnName string
+	increment  int64
 }
 
+func (yb *TargetYugabyteDB) restoreSequence(conn *pgx.Conn, sequenceName string, lastValue int64) error {
+	// same function logic will work for sequences as well
+	sequenceName = yb.qualifyTableName(sequenceName)
+	var owner identityColumn
+	err := conn.QueryRow(context.Background(), IDENTITY_SEQUENCE_QUERY, sequenceName).Scan(&owner.tableName, &owner.columnName, &owner.increment)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("find identity column of sequence %s: %w", sequenceName, err)
+	}
+	stmt := getSequenceRestoreStmt(sequenceName, lastValue, lo.Ternary(err == nil, &owner, nil))
+	log.Infof("restore sequence %s to %d: %s", sequenceName, lastValue, stmt)
+	_, err = conn.Exec(context.Background(), stmt)
+	if err != nil {
+		log.Errorf("error executing restore sequence stmt: %v", err)
+		return fmt.Errorf("error executing restore sequence stmt %q: %w", stmt, err)
+	}
+	return nil
+}
+
+// getSequenceRestoreStmt returns the statement to restore the sequence to its last value. The sequence of an
+// identity column is restarted through the column, with the value following the last value.
+func getSequenceRestoreStmt(sequenceName string, lastValue int64, owner *identityColumn) string {
+	if owner != nil {
+		return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s RESTART WITH %d",
+			owner.tableName, pgx.Identifier{owner.columnName}.Sanitize(), lastValue+owner.increment)
+	}
+	return fmt.Sprintf("SELECT pg_catalog.setval('%s', %d, true)", strings.ReplaceAll(sequenceName, "'", "''"), lastValue)
+}
+
+const MAX_BATCH_RECONNECT_ATTEMPTS = 3
+
 /*
 TODO(future): figure out the sql error codes for prepared statements which have become invalid
 and needs to be prepared again
 */
 func (yb *TargetYugabyteDB) ExecuteBatch(migrationUUID uuid.UUID, batch *EventBatch) error {
-	log.Infof("executing batch of %d events", len(batch.Events))
-	ybBatch := pgx.Batch{}
-	stmtToPrepare := make(map[string]string)
-	// processing batch events to convert into prepared or unprepared statements based on Op type
-	for i := 0; i < len(batch.Events); i++ {
-		event := batch.Events[i]
-		if event.Op == "u" {
-			stmt := event.GetSQLStmt(yb.tconf.Schema)
-			ybBatch.Queue(stmt)
-		} else {
-			stmt := event.GetPreparedSQLStmt(yb.tconf.Schema)
-			params := event.GetParams()
-			if _, ok := stmtToPrepare[stmt]; !ok {
-				stmtToPrepare[event.GetPreparedStmtName(yb.tconf.Schema)] = stmt
-			}
-			ybBatch.Queue(stmt, params...)
-		}
-	}
-
+	batch.logger().Infof("executing batch of %d events", len(batch.Events))
+	start := time.Now()
+	numReconnects := 0
 	err := yb.connPool.WithConn(func(conn *pgx.Conn) (retry bool, err error) {
+		// The batch is applied in a transaction, hence it is safe to apply it again on a new connection
+		// if the connection is lost midway.
+		defer func() {
+			if err != nil && conn.IsClosed() && numReconnects < MAX_BATCH_RECONNECT_ATTEMPTS {
+				numReconnects++
+				batch.logger().Warnf("connection lost while executing batch of %d events, reconnecting (attempt %d): %s",
+					len(batch.Events), numReconnects, err)
+				retry = true
+			}
+		}()
 		ctx := context.Background()
+		if numReconnects > 0 {
+			// The connection might have been lost after the commit of the batch, before its acknowledgement.
+			applied, err := yb.isEventBatchApplied(ctx, conn, migrationUUID, batch)
+			if err != nil {
+				return false, err
+			}
+			if applied {
+				batch.logger().Infof("batch of %d events is already applied before the connection was lost", len(batch.Events))
+				return false, nil
+			}
+		}
 		tx, err := conn.BeginTx(ctx, pgx.TxOptions{})
 		if err != nil {
 			return false, fmt.Errorf("error creating tx: %w", err)
 		}
 		defer tx.Rollback(ctx)
 
-		for name, stmt := range stmtToPrepare {
-			err := yb.connPool.PrepareStatement(conn, name, stmt)
-			if err != nil {
-				log.Errorf("error preparing stmt(%q): %v", stmt, err)
-				return false, fmt.Errorf("error preparing stmt: %w", err)
-			}
-		}
-
-		br := conn.SendBatch(ctx, &ybBatch)
-		for i := 0; i < len(batch.Events); i++ {
-			_, err := br.Exec()
-			if err != nil {
-				log.Errorf("error executing stmt for event with vsn(%d): %v", batch.Events[i].Vsn, err)
-				return false, fmt.Errorf("error executing stmt for event with vsn(%d): %v", batch.Events[i].Vsn, err)
-			}
-		}
-		if err = br.Close(); err != nil {
-			log.Errorf("error closing batch: %v", err)
-			return false, fmt.Errorf("error closing batch: %v", err)
+		err = yb.applyEvents(ctx, conn, batch)
+		if err != nil {
+			return false, err
 		}
 
 		updateVsnQuery := batch.GetChannelMetadataUpdateQuery(migrationUUID)
@@ -895,6 +1244,9 @@ func (yb *TargetYugabyteDB) ExecuteBatch(migrationUUID uuid.UUID, batch *EventBa
 	if err != nil {
 		return fmt.Errorf("error executing batch: %w", err)
 	}
+	elapsed := time.Since(start)
+	batch.logger().Infof("executed batch of %d events in %s using %q apply statement mode (%.2f events/sec)",
+		len(batch.Events), elapsed, yb.tconf.ApplyStatementMode, float64(len(batch.Events))/elapsed.Seconds())
 
 	// Idempotency considerations:
 	// Note: Assuming PK column value is not changed via UPDATEs
@@ -906,6 +1258,119 @@ func (yb *TargetYugabyteDB) ExecuteBatch(migrationUUID uuid.UUID, batch *EventBa
 	return nil
 }
 
+// isEventBatchApplied checks if the batch is applied, as per the last applied vsn of its channel. The batch is applied
+// in a transaction along with the last applied vsn, hence either all or none of its events are applied.
+func (yb *TargetYugabyteDB) isEventBatchApplied(ctx context.Context, conn *pgx.Conn, migrationUUID uuid.UUID, batch *EventBatch) (bool, error) {
+	query := fmt.Sprintf("SELECT last_applied_vsn FROM %s WHERE migration_uuid='%s' AND channel_no=%d",
+		EVENT_CHANNELS_METADATA_TABLE_NAME, migrationUUID, batch.ChanNo)
+	var lastAppliedVsn int64
+	err := conn.QueryRow(ctx, query).Scan(&lastAppliedVsn)
+	if err != nil {
+		return false, fmt.Errorf("run query %q: %w", query, err)
+	}
+	return lastAppliedVsn >= batch.GetLastVsn(), nil
+}
+
+// Postgres limits the number of parameters in a statement to 65535.
+const MAX_PARAMS_PER_STMT = 65535
+const MAX_INSERTS_PER_RUN = 1000
+
+// applyEvents executes the events of the batch in order as per the configured apply statement mode.
+// Statements are sent to the target in a pipeline; a pending pipeline is flushed before every COPY.
+func (yb *TargetYugabyteDB) applyEvents(ctx context.Context, conn *pgx.Conn, batch *EventBatch) error {
+	ybBatch := &pgx.Batch{}
+	var queuedVsns []int64 // vsn of the first event covered by each queued stmt
+	queue := func(vsn int64, stmt string, params ...interface{}) {
+		ybBatch.Queue(stmt, params...)
+		queuedVsns = append(queuedVsns, vsn)
+	}
+	flush := func() error {
+		if ybBatch.Len() == 0 {
+			return nil
+		}
+		br := conn.SendBatch(ctx, ybBatch)
+		for _, vsn := range queuedVsns {
+			_, err := br.Exec()
+			if err != nil {
+				br.Close()
+				log.Errorf("error executing stmt for event with vsn(%d): %v", vsn, err)
+				return fmt.Errorf("error executing stmt for event with vsn(%d): %w", vsn, err)
+			}
+		}
+		if err := br.Close(); err != nil {
+			log.Errorf("error closing batch: %v", err)
+			return fmt.Errorf("error closing batch: %w", err)
+		}
+		ybBatch = &pgx.Batch{}
+		queuedVsns = nil
+		return nil
+	}
+
+	for _, run := range batch.SplitIntoRuns(yb.tconf.Schema, MAX_INSERTS_PER_RUN) {
+		event := run[0]
+		switch true {
+		case event.Op == "u":
+			queue(event.Vsn, event.GetSQLStmt(yb.tconf.Schema))
+		case event.Op == "c" && yb.tconf.ApplyStatementMode == APPLY_STATEMENT_MODE_MULTI_ROW:
+			maxRowsPerStmt := MAX_PARAMS_PER_STMT / lo.Max([]int{len(event.Fields), 1})
+			for _, chunk := range lo.Chunk(run, maxRowsPerStmt) {
+				stmt, params := getPreparedMultiRowInsertStmt(chunk, yb.tconf.Schema)
+				queue(chunk[0].Vsn, stmt, params...)
+			}
+		case event.Op == "c" && yb.tconf.ApplyStatementMode == APPLY_STATEMENT_MODE_COPY:
+			err := flush()
+			if err != nil {
+				return err
+			}
+			err = yb.copyInsertEvents(ctx, conn, run)
+			if err != nil {
+				return err
+			}
+		default:
+			for _, event := range run {
+				stmt := event.GetPreparedSQLStmt(yb.tconf.Schema)
+				err := yb.connPool.PrepareStatement(conn, event.GetPreparedStmtName(yb.tconf.Schema), stmt)
+				if err != nil {
+					log.Errorf("error preparing stmt(%q): %v", stmt, err)
+					return fmt.Errorf("error preparing stmt: %w", err)
+				}
+				queue(event.Vsn, stmt, event.GetParams()...)
+			}
+		}
+	}
+	return flush()
+}
+
+// copyInsertEvents imports the given insert events of the same table and columns using COPY in text format.
+func (yb *TargetYugabyteDB) copyInsertEvents(ctx context.Context, conn *pgx.Conn, events []*Event) error {
+	columns := utils.GetMapKeysSorted(events[0].Fields)
+	var data strings.Builder
+	for _, event := range events {
+		for i, column := range columns {
+			if i > 0 {
+				data.WriteByte('\t')
+			}
+			value := event.Fields[column]
+			if value == nil {
+				data.WriteString(`\N`)
+			} else {
+				data.WriteString(copyTextEscaper.Replace(*value))
+			}
+		}
+		data.WriteByte('\n')
+	}
+	copyCommand := fmt.Sprintf("COPY %s (%s) FROM STDIN", events[0].getTableName(yb.tconf.Schema), strings.Join(columns, ", "))
+	_, err := conn.PgConn().CopyFrom(ctx, strings.NewReader(data.String()), copyCommand)
+	if err != nil {
+		log.Errorf("error executing COPY for events with vsn(%d..%d): %v", events[0].Vsn, events[len(events)-1].Vsn, err)
+		return fmt.Errorf("error executing COPY for events with vsn(%d..%d): %w", events[0].Vsn, events[len(events)-1].Vsn, err)
+	}
+	return nil
+}
+
+// escapes the special characters of the COPY text format
+var copyTextEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
 //==============================================================================
 
 const (
@@ -1124,6 +1589,8 @@ func getYBSessionInitScript(tconf *TargetConf) []string {
 	}
 	if checkSessionVariableSupport(tconf, SET_SESSION_REPLICATE_ROLE_TO_REPLICA) {
 		sessionVars = append(sessionVars, SET_SESSION_REPLICATE_ROLE_TO_REPLICA)
+	} else if tconf.DisableConstraintsDuringLoad {
+		utils.ErrExit("Error: --disable-constraints-during-load requires session_replication_role, which is not supported by the target db")
 	}
 
 	if tconf.EnableUpsert {
@@ -1189,29 +1656,31 @@ func checkSessionVariableSupport(tconf *TargetConf, sqlStmt string) bool {
 	return err == nil
 }
 
-func (yb *TargetYugabyteDB) setTargetSchema(conn *pgx.Conn) {
-	setSchemaQuery := fmt.Sprintf("SET SCHEMA '%s'", yb.tconf.Schema)
-	_, err := conn.Exec(context.Background(), setSchemaQuery)
-	if err != nil {
-		utils.ErrExit("run query %q on target %q: %s", setSchemaQuery, yb.tconf.Host, err)
+// SetTargetSchema sets the search_path to the target schema, followed by the oracle schema of orafce for the
+// Oracle source. The whole search_path is set, rather than appended to, so that it doesn't grow on every call.
+func (yb *TargetYugabyteDB) SetTargetSchema(conn interface{}) error {
+	pgConn, ok := conn.(*pgx.Conn)
+	if !ok {
+		return fmt.Errorf("set target schema: unexpected connection type %T", conn)
 	}
-
-	// append oracle schema in the search_path for orafce
-	// It is okay even if the schema does not exist in the target.
-	updateSearchPath := `SELECT set_config('search_path', current_setting('search_path') || ', oracle', false)`
-	_, err = conn.Exec(context.Background(), updateSearchPath)
-	if err != nil {
-		utils.ErrExit("unable to update search_path for orafce extension: %v", err)
+	if !yb.targetSchemaChecked.Load() {
+		err := yb.checkTargetSchemasExist(pgConn)
+		if err != nil {
+			return err
+		}
+		yb.targetSchemaChecked.Store(true)
 	}
 
-}
-
-func (yb *TargetYugabyteDB) getTargetSchemaName(tableName string) string {
-	parts := strings.Split(tableName, ".")
-	if len(parts) == 2 {
-		return parts[0]
+	setSearchPathQuery := fmt.Sprintf("SET search_path TO '%s'", yb.tconf.Schema)
+	if yb.tconf.SourceDBType == ORACLE {
+		// It is okay even if the schema does not exist in the target.
+		setSearchPathQuery += ", 'oracle'"
+	}
+	_, err := pgConn.Exec(context.Background(), setSearchPathQuery)
+	if err != nil {
+		return fmt.Errorf("run query %q on target %q: %w", setSearchPathQuery, yb.tconf.Host, err)
 	}
-	return yb.tconf.Schema // default set to "public"
+	return nil
 }
 
 func (yb *TargetYugabyteDB) isBatchAlreadyImported(tx pgx.Tx, batch Batch) (bool, int64, error) {
@@ -1219,11 +1688,11 @@ func (yb *TargetYugabyteDB) isBatchAlreadyImported(tx pgx.Tx, batch Batch) (bool
 	query := batch.GetQueryIsBatchAlreadyImported()
 	err := tx.QueryRow(context.Background(), query).Scan(&rowsImported)
 	if err == nil {
-		log.Infof("%v rows from %q are already imported", rowsImported, batch.GetFilePath())
+		batchLogger(batch).Infof("%v rows from %q are already imported", rowsImported, batch.GetFilePath())
 		return true, rowsImported, nil
 	}
 	if err == pgx.ErrNoRows {
-		log.Infof("%q is not imported yet", batch.GetFilePath())
+		batchLogger(batch).Infof("%q is not imported yet", batch.GetFilePath())
 		return false, 0, nil
 	}
 	return false, 0, fmt.Errorf("check if %s is already imported: %w", batch.GetFilePath(), err)
@@ -1249,6 +1718,163 @@ func (yb *TargetYugabyteDB) GetTotalNumOfEventsImportedByType(migrationUUID uuid
 	return numInserts, numUpdates, numDeletes, nil
 }
 
+func (yb *TargetYugabyteDB) GetImportedEventCountsByTable(migrationUUID uuid.UUID) (map[string]*EventCounter, error) {
+	query := fmt.Sprintf(`SELECT table_name, SUM(total_events), SUM(num_inserts), SUM(num_updates), SUM(num_deletes)
+		FROM %s WHERE migration_uuid='%s' GROUP BY table_name`, EVENTS_PER_TABLE_METADATA_TABLE_NAME, migrationUUID)
+	rows, err := yb.Conn().Query(context.Background(), query)
+	if err != nil {
+		return nil, fmt.Errorf("error in getting import stats by table from target db: %w", err)
+	}
+	defer rows.Close()
+	result := make(map[string]*EventCounter)
+	for rows.Next() {
+		var tableName string
+		counter := &EventCounter{}
+		err = rows.Scan(&tableName, &counter.TotalEvents, &counter.NumInserts, &counter.NumUpdates, &counter.NumDeletes)
+		if err != nil {
+			return nil, fmt.Errorf("error in scanning import stats by table: %w", err)
+		}
+		result[tableName] = counter
+	}
+	return result, rows.Err()
+}
+
+func (yb *TargetYugabyteDB) GetRowCount(tableName string) (int64, error) {
+	var rowCount int64
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", tableName)
+	err := yb.Conn().QueryRow(context.Background(), query).Scan(&rowCount)
+	if err != nil {
+		return 0, fmt.Errorf("run query %q on target: %w", query, err)
+	}
+	return rowCount, nil
+}
+
+func (yb *TargetYugabyteDB) TruncateTables(tableNames []string) error {
+	// in a single statement, so that the foreign keys among the tables don't fail it
+	query := fmt.Sprintf("TRUNCATE TABLE %s", strings.Join(tableNames, ", "))
+	_, err := yb.Conn().Exec(context.Background(), query)
+	if err != nil {
+		return fmt.Errorf("run query %q on target: %w", query, err)
+	}
+	return nil
+}
+
+func (yb *TargetYugabyteDB) GetSequenceLastValue(sequenceName string) (int64, error) {
+	var lastValue int64
+	var isCalled bool
+	query := fmt.Sprintf("SELECT last_value, is_called FROM %s", yb.qualifyTableName(sequenceName))
+	err := yb.Conn().QueryRow(context.Background(), query).Scan(&lastValue, &isCalled)
+	if err != nil {
+		return 0, fmt.Errorf("run query %q on target: %w", query, err)
+	}
+	if !isCalled {
+		// last_value is the next value to be generated
+		lastValue--
+	}
+	return lastValue, nil
+}
+
+func (yb *TargetYugabyteDB) GetInvalidForeignKeys() ([]string, error) {
+	query := `SELECT conrelid::regclass::text || '.' || conname FROM pg_constraint
+		WHERE contype = 'f' AND NOT convalidated AND connamespace = to_regnamespace($1)`
+	rows, err := yb.Conn().Query(context.Background(), query, yb.tconf.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("run query %q on target: %w", query, err)
+	}
+	defer rows.Close()
+	var result []string
+	for rows.Next() {
+		var constraintName string
+		err = rows.Scan(&constraintName)
+		if err != nil {
+			return nil, fmt.Errorf("scan foreign key constraint name: %w", err)
+		}
+		result = append(result, constraintName)
+	}
+	return result, rows.Err()
+}
+
+// foreignKey is a foreign key constraint, with the names of its columns and of the referenced columns quoted if required.
+type foreignKey struct {
+	name         string
+	tableName    string
+	refTableName string
+	columns      []string
+	refColumns   []string
+}
+
+func (yb *TargetYugabyteDB) GetForeignKeyViolations(tableNames []string) ([]*ForeignKeyViolation, error) {
+	query := `SELECT con.conname, con.conrelid::regclass::text, con.confrelid::regclass::text,
+		ARRAY(SELECT quote_ident(a.attname) FROM unnest(con.conkey) WITH ORDINALITY k(attnum, n)
+			JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum ORDER BY k.n),
+		ARRAY(SELECT quote_ident(a.attname) FROM unnest(con.confkey) WITH ORDINALITY k(attnum, n)
+			JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum ORDER BY k.n)
+		FROM pg_constraint con
+		WHERE con.contype = 'f' AND con.conrelid IN (SELECT to_regclass(t) FROM unnest($1::text[]) t)
+		ORDER BY 2, 1`
+	rows, err := yb.Conn().Query(context.Background(), query, tableNames)
+	if err != nil {
+		return nil, fmt.Errorf("run query %q on target: %w", query, err)
+	}
+	var foreignKeys []*foreignKey
+	for rows.Next() {
+		fk := &foreignKey{}
+		err = rows.Scan(&fk.name, &fk.tableName, &fk.refTableName, &fk.columns, &fk.refColumns)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan foreign key constraint: %w", err)
+		}
+		foreignKeys = append(foreignKeys, fk)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("fetch foreign key constraints: %w", rows.Err())
+	}
+
+	var result []*ForeignKeyViolation
+	for _, fk := range foreignKeys {
+		violation := &ForeignKeyViolation{ConstraintName: fk.name, TableName: fk.tableName, RefTableName: fk.refTableName}
+		stmt := getForeignKeyViolationsQuery(fk)
+		log.Infof("checking foreign key %s of table %s: %s", fk.name, fk.tableName, stmt)
+		rows, err := yb.Conn().Query(context.Background(), stmt)
+		if err != nil {
+			return nil, fmt.Errorf("run query %q on target: %w", stmt, err)
+		}
+		for rows.Next() {
+			var row string
+			err = rows.Scan(&violation.NumRows, &row)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan rows violating foreign key %s: %w", fk.name, err)
+			}
+			violation.SampleRows = append(violation.SampleRows, row)
+		}
+		rows.Close()
+		if rows.Err() != nil {
+			return nil, fmt.Errorf("fetch rows violating foreign key %s: %w", fk.name, rows.Err())
+		}
+		if violation.NumRows > 0 {
+			result = append(result, violation)
+		}
+	}
+	return result, nil
+}
+
+// getForeignKeyViolationsQuery returns the query for the total number of the rows violating the foreign key, along with
+// the values of the foreign key columns of some of them. The rows with a NULL in any of the columns don't violate it
+// (MATCH SIMPLE, the default).
+func getForeignKeyViolationsQuery(fk *foreignKey) string {
+	var columns, notNullConditions, joinConditions []string
+	for i, column := range fk.columns {
+		columns = append(columns, "c."+column)
+		notNullConditions = append(notNullConditions, fmt.Sprintf("c.%s IS NOT NULL", column))
+		joinConditions = append(joinConditions, fmt.Sprintf("p.%s = c.%s", fk.refColumns[i], column))
+	}
+	return fmt.Sprintf("SELECT count(*) OVER (), ROW(%s)::text FROM %s c WHERE %s AND NOT EXISTS (SELECT 1 FROM %s p WHERE %s) LIMIT %d",
+		strings.Join(columns, ", "), fk.tableName, strings.Join(notNullConditions, " AND "),
+		fk.refTableName, strings.Join(joinConditions, " AND "), MAX_FOREIGN_KEY_VIOLATION_SAMPLE_ROWS)
+}
+
 func (yb *TargetYugabyteDB) GetDebeziumValueConverterSuite() map[string]ConverterFn {
 	return ybValueConverterSuite
 }
@@ -0,0 +1,358 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tracing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/yugabyte/yb-voyager/yb-voyager/src/utils"
+)
+
+/*
+The tracing package emits OpenTelemetry traces of the migration phases. The spans are exported
+in the OTLP/HTTP JSON encoding to the `/v1/traces` path of the configured endpoint, which is
+accepted by the OpenTelemetry collector and most of the tracing backends.
+
+Tracing is a no-op until Init() is called: StartSpan() returns a nil *Span, and all the
+methods of *Span are safe to call on nil.
+*/
+
+const (
+	SERVICE_NAME   = "yb-voyager"
+	FLUSH_INTERVAL = 5 * time.Second
+	// spans ended beyond this within a FLUSH_INTERVAL are dropped, to bound the memory usage. The spans are
+	// exported once; those failing to be exported, e.g. as the endpoint is not reachable, are dropped as well.
+	MAX_PENDING_SPANS = 10000
+)
+
+var tracer *Tracer
+
+type Tracer struct {
+	url    string
+	client *http.Client
+
+	mu           sync.Mutex
+	pendingSpans []*Span
+	numDropped   int
+	stop         chan struct{}
+	stopped      chan struct{}
+}
+
+// Init enables the tracing with the spans exported to the OTLP/HTTP endpoint, e.g. `http://localhost:4318`.
+func Init(endpoint string) {
+	url := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(url, "/v1/traces") {
+		url += "/v1/traces"
+	}
+	tracer = &Tracer{
+		url:     url,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	log.Infof("exporting traces to %s", url)
+	go tracer.run()
+}
+
+// Shutdown exports the pending spans. Spans ended after it are not exported. It must be called before exiting,
+// normally or through atexit, for the spans ended last to be exported.
+func Shutdown() {
+	if tracer == nil {
+		return
+	}
+	close(tracer.stop)
+	<-tracer.stopped
+	tracer = nil
+}
+
+func (t *Tracer) run() {
+	defer close(t.stopped)
+	ticker := time.NewTicker(FLUSH_INTERVAL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			t.flush()
+		case <-t.stop:
+			t.flush()
+			return
+		}
+	}
+}
+
+func (t *Tracer) addSpan(span *Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.pendingSpans) >= MAX_PENDING_SPANS {
+		t.numDropped++
+		return
+	}
+	t.pendingSpans = append(t.pendingSpans, span)
+}
+
+func (t *Tracer) flush() {
+	t.mu.Lock()
+	spans := t.pendingSpans
+	t.pendingSpans = nil
+	numDropped := t.numDropped
+	t.numDropped = 0
+	t.mu.Unlock()
+	if numDropped > 0 {
+		log.Warnf("dropped %d spans ended beyond %d within %s", numDropped, MAX_PENDING_SPANS, FLUSH_INTERVAL)
+	}
+	if len(spans) == 0 {
+		return
+	}
+	err := t.export(spans)
+	if err != nil {
+		log.Warnf("failed to export %d spans to %s: %s", len(spans), t.url, err)
+	}
+}
+
+func (t *Tracer) export(spans []*Span) error {
+	body, err := json.Marshal(newExportRequest(spans))
+	if err != nil {
+		return fmt.Errorf("marshal spans: %w", err)
+	}
+	resp, err := t.client.Post(t.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("status %s: %s", resp.Status, string(respBody))
+	}
+	return nil
+}
+
+//============================================================================
+
+type Span struct {
+	name         string
+	traceID      string
+	spanID       string
+	parentSpanID string
+	startTime    time.Time
+
+	mu         sync.Mutex
+	endTime    time.Time
+	attributes map[string]interface{}
+	err        error
+}
+
+// StartSpan starts the root span of a new trace. It returns nil when the tracing is not enabled.
+func StartSpan(name string) *Span {
+	if tracer == nil {
+		return nil
+	}
+	return &Span{
+		name:       name,
+		traceID:    newID(16),
+		spanID:     newID(8),
+		startTime:  time.Now(),
+		attributes: map[string]interface{}{},
+	}
+}
+
+// StartChild starts a span nested under `s`.
+func (s *Span) StartChild(name string) *Span {
+	if s == nil {
+		return nil
+	}
+	return &Span{
+		name:         name,
+		traceID:      s.traceID,
+		spanID:       newID(8),
+		parentSpanID: s.spanID,
+		startTime:    time.Now(),
+		attributes:   map[string]interface{}{},
+	}
+}
+
+// SetAttribute records an attribute of the span. The values can be strings, bools, or integers.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attributes[key] = value
+}
+
+// SetError marks the span as failed.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// End ends the span and queues it for the export. Ending an ended span is a no-op.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if !s.endTime.IsZero() {
+		s.mu.Unlock()
+		return
+	}
+	s.endTime = time.Now()
+	s.attributes["duration_ms"] = s.endTime.Sub(s.startTime).Milliseconds()
+	s.mu.Unlock()
+	t := tracer
+	if t != nil {
+		t.addSpan(s)
+	}
+}
+
+func newID(numBytes int) string {
+	buf := make([]byte, numBytes)
+	_, err := rand.Read(buf)
+	if err != nil {
+		// ids need not be cryptographically random, but crypto/rand is not expected to fail.
+		panic(fmt.Sprintf("generate trace id: %s", err))
+	}
+	return hex.EncodeToString(buf)
+}
+
+//============================================================================
+// OTLP/HTTP JSON encoding, see https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+
+const (
+	SPAN_KIND_INTERNAL = 1
+	STATUS_CODE_ERROR  = 2
+)
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            *status    `json:"status,omitempty"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string         `json:"key"`
+	Value attributeValue `json:"value"`
+}
+
+type attributeValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"` // int64 values are strings in the JSON encoding
+}
+
+func newExportRequest(spans []*Span) *exportRequest {
+	serviceName := SERVICE_NAME
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		otlpSpans = append(otlpSpans, s.toOTLP())
+	}
+	return &exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{Attributes: []keyValue{{Key: "service.name", Value: attributeValue{StringValue: &serviceName}}}},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: SERVICE_NAME, Version: utils.YB_VOYAGER_VERSION},
+				Spans: otlpSpans,
+			}},
+		}},
+	}
+}
+
+func (s *Span) toOTLP() otlpSpan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := otlpSpan{
+		TraceID:           s.traceID,
+		SpanID:            s.spanID,
+		ParentSpanID:      s.parentSpanID,
+		Name:              s.name,
+		Kind:              SPAN_KIND_INTERNAL,
+		StartTimeUnixNano: strconv.FormatInt(s.startTime.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.endTime.UnixNano(), 10),
+	}
+	for key, value := range s.attributes {
+		result.Attributes = append(result.Attributes, keyValue{Key: key, Value: toAttributeValue(value)})
+	}
+	if s.err != nil {
+		result.Status = &status{Code: STATUS_CODE_ERROR, Message: s.err.Error()}
+	}
+	return result
+}
+
+func toAttributeValue(value interface{}) attributeValue {
+	var intValue string
+	switch v := value.(type) {
+	case bool:
+		return attributeValue{BoolValue: &v}
+	case int:
+		intValue = strconv.FormatInt(int64(v), 10)
+	case int64:
+		intValue = strconv.FormatInt(v, 10)
+	default:
+		str := fmt.Sprint(v)
+		return attributeValue{StringValue: &str}
+	}
+	return attributeValue{IntValue: &intValue}
+}
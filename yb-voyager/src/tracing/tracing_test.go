@@ -0,0 +1,78 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpansAreNoOpWhenDisabled(t *testing.T) {
+	span := StartSpan("import data")
+	assert.Nil(t, span)
+	child := span.StartChild("import table")
+	child.SetAttribute("rows", 10)
+	child.SetError(errors.New("failed"))
+	child.End()
+}
+
+func TestExportSpans(t *testing.T) {
+	assert := assert.New(t)
+	var requests []exportRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/v1/traces", r.URL.Path)
+		var req exportRequest
+		assert.NoError(json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+	}))
+	defer server.Close()
+
+	Init(server.URL)
+	root := StartSpan("import data")
+	child := root.StartChild("import batch")
+	child.SetAttribute("rows", int64(100))
+	child.SetAttribute("table_name", "foo")
+	child.SetError(errors.New("copy failed"))
+	child.End()
+	root.End()
+	root.End() // no-op
+	Shutdown()
+
+	assert.Len(requests, 1)
+	spans := requests[0].ResourceSpans[0].ScopeSpans[0].Spans
+	assert.Len(spans, 2)
+	batchSpan, rootSpan := spans[0], spans[1]
+	assert.Equal("import batch", batchSpan.Name)
+	assert.Equal(rootSpan.TraceID, batchSpan.TraceID)
+	assert.Equal(rootSpan.SpanID, batchSpan.ParentSpanID)
+	assert.Len(rootSpan.TraceID, 32)
+	assert.Len(rootSpan.SpanID, 16)
+	assert.Empty(rootSpan.ParentSpanID)
+	assert.Equal(STATUS_CODE_ERROR, batchSpan.Status.Code)
+	assert.Nil(rootSpan.Status)
+	attrs := map[string]attributeValue{}
+	for _, kv := range batchSpan.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	assert.Equal("100", *attrs["rows"].IntValue)
+	assert.Equal("foo", *attrs["table_name"].StringValue)
+	assert.NotNil(attrs["duration_ms"].IntValue)
+}
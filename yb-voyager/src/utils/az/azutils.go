@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"os"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
@@ -33,9 +34,28 @@ import (
 
 var client *azblob.Client
 
+// AZBLOB_URL_PREFIX is the scheme of the azblob://<container_name>/<path> URLs. As with the gocloud.dev
+// URLs, the storage account is the one in the AZURE_STORAGE_ACCOUNT env var.
+const AZBLOB_URL_PREFIX = "azblob://"
+
+// toHTTPSURL converts an azblob:// URL to the https:// URL of the blob service. Other URLs are returned as they are.
+func toHTTPSURL(objectURL string) (string, error) {
+	if !strings.HasPrefix(objectURL, AZBLOB_URL_PREFIX) {
+		return objectURL, nil
+	}
+	accountName := os.Getenv("AZURE_STORAGE_ACCOUNT")
+	if accountName == "" {
+		return "", fmt.Errorf("AZURE_STORAGE_ACCOUNT env var must be set to access %q", objectURL)
+	}
+	return fmt.Sprintf("https://%s.blob.core.windows.net/%s", accountName, strings.TrimPrefix(objectURL, AZBLOB_URL_PREFIX)), nil
+}
+
 // creates a client for the account in the url with the default creds.
 func createClientIfNotExists(dataDir string) {
-	var err error
+	dataDir, err := toHTTPSURL(dataDir)
+	if err != nil {
+		utils.ErrExit("%s", err)
+	}
 	url, err := url.Parse(dataDir)
 	if err != nil {
 		utils.ErrExit("parse azure blob url for dataDir %s: %w", dataDir, err)
@@ -70,7 +90,12 @@ func createContainerClient(url string) (*container.Client, error) {
 
 // check if url is in format
 // https://<account_name>.blob.core.windows.net/<container_name or bucket_name>
+// or azblob://<container_name or bucket_name>
 func ValidateObjectURL(dataDir string) error {
+	dataDir, err := toHTTPSURL(dataDir)
+	if err != nil {
+		return err
+	}
 	dataDirUrl, err := url.Parse(dataDir)
 	if err != nil {
 		return fmt.Errorf("parsing the object of %q: %w", dataDir, err)
@@ -93,6 +118,10 @@ func splitObjectPath(objectPath string) (string, string, string, error) {
 	if err != nil {
 		return "", "", "", fmt.Errorf("invalid azure blob url %v: %w", objectPath, err)
 	}
+	objectPath, err = toHTTPSURL(objectPath)
+	if err != nil {
+		return "", "", "", err
+	}
 	objectUrl, err := url.Parse(objectPath)
 	if err != nil {
 		return "", "", "", fmt.Errorf("parsing the object of %q: %w", objectPath, err)
@@ -0,0 +1,27 @@
+package az
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToHTTPSURL(t *testing.T) {
+	t.Setenv("AZURE_STORAGE_ACCOUNT", "")
+	_, err := toHTTPSURL("azblob://container/data")
+	assert.Error(t, err)
+
+	t.Setenv("AZURE_STORAGE_ACCOUNT", "account")
+	url, err := toHTTPSURL("azblob://container/data/t1.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://account.blob.core.windows.net/container/data/t1.csv", url)
+	assert.NoError(t, ValidateObjectURL("azblob://container/data"))
+	_, containerName, key, err := splitObjectPath("azblob://container/data/t1.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "container", containerName)
+	assert.Equal(t, "data/t1.csv", key)
+
+	url, err = toHTTPSURL("https://account.blob.core.windows.net/container/data")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://account.blob.core.windows.net/container/data", url)
+}
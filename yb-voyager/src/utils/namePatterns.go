@@ -0,0 +1,90 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package utils
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+/*
+NamePattern matches names (e.g. of tables) against one of:
+  - a regular expression enclosed in slashes, e.g. `/^audit_.*$/`
+  - a glob pattern with `*`, `?` or `[...]`, e.g. `sales_*`
+  - a plain name, matched exactly
+
+The matching is case-sensitive, like that of the plain names.
+*/
+type NamePattern struct {
+	pattern string
+	regex   *regexp.Regexp
+	isGlob  bool
+}
+
+func NewNamePattern(pattern string) (*NamePattern, error) {
+	p := &NamePattern{pattern: pattern}
+	if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		regex, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+		}
+		p.regex = regex
+	} else if strings.ContainsAny(pattern, "*?[") {
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+		p.isGlob = true
+	}
+	return p, nil
+}
+
+func (p *NamePattern) Match(name string) bool {
+	switch {
+	case p.regex != nil:
+		return p.regex.MatchString(name)
+	case p.isGlob:
+		matched, _ := path.Match(p.pattern, name) // the pattern is validated in NewNamePattern()
+		return matched
+	default:
+		return p.pattern == name
+	}
+}
+
+func (p *NamePattern) String() string {
+	return p.pattern
+}
+
+// ExpandNamePatterns returns the names matching each of the patterns, in the order of `names`.
+func ExpandNamePatterns(patterns []string, names []string) (map[string][]string, error) {
+	result := make(map[string][]string)
+	for _, pattern := range patterns {
+		p, err := NewNamePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		matchedNames := []string{}
+		for _, name := range names {
+			if p.Match(name) {
+				matchedNames = append(matchedNames, name)
+			}
+		}
+		result[pattern] = matchedNames
+	}
+	return result, nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandNamePatterns(t *testing.T) {
+	assert := assert.New(t)
+	names := []string{"sales_2022", "sales_2023", "Sales_old", "audit_log", "audit_log_archive", "users"}
+	testcases := []struct {
+		pattern  string
+		expected []string
+	}{
+		{"users", []string{"users"}},
+		{"Users", []string{}},
+		{"sales_*", []string{"sales_2022", "sales_2023"}},
+		{"sales_202?", []string{"sales_2022", "sales_2023"}},
+		{"[Ss]ales_*", []string{"sales_2022", "sales_2023", "Sales_old"}},
+		{"/^audit_.*$/", []string{"audit_log", "audit_log_archive"}},
+		{"/^audit_log$/", []string{"audit_log"}},
+		{"/log/", []string{"audit_log", "audit_log_archive"}},
+		{"orders_*", []string{}},
+	}
+	for _, tc := range testcases {
+		result, err := ExpandNamePatterns([]string{tc.pattern}, names)
+		assert.NoError(err)
+		assert.Equal(tc.expected, result[tc.pattern], "%q", tc.pattern)
+	}
+
+	_, err := ExpandNamePatterns([]string{"/audit_(/"}, names)
+	assert.Error(err)
+	_, err = ExpandNamePatterns([]string{"sales_[2"}, names)
+	assert.Error(err)
+}
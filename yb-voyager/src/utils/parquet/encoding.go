@@ -0,0 +1,239 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+var errTruncated = errors.New("parquet: truncated page")
+
+// Int96 is the legacy timestamp type: nanoseconds of the day (8 bytes) followed by the julian day (4 bytes), little-endian.
+type Int96 [12]byte
+
+// decodeRLEHybrid decodes `n` values of `bitWidth` bits encoded with the RLE/bit-packing hybrid encoding,
+// used by the definition levels and the dictionary indices.
+func decodeRLEHybrid(buf []byte, bitWidth int, n int) ([]uint32, error) {
+	if bitWidth < 0 || bitWidth > 32 {
+		return nil, fmt.Errorf("parquet: invalid bit width %d", bitWidth)
+	}
+	result := make([]uint32, 0, n)
+	pos := 0
+	byteWidth := (bitWidth + 7) / 8
+	for len(result) < n {
+		header, k := binary.Uvarint(buf[pos:])
+		if k <= 0 {
+			return nil, errTruncated
+		}
+		pos += k
+		if header&1 == 0 {
+			// RLE run: the value repeated `header >> 1` times
+			count := int(header >> 1)
+			if pos+byteWidth > len(buf) {
+				return nil, errTruncated
+			}
+			var v uint32
+			for i := 0; i < byteWidth; i++ {
+				v |= uint32(buf[pos+i]) << (8 * i)
+			}
+			pos += byteWidth
+			for i := 0; i < count && len(result) < n; i++ {
+				result = append(result, v)
+			}
+		} else {
+			// bit-packed run of `header >> 1` groups of 8 values, least significant bits first
+			count := int(header>>1) * 8
+			numBytes := int(header>>1) * bitWidth
+			if pos+numBytes > len(buf) {
+				return nil, errTruncated
+			}
+			packed := buf[pos : pos+numBytes]
+			pos += numBytes
+			for i := 0; i < count && len(result) < n; i++ {
+				var v uint32
+				for b := 0; b < bitWidth; b++ {
+					bit := i*bitWidth + b
+					if packed[bit/8]&(1<<(bit%8)) != 0 {
+						v |= 1 << b
+					}
+				}
+				result = append(result, v)
+			}
+		}
+	}
+	return result, nil
+}
+
+// decodePlain decodes `n` values of the PLAIN encoding.
+func decodePlain(buf []byte, typ Type, typeLength int, n int) ([]interface{}, error) {
+	result := make([]interface{}, 0, n)
+	fixedSize := map[Type]int{INT32: 4, INT64: 8, INT96: 12, FLOAT: 4, DOUBLE: 8, FIXED_LEN_BYTE_ARRAY: typeLength}[typ]
+	switch typ {
+	case BOOLEAN:
+		if len(buf) < (n+7)/8 {
+			return nil, errTruncated
+		}
+		for i := 0; i < n; i++ {
+			result = append(result, buf[i/8]&(1<<(i%8)) != 0)
+		}
+		return result, nil
+	case BYTE_ARRAY:
+		pos := 0
+		for i := 0; i < n; i++ {
+			if pos+4 > len(buf) {
+				return nil, errTruncated
+			}
+			length := int(binary.LittleEndian.Uint32(buf[pos:]))
+			pos += 4
+			if length < 0 || pos+length > len(buf) {
+				return nil, errTruncated
+			}
+			result = append(result, buf[pos:pos+length])
+			pos += length
+		}
+		return result, nil
+	case INT32, INT64, INT96, FLOAT, DOUBLE, FIXED_LEN_BYTE_ARRAY:
+		if fixedSize <= 0 {
+			return nil, fmt.Errorf("parquet: invalid length %d of %s", fixedSize, typ)
+		}
+		if len(buf) < n*fixedSize {
+			return nil, errTruncated
+		}
+		for i := 0; i < n; i++ {
+			b := buf[i*fixedSize : (i+1)*fixedSize]
+			switch typ {
+			case INT32:
+				result = append(result, int32(binary.LittleEndian.Uint32(b)))
+			case INT64:
+				result = append(result, int64(binary.LittleEndian.Uint64(b)))
+			case INT96:
+				var v Int96
+				copy(v[:], b)
+				result = append(result, v)
+			case FLOAT:
+				result = append(result, math.Float32frombits(binary.LittleEndian.Uint32(b)))
+			case DOUBLE:
+				result = append(result, math.Float64frombits(binary.LittleEndian.Uint64(b)))
+			case FIXED_LEN_BYTE_ARRAY:
+				result = append(result, b)
+			}
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("parquet: unknown type %s", typ)
+	}
+}
+
+func decompress(codec Codec, buf []byte, uncompressedSize int) ([]byte, error) {
+	switch codec {
+	case UNCOMPRESSED:
+		return buf, nil
+	case SNAPPY:
+		return snappyDecode(buf)
+	case GZIP:
+		r, err := gzip.NewReader(bytes.NewReader(buf))
+		if err != nil {
+			return nil, fmt.Errorf("parquet: gzip: %w", err)
+		}
+		defer r.Close()
+		result := bytes.NewBuffer(make([]byte, 0, uncompressedSize))
+		_, err = io.Copy(result, r)
+		if err != nil {
+			return nil, fmt.Errorf("parquet: gzip: %w", err)
+		}
+		return result.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("parquet: unsupported compression codec %d, only UNCOMPRESSED, SNAPPY and GZIP are supported", codec)
+	}
+}
+
+// snappyDecode decodes a block of the snappy format (https://github.com/google/snappy/blob/main/format_description.txt),
+// which is what Parquet uses, without the framing of the snappy streams.
+func snappyDecode(src []byte) ([]byte, error) {
+	errCorrupt := errors.New("parquet: corrupt snappy block")
+	length, k := binary.Uvarint(src)
+	if k <= 0 || length > math.MaxInt32 {
+		return nil, errCorrupt
+	}
+	src = src[k:]
+	dst := make([]byte, 0, length)
+	for len(src) > 0 {
+		tag := src[0]
+		var n, offset int
+		switch tag & 3 {
+		case 0: // literal
+			n = int(tag >> 2)
+			src = src[1:]
+			if n >= 60 {
+				numBytes := n - 59
+				if len(src) < numBytes {
+					return nil, errCorrupt
+				}
+				n = 0
+				for i := 0; i < numBytes; i++ {
+					n |= int(src[i]) << (8 * i)
+				}
+				src = src[numBytes:]
+			}
+			n++
+			if n <= 0 || len(src) < n {
+				return nil, errCorrupt
+			}
+			dst = append(dst, src[:n]...)
+			src = src[n:]
+			continue
+		case 1: // copy with a 1 byte offset
+			if len(src) < 2 {
+				return nil, errCorrupt
+			}
+			n = 4 + int(tag>>2)&7
+			offset = int(tag>>5)<<8 | int(src[1])
+			src = src[2:]
+		case 2: // copy with a 2 bytes offset
+			if len(src) < 3 {
+				return nil, errCorrupt
+			}
+			n = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint16(src[1:]))
+			src = src[3:]
+		case 3: // copy with a 4 bytes offset
+			if len(src) < 5 {
+				return nil, errCorrupt
+			}
+			n = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint32(src[1:]))
+			src = src[5:]
+		}
+		if offset <= 0 || offset > len(dst) {
+			return nil, errCorrupt
+		}
+		// the copies can overlap with the bytes they produce, hence byte by byte
+		start := len(dst) - offset
+		for i := 0; i < n; i++ {
+			dst = append(dst, dst[start+i])
+		}
+	}
+	if uint64(len(dst)) != length {
+		return nil, errCorrupt
+	}
+	return dst, nil
+}
@@ -0,0 +1,544 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package parquet
+
+import (
+	"fmt"
+)
+
+// The structures of parquet.thrift (https://github.com/apache/parquet-format) read by the Reader,
+// with only the fields used by it.
+
+// Type is the physical type of a column.
+type Type int32
+
+const (
+	BOOLEAN              Type = 0
+	INT32                Type = 1
+	INT64                Type = 2
+	INT96                Type = 3
+	FLOAT                Type = 4
+	DOUBLE               Type = 5
+	BYTE_ARRAY           Type = 6
+	FIXED_LEN_BYTE_ARRAY Type = 7
+)
+
+func (t Type) String() string {
+	names := []string{"BOOLEAN", "INT32", "INT64", "INT96", "FLOAT", "DOUBLE", "BYTE_ARRAY", "FIXED_LEN_BYTE_ARRAY"}
+	if t < 0 || int(t) >= len(names) {
+		return fmt.Sprintf("Type(%d)", int32(t))
+	}
+	return names[t]
+}
+
+const (
+	repetitionRequired = 0
+	repetitionOptional = 1
+	repetitionRepeated = 2
+)
+
+// Converted types, the predecessors of the logical types, still written by the writers for compatibility.
+const (
+	convertedUTF8            = 0
+	convertedEnum            = 4
+	convertedDecimal         = 5
+	convertedDate            = 6
+	convertedTimeMillis      = 7
+	convertedTimeMicros      = 8
+	convertedTimestampMillis = 9
+	convertedTimestampMicros = 10
+	convertedUint8           = 11
+	convertedUint16          = 12
+	convertedUint32          = 13
+	convertedUint64          = 14
+	convertedInt8            = 15
+	convertedInt16           = 16
+	convertedInt32           = 17
+	convertedInt64           = 18
+	convertedJSON            = 19
+	convertedBSON            = 20
+)
+
+type Codec int32
+
+const (
+	UNCOMPRESSED Codec = 0
+	SNAPPY       Codec = 1
+	GZIP         Codec = 2
+	LZO          Codec = 3
+	BROTLI       Codec = 4
+	LZ4          Codec = 5
+	ZSTD         Codec = 6
+	LZ4_RAW      Codec = 7
+)
+
+const (
+	encodingPlain           = 0
+	encodingPlainDictionary = 2
+	encodingRLE             = 3
+	encodingBitPacked       = 4
+	encodingRLEDictionary   = 8
+)
+
+const (
+	pageTypeData       = 0
+	pageTypeIndex      = 1
+	pageTypeDictionary = 2
+	pageTypeDataV2     = 3
+)
+
+type LogicalKind int
+
+const (
+	LogicalNone LogicalKind = iota
+	LogicalString
+	LogicalEnum
+	LogicalDecimal
+	LogicalDate
+	LogicalTime
+	LogicalTimestamp
+	LogicalInteger
+	LogicalJSON
+	LogicalBSON
+	LogicalUUID
+)
+
+type TimeUnit int
+
+const (
+	MILLIS TimeUnit = iota
+	MICROS
+	NANOS
+)
+
+// LogicalType is the logical type of a column, i.e. how to interpret its physical type.
+type LogicalType struct {
+	Kind LogicalKind
+	// LogicalDecimal
+	Precision int32
+	Scale     int32
+	// LogicalTime and LogicalTimestamp
+	Unit            TimeUnit
+	IsAdjustedToUTC bool
+	// LogicalInteger
+	BitWidth int8
+	IsSigned bool
+}
+
+// Column is a (leaf) column of a flat schema.
+type Column struct {
+	Name       string
+	Type       Type
+	TypeLength int32 // of FIXED_LEN_BYTE_ARRAY
+	Optional   bool
+	Logical    LogicalType
+}
+
+func (c *Column) maxDefinitionLevel() int {
+	if c.Optional {
+		return 1
+	}
+	return 0
+}
+
+type fileMetaData struct {
+	schema    []*schemaElement
+	numRows   int64
+	rowGroups []*rowGroup
+}
+
+type schemaElement struct {
+	typ            Type
+	hasType        bool
+	typeLength     int32
+	repetitionType int32
+	name           string
+	numChildren    int32
+	convertedType  int32
+	hasConverted   bool
+	scale          int32
+	precision      int32
+	logical        *LogicalType
+}
+
+type rowGroup struct {
+	columns             []*columnChunk
+	totalByteSize       int64
+	numRows             int64
+	totalCompressedSize int64
+}
+
+type columnChunk struct {
+	filePath string
+	metaData *columnMetaData
+}
+
+type columnMetaData struct {
+	typ                   Type
+	encodings             []int32
+	codec                 Codec
+	numValues             int64
+	totalCompressedSize   int64
+	dataPageOffset        int64
+	dictionaryPageOffset  int64
+	hasDictionaryPageOffs bool
+}
+
+type pageHeader struct {
+	typ                  int32
+	uncompressedPageSize int32
+	compressedPageSize   int32
+	dataPageHeader       *dataPageHeader
+	dictionaryPageHeader *dictionaryPageHeader
+	dataPageHeaderV2     *dataPageHeaderV2
+}
+
+type dataPageHeader struct {
+	numValues int32
+	encoding  int32
+}
+
+type dictionaryPageHeader struct {
+	numValues int32
+	encoding  int32
+}
+
+type dataPageHeaderV2 struct {
+	numValues                  int32
+	numNulls                   int32
+	numRows                    int32
+	encoding                   int32
+	definitionLevelsByteLength int32
+	repetitionLevelsByteLength int32
+	isCompressed               bool
+}
+
+//============================================================================
+
+func readFileMetaData(d *thriftDecoder) (*fileMetaData, error) {
+	m := &fileMetaData{}
+	err := d.readStruct(func(id int16, typ byte) (bool, error) {
+		var err error
+		switch {
+		case id == 2 && typ == thriftList:
+			err = readList(d, func() error {
+				e, err := readSchemaElement(d)
+				m.schema = append(m.schema, e)
+				return err
+			})
+		case id == 3 && typ == thriftI64:
+			m.numRows, err = d.readVarint()
+		case id == 4 && typ == thriftList:
+			err = readList(d, func() error {
+				rg, err := readRowGroup(d)
+				m.rowGroups = append(m.rowGroups, rg)
+				return err
+			})
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return m, err
+}
+
+func readList(d *thriftDecoder, elemFn func() error) error {
+	size, _, err := d.readListHeader()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < size; i++ {
+		err = elemFn()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readSchemaElement(d *thriftDecoder) (*schemaElement, error) {
+	e := &schemaElement{}
+	err := d.readStruct(func(id int16, typ byte) (bool, error) {
+		var err error
+		var v int32
+		switch {
+		case id == 1 && typ == thriftI32:
+			v, err = d.readI32()
+			e.typ, e.hasType = Type(v), true
+		case id == 2 && typ == thriftI32:
+			e.typeLength, err = d.readI32()
+		case id == 3 && typ == thriftI32:
+			e.repetitionType, err = d.readI32()
+		case id == 4 && typ == thriftBinary:
+			e.name, err = d.readString()
+		case id == 5 && typ == thriftI32:
+			e.numChildren, err = d.readI32()
+		case id == 6 && typ == thriftI32:
+			e.convertedType, err = d.readI32()
+			e.hasConverted = true
+		case id == 7 && typ == thriftI32:
+			e.scale, err = d.readI32()
+		case id == 8 && typ == thriftI32:
+			e.precision, err = d.readI32()
+		case id == 10 && typ == thriftStruct:
+			e.logical, err = readLogicalType(d)
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return e, err
+}
+
+// readLogicalType reads the LogicalType union. It returns nil for the types not known to the reader.
+func readLogicalType(d *thriftDecoder) (*LogicalType, error) {
+	var result *LogicalType
+	err := d.readStruct(func(id int16, typ byte) (bool, error) {
+		if typ != thriftStruct {
+			return false, nil
+		}
+		var err error
+		switch id {
+		case 1:
+			result = &LogicalType{Kind: LogicalString}
+		case 4:
+			result = &LogicalType{Kind: LogicalEnum}
+		case 5:
+			result = &LogicalType{Kind: LogicalDecimal}
+			err = d.readStruct(func(id int16, typ byte) (bool, error) {
+				var err error
+				switch {
+				case id == 1 && typ == thriftI32:
+					result.Scale, err = d.readI32()
+				case id == 2 && typ == thriftI32:
+					result.Precision, err = d.readI32()
+				default:
+					return false, nil
+				}
+				return true, err
+			})
+			return true, err
+		case 6:
+			result = &LogicalType{Kind: LogicalDate}
+		case 7, 8:
+			result = &LogicalType{Kind: LogicalTime}
+			if id == 8 {
+				result.Kind = LogicalTimestamp
+			}
+			err = d.readStruct(func(id int16, typ byte) (bool, error) {
+				switch {
+				case id == 1 && (typ == thriftBoolTrue || typ == thriftBoolFalse):
+					result.IsAdjustedToUTC = readBool(typ)
+					return true, nil
+				case id == 2 && typ == thriftStruct:
+					var err error
+					result.Unit, err = readTimeUnit(d)
+					return true, err
+				default:
+					return false, nil
+				}
+			})
+			return true, err
+		case 10:
+			result = &LogicalType{Kind: LogicalInteger}
+			err = d.readStruct(func(id int16, typ byte) (bool, error) {
+				switch {
+				case id == 1 && typ == thriftByte:
+					b, err := d.readByte()
+					result.BitWidth = int8(b)
+					return true, err
+				case id == 2 && (typ == thriftBoolTrue || typ == thriftBoolFalse):
+					result.IsSigned = readBool(typ)
+					return true, nil
+				default:
+					return false, nil
+				}
+			})
+			return true, err
+		case 12:
+			result = &LogicalType{Kind: LogicalJSON}
+		case 13:
+			result = &LogicalType{Kind: LogicalBSON}
+		case 14:
+			result = &LogicalType{Kind: LogicalUUID}
+		}
+		return false, nil // the empty structs are skipped
+	})
+	return result, err
+}
+
+func readTimeUnit(d *thriftDecoder) (TimeUnit, error) {
+	unit := MILLIS
+	err := d.readStruct(func(id int16, typ byte) (bool, error) {
+		switch id {
+		case 2:
+			unit = MICROS
+		case 3:
+			unit = NANOS
+		}
+		return false, nil
+	})
+	return unit, err
+}
+
+func readRowGroup(d *thriftDecoder) (*rowGroup, error) {
+	rg := &rowGroup{}
+	err := d.readStruct(func(id int16, typ byte) (bool, error) {
+		var err error
+		switch {
+		case id == 1 && typ == thriftList:
+			err = readList(d, func() error {
+				cc, err := readColumnChunk(d)
+				rg.columns = append(rg.columns, cc)
+				return err
+			})
+		case id == 2 && typ == thriftI64:
+			rg.totalByteSize, err = d.readVarint()
+		case id == 3 && typ == thriftI64:
+			rg.numRows, err = d.readVarint()
+		case id == 6 && typ == thriftI64:
+			rg.totalCompressedSize, err = d.readVarint()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return rg, err
+}
+
+func readColumnChunk(d *thriftDecoder) (*columnChunk, error) {
+	cc := &columnChunk{}
+	err := d.readStruct(func(id int16, typ byte) (bool, error) {
+		var err error
+		switch {
+		case id == 1 && typ == thriftBinary:
+			cc.filePath, err = d.readString()
+		case id == 3 && typ == thriftStruct:
+			cc.metaData, err = readColumnMetaData(d)
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return cc, err
+}
+
+func readColumnMetaData(d *thriftDecoder) (*columnMetaData, error) {
+	m := &columnMetaData{}
+	err := d.readStruct(func(id int16, typ byte) (bool, error) {
+		var err error
+		var v int32
+		switch {
+		case id == 1 && typ == thriftI32:
+			v, err = d.readI32()
+			m.typ = Type(v)
+		case id == 2 && typ == thriftList:
+			err = readList(d, func() error {
+				encoding, err := d.readI32()
+				m.encodings = append(m.encodings, encoding)
+				return err
+			})
+		case id == 4 && typ == thriftI32:
+			v, err = d.readI32()
+			m.codec = Codec(v)
+		case id == 5 && typ == thriftI64:
+			m.numValues, err = d.readVarint()
+		case id == 7 && typ == thriftI64:
+			m.totalCompressedSize, err = d.readVarint()
+		case id == 9 && typ == thriftI64:
+			m.dataPageOffset, err = d.readVarint()
+		case id == 11 && typ == thriftI64:
+			m.dictionaryPageOffset, err = d.readVarint()
+			m.hasDictionaryPageOffs = true
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return m, err
+}
+
+func readPageHeader(d *thriftDecoder) (*pageHeader, error) {
+	h := &pageHeader{}
+	err := d.readStruct(func(id int16, typ byte) (bool, error) {
+		var err error
+		switch {
+		case id == 1 && typ == thriftI32:
+			h.typ, err = d.readI32()
+		case id == 2 && typ == thriftI32:
+			h.uncompressedPageSize, err = d.readI32()
+		case id == 3 && typ == thriftI32:
+			h.compressedPageSize, err = d.readI32()
+		case id == 5 && typ == thriftStruct:
+			h.dataPageHeader = &dataPageHeader{}
+			err = d.readStruct(func(id int16, typ byte) (bool, error) {
+				var err error
+				switch {
+				case id == 1 && typ == thriftI32:
+					h.dataPageHeader.numValues, err = d.readI32()
+				case id == 2 && typ == thriftI32:
+					h.dataPageHeader.encoding, err = d.readI32()
+				default:
+					return false, nil
+				}
+				return true, err
+			})
+		case id == 7 && typ == thriftStruct:
+			h.dictionaryPageHeader = &dictionaryPageHeader{}
+			err = d.readStruct(func(id int16, typ byte) (bool, error) {
+				var err error
+				switch {
+				case id == 1 && typ == thriftI32:
+					h.dictionaryPageHeader.numValues, err = d.readI32()
+				case id == 2 && typ == thriftI32:
+					h.dictionaryPageHeader.encoding, err = d.readI32()
+				default:
+					return false, nil
+				}
+				return true, err
+			})
+		case id == 8 && typ == thriftStruct:
+			h.dataPageHeaderV2 = &dataPageHeaderV2{isCompressed: true}
+			v2 := h.dataPageHeaderV2
+			err = d.readStruct(func(id int16, typ byte) (bool, error) {
+				var err error
+				switch {
+				case id == 1 && typ == thriftI32:
+					v2.numValues, err = d.readI32()
+				case id == 2 && typ == thriftI32:
+					v2.numNulls, err = d.readI32()
+				case id == 3 && typ == thriftI32:
+					v2.numRows, err = d.readI32()
+				case id == 4 && typ == thriftI32:
+					v2.encoding, err = d.readI32()
+				case id == 5 && typ == thriftI32:
+					v2.definitionLevelsByteLength, err = d.readI32()
+				case id == 6 && typ == thriftI32:
+					v2.repetitionLevelsByteLength, err = d.readI32()
+				case id == 7 && (typ == thriftBoolTrue || typ == thriftBoolFalse):
+					v2.isCompressed = readBool(typ)
+				default:
+					return false, nil
+				}
+				return true, err
+			})
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return h, err
+}
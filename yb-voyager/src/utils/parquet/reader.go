@@ -0,0 +1,384 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+/*
+Reader reads the rows of a Parquet file (https://github.com/apache/parquet-format). It supports the
+files written by the common writers (Spark, Arrow, DuckDB, ...) for flat schemas:
+  - required and optional columns of all the physical types; no nested or repeated columns
+  - PLAIN and dictionary encoded values, in v1 and v2 data pages
+  - UNCOMPRESSED, SNAPPY and GZIP compression
+
+The values of a column are decoded as: nil (null), bool, int32, int64, Int96, float32, float64 or []byte
+(BYTE_ARRAY and FIXED_LEN_BYTE_ARRAY), to be interpreted with the logical type of the column.
+*/
+type Reader struct {
+	r        io.ReaderAt
+	size     int64
+	metaData *fileMetaData
+	columns  []*Column
+}
+
+const magic = "PAR1"
+
+// RowGroup is the values of the columns of a row group, Columns[i][j] being the value of column i in row j.
+type RowGroup struct {
+	NumRows        int64
+	CompressedSize int64
+	Columns        [][]interface{}
+}
+
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	if size < int64(2*len(magic)+4) {
+		return nil, fmt.Errorf("parquet: file too small (%d bytes)", size)
+	}
+	tail := make([]byte, 4+len(magic))
+	_, err := r.ReadAt(tail, size-int64(len(tail)))
+	if err != nil {
+		return nil, fmt.Errorf("parquet: read footer: %w", err)
+	}
+	if string(tail[4:]) != magic {
+		return nil, fmt.Errorf("parquet: not a parquet file (magic %q)", tail[4:])
+	}
+	footerSize := int64(binary.LittleEndian.Uint32(tail))
+	if footerSize > size-int64(2*len(magic)+4) {
+		return nil, fmt.Errorf("parquet: invalid footer size %d", footerSize)
+	}
+	footer := make([]byte, footerSize)
+	_, err = r.ReadAt(footer, size-int64(len(tail))-footerSize)
+	if err != nil {
+		return nil, fmt.Errorf("parquet: read footer: %w", err)
+	}
+	metaData, err := readFileMetaData(&thriftDecoder{buf: footer})
+	if err != nil {
+		return nil, fmt.Errorf("parquet: decode footer: %w", err)
+	}
+	columns, err := getColumns(metaData.schema)
+	if err != nil {
+		return nil, err
+	}
+	for i, rg := range metaData.rowGroups {
+		if len(rg.columns) != len(columns) {
+			return nil, fmt.Errorf("parquet: row group %d has %d columns, expected %d", i, len(rg.columns), len(columns))
+		}
+		for _, cc := range rg.columns {
+			if cc.filePath != "" {
+				return nil, fmt.Errorf("parquet: columns in external files (%q) are not supported", cc.filePath)
+			}
+			if cc.metaData == nil {
+				return nil, fmt.Errorf("parquet: column chunk of row group %d without metadata", i)
+			}
+			err = checkColumnChunkSupported(cc.metaData)
+			if err != nil {
+				return nil, fmt.Errorf("parquet: row group %d: %w", i, err)
+			}
+		}
+	}
+	return &Reader{r: r, size: size, metaData: metaData, columns: columns}, nil
+}
+
+// checkColumnChunkSupported rejects, upfront, the column chunks that the reader can't decode; instead of failing
+// the import midway, after importing the preceding row groups.
+func checkColumnChunkSupported(m *columnMetaData) error {
+	switch m.codec {
+	case UNCOMPRESSED, SNAPPY, GZIP:
+	default:
+		return fmt.Errorf("unsupported compression codec %d, only UNCOMPRESSED, SNAPPY and GZIP are supported", m.codec)
+	}
+	for _, encoding := range m.encodings {
+		switch encoding {
+		case encodingPlain, encodingPlainDictionary, encodingRLE, encodingBitPacked, encodingRLEDictionary:
+		default:
+			return fmt.Errorf("unsupported encoding %d, only PLAIN and dictionary encodings are supported", encoding)
+		}
+	}
+	return nil
+}
+
+func getColumns(schema []*schemaElement) ([]*Column, error) {
+	if len(schema) == 0 {
+		return nil, fmt.Errorf("parquet: empty schema")
+	}
+	if int(schema[0].numChildren) != len(schema)-1 {
+		return nil, fmt.Errorf("parquet: nested schemas are not supported")
+	}
+	var columns []*Column
+	for _, e := range schema[1:] {
+		if !e.hasType || e.numChildren > 0 {
+			return nil, fmt.Errorf("parquet: nested column %q is not supported", e.name)
+		}
+		if e.repetitionType == repetitionRepeated {
+			return nil, fmt.Errorf("parquet: repeated column %q is not supported", e.name)
+		}
+		column := &Column{
+			Name:       e.name,
+			Type:       e.typ,
+			TypeLength: e.typeLength,
+			Optional:   e.repetitionType == repetitionOptional,
+		}
+		if e.logical != nil {
+			column.Logical = *e.logical
+		} else if e.hasConverted {
+			column.Logical = getLogicalTypeOfConvertedType(e.convertedType, e.precision, e.scale)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+func getLogicalTypeOfConvertedType(convertedType int32, precision, scale int32) LogicalType {
+	switch convertedType {
+	case convertedUTF8:
+		return LogicalType{Kind: LogicalString}
+	case convertedEnum:
+		return LogicalType{Kind: LogicalEnum}
+	case convertedDecimal:
+		return LogicalType{Kind: LogicalDecimal, Precision: precision, Scale: scale}
+	case convertedDate:
+		return LogicalType{Kind: LogicalDate}
+	case convertedTimeMillis, convertedTimeMicros:
+		unit := map[int32]TimeUnit{convertedTimeMillis: MILLIS, convertedTimeMicros: MICROS}[convertedType]
+		return LogicalType{Kind: LogicalTime, Unit: unit, IsAdjustedToUTC: true}
+	case convertedTimestampMillis, convertedTimestampMicros:
+		unit := map[int32]TimeUnit{convertedTimestampMillis: MILLIS, convertedTimestampMicros: MICROS}[convertedType]
+		return LogicalType{Kind: LogicalTimestamp, Unit: unit, IsAdjustedToUTC: true}
+	case convertedUint8, convertedUint16, convertedUint32, convertedUint64:
+		bitWidth := map[int32]int8{convertedUint8: 8, convertedUint16: 16, convertedUint32: 32, convertedUint64: 64}[convertedType]
+		return LogicalType{Kind: LogicalInteger, BitWidth: bitWidth, IsSigned: false}
+	case convertedInt8, convertedInt16, convertedInt32, convertedInt64:
+		bitWidth := map[int32]int8{convertedInt8: 8, convertedInt16: 16, convertedInt32: 32, convertedInt64: 64}[convertedType]
+		return LogicalType{Kind: LogicalInteger, BitWidth: bitWidth, IsSigned: true}
+	case convertedJSON:
+		return LogicalType{Kind: LogicalJSON}
+	case convertedBSON:
+		return LogicalType{Kind: LogicalBSON}
+	default:
+		return LogicalType{}
+	}
+}
+
+func (r *Reader) Columns() []*Column {
+	return r.columns
+}
+
+func (r *Reader) NumRows() int64 {
+	return r.metaData.numRows
+}
+
+func (r *Reader) NumRowGroups() int {
+	return len(r.metaData.rowGroups)
+}
+
+func (r *Reader) RowGroupNumRows(i int) int64 {
+	return r.metaData.rowGroups[i].numRows
+}
+
+// ReadRowGroup reads and decodes all the values of the i-th row group.
+func (r *Reader) ReadRowGroup(i int) (*RowGroup, error) {
+	rg := r.metaData.rowGroups[i]
+	result := &RowGroup{NumRows: rg.numRows, CompressedSize: rg.totalCompressedSize}
+	for j, cc := range rg.columns {
+		values, err := r.readColumnChunk(r.columns[j], cc.metaData, rg.numRows)
+		if err != nil {
+			return nil, fmt.Errorf("parquet: row group %d, column %q: %w", i, r.columns[j].Name, err)
+		}
+		result.Columns = append(result.Columns, values)
+		if rg.totalCompressedSize == 0 {
+			result.CompressedSize += cc.metaData.totalCompressedSize
+		}
+	}
+	return result, nil
+}
+
+func (r *Reader) readColumnChunk(column *Column, m *columnMetaData, numRows int64) ([]interface{}, error) {
+	if m.typ != column.Type {
+		return nil, fmt.Errorf("type %s of the column chunk differs from the type %s in the schema", m.typ, column.Type)
+	}
+	offset := m.dataPageOffset
+	if m.hasDictionaryPageOffs && m.dictionaryPageOffset > 0 && m.dictionaryPageOffset < offset {
+		offset = m.dictionaryPageOffset
+	}
+	if offset < 0 || m.totalCompressedSize < 0 || offset+m.totalCompressedSize > r.size {
+		return nil, fmt.Errorf("invalid column chunk range %d+%d", offset, m.totalCompressedSize)
+	}
+	buf := make([]byte, m.totalCompressedSize)
+	_, err := r.r.ReadAt(buf, offset)
+	if err != nil {
+		return nil, fmt.Errorf("read column chunk: %w", err)
+	}
+
+	values := make([]interface{}, 0, numRows)
+	var dictionary []interface{}
+	pos := 0
+	for int64(len(values)) < m.numValues {
+		if pos >= len(buf) {
+			return nil, fmt.Errorf("%d values in the column chunk, expected %d", len(values), m.numValues)
+		}
+		d := &thriftDecoder{buf: buf[pos:]}
+		header, err := readPageHeader(d)
+		if err != nil {
+			return nil, fmt.Errorf("decode page header: %w", err)
+		}
+		pos += d.pos
+		if header.compressedPageSize < 0 || pos+int(header.compressedPageSize) > len(buf) {
+			return nil, errTruncated
+		}
+		page := buf[pos : pos+int(header.compressedPageSize)]
+		pos += int(header.compressedPageSize)
+
+		switch {
+		case header.typ == pageTypeDictionary && header.dictionaryPageHeader != nil:
+			page, err = decompress(m.codec, page, int(header.uncompressedPageSize))
+			if err != nil {
+				return nil, err
+			}
+			dictionary, err = decodePlain(page, column.Type, int(column.TypeLength), int(header.dictionaryPageHeader.numValues))
+		case header.typ == pageTypeData && header.dataPageHeader != nil:
+			values, err = r.decodeDataPage(column, m.codec, header, page, dictionary, values)
+		case header.typ == pageTypeDataV2 && header.dataPageHeaderV2 != nil:
+			values, err = r.decodeDataPageV2(column, m.codec, header, page, dictionary, values)
+		case header.typ == pageTypeIndex:
+			continue
+		default:
+			return nil, fmt.Errorf("unsupported page type %d", header.typ)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if int64(len(values)) != numRows {
+		return nil, fmt.Errorf("%d values in the column chunk, expected %d", len(values), numRows)
+	}
+	return values, nil
+}
+
+func (r *Reader) decodeDataPage(column *Column, codec Codec, header *pageHeader, page []byte,
+	dictionary []interface{}, values []interface{}) ([]interface{}, error) {
+
+	page, err := decompress(codec, page, int(header.uncompressedPageSize))
+	if err != nil {
+		return nil, err
+	}
+	numValues := int(header.dataPageHeader.numValues)
+	var defLevels []uint32
+	if column.maxDefinitionLevel() > 0 {
+		if len(page) < 4 {
+			return nil, errTruncated
+		}
+		length := int(binary.LittleEndian.Uint32(page))
+		if length < 0 || 4+length > len(page) {
+			return nil, errTruncated
+		}
+		defLevels, err = decodeRLEHybrid(page[4:4+length], 1, numValues)
+		if err != nil {
+			return nil, fmt.Errorf("decode definition levels: %w", err)
+		}
+		page = page[4+length:]
+	}
+	return appendPageValues(column, header.dataPageHeader.encoding, page, defLevels, numValues, dictionary, values)
+}
+
+func (r *Reader) decodeDataPageV2(column *Column, codec Codec, header *pageHeader, page []byte,
+	dictionary []interface{}, values []interface{}) ([]interface{}, error) {
+
+	v2 := header.dataPageHeaderV2
+	repLength, defLength := int(v2.repetitionLevelsByteLength), int(v2.definitionLevelsByteLength)
+	if repLength < 0 || defLength < 0 || repLength+defLength > len(page) {
+		return nil, errTruncated
+	}
+	numValues := int(v2.numValues)
+	var defLevels []uint32
+	var err error
+	if column.maxDefinitionLevel() > 0 {
+		defLevels, err = decodeRLEHybrid(page[repLength:repLength+defLength], 1, numValues)
+		if err != nil {
+			return nil, fmt.Errorf("decode definition levels: %w", err)
+		}
+	}
+	// the levels are never compressed
+	data := page[repLength+defLength:]
+	if v2.isCompressed {
+		data, err = decompress(codec, data, int(header.uncompressedPageSize)-repLength-defLength)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return appendPageValues(column, v2.encoding, data, defLevels, numValues, dictionary, values)
+}
+
+// appendPageValues decodes the non-null values of the page and appends them, along with the nulls, to `values`.
+func appendPageValues(column *Column, encoding int32, data []byte, defLevels []uint32, numValues int,
+	dictionary []interface{}, values []interface{}) ([]interface{}, error) {
+
+	numNonNull := numValues
+	if defLevels != nil {
+		numNonNull = 0
+		for _, level := range defLevels {
+			if level == 1 {
+				numNonNull++
+			}
+		}
+	}
+	var nonNullValues []interface{}
+	var err error
+	switch encoding {
+	case encodingPlain:
+		nonNullValues, err = decodePlain(data, column.Type, int(column.TypeLength), numNonNull)
+	case encodingPlainDictionary, encodingRLEDictionary:
+		if dictionary == nil {
+			return nil, fmt.Errorf("dictionary encoded page without a dictionary page")
+		}
+		if len(data) < 1 {
+			return nil, errTruncated
+		}
+		var indices []uint32
+		indices, err = decodeRLEHybrid(data[1:], int(data[0]), numNonNull)
+		if err != nil {
+			return nil, fmt.Errorf("decode dictionary indices: %w", err)
+		}
+		nonNullValues = make([]interface{}, 0, numNonNull)
+		for _, index := range indices {
+			if int(index) >= len(dictionary) {
+				return nil, fmt.Errorf("dictionary index %d out of range (%d values)", index, len(dictionary))
+			}
+			nonNullValues = append(nonNullValues, dictionary[index])
+		}
+	default:
+		return nil, fmt.Errorf("unsupported encoding %d, only PLAIN and dictionary encodings are supported", encoding)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if defLevels == nil {
+		return append(values, nonNullValues...), nil
+	}
+	next := 0
+	for _, level := range defLevels {
+		if level == 1 {
+			values = append(values, nonNullValues[next])
+			next++
+		} else {
+			values = append(values, nil)
+		}
+	}
+	return values, nil
+}
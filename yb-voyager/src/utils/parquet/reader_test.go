@@ -0,0 +1,130 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package parquet
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testColumns = []*Column{
+	{Name: "id", Type: INT64},
+	{Name: "name", Type: BYTE_ARRAY, Optional: true, Logical: LogicalType{Kind: LogicalString}},
+	{Name: "price", Type: INT32, Optional: true, Logical: LogicalType{Kind: LogicalDecimal, Precision: 9, Scale: 2}},
+	{Name: "created_at", Type: INT64, Optional: true, Logical: LogicalType{Kind: LogicalTimestamp, Unit: MICROS, IsAdjustedToUTC: true}},
+	{Name: "active", Type: BOOLEAN, Optional: true},
+	{Name: "score", Type: DOUBLE},
+	{Name: "code", Type: FIXED_LEN_BYTE_ARRAY, TypeLength: 2, Optional: true},
+}
+
+var testRows = [][]interface{}{
+	{int64(1), []byte("alice"), int32(12345), int64(1690000000123456), true, 1.5, []byte("ab")},
+	{int64(2), nil, nil, nil, nil, -2.25, nil},
+	{int64(3), []byte("bob"), int32(-5), int64(0), false, 0.0, []byte("cd")},
+	{int64(4), []byte("alice"), int32(0), int64(-1000000), true, 3e100, nil},
+}
+
+// readTestFile reads a file of testdata, with the testRows in two row groups (testRows[:3] and testRows[3:]).
+// TODO: the files were written by the writer this package used to have for the tests, regenerate them with an
+// established writer (e.g. pyarrow) to check the reader against it.
+func readTestFile(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	assert.NoError(t, err)
+	return data
+}
+
+func readAllRows(t *testing.T, data []byte) [][]interface{} {
+	r, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	assert.Equal(t, testColumns, r.Columns())
+	var rows [][]interface{}
+	for i := 0; i < r.NumRowGroups(); i++ {
+		rg, err := r.ReadRowGroup(i)
+		assert.NoError(t, err)
+		assert.Greater(t, rg.CompressedSize, int64(0))
+		for j := int64(0); j < rg.NumRows; j++ {
+			row := make([]interface{}, len(rg.Columns))
+			for k := range rg.Columns {
+				row[k] = rg.Columns[k][j]
+			}
+			rows = append(rows, row)
+		}
+	}
+	assert.Equal(t, int64(len(rows)), r.NumRows())
+	return rows
+}
+
+func TestReadFile(t *testing.T) {
+	for _, codec := range []string{"uncompressed", "snappy", "gzip"} {
+		for _, encoding := range []string{"plain", "dictionary"} {
+			name := fmt.Sprintf("%s_%s.parquet", encoding, codec)
+			assert.Equal(t, testRows, readAllRows(t, readTestFile(t, name)), name)
+		}
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	_, err := NewReader(bytes.NewReader([]byte("PAR1")), 4)
+	assert.Error(t, err)
+
+	data := []byte("not a parquet file at all")
+	_, err = NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.ErrorContains(t, err, "not a parquet file")
+
+	data = readTestFile(t, "plain_uncompressed.parquet")
+	truncated := append([]byte{}, data...)
+	for i := 4; i < 40; i++ {
+		truncated[i] = 0xff // garble the first page
+	}
+	r, err := NewReader(bytes.NewReader(truncated), int64(len(truncated)))
+	assert.NoError(t, err)
+	_, err = r.ReadRowGroup(0)
+	assert.Error(t, err)
+}
+
+func TestDecodeRLEHybrid(t *testing.T) {
+	// an RLE run of 3 x 5, followed by a bit-packed run of 8 values of 3 bits: 0..7
+	buf := []byte{3 << 1, 5, 1<<1 | 1, 0x88, 0xc6, 0xfa}
+	values, err := decodeRLEHybrid(buf, 3, 11)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{5, 5, 5, 0, 1, 2, 3, 4, 5, 6, 7}, values)
+
+	_, err = decodeRLEHybrid(buf[:4], 3, 11)
+	assert.Error(t, err)
+}
+
+func TestSnappyDecode(t *testing.T) {
+	// "abcd" as a literal, followed by a copy of 8 bytes at offset 4 (overlapping what it produces)
+	block := []byte{12, 3 << 2, 'a', 'b', 'c', 'd', 1 | (8-4)<<2, 4}
+	decoded, err := snappyDecode(block)
+	assert.NoError(t, err)
+	assert.Equal(t, "abcdabcdabcd", string(decoded))
+
+	_, err = snappyDecode([]byte{12, 3 << 2, 'a', 'b', 'c', 'd', 1 | (8-4)<<2, 5}) // offset beyond the output
+	assert.Error(t, err)
+}
+
+func TestCheckColumnChunkSupported(t *testing.T) {
+	assert.NoError(t, checkColumnChunkSupported(&columnMetaData{codec: SNAPPY, encodings: []int32{encodingRLEDictionary, encodingPlain, encodingRLE}}))
+	deltaBinaryPacked := int32(5)
+	assert.ErrorContains(t, checkColumnChunkSupported(&columnMetaData{codec: GZIP, encodings: []int32{deltaBinaryPacked}}), "unsupported encoding 5")
+	assert.ErrorContains(t, checkColumnChunkSupported(&columnMetaData{codec: ZSTD}), "unsupported compression codec 6")
+}
@@ -0,0 +1,231 @@
+/*
+Copyright (c) YugabyteDB, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package parquet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+/*
+The metadata of a Parquet file (the footer and the page headers) is serialized with the Thrift
+compact protocol, see https://github.com/apache/thrift/blob/master/doc/specs/thrift-compact-protocol.md.
+Only the subset of the protocol used by the Parquet metadata is implemented.
+*/
+
+const (
+	thriftStop       = 0
+	thriftBoolTrue   = 1
+	thriftBoolFalse  = 2
+	thriftByte       = 3
+	thriftI16        = 4
+	thriftI32        = 5
+	thriftI64        = 6
+	thriftDouble     = 7
+	thriftBinary     = 8
+	thriftList       = 9
+	thriftSet        = 10
+	thriftMap        = 11
+	thriftStruct     = 12
+	thriftMaxNesting = 64
+)
+
+var errThriftTruncated = errors.New("thrift: truncated input")
+
+type thriftDecoder struct {
+	buf   []byte
+	pos   int
+	depth int
+}
+
+func (d *thriftDecoder) readByte() (byte, error) {
+	if d.pos >= len(d.buf) {
+		return 0, errThriftTruncated
+	}
+	b := d.buf[d.pos]
+	d.pos++
+	return b, nil
+}
+
+func (d *thriftDecoder) readUvarint() (uint64, error) {
+	v, n := binary.Uvarint(d.buf[d.pos:])
+	if n <= 0 {
+		return 0, errThriftTruncated
+	}
+	d.pos += n
+	return v, nil
+}
+
+func (d *thriftDecoder) readVarint() (int64, error) {
+	v, err := d.readUvarint()
+	if err != nil {
+		return 0, err
+	}
+	return int64(v>>1) ^ -int64(v&1), nil // zigzag
+}
+
+func (d *thriftDecoder) readI32() (int32, error) {
+	v, err := d.readVarint()
+	return int32(v), err
+}
+
+func (d *thriftDecoder) readBinary() ([]byte, error) {
+	n, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(d.buf)-d.pos) {
+		return nil, errThriftTruncated
+	}
+	b := d.buf[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+func (d *thriftDecoder) readString() (string, error) {
+	b, err := d.readBinary()
+	return string(b), err
+}
+
+// readListHeader returns the number of elements and their type.
+func (d *thriftDecoder) readListHeader() (int, byte, error) {
+	b, err := d.readByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	size := int(b >> 4)
+	if size == 15 {
+		n, err := d.readUvarint()
+		if err != nil {
+			return 0, 0, err
+		}
+		if n > uint64(len(d.buf)) { // every element takes at least a byte
+			return 0, 0, errThriftTruncated
+		}
+		size = int(n)
+	}
+	return size, b & 0x0f, nil
+}
+
+// readStruct calls fieldFn for each field of the struct. fieldFn returns false for the fields
+// it does not read, which are skipped.
+func (d *thriftDecoder) readStruct(fieldFn func(id int16, typ byte) (bool, error)) error {
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > thriftMaxNesting {
+		return fmt.Errorf("thrift: structs nested deeper than %d", thriftMaxNesting)
+	}
+	var lastID int16
+	for {
+		b, err := d.readByte()
+		if err != nil {
+			return err
+		}
+		typ := b & 0x0f
+		if typ == thriftStop {
+			return nil
+		}
+		id := lastID + int16(b>>4)
+		if b>>4 == 0 {
+			v, err := d.readVarint()
+			if err != nil {
+				return err
+			}
+			id = int16(v)
+		}
+		lastID = id
+		read, err := fieldFn(id, typ)
+		if err != nil {
+			return err
+		}
+		if !read {
+			err = d.skip(typ)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readBool reads the value of a bool field, which is encoded in its type.
+func readBool(typ byte) bool {
+	return typ == thriftBoolTrue
+}
+
+func (d *thriftDecoder) skip(typ byte) error {
+	switch typ {
+	case thriftBoolTrue, thriftBoolFalse:
+		return nil
+	case thriftByte:
+		_, err := d.readByte()
+		return err
+	case thriftI16, thriftI32, thriftI64:
+		_, err := d.readVarint()
+		return err
+	case thriftDouble:
+		if len(d.buf)-d.pos < 8 {
+			return errThriftTruncated
+		}
+		d.pos += 8
+		return nil
+	case thriftBinary:
+		_, err := d.readBinary()
+		return err
+	case thriftList, thriftSet:
+		size, elemType, err := d.readListHeader()
+		if err != nil {
+			return err
+		}
+		for i := 0; i < size; i++ {
+			if elemType == thriftBoolTrue || elemType == thriftBoolFalse {
+				_, err = d.readByte() // bool elements take a byte
+			} else {
+				err = d.skip(elemType)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case thriftMap:
+		size, err := d.readUvarint()
+		if err != nil {
+			return err
+		}
+		if size == 0 {
+			return nil
+		}
+		types, err := d.readByte()
+		if err != nil {
+			return err
+		}
+		for i := uint64(0); i < size; i++ {
+			err = d.skip(types >> 4)
+			if err == nil {
+				err = d.skip(types & 0x0f)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case thriftStruct:
+		return d.readStruct(func(int16, byte) (bool, error) { return false, nil })
+	default:
+		return fmt.Errorf("thrift: unknown type %d", typ)
+	}
+}
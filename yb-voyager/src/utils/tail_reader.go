@@ -31,6 +31,11 @@ func NewTailReader(r io.Reader, getLastValidOffsetFn func() (int64, error)) *Tai
 	return &TailReader{r: r, getLastValidOffsetFn: getLastValidOffsetFn}
 }
 
+// NewTailReaderAt returns a TailReader of r positioned at the given offset, i.e. the first offset bytes are already read.
+func NewTailReaderAt(r io.Reader, offset int64, getLastValidOffsetFn func() (int64, error)) *TailReader {
+	return &TailReader{r: r, bytesRead: offset, getLastValidOffsetFn: getLastValidOffsetFn}
+}
+
 // Read the underlying io.Reader and return the contents.
 // If the underlying reader returns io.EOF, keep on retrying until some data is available.
 func (t *TailReader) Read(p []byte) (n int, err error) {
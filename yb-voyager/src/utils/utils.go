@@ -427,6 +427,25 @@ func GetEnvAsInt(key string, fallback int) int {
 	return int(valueInt)
 }
 
+// GetEnvAsDuration reads a duration from the env var `key`. The value is either a duration string
+// (e.g. "2s", "500ms") or, for backward compatibility, a plain integer in `defaultUnit`.
+func GetEnvAsDuration(key string, fallback time.Duration, defaultUnit time.Duration) time.Duration {
+	valueStr, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	valueInt, err := strconv.ParseInt(valueStr, 10, 64)
+	if err == nil {
+		return time.Duration(valueInt) * defaultUnit
+	}
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		PrintAndLog("Couldn't interpret env var %v=%v. Defaulting to %v", key, valueStr, fallback)
+		return fallback
+	}
+	return value
+}
+
 func GetMapKeysSorted(m map[string]*string) []string {
 	keys := lo.Keys(m)
 	sort.Strings(keys)